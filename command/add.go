@@ -3,6 +3,11 @@ package command
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"warden/internal/api/nexus"
+	"warden/internal/api/source"
+	"warden/internal/data/file"
 	"warden/internal/service"
 
 	"github.com/spf13/cobra"
@@ -11,44 +16,104 @@ import (
 func NewAddCommand(fs service.Framework, ms service.Mod) *cobra.Command {
 	var namespace string
 	var modPkg string
+	var src string
+	var archive string
+	var url string
 
 	cmd := &cobra.Command{
 		Use:   "add",
 		Short: "Adds the specified mod.",
-		Long:  "Searches Thunderstone for the specified mod, downloads it, then adds it to your local mod collection.",
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := fs.InstallBepInEx(); err != nil {
-				parseAddError(err)
-				return
+		Long:  "Searches Thunderstore or Nexus Mods for the specified mod, downloads it, then adds it to your local mod collection. Nexus Mods mods are added by their mod ID, e.g. --source nexus --mod 4. Mods that aren't listed anywhere can be added from their package's zip file with --file or --url instead, which is described by the manifest.json inside it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Mods added from an archive are looked up by where the archive is
+			if archive != "" {
+				path, err := filepath.Abs(archive)
+				if err != nil {
+					return err
+				}
+				src, modPkg = source.File, path
+			} else if url != "" {
+				src, modPkg = source.URL, url
 			}
-			if err := ms.AddMod(namespace, modPkg); err != nil {
-				parseAddError(err)
-			} else {
-				fmt.Println("... successfully installed mod! ...")
+
+			// Nexus Mods identifies mods by their ID alone, and archives name themselves
+			if src == source.Thunderstore && namespace == "" {
+				return fmt.Errorf("required flag \"%s\" not set", namespaceFlagLong)
+			}
+			if isDryRun(cmd) {
+				return planAdd(fs, ms, src, namespace, modPkg)
+			}
+			// BepInEx can be managed outside of Warden, so declining to install it isn't fatal
+			if err := fs.InstallBepInEx(); err != nil && !errors.Is(err, service.ErrAborted) {
+				return fail(err, addErrorMessage(err))
+			}
+			if err := ms.AddMod(src, namespace, modPkg); err != nil {
+				return fail(err, addErrorMessage(err))
 			}
+			writeMessage("successfully installed mod!")
+			return nil
 		},
 	}
-	cmd.Flags().StringVarP(&namespace, namespaceFlagLong, namespaceFlagShort, "", namespaceFlagDesc)
-	cmd.Flags().StringVarP(&modPkg, modPackageFlagLong, modPackageFlagShort, "", modPackageFlagDesc)
+	cmd.Flags().StringVarP(&namespace, namespaceFlagLong, namespaceFlagShort, "", addNamespaceFlagDesc)
+	cmd.Flags().StringVarP(&modPkg, modPackageFlagLong, modPackageFlagShort, "", addModPackageFlagDesc)
+	cmd.Flags().StringVar(&src, sourceFlagLong, source.Thunderstore, sourceFlagDesc)
+	cmd.Flags().StringVar(&archive, fileFlagLong, "", fileFlagDesc)
+	cmd.Flags().StringVar(&url, urlFlagLong, "", urlFlagDesc)
+	cmd.PersistentFlags().Bool(dryRunFlagLong, false, dryRunFlagDesc)
 
-	cmd.MarkFlagRequired(namespaceFlagLong)
-	cmd.MarkFlagRequired(modPackageFlagLong)
-	cmd.MarkFlagsRequiredTogether(namespaceFlagLong, modPackageFlagLong)
+	cmd.MarkFlagsOneRequired(modPackageFlagLong, fileFlagLong, urlFlagLong)
+	cmd.MarkFlagsMutuallyExclusive(modPackageFlagLong, fileFlagLong, urlFlagLong)
+	cmd.MarkFlagsMutuallyExclusive(sourceFlagLong, fileFlagLong, urlFlagLong)
 	return cmd
 }
 
-func parseAddError(err error) {
+func planAdd(fs service.Framework, ms service.Mod, src, namespace, modPkg string) error {
+	p, err := fs.PlanInstallBepInEx()
+	if err != nil {
+		return fail(err, addErrorMessage(err))
+	}
+	mp, err := ms.PlanAddMod(src, namespace, modPkg)
+	if err != nil {
+		return fail(err, addErrorMessage(err))
+	}
+	p.Merge(mp)
+	writeResult(newPlanView(p))
+	return nil
+}
+
+func addErrorMessage(err error) string {
 	if errors.Is(err, service.ErrModAlreadyInstalled) {
-		fmt.Println("... mod already installed ...")
+		return "mod already installed"
 	} else if errors.Is(err, service.ErrModInstallFailed) {
-		fmt.Println("... unable to install mod ...")
+		return "unable to install mod"
+	} else if errors.Is(err, source.ErrUnknownSource) {
+		return "--source must be thunderstore or nexus"
+	} else if errors.Is(err, nexus.ErrMissingAPIKey) {
+		return "set nexus-api-key in the config to add mods from Nexus Mods"
+	} else if errors.Is(err, nexus.ErrInvalidAPIKey) {
+		return "Nexus Mods rejected the API key, check nexus-api-key in the config"
+	} else if errors.Is(err, nexus.ErrInvalidModID) {
+		return "mods from Nexus Mods are added by their mod ID, e.g. --mod 4"
+	} else if errors.Is(err, nexus.ErrModNotFound) {
+		return "unable to find mod on Nexus Mods"
+	} else if errors.Is(err, service.ErrInvalidNamespace) {
+		return "--namespace must be letters, numbers or _"
+	} else if errors.Is(err, file.ErrInvalidManifest) {
+		return "mod package's zip file doesn't have a valid manifest.json"
+	} else if errors.Is(err, fs.ErrNotExist) {
+		return "unable to find the mod package's zip file"
+	} else if errors.Is(err, source.ErrReleaseNotFound) {
+		return "unable to find a release of the mod"
 	} else if errors.Is(err, service.ErrModNotFound) {
-		fmt.Println("... unable to find mod on Thunderstore")
+		return "unable to find mod on Thunderstore"
 	} else if errors.Is(err, service.ErrAddDependenciesFailed) {
-		fmt.Println("... unable to install mod's dependencies...")
+		return "unable to install mod's dependencies"
 	} else if errors.Is(err, service.ErrUnableToInstallFramework) {
-		fmt.Println("... unable to install BepInEx ...")
+		return "unable to install BepInEx"
 	} else if errors.Is(err, service.ErrFrameworkNotFound) {
-		fmt.Println("... unable to find BepInEx ...")
+		return "unable to find BepInEx"
+	} else if errors.Is(err, service.ErrConfirmationRequired) {
+		return confirmationRequiredMessage
 	}
+	return err.Error()
 }
@@ -1,31 +1,57 @@
 package command
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 	"warden/internal/config"
+	"warden/internal/domain/schedule"
+	"warden/internal/domain/server"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var (
+	errConfigKeyNotFound  = errors.New("configuration key does not exist")
+	errInvalidConfigKey   = errors.New("not a valid config setting")
+	errInvalidConfigValue = errors.New("not a valid value for the config setting")
+	errConfigWriteFailed  = errors.New("unable to save configuration")
+	configErrorCodes      = map[error]string{
+		errConfigKeyNotFound:         "config_key_not_found",
+		errInvalidConfigKey:          "invalid_config_key",
+		errInvalidConfigValue:        "invalid_config_value",
+		errConfigWriteFailed:         "config_write_failed",
+		config.ErrPathNotFound:       "config_path_not_found",
+		config.ErrPathNotDirectory:   "config_path_not_directory",
+		config.ErrPathNotWritable:    "config_path_not_writable",
+		config.ErrPathOutsideValheim: "config_path_outside_valheim",
+	}
+)
+
 func NewConfigCommand(cfg config.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Prints the current config of the app.",
-		Long:  "Prints out all current configuration values for Warden. These are stored in .warden.yaml in your $HOME directory (by default)",
+		Long:  "Prints out all current configuration values for Warden. These are stored in .warden.yaml in your $HOME directory, or the directory set in WARDEN_CONFIG_DIR.",
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf(configTitle + "\n")
-			fmt.Printf("\nUsing configuration file at: %s\n\n", viper.ConfigFileUsed())
-
-			for _, key := range viper.AllKeys() {
-				value := viper.Get(key)
-				fmt.Printf("%s : %s\n", key, value)
+			v := configView{
+				File:     viper.ConfigFileUsed(),
+				Settings: map[string]any{},
+				keys:     viper.AllKeys(),
+			}
+			sort.Strings(v.keys)
+			for _, key := range v.keys {
+				v.Settings[key] = viper.Get(key)
 			}
-			fmt.Println()
+			writeResult(v)
 		},
 	}
 	cmd.AddCommand(newConfigGetCommand())
-	cmd.AddCommand(newConfigSetCommand())
+	cmd.AddCommand(newConfigSetCommand(cfg))
 	return cmd
 }
 
@@ -35,40 +61,49 @@ func newConfigGetCommand() *cobra.Command {
 		Short: "Print the configuration value.",
 		Long:  "Print the value of the given configuration key.",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			value := viper.Get(args[0])
 
 			if value == nil {
-				fmt.Println("configuration key does not exist")
-			} else {
-				fmt.Println(value)
+				return fail(errConfigKeyNotFound, "configuration key does not exist")
 			}
+			writeResult(configValue{Key: args[0], Value: value})
+			return nil
 		},
 	}
 	return cmd
 }
 
-func newConfigSetCommand() *cobra.Command {
+func newConfigSetCommand(cfg config.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "set",
 		Short: "Updates the config value.",
-		Long:  "Updates the configuration value for the given key. Changes are saved to .warden.yaml.",
+		Long:  "Updates the configuration value for the given key. Changes are saved to .warden.yaml. Paths are checked before they're saved: directories have to be writable, or creatable, the database's directory has to exist, and the mod directory has to be inside the Valheim directory. Every other value is checked the same way Warden checks it when it's used, e.g. the server port has to be a number in range and schedules have to be valid cron expressions, so a bad value is never saved.",
 		Args:  cobra.MinimumNArgs(2),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			key, value := args[0], args[1]
 
 			if !isValidConfigKey(key) {
-				fmt.Printf("'%s' is not a valid config setting\n", key)
-				return
+				return fail(errInvalidConfigKey, "'"+key+"' is not a valid config setting")
+			}
+			if config.IsPathKey(key) {
+				if err := config.ValidatePath(key, value, cfg); err != nil {
+					return fail(err, configPathErrorMessage(err, key))
+				}
+			}
+			parsed, err := parseConfigValue(key, value, cfg)
+			if err != nil {
+				return fail(fmt.Errorf("%w: %w", errInvalidConfigValue, err), "'"+value+"' is not a valid value for '"+key+"': "+err.Error())
 			}
 			// Save updated key in memory
-			viper.Set(key, value)
+			viper.Set(key, parsed)
 
 			// Write change to file
-			err := viper.WriteConfig()
-			if err != nil {
-				fmt.Println("unable to save configuration")
+			if err := viper.WriteConfig(); err != nil {
+				return fail(fmt.Errorf("%w: %w", errConfigWriteFailed, err), "unable to save configuration")
 			}
+			writeMessage("configuration saved")
+			return nil
 		},
 	}
 	return cmd
@@ -76,9 +111,135 @@ func newConfigSetCommand() *cobra.Command {
 
 func isValidConfigKey(key string) bool {
 	switch key {
-	case "valheim-directory":
+	case "valheim-directory", "mod-directory", "save-directory", "backup-directory", "cache-directory", "database-path":
+		return true
+	case "backup-keep-last", "backup-keep-daily", "backup-keep-weekly":
+		return true
+	case "supervise-max-crashes", "supervise-crash-window", "supervise-backoff":
+		return true
+	case "log-max-size", "log-max-files":
+		return true
+	case "server-name", "server-port", "server-world", "server-password", "server-public", "server-crossplay":
+		return true
+	case "server-preset", "server-modifiers":
+		return true
+	case "schedule-backup", "schedule-update", "schedule-restart", "schedule-restart-delay":
+		return true
+	case "verify-timeout":
+		return true
+	case "steamcmd-path", "steam-beta":
+		return true
+	case "nexus-api-key":
 		return true
 	default:
 		return false
 	}
 }
+
+// parseConfigValue converts a value given on the command line to the type its setting is read
+// as, checking it the same way it's checked when it's used. Otherwise a bad value would be saved,
+// and every command after it would fail to read the config file.
+func parseConfigValue(key, value string, cfg config.Config) (any, error) {
+	switch key {
+	case "backup-keep-last", "backup-keep-daily", "backup-keep-weekly", "supervise-max-crashes", "log-max-size", "log-max-files":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, errors.New("must be a whole number, zero or more")
+		}
+		return n, nil
+	case "supervise-crash-window", "supervise-backoff", "verify-timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, errors.New("must be a duration longer than zero, e.g. 5m")
+		}
+		return d.String(), nil
+	case "schedule-restart-delay":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, errors.New("must be a duration, e.g. 5m")
+		}
+		return d.String(), nil
+	case "schedule-backup", "schedule-update", "schedule-restart":
+		// An empty schedule turns the job off
+		if value == "" {
+			return value, nil
+		}
+		if _, err := schedule.Parse(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "server-name", "server-port", "server-world", "server-password", "server-public", "server-crossplay", "server-preset", "server-modifiers":
+		return parseServerSetting(key, value, cfg)
+	default:
+		return value, nil
+	}
+}
+
+// parseServerSetting checks a server setting along with the rest of the configured ones, since
+// some rules depend on others, e.g. a public server needs a password
+func parseServerSetting(key, value string, cfg config.Config) (any, error) {
+	settings := server.Settings{
+		Name:      cfg.ServerName,
+		Port:      cfg.ServerPort,
+		World:     cfg.ServerWorld,
+		Password:  cfg.ServerPassword,
+		Public:    cfg.ServerPublic,
+		Crossplay: cfg.ServerCrossplay,
+		Preset:    cfg.ServerPreset,
+		Modifiers: cfg.ServerModifiers,
+	}
+
+	var parsed any
+	switch key {
+	case "server-name":
+		settings.Name, parsed = value, value
+	case "server-port":
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		settings.Port, parsed = port, port
+	case "server-world":
+		settings.World, parsed = value, value
+	case "server-password":
+		settings.Password, parsed = value, value
+	case "server-public", "server-crossplay":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		if key == "server-public" {
+			settings.Public = b
+		} else {
+			settings.Crossplay = b
+		}
+		parsed = b
+	case "server-preset":
+		settings.Preset, parsed = value, value
+	case "server-modifiers":
+		settings.Modifiers = []string{}
+		for _, m := range strings.Split(value, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				settings.Modifiers = append(settings.Modifiers, m)
+			}
+		}
+		parsed = settings.Modifiers
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+func configPathErrorMessage(err error, key string) string {
+	if errors.Is(err, config.ErrPathOutsideValheim) {
+		return "mod-directory must be inside valheim-directory, so BepInEx loads the mods in it"
+	} else if errors.Is(err, config.ErrPathNotFound) {
+		return "'" + key + "' must be in a directory that exists"
+	} else if errors.Is(err, config.ErrPathNotDirectory) {
+		return "'" + key + "' must be a directory"
+	} else if errors.Is(err, config.ErrPathNotWritable) {
+		return "'" + key + "' must be somewhere Warden can write to"
+	}
+	return err.Error()
+}
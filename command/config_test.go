@@ -0,0 +1,148 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"warden/internal/config"
+	"warden/internal/format"
+
+	"github.com/spf13/viper"
+)
+
+func TestConfigSet_Happy(t *testing.T) {
+	tests := map[string]struct {
+		key      string
+		value    string
+		expected func(cfg *config.Config) bool
+	}{
+		"save a server port as a number": {
+			key:      "server-port",
+			value:    "2457",
+			expected: func(cfg *config.Config) bool { return cfg.ServerPort == 2457 },
+		},
+		"save world modifiers as a list": {
+			key:   "server-modifiers",
+			value: "combat=hard, raids=none",
+			expected: func(cfg *config.Config) bool {
+				return len(cfg.ServerModifiers) == 2 && cfg.ServerModifiers[1] == "raids=none"
+			},
+		},
+		"save a duration": {
+			key:      "supervise-backoff",
+			value:    "10s",
+			expected: func(cfg *config.Config) bool { return cfg.SuperviseBackoff.String() == "10s" },
+		},
+		"save an empty schedule to turn the job off": {
+			key:      "schedule-backup",
+			value:    "",
+			expected: func(cfg *config.Config) bool { return cfg.ScheduleBackup == "" },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, cfg := newTestConfig(t)
+
+			if err := runConfigSet(cfg, test.key, test.value); err != nil {
+				t.Fatalf("expected a nil error, received: %+v", err)
+			}
+			saved, err := config.Read(dir)
+			if err != nil {
+				t.Fatalf("expected the saved config to be readable, received: %+v", err)
+			}
+			if !test.expected(saved) {
+				t.Errorf("expected %s to be saved as %q, received: %+v", test.key, test.value, viper.Get(test.key))
+			}
+		})
+	}
+}
+
+func TestConfigSet_Sad(t *testing.T) {
+	tests := map[string]struct {
+		key   string
+		value string
+	}{
+		"return an error if the server port isn't a number": {
+			key:   "server-port",
+			value: "abc",
+		},
+		"return an error if the server port is out of range": {
+			key:   "server-port",
+			value: "99999",
+		},
+		"return an error if the server password is too short": {
+			key:   "server-password",
+			value: "ab",
+		},
+		"return an error if the world preset is unknown": {
+			key:   "server-preset",
+			value: "nightmare",
+		},
+		"return an error if a world modifier is unknown": {
+			key:   "server-modifiers",
+			value: "combat=impossible",
+		},
+		"return an error if a duration isn't positive": {
+			key:   "supervise-backoff",
+			value: "0s",
+		},
+		"return an error if a backup count isn't a number": {
+			key:   "backup-keep-last",
+			value: "ten",
+		},
+		"return an error if a schedule isn't a cron expression": {
+			key:   "schedule-backup",
+			value: "nonsense",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, cfg := newTestConfig(t)
+			path := filepath.Join(dir, config.WardenConfigFile)
+			before, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error reading test config, received: %+v", err)
+			}
+
+			if err := runConfigSet(cfg, test.key, test.value); !errors.Is(err, errInvalidConfigValue) {
+				t.Errorf("expected error: %+v, received: %+v", errInvalidConfigValue, err)
+			}
+			after, _ := os.ReadFile(path)
+			if !bytes.Equal(before, after) {
+				t.Errorf("expected the config file to be unchanged, received: %s", after)
+			}
+			if _, err := config.Read(dir); err != nil {
+				t.Errorf("expected the config to still be readable, received: %+v", err)
+			}
+		})
+	}
+}
+
+// newTestConfig creates a default config file in a temporary directory, and loads it as the one
+// `warden config set` changes
+func newTestConfig(t *testing.T) (string, config.Config) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	output, _ = format.New(format.Table, io.Discard)
+
+	dir := t.TempDir()
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error creating test config, received: %+v", err)
+	}
+	return dir, *cfg
+}
+
+func runConfigSet(cfg config.Config, key, value string) error {
+	cmd := newConfigSetCommand(cfg)
+	cmd.SetArgs([]string{key, value})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
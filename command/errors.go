@@ -0,0 +1,233 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"warden/internal/api"
+	"warden/internal/api/nexus"
+	"warden/internal/api/source"
+	"warden/internal/api/thunderstore"
+	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/format"
+	"warden/internal/service"
+)
+
+// Exit codes returned by Warden. Scripts rely on these, so existing ones must never change.
+const (
+	exitOK         = 0
+	exitError      = 1 // Anything not covered below, e.g. a database error
+	exitUsage      = 2 // Invalid flags, arguments, server settings, platforms, config paths or instance names, or a confirmation is needed but can't be asked
+	exitNotFound   = 3 // A mod, framework, world backup, log, config key, listed player, instance, or the game server or SteamCMD doesn't exist
+	exitNetwork    = 4 // Thunderstore couldn't be reached, or returned an unexpected error
+	exitConflict   = 5 // The thing being created already exists or is locked, or the server or instance is already running or stopped
+	exitAborted    = 6 // The user declined a confirmation prompt
+	exitFilesystem = 7 // Files or directories couldn't be read or written
+)
+
+// exitCodes maps errors to their exit codes. Order matters, since a wrapped error can match more
+// than one, e.g. a mod that can't be found because Thunderstore is down is a network error.
+var exitCodes = []struct {
+	code int
+	errs []error
+}{
+	{exitAborted, []error{service.ErrAborted, service.ErrMaxAttempts}},
+	{exitUsage, []error{service.ErrConfirmationRequired, service.ErrInvalidGameType, service.ErrInvalidServerSettings, service.ErrUnsupportedPlatform, service.ErrInvalidUnitScope, service.ErrInvalidSchedule, service.ErrNoJobsScheduled, service.ErrInvalidLogSource, service.ErrInvalidPlayerList, service.ErrInvalidPlayerID, service.ErrInvalidInstanceName, service.ErrInvalidProfileName, service.ErrInvalidProfileExport, service.ErrInvalidModpack, service.ErrInvalidMirrorManifest, source.ErrUnknownSource, service.ErrInvalidNamespace, file.ErrInvalidManifest, nexus.ErrMissingAPIKey, nexus.ErrInvalidAPIKey, nexus.ErrInvalidModID, errInvalidConfigKey, errInvalidConfigValue, config.ErrPathOutsideValheim, config.ErrPathNotDirectory}},
+	{exitConflict, []error{
+		service.ErrModAlreadyInstalled,
+		service.ErrModInModpack,
+		file.ErrSnapshotAlreadyExists,
+		service.ErrServerAlreadyRunning,
+		service.ErrServerNotRunning,
+		service.ErrServerSupervised,
+		service.ErrServerAlreadyInstalled,
+		service.ErrPlayerAlreadyListed,
+		file.ErrPlayerListLocked,
+		service.ErrInstanceAlreadyExists,
+		service.ErrInstanceInUse,
+		service.ErrInstanceRunning,
+		service.ErrValheimDirectoryInUse,
+		service.ErrProfileAlreadyExists,
+		service.ErrProfileAlreadyActive,
+	}},
+	{exitNetwork, []error{api.ErrHTTPClient, api.ErrByteIO, thunderstore.ErrThunderstoreAPI, nexus.ErrNexusAPI}},
+	{exitNotFound, []error{
+		service.ErrModNotFound,
+		service.ErrModNotInstalled,
+		service.ErrFrameworkNotFound,
+		service.ErrFrameworkNotInstalled,
+		service.ErrWorldBackupNotFound,
+		service.ErrUnitNotInstalled,
+		service.ErrJobNotFound,
+		service.ErrServerNotInstalled,
+		service.ErrSteamCMDNotFound,
+		file.ErrLogNotFound,
+		service.ErrPlayerNotListed,
+		service.ErrInstanceNotFound,
+		service.ErrProfileNotFound,
+		service.ErrProfileExportNotFound,
+		service.ErrModpackFileNotFound,
+		service.ErrMirrorManifestNotFound,
+		thunderstore.ErrPackageNotFound,
+		nexus.ErrModNotFound,
+		source.ErrReleaseNotFound,
+		errConfigKeyNotFound,
+		config.ErrPathNotFound,
+	}},
+	{exitFilesystem, []error{errConfigWriteFailed, config.ErrPathNotWritable, fs.ErrPermission}},
+}
+
+// fileErrors are the file layer's errors for files and directories that couldn't be read or
+// written. They're only checked once network errors have been, since a download that fails
+// partway is reported as a file that couldn't be written.
+var fileErrors = []error{
+	file.ErrFileOpenFailed,
+	file.ErrFileCreateFailed,
+	file.ErrFileWriteFailed,
+	file.ErrFileRenameFailed,
+	file.ErrFileCopyFailed,
+	file.ErrDirectoryCreateFailed,
+	file.ErrDirectoryOpenFailed,
+	file.ErrBackupCreateFailed,
+	file.ErrBackupDeleteFailed,
+	file.ErrBackupRestoreFailed,
+	file.ErrZipDeleteFailed,
+	file.ErrModDeleteFailed,
+	file.ErrDeleteAllModsFailed,
+	file.ErrLogOpenFailed,
+	file.ErrLogRotateFailed,
+	file.ErrLogReadFailed,
+	file.ErrPIDFileReadFailed,
+	file.ErrPIDFileWriteFailed,
+	file.ErrPIDFileDeleteFailed,
+	file.ErrPlayerListReadFailed,
+	file.ErrPlayerListWriteFailed,
+	file.ErrProfileReadFailed,
+	file.ErrProfileWriteFailed,
+	file.ErrProfileDeleteFailed,
+	file.ErrMirrorReadFailed,
+	file.ErrMirrorWriteFailed,
+}
+
+// commandError is an error returned by a command, along with a message explaining it to the user.
+// The underlying error is kept so its code and cause can still be reported.
+type commandError struct {
+	err     error
+	message string
+}
+
+// fail wraps an error returned to cobra by a command
+func fail(err error, message string) error {
+	return &commandError{err: err, message: message}
+}
+
+func (e *commandError) Error() string {
+	return e.message
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// exitCode picks the process exit code for an error returned by cobra. Anything that isn't a
+// commandError came from cobra itself, which means the flags or arguments were invalid.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var ce *commandError
+	if !errors.As(err, &ce) {
+		return exitUsage
+	}
+
+	for _, c := range exitCodes {
+		for _, target := range c.errs {
+			if errors.Is(err, target) {
+				return c.code
+			}
+		}
+	}
+
+	// Filesystem errors are checked first, since the syscall errors they wrap also satisfy net.Error
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	if errors.As(err, &pathErr) || errors.As(err, &linkErr) {
+		return exitFilesystem
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return exitNetwork
+	}
+	for _, target := range fileErrors {
+		if errors.Is(err, target) {
+			return exitFilesystem
+		}
+	}
+	return exitError
+}
+
+// errorCode returns the machine-readable code for an error. Config errors come from the command
+// itself rather than a service, so they have their own codes.
+func errorCode(err error) string {
+	for target, code := range configErrorCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	return service.Code(err)
+}
+
+// reportError writes a failed command's error in the chosen output format. If output was never
+// set up, e.g. because the --output flag itself was invalid, it's printed to stderr instead.
+func reportError(err error, code int) {
+	message := err.Error()
+	cause := err
+
+	var ce *commandError
+	if errors.As(err, &ce) {
+		cause = ce.err
+	}
+
+	if output == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", message)
+	} else {
+		writeResult(format.Error{
+			Code:     errorCode(cause),
+			Message:  message,
+			Detail:   cause.Error(),
+			ExitCode: code,
+		})
+	}
+
+	if verbose {
+		writeErrorChain(os.Stderr, err)
+	}
+}
+
+// writeErrorChain prints every error wrapped inside err, one per line and indented by depth, so
+// the root cause of a failure can be found.
+func writeErrorChain(w io.Writer, err error) {
+	fmt.Fprintln(w, "error chain:")
+	writeErrorLink(w, err, 1)
+}
+
+func writeErrorLink(w io.Writer, err error, depth int) {
+	fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth), err.Error())
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			writeErrorLink(w, next, depth+1)
+		}
+	case interface{ Unwrap() []error }:
+		for _, next := range e.Unwrap() {
+			writeErrorLink(w, next, depth+1)
+		}
+	}
+}
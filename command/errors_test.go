@@ -0,0 +1,72 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"testing"
+	"warden/internal/api"
+	"warden/internal/data/file"
+	"warden/internal/service"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected int
+	}{
+		"return ok if there's no error": {
+			err:      nil,
+			expected: exitOK,
+		},
+		"return usage for errors from cobra itself": {
+			err:      errors.New("unknown flag: --missing"),
+			expected: exitUsage,
+		},
+		"return the code of a mapped error": {
+			err:      fail(fmt.Errorf("%w: sleepover", service.ErrModNotFound), "mod not found"),
+			expected: exitNotFound,
+		},
+		"return the first code that matches a wrapped error": {
+			err:      fail(fmt.Errorf("%w: %w", service.ErrModNotFound, api.ErrHTTPClient), "mod not found"),
+			expected: exitNetwork,
+		},
+		"return usage for an unsupported platform": {
+			err:      fail(fmt.Errorf("%w: %w", service.ErrServerStartFailed, service.ErrUnsupportedPlatform), "unsupported platform"),
+			expected: exitUsage,
+		},
+		"return aborted if the user declined": {
+			err:      fail(service.ErrAborted, "aborted"),
+			expected: exitAborted,
+		},
+		"return filesystem for file layer errors": {
+			err:      fail(fmt.Errorf("%w: %w", file.ErrFileWriteFailed, errors.New("disk full")), "unable to write"),
+			expected: exitFilesystem,
+		},
+		"return network for downloads that fail partway": {
+			err:      fail(fmt.Errorf("%w: %w", file.ErrFileWriteFailed, &net.OpError{Op: "read", Err: errors.New("connection reset")}), "unable to download"),
+			expected: exitNetwork,
+		},
+		"return filesystem for path errors": {
+			err:      fail(&fs.PathError{Op: "open", Path: "/missing", Err: fs.ErrNotExist}, "unable to open"),
+			expected: exitFilesystem,
+		},
+		"return network for errors reaching a host": {
+			err:      fail(&net.OpError{Op: "dial", Err: errors.New("connection refused")}, "unable to connect"),
+			expected: exitNetwork,
+		},
+		"return error for anything else": {
+			err:      fail(errors.New("database is locked"), "unable to list mods"),
+			expected: exitError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if code := exitCode(test.err); code != test.expected {
+				t.Errorf("expected exit code: %d, received: %d", test.expected, code)
+			}
+		})
+	}
+}
@@ -5,7 +5,97 @@ const (
 	namespaceFlagShort = "n"
 	namespaceFlagDesc  = "The namespace, AKA author, of the mod package (required)."
 
+	addNamespaceFlagDesc = "The namespace, AKA author, of the mod package (required for Thunderstore). Mods added from a file or URL default to Local."
+
+	sourceFlagLong = "source"
+	sourceFlagDesc = "Where to find the mod: thunderstore or nexus."
+
 	modPackageFlagLong  = "mod"
 	modPackageFlagShort = "m"
 	modPackageFlagDesc  = "The name of the mod, AKA package, to add (required)."
+
+	addModPackageFlagDesc = "The name of the mod, AKA package, to add."
+
+	fileFlagLong = "file"
+	fileFlagDesc = "A mod package's zip file to add, instead of finding the mod online."
+
+	urlFlagLong = "url"
+	urlFlagDesc = "A URL to download a mod package's zip file from, instead of finding the mod online."
+
+	dryRunFlagLong = "dry-run"
+	dryRunFlagDesc = "Print everything the command would change, without changing anything."
+
+	outputFlagLong  = "output"
+	outputFlagShort = "o"
+	outputFlagDesc  = "The output format: table, json or yaml."
+
+	yesFlagLong  = "yes"
+	yesFlagShort = "y"
+	yesFlagDesc  = "Automatically answer yes to every confirmation prompt."
+
+	assumeNoFlagLong = "assume-no"
+	assumeNoFlagDesc = "Automatically answer no to every confirmation prompt."
+
+	scopeFlagLong = "scope"
+	scopeFlagDesc = "Where the systemd unit is installed: user or system."
+
+	limitFlagLong = "limit"
+	limitFlagDesc = "The most entries to list, or 0 to list all of them."
+
+	verifyStartFlagLong = "verify-start"
+	verifyStartFlagDesc = "Start the modded server after updating, and roll the update back if a plugin fails to load or the world doesn't load."
+
+	followFlagLong  = "follow"
+	followFlagShort = "f"
+	followFlagDesc  = "Keep printing new lines as they're logged, until Ctrl+C is pressed."
+
+	levelFlagLong = "level"
+	levelFlagDesc = "The least severe lines to print: debug, info, message, warning, error or fatal."
+
+	linesFlagLong  = "lines"
+	linesFlagShort = "n"
+	linesFlagDesc  = "How many lines to print from the end of the log."
+
+	problemsFlagLong = "problems"
+	problemsFlagDesc = "List every problem in the log, along with how often it was logged."
+
+	restartFlagLong = "restart"
+	restartFlagDesc = "Restart the server if it's running, so it reads the changed list straight away."
+
+	verboseFlagLong  = "verbose"
+	verboseFlagShort = "v"
+	verboseFlagDesc  = "Print the full chain of errors when a command fails."
+
+	instanceFlagLong = "instance"
+	instanceFlagDesc = "The server instance to manage. Defaults to WARDEN_INSTANCE, or the default instance."
+
+	valheimDirectoryFlagLong = "valheim-directory"
+	valheimDirectoryFlagDesc = "The directory the instance's Valheim server is installed in."
+
+	profileNameFlagLong       = "name"
+	importProfileNameFlagDesc = "The name to save the imported profile as. Defaults to the r2modman profile's name."
+	exportProfileNameFlagDesc = "The profile name players see in r2modman. Defaults to the active profile's name."
+
+	modpackNameFlagLong        = "name"
+	modpackNameFlagDesc        = "The modpack's package name on Thunderstore: letters, numbers or _ (required)."
+	modpackVersionFlagLong     = "version"
+	modpackVersionFlagDesc     = "The modpack's version, as major.minor.patch."
+	modpackDescriptionFlagLong = "description"
+	modpackDescriptionFlagDesc = "A short description of the modpack, at most 250 characters."
+	modpackWebsiteFlagLong     = "website-url"
+	modpackWebsiteFlagDesc     = "A link to the server's website or Discord."
+	modpackIconFlagLong        = "icon"
+	modpackIconFlagDesc        = "A 256x256 PNG icon. A plain one is generated when left out."
+	modpackReadmeFlagLong      = "readme"
+	modpackReadmeFlagDesc      = "A markdown README. One listing every mod is generated when left out."
+	modpackFileFlagLong        = "file"
+	modpackFileFlagDesc        = "Where to write the package. Defaults to <name>-<version>.zip in the current directory."
+
+	mirrorAddressFlagLong = "address"
+	mirrorAddressFlagDesc = "The address to listen on, as host:port."
+	mirrorURLFlagLong     = "url"
+	mirrorURLFlagDesc     = "The URL other hosts reach the mirror at, which download links point to. Defaults to the host each request was sent to."
+
+	// Set to run without any prompts, e.g. from cron or CI
+	nonInteractiveEnv = "WARDEN_NONINTERACTIVE"
 )
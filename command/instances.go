@@ -0,0 +1,127 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/config"
+	"warden/internal/domain/instance"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewInstancesCommand(is service.Instances, current string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instances",
+		Short: "Manages the Valheim server instances Warden looks after.",
+		Long:  "Every instance is a separate Valheim server, with its own Valheim directory, config file, database and world backups. Pick the instance a command manages with --instance or WARDEN_INSTANCE, or leave both out to manage the default instance.",
+	}
+	cmd.AddCommand(newInstancesListCommand(is, current))
+	cmd.AddCommand(newInstancesAddCommand(is))
+	cmd.AddCommand(newInstancesCloneCommand(is))
+	cmd.AddCommand(newInstancesRemoveCommand(is))
+	return cmd
+}
+
+func newInstancesListCommand(is service.Instances, current string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists every instance.",
+		Long:  "Lists the default instance and every named one. The instance being managed is marked with *.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instances, err := is.ListInstances()
+			if err != nil {
+				return fail(err, instancesErrorMessage(err))
+			}
+			writeResult(newInstanceList(instances, current))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newInstancesAddCommand(is service.Instances) *cobra.Command {
+	var valheimDirectory string
+
+	cmd := &cobra.Command{
+		Use:   "add [name]",
+		Short: "Adds a new instance.",
+		Long:  "Adds an instance with the default settings, for the Valheim server in the given directory. It gets its own save directory, so it never loads another instance's world, and the first server port no other instance uses. Change its settings with 'warden --instance [name] config set'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			i, err := is.AddInstance(args[0], valheimDirectory)
+			if err != nil {
+				return fail(err, instancesErrorMessage(err))
+			}
+			writeResult(newInstanceList([]instance.Instance{i}, ""))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&valheimDirectory, valheimDirectoryFlagLong, "", valheimDirectoryFlagDesc+" (required)")
+	cmd.MarkFlagRequired(valheimDirectoryFlagLong)
+	return cmd
+}
+
+func newInstancesCloneCommand(is service.Instances) *cobra.Command {
+	var valheimDirectory string
+
+	cmd := &cobra.Command{
+		Use:   "clone [source] [name]",
+		Short: "Adds a new instance with a copy of another one's settings.",
+		Long:  "Adds an instance with a copy of the source instance's settings, e.g. to set up a test world like the main one. The new instance starts out with its own empty database, backups and save directory, and its own server port. It needs its own Valheim directory, since instances sharing one would share their mods.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			i, err := is.CloneInstance(args[0], args[1], valheimDirectory)
+			if err != nil {
+				return fail(err, instancesErrorMessage(err))
+			}
+			writeResult(newInstanceList([]instance.Instance{i}, ""))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&valheimDirectory, valheimDirectoryFlagLong, "", valheimDirectoryFlagDesc+" (required)")
+	cmd.MarkFlagRequired(valheimDirectoryFlagLong)
+	return cmd
+}
+
+func newInstancesRemoveCommand(is service.Instances) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [name]",
+		Short: "Removes an instance.",
+		Long:  "Deletes a named instance's directory, along with its config file, database and world backups. The Valheim server it points at is left alone. The instance's server has to be stopped first, and the default instance can't be removed.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := is.RemoveInstance(args[0]); err != nil {
+				return fail(err, instancesErrorMessage(err))
+			}
+			writeMessage("instance removed")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func instancesErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidInstanceName) {
+		return "instance names must be lowercase letters, numbers, - or _, and can't be 'default'"
+	} else if errors.Is(err, service.ErrInstanceNotFound) {
+		return "instance does not exist"
+	} else if errors.Is(err, service.ErrInstanceAlreadyExists) {
+		return "instance already exists"
+	} else if errors.Is(err, service.ErrInstanceInUse) {
+		return "can't remove the instance being managed, pick another one with --instance"
+	} else if errors.Is(err, service.ErrInstanceRunning) {
+		return "instance's server is running, stop it first"
+	} else if errors.Is(err, service.ErrValheimDirectoryInUse) {
+		return "another instance already uses that Valheim directory, each instance needs its own"
+	} else if errors.Is(err, service.ErrUnableToListInstances) {
+		return "unable to list instances"
+	} else if errors.Is(err, service.ErrUnableToCreateInstance) {
+		return "unable to create instance"
+	} else if errors.Is(err, service.ErrUnableToRemoveInstance) {
+		return "unable to remove instance"
+	} else if errors.Is(err, config.ErrPathNotDirectory) || errors.Is(err, config.ErrPathNotFound) || errors.Is(err, config.ErrPathNotWritable) {
+		return configPathErrorMessage(err, valheimDirectoryFlagLong)
+	}
+	return err.Error()
+}
@@ -1,8 +1,6 @@
 package command
 
 import (
-	"fmt"
-	"warden/internal/domain/mod"
 	"warden/internal/service"
 
 	"github.com/spf13/cobra"
@@ -12,22 +10,17 @@ func NewListCommand(ms service.Mod) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all currently installed mods and their versions",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long:  "List all currently installed mods and their versions, along with when each was last updated on Thunderstore and its categories. Mods that are deprecated, or that haven't been updated since the installed Valheim build, are flagged with a warning.",
+		RunE: func(cmd *cobra.Command, args []string) error {
 			mods, err := ms.ListMods()
 			if err != nil {
-				fmt.Println("... unable to retrieve list of mods ...")
+				return fail(err, "unable to retrieve list of mods")
 			}
-			prettyPrint(mods)
+			// Servers that weren't installed through Steam have no build to check mods against
+			build, _ := ms.GameBuild()
+			writeResult(newModList(mods, build))
+			return nil
 		},
 	}
 	return cmd
 }
-
-func prettyPrint(mods []mod.Mod) {
-	if len(mods) == 0 {
-		fmt.Print("... no mods are installed...")
-	}
-	for _, m := range mods {
-		fmt.Printf(" %s | %s | %s \n", m.Name, m.Version, m.Description)
-	}
-}
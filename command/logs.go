@@ -0,0 +1,85 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"warden/internal/data/file"
+	"warden/internal/domain/logs"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewLogsCommand(logService service.Logs) *cobra.Command {
+	var (
+		follow   bool
+		level    string
+		lines    int
+		problems bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs [server|bepinex]",
+		Short: "Shows the game server or BepInEx log.",
+		Long:  "Shows the end of the game server's log, or the log BepInEx writes into the Valheim directory. Plugins that fail to load, missing dependencies, and exceptions are flagged, along with the installed mod they come from when it can be worked out. Pass --problems to list every problem in the log instead.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if len(args) == 0 || logService.IsValidSource(args[0]) {
+				return nil
+			}
+			return service.ErrInvalidLogSource
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := logs.Server
+			if len(args) > 0 {
+				source = args[0]
+			}
+
+			if problems {
+				found, err := logService.Problems(source)
+				if err != nil {
+					return fail(err, logsErrorMessage(err, source))
+				}
+				writeResult(problemList(found))
+				return nil
+			}
+
+			minLevel, err := logs.ParseLevel(level)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			err = logService.Tail(ctx, source, lines, follow, minLevel, func(l logs.Line) {
+				writeResult(lineView{l})
+			})
+			if err != nil {
+				return fail(err, logsErrorMessage(err, source))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, followFlagLong, followFlagShort, false, followFlagDesc)
+	cmd.Flags().StringVar(&level, levelFlagLong, logs.Info.String(), levelFlagDesc)
+	cmd.Flags().IntVarP(&lines, linesFlagLong, linesFlagShort, 50, linesFlagDesc)
+	cmd.Flags().BoolVar(&problems, problemsFlagLong, false, problemsFlagDesc)
+	return cmd
+}
+
+func logsErrorMessage(err error, source string) string {
+	if errors.Is(err, file.ErrLogNotFound) {
+		if source == logs.BepInEx {
+			return "BepInEx log does not exist, start the modded server first"
+		}
+		return "server log does not exist, start the server first"
+	} else if errors.Is(err, service.ErrUnableToReadLog) {
+		return "unable to read " + source + " log"
+	}
+	return err.Error()
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Requests only carry a path, so they should arrive quickly
+	mirrorReadTimeout = 30 * time.Second
+
+	// Archives can be large and hosts on slow links still need to finish downloading them
+	mirrorWriteTimeout = 10 * time.Minute
+	mirrorIdleTimeout  = 2 * time.Minute
+
+	// How long downloads that are under way get to finish once the mirror is stopped
+	mirrorShutdownTimeout = 30 * time.Second
+)
+
+func NewMirrorCommand(mrs service.Mirror) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Mirrors Thunderstore packages for hosts that can't reach Thunderstore.",
+		Long:  "A mirror is a Warden host that serves the packages in its cache over HTTP, in the same shape as Thunderstore's API. Other hosts add it to their thunderstore-registries to install mods from it.",
+	}
+	cmd.AddCommand(newMirrorSyncCommand(mrs))
+	cmd.AddCommand(newMirrorServeCommand(mrs))
+	return cmd
+}
+
+func newMirrorSyncCommand(mrs service.Mirror) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync <manifest>",
+		Short: "Downloads the packages a manifest depends on into the mirror.",
+		Long:  "Downloads every package a Thunderstore manifest.json depends on, at the exact versions it lists, along with everything those depend on, e.g. BepInEx. Packages that were mirrored before are kept, so a mirror can serve more than one manifest.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			synced, err := mrs.SyncMirror(args[0])
+			if err != nil {
+				message := mirrorErrorMessage(err)
+				// Packages mirrored before the failure are kept, so it's worth knowing which they are
+				if len(synced) > 0 {
+					message += fmt.Sprintf(", these were mirrored before it stopped: %s", strings.Join(synced, ", "))
+				}
+				return fail(err, message)
+			}
+			writeResult(mirrorList(synced))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newMirrorServeCommand(mrs service.Mirror) *cobra.Command {
+	var address string
+	var url string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serves the mirrored packages over HTTP.",
+		Long:  "Serves the mirrored packages over HTTP until it's stopped with Ctrl+C or SIGTERM, in the same shape as Thunderstore's API. Downloads that are under way get a short while to finish. Only packages added with `mirror sync` are served.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// Listening first means the message is only written once the mirror can be reached
+			l, err := net.Listen("tcp", address)
+			if err != nil {
+				return fail(err, "unable to serve mirror")
+			}
+			srv := &http.Server{
+				Handler:           mrs.MirrorHandler(url),
+				ReadHeaderTimeout: mirrorReadTimeout,
+				ReadTimeout:       mirrorReadTimeout,
+				WriteTimeout:      mirrorWriteTimeout,
+				IdleTimeout:       mirrorIdleTimeout,
+			}
+			errs := make(chan error, 1)
+			go func() {
+				errs <- srv.Serve(l)
+			}()
+			writeMessage("serving mirror on " + address)
+
+			select {
+			case err := <-errs:
+				return fail(err, "unable to serve mirror")
+			case <-ctx.Done():
+			}
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), mirrorShutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				return fail(err, "unable to stop mirror cleanly")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&address, mirrorAddressFlagLong, ":8080", mirrorAddressFlagDesc)
+	cmd.Flags().StringVar(&url, mirrorURLFlagLong, "", mirrorURLFlagDesc)
+	return cmd
+}
+
+func mirrorErrorMessage(err error) string {
+	if errors.Is(err, service.ErrMirrorManifestNotFound) {
+		return "manifest does not exist"
+	} else if errors.Is(err, service.ErrInvalidMirrorManifest) {
+		return "manifest isn't a valid Thunderstore manifest.json"
+	} else if errors.Is(err, service.ErrUnableToSyncMirror) {
+		return "unable to mirror every package"
+	}
+	return err.Error()
+}
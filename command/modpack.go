@@ -0,0 +1,78 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/domain/modpack"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewModpackCommand(mps service.Modpacks) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "modpack",
+		Short: "Distributes the server's mods as a Thunderstore modpack.",
+		Long:  "A modpack is a Thunderstore package that depends on every mod the server runs, at the exact versions it runs them, so players can install the same mods in one go.",
+	}
+	cmd.AddCommand(newModpackBuildCommand(mps))
+	return cmd
+}
+
+func newModpackBuildCommand(mps service.Modpacks) *cobra.Command {
+	var o modpack.Options
+
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Builds a modpack that's ready to upload to Thunderstore.",
+		Long:  "Builds a zip with a manifest.json depending on every installed mod, including BepInEx, along with an icon and README. The manifest, icon and README are checked against Thunderstore's rules, so the zip can be uploaded as is.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, path, err := mps.BuildModpack(o)
+			if err != nil {
+				return fail(err, modpackErrorMessage(err))
+			}
+			writeResult(modpackView{Manifest: m, Path: path})
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&o.Name, modpackNameFlagLong, "", modpackNameFlagDesc)
+	cmd.Flags().StringVar(&o.Version, modpackVersionFlagLong, "1.0.0", modpackVersionFlagDesc)
+	cmd.Flags().StringVar(&o.Description, modpackDescriptionFlagLong, "", modpackDescriptionFlagDesc)
+	cmd.Flags().StringVar(&o.WebsiteURL, modpackWebsiteFlagLong, "", modpackWebsiteFlagDesc)
+	cmd.Flags().StringVar(&o.Icon, modpackIconFlagLong, "", modpackIconFlagDesc)
+	cmd.Flags().StringVar(&o.Readme, modpackReadmeFlagLong, "", modpackReadmeFlagDesc)
+	cmd.Flags().StringVar(&o.Path, modpackFileFlagLong, "", modpackFileFlagDesc)
+	cmd.MarkFlagRequired(modpackNameFlagLong)
+	return cmd
+}
+
+// modpackRules are the Thunderstore rules a modpack can break
+var modpackRules = []error{
+	modpack.ErrInvalidName,
+	modpack.ErrInvalidVersion,
+	modpack.ErrInvalidDescription,
+	modpack.ErrInvalidWebsiteURL,
+	modpack.ErrInvalidDependency,
+	modpack.ErrDuplicateDependency,
+	modpack.ErrSelfDependency,
+	modpack.ErrNoDependencies,
+	modpack.ErrInvalidIcon,
+	modpack.ErrInvalidReadme,
+}
+
+func modpackErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidModpack) {
+		// Say which of Thunderstore's rules the modpack breaks
+		for _, rule := range modpackRules {
+			if errors.Is(err, rule) {
+				return rule.Error()
+			}
+		}
+		return "invalid modpack"
+	} else if errors.Is(err, service.ErrModpackFileNotFound) {
+		return "modpack icon or README does not exist"
+	} else if errors.Is(err, service.ErrUnableToBuildModpack) {
+		return "unable to build modpack"
+	}
+	return err.Error()
+}
@@ -0,0 +1,507 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"warden/internal/domain/game"
+	"warden/internal/domain/instance"
+	"warden/internal/domain/logs"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/modpack"
+	"warden/internal/domain/plan"
+	"warden/internal/domain/profile"
+	"warden/internal/domain/schedule"
+	"warden/internal/domain/server"
+	"warden/internal/domain/systemd"
+	"warden/internal/domain/world"
+	"warden/internal/format"
+	"warden/internal/service"
+)
+
+// output renders every command result. It's set up from the --output flag before any command runs.
+var output format.Formatter
+
+// progress is where commands print what they're doing, separate from their results
+var progress io.Writer = os.Stdout
+
+// setUpOutput creates the formatter for the given format. For structured formats, progress and
+// prompts from commands and services go to stderr, so stdout only holds results for scripts to
+// parse.
+func setUpOutput(name string) error {
+	f, err := format.New(name, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+	output = f
+
+	progress = os.Stdout
+	if f.Format() != format.Table {
+		progress = os.Stderr
+	}
+	service.SetProgressOutput(progress)
+	return nil
+}
+
+func writeResult(v any) {
+	if err := output.Write(v); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+}
+
+func writeMessage(message string) {
+	writeResult(format.Message{Status: "ok", Message: message})
+}
+
+// modView is an installed mod, along with every reason it might not work with the installed game
+type modView struct {
+	mod.Mod  `yaml:",inline"`
+	Warnings []string `json:"warnings" yaml:"warnings"`
+}
+
+type modList []modView
+
+func newModList(mods []mod.Mod, build game.Build) modList {
+	l := modList{}
+	for _, m := range mods {
+		l = append(l, modView{Mod: m, Warnings: m.Warnings(build)})
+	}
+	return l
+}
+
+func (l modList) Header() []string {
+	return []string{"name", "version", "modpack", "updated", "categories", "warnings", "description"}
+}
+
+// Mods installed by a modpack list it in the modpack column
+func (l modList) Rows() [][]string {
+	rows := [][]string{}
+	for _, m := range l {
+		updated := ""
+		if !m.UpdatedAt.IsZero() {
+			updated = m.UpdatedAt.Format(time.DateOnly)
+		}
+		rows = append(rows, []string{
+			m.Name,
+			m.Version,
+			m.Parent,
+			updated,
+			strings.Join(m.Categories, ", "),
+			strings.Join(m.Warnings, ", "),
+			m.Description,
+		})
+	}
+	return rows
+}
+
+type snapshotList []world.Snapshot
+
+func (l snapshotList) Header() []string {
+	return []string{"id", "world", "created"}
+}
+
+func (l snapshotList) Rows() [][]string {
+	rows := [][]string{}
+	for _, s := range l {
+		rows = append(rows, []string{s.ID(), s.World, s.CreatedAt.Format(time.DateTime)})
+	}
+	return rows
+}
+
+type jobList []schedule.Job
+
+func (l jobList) Header() []string {
+	return []string{"job", "schedule", "next run"}
+}
+
+func (l jobList) Rows() [][]string {
+	rows := [][]string{}
+	for _, j := range l {
+		next := "never"
+		if !j.Next.IsZero() {
+			next = j.Next.Format(time.DateTime)
+		}
+		rows = append(rows, []string{j.Name, j.Expression.String(), next})
+	}
+	return rows
+}
+
+type runList []schedule.Run
+
+func (l runList) Header() []string {
+	return []string{"id", "job", "started", "duration", "status", "message"}
+}
+
+func (l runList) Rows() [][]string {
+	rows := [][]string{}
+	for _, r := range l {
+		rows = append(rows, []string{
+			strconv.Itoa(r.ID),
+			r.Job,
+			r.StartedAt.Local().Format(time.DateTime),
+			r.Duration().String(),
+			r.Status,
+			r.Message,
+		})
+	}
+	return rows
+}
+
+// runView is a single job run that just finished
+type runView struct {
+	schedule.Run `yaml:",inline"`
+}
+
+func newRunView(r schedule.Run) runView {
+	return runView{r}
+}
+
+func (v runView) WriteText(w io.Writer) error {
+	if v.Message != "" {
+		_, err := fmt.Fprintf(w, "... %s job %s after %s: %s ...\n", v.Job, v.Status, v.Duration(), v.Message)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "... %s job %s after %s ...\n", v.Job, v.Status, v.Duration())
+	return err
+}
+
+// lineView is a single log line. Problems are called out after the line, along with the mod they
+// come from.
+type lineView struct {
+	logs.Line `yaml:",inline"`
+}
+
+func (v lineView) WriteText(w io.Writer) error {
+	if v.Problem == nil || v.Problem.Mod == "" {
+		_, err := fmt.Fprintln(w, v.Text)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s (mod: %s)\n", v.Text, v.Problem.Mod)
+	return err
+}
+
+type problemList []logs.Problem
+
+func (l problemList) Header() []string {
+	return []string{"kind", "plugin", "mod", "count", "detail"}
+}
+
+func (l problemList) Rows() [][]string {
+	rows := [][]string{}
+	for _, p := range l {
+		detail := p.Detail
+		if p.Dependency != "" {
+			detail = "missing " + p.Dependency
+		} else if p.Origin != "" {
+			detail = p.Detail + " at " + p.Origin
+		}
+		rows = append(rows, []string{p.Kind, p.Plugin, p.Mod, strconv.Itoa(p.Count), detail})
+	}
+	return rows
+}
+
+// instanceView is a server instance, and whether it's the one commands are managing
+type instanceView struct {
+	instance.Instance `yaml:",inline"`
+	Current           bool `json:"current" yaml:"current"`
+}
+
+type instanceList []instanceView
+
+func newInstanceList(instances []instance.Instance, current string) instanceList {
+	l := instanceList{}
+	for _, i := range instances {
+		l = append(l, instanceView{
+			Instance: i,
+			Current:  i.Name == current || (instance.IsDefault(i.Name) && instance.IsDefault(current)),
+		})
+	}
+	return l
+}
+
+func (l instanceList) Header() []string {
+	return []string{"", "name", "port", "world", "valheim directory", "config directory"}
+}
+
+func (l instanceList) Rows() [][]string {
+	rows := [][]string{}
+	for _, i := range l {
+		current := ""
+		if i.Current {
+			current = "*"
+		}
+		rows = append(rows, []string{current, i.Name, strconv.Itoa(i.ServerPort), i.ServerWorld, i.ValheimDirectory, i.ConfigDirectory})
+	}
+	return rows
+}
+
+// profileView is a mod profile, and whether it's the one that was switched to last
+type profileView struct {
+	profile.Profile `yaml:",inline"`
+	Active          bool `json:"active" yaml:"active"`
+}
+
+type profileList []profileView
+
+func newProfileList(profiles []profile.Profile, active string) profileList {
+	l := profileList{}
+	for _, p := range profiles {
+		l = append(l, profileView{Profile: p, Active: p.Name == active})
+	}
+	return l
+}
+
+func (l profileList) Header() []string {
+	return []string{"", "name", "mods", "config files", "created"}
+}
+
+func (l profileList) Rows() [][]string {
+	rows := [][]string{}
+	for _, p := range l {
+		active := ""
+		if p.Active {
+			active = "*"
+		}
+		rows = append(rows, []string{active, p.Name, strconv.Itoa(len(p.Mods)), strconv.Itoa(len(p.ConfigFiles)), p.CreatedAt.Format(time.DateTime)})
+	}
+	return rows
+}
+
+// exportView is the list of mods written to an r2modman profile
+type exportView profile.Export
+
+func (v exportView) Header() []string {
+	return []string{"mod", "version", "enabled"}
+}
+
+func (v exportView) Rows() [][]string {
+	rows := [][]string{}
+	for _, m := range v.Mods {
+		rows = append(rows, []string{m.Name, m.Version.String(), strconv.FormatBool(m.Enabled)})
+	}
+	return rows
+}
+
+// modpackView is a built modpack, along with where it was written
+type modpackView struct {
+	modpack.Manifest `yaml:",inline"`
+	Path             string `json:"path" yaml:"path"`
+}
+
+func (v modpackView) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "name         : %s\n", v.Name)
+	fmt.Fprintf(w, "version      : %s\n", v.VersionNumber)
+	fmt.Fprintf(w, "dependencies : %d\n", len(v.Dependencies))
+	_, err := fmt.Fprintf(w, "path         : %s\n", v.Path)
+	return err
+}
+
+type playerList []string
+
+func (l playerList) Header() []string {
+	return []string{"player id"}
+}
+
+func (l playerList) Rows() [][]string {
+	rows := [][]string{}
+	for _, id := range l {
+		rows = append(rows, []string{id})
+	}
+	return rows
+}
+
+// mirrorList is the full name of every release that was mirrored
+type mirrorList []string
+
+func (l mirrorList) Header() []string {
+	return []string{"release"}
+}
+
+func (l mirrorList) Rows() [][]string {
+	rows := [][]string{}
+	for _, name := range l {
+		rows = append(rows, []string{name})
+	}
+	return rows
+}
+
+// planView is a dry-run plan, along with its total download size
+type planView struct {
+	Steps        []plan.Step `json:"steps" yaml:"steps"`
+	DownloadSize int64       `json:"download_size" yaml:"download_size"`
+}
+
+func newPlanView(p plan.Plan) planView {
+	steps := p.Steps
+	if steps == nil {
+		steps = []plan.Step{}
+	}
+	return planView{
+		Steps:        steps,
+		DownloadSize: p.DownloadSize(),
+	}
+}
+
+func (v planView) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "... dry run, nothing will be changed ...")
+	if len(v.Steps) == 0 {
+		_, err := fmt.Fprintln(w, "... nothing to do ...")
+		return err
+	}
+
+	for _, s := range v.Steps {
+		dependency := ""
+		if s.Dependency {
+			dependency = " (dependency)"
+		}
+		fmt.Fprintf(w, " %s | %s-%s | %s | %s%s \n", s.Action, s.Namespace, s.Name, versionChange(s), formatSize(s.Size, s.DownloadURL), dependency)
+
+		if s.DownloadURL != "" {
+			fmt.Fprintf(w, "     download: %s\n", s.DownloadURL)
+		}
+		for _, f := range s.Delete {
+			fmt.Fprintf(w, "     delete: %s\n", f)
+		}
+		fmt.Fprintf(w, "     database: %s\n", s.DatabaseChange())
+	}
+	_, err := fmt.Fprintf(w, "\n total download size: %s\n", formatBytes(v.DownloadSize))
+	return err
+}
+
+// configView is every configuration value, along with the file they were loaded from
+type configView struct {
+	File     string         `json:"file" yaml:"file"`
+	Settings map[string]any `json:"settings" yaml:"settings"`
+	keys     []string
+}
+
+func (v configView) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, configTitle+"\n")
+	fmt.Fprintf(w, "\nUsing configuration file at: %s\n\n", v.File)
+
+	for _, key := range v.keys {
+		fmt.Fprintf(w, "%s : %v\n", key, v.Settings[key])
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// configValue is a single configuration value
+type configValue struct {
+	Key   string `json:"key" yaml:"key"`
+	Value any    `json:"value" yaml:"value"`
+}
+
+func (v configValue) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintln(w, v.Value)
+	return err
+}
+
+// processView is a game server that was started in the background
+type processView struct {
+	server.Process `yaml:",inline"`
+}
+
+func (v processView) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "... %s server started in the background (PID %d), logging to %s ...\n", v.GameType, v.PID, v.LogFile)
+	return err
+}
+
+// statusView is the game server's current status. Everything but Running is left out when the
+// server is stopped.
+type statusView struct {
+	Running        bool       `json:"running" yaml:"running"`
+	PID            int        `json:"pid,omitempty" yaml:"pid,omitempty"`
+	GameType       string     `json:"game_type,omitempty" yaml:"game_type,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	UptimeSeconds  int64      `json:"uptime_seconds,omitempty" yaml:"uptime_seconds,omitempty"`
+	BepInExVersion string     `json:"bepinex_version,omitempty" yaml:"bepinex_version,omitempty"`
+	LogFile        string     `json:"log_file,omitempty" yaml:"log_file,omitempty"`
+}
+
+func newStatusView(s server.Status, now time.Time) statusView {
+	if !s.Running {
+		return statusView{}
+	}
+	startedAt := s.Process.StartedAt
+	return statusView{
+		Running:        true,
+		PID:            s.Process.PID,
+		GameType:       s.Process.GameType,
+		StartedAt:      &startedAt,
+		UptimeSeconds:  int64(s.Process.Uptime(now).Seconds()),
+		BepInExVersion: s.BepInExVersion,
+		LogFile:        s.Process.LogFile,
+	}
+}
+
+func (v statusView) WriteText(w io.Writer) error {
+	if !v.Running {
+		_, err := fmt.Fprintln(w, "... server is not running ...")
+		return err
+	}
+
+	fmt.Fprintf(w, "status  : running\n")
+	fmt.Fprintf(w, "pid     : %d\n", v.PID)
+	fmt.Fprintf(w, "type    : %s\n", v.GameType)
+	fmt.Fprintf(w, "uptime  : %s\n", time.Duration(v.UptimeSeconds)*time.Second)
+	if v.BepInExVersion != "" {
+		fmt.Fprintf(w, "bepinex : %s\n", v.BepInExVersion)
+	}
+	_, err := fmt.Fprintf(w, "logs    : %s\n", v.LogFile)
+	return err
+}
+
+// unitView is the game server's systemd unit, along with the systemctl commands to run after it
+// was changed
+type unitView struct {
+	Scope     string   `json:"scope" yaml:"scope"`
+	Path      string   `json:"path" yaml:"path"`
+	Installed bool     `json:"installed" yaml:"installed"`
+	Enabled   bool     `json:"enabled" yaml:"enabled"`
+	UpToDate  bool     `json:"up_to_date" yaml:"up_to_date"`
+	GameType  string   `json:"game_type,omitempty" yaml:"game_type,omitempty"`
+	Commands  []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+func newUnitView(s systemd.Status, commands []string) unitView {
+	return unitView{
+		Scope:     s.Scope,
+		Path:      s.Path,
+		Installed: s.Installed,
+		Enabled:   s.Enabled,
+		UpToDate:  s.UpToDate,
+		GameType:  s.GameType,
+		Commands:  commands,
+	}
+}
+
+func (v unitView) WriteText(w io.Writer) error {
+	if len(v.Commands) > 0 {
+		if v.Installed {
+			fmt.Fprintf(w, "... systemd unit written to %s ...\n", v.Path)
+		} else {
+			fmt.Fprintf(w, "... systemd unit removed from %s ...\n", v.Path)
+		}
+		fmt.Fprintln(w, "run these commands to apply the change:")
+		for _, c := range v.Commands {
+			fmt.Fprintf(w, "  %s\n", c)
+		}
+		return nil
+	}
+
+	if !v.Installed {
+		_, err := fmt.Fprintf(w, "... systemd unit is not installed (%s scope) ...\n", v.Scope)
+		return err
+	}
+	fmt.Fprintf(w, "status     : installed\n")
+	fmt.Fprintf(w, "scope      : %s\n", v.Scope)
+	fmt.Fprintf(w, "path       : %s\n", v.Path)
+	fmt.Fprintf(w, "type       : %s\n", v.GameType)
+	fmt.Fprintf(w, "enabled    : %t\n", v.Enabled)
+	_, err := fmt.Fprintf(w, "up to date : %t\n", v.UpToDate)
+	return err
+}
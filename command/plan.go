@@ -0,0 +1,49 @@
+package command
+
+import (
+	"fmt"
+	"warden/internal/domain/plan"
+
+	"github.com/spf13/cobra"
+)
+
+// isDryRun checks the --dry-run flag, which is inherited by every sub-command of add, update
+// and remove
+func isDryRun(cmd *cobra.Command) bool {
+	dryRun, err := cmd.Flags().GetBool(dryRunFlagLong)
+	return err == nil && dryRun
+}
+
+func versionChange(s plan.Step) string {
+	switch {
+	case s.FromVersion != "" && s.ToVersion != "":
+		return s.FromVersion + " -> " + s.ToVersion
+	case s.ToVersion != "":
+		return s.ToVersion
+	default:
+		return s.FromVersion
+	}
+}
+
+func formatSize(size int64, url string) string {
+	if url == "" {
+		return "-"
+	}
+	if size == plan.UnknownSize {
+		return "unknown size"
+	}
+	return formatBytes(size)
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
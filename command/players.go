@@ -0,0 +1,98 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/data/file"
+	"warden/internal/domain/player"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewPlayersCommand(ps service.Players) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "players",
+		Short: "Manages the admin, banned and permitted player lists.",
+		Long:  "Edits the adminlist.txt, bannedlist.txt and permittedlist.txt files Valheim reads from the save directory. Players are listed by their Steam64 ID, or by a Steam_ or Xbox_ prefixed ID on crossplay servers. The previous list is backed up next to it before every change.",
+	}
+	cmd.AddCommand(newPlayerListCommand(ps, player.Admin, "Manages the players who can use admin commands in game."))
+	cmd.AddCommand(newPlayerListCommand(ps, player.Banned, "Manages the players who are banned from the server."))
+	cmd.AddCommand(newPlayerListCommand(ps, player.Permitted, "Manages the players who can join the server. If anyone is permitted, everyone else is turned away."))
+	return cmd
+}
+
+func newPlayerListCommand(ps service.Players, list, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   list,
+		Short: short,
+	}
+	cmd.AddCommand(newPlayerEditCommand(ps, list, "add", "Adds a player to the "+player.FileName(list)+" list.", ps.AddPlayer))
+	cmd.AddCommand(newPlayerEditCommand(ps, list, "remove", "Removes a player from the "+player.FileName(list)+" list.", ps.RemovePlayer))
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lists every player in the " + player.FileName(list) + " list.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := ps.ListPlayers(list)
+			if err != nil {
+				return fail(err, playersErrorMessage(err))
+			}
+			writeResult(playerList(ids))
+			return nil
+		},
+	})
+	return cmd
+}
+
+// newPlayerEditCommand creates a command that changes a player list, then restarts the server if
+// asked to so the change applies straight away
+func newPlayerEditCommand(ps service.Players, list, action, short string, edit func(list, id string) error) *cobra.Command {
+	var restart bool
+
+	cmd := &cobra.Command{
+		Use:   action + " [player ID]",
+		Short: short,
+		Long:  short + " Valheim only reads the list when it starts, so pass --restart to restart a running server and apply the change straight away.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := edit(list, args[0]); err != nil {
+				return fail(err, playersErrorMessage(err))
+			}
+			if !restart {
+				writeMessage("player list updated, restart the server to apply the change")
+				return nil
+			}
+
+			reloaded, err := ps.Reload()
+			if err != nil {
+				return fail(err, playersErrorMessage(err))
+			}
+			if !reloaded {
+				writeMessage("player list updated, it will be applied the next time the server starts")
+				return nil
+			}
+			writeMessage("player list updated and the server was restarted!")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&restart, restartFlagLong, false, restartFlagDesc)
+	return cmd
+}
+
+func playersErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidPlayerID) {
+		return "player ID must be a 17 digit Steam64 ID, or a Steam_ or Xbox_ prefixed ID"
+	} else if errors.Is(err, service.ErrPlayerAlreadyListed) {
+		return "player is already in the list"
+	} else if errors.Is(err, service.ErrPlayerNotListed) {
+		return "player is not in the list"
+	} else if errors.Is(err, file.ErrPlayerListLocked) {
+		return "player list is being edited by another process, try again shortly"
+	} else if errors.Is(err, service.ErrUnableToListPlayers) {
+		return "unable to read player list"
+	} else if errors.Is(err, service.ErrUnableToEditPlayerList) {
+		return "unable to edit player list"
+	} else if errors.Is(err, service.ErrUnableToReloadPlayers) {
+		return "player list updated, but " + restartErrorMessage(err)
+	}
+	return err.Error()
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/domain/profile"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewProfileCommand(ps service.Profiles) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manages mod profiles.",
+		Long:  "A profile is a named set of mods, at specific versions, along with the BepInEx config files they're set up with. Switching between profiles swaps the installed mods and config files, e.g. to go from a vanilla+ world to a hardcore one. Every mod a profile needs is kept in the archive cache, so switching doesn't download them again.",
+	}
+	cmd.AddCommand(newProfileCreateCommand(ps))
+	cmd.AddCommand(newProfileListCommand(ps))
+	cmd.AddCommand(newProfileSwitchCommand(ps))
+	cmd.AddCommand(newProfileDeleteCommand(ps))
+	cmd.AddCommand(newProfileImportCommand(ps))
+	cmd.AddCommand(newProfileExportCommand(ps))
+	return cmd
+}
+
+func newProfileCreateCommand(ps service.Profiles) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Saves the installed mods and config files as a new profile.",
+		Long:  "Saves the installed mods, at their installed versions, along with a copy of every file in BepInEx/config, as a new profile.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := ps.CreateProfile(args[0])
+			if err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeResult(newProfileList([]profile.Profile{p}, ""))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newProfileListCommand(ps service.Profiles) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists every profile.",
+		Long:  "Lists every saved profile. The profile that was switched to last is marked with *.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profiles, active, err := ps.ListProfiles()
+			if err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeResult(newProfileList(profiles, active))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newProfileSwitchCommand(ps service.Profiles) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch [name]",
+		Short: "Switches to a profile's mods and config files.",
+		Long:  "Removes every installed mod that isn't in the profile, installs the profile's mods at their saved versions, and replaces BepInEx/config with the profile's config files. Changes made since the last switch are saved to the profile that was active first. The server has to be stopped first.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := ps.SwitchProfile(args[0]); err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeMessage("switched to " + args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newProfileDeleteCommand(ps service.Profiles) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Deletes a profile.",
+		Long:  "Deletes a saved profile, along with its copy of the config files. The installed mods are left alone.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ps.DeleteProfile(args[0]); err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeMessage("profile deleted")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newProfileImportCommand(ps service.Profiles) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Imports an r2modman profile and switches to it.",
+		Long:  "Reads an r2modman or Thunderstore Mod Manager profile (.r2z) file, saves its enabled mods and config files as a new profile, and switches to it, so the server runs the same mods as players. BepInEx is left out, since Warden manages it separately.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := ps.ImportProfile(args[0], name)
+			if err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeResult(newProfileList([]profile.Profile{p}, p.Name))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, profileNameFlagLong, "", importProfileNameFlagDesc)
+	return cmd
+}
+
+func newProfileExportCommand(ps service.Profiles) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Exports the installed mods as an r2modman profile.",
+		Long:  "Writes the installed mods, including BepInEx, and the files in BepInEx/config to an r2modman profile (.r2z) file. Players can import it with r2modman or Thunderstore Mod Manager to run the same mods as the server.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e, err := ps.ExportProfile(args[0], name)
+			if err != nil {
+				return fail(err, profileErrorMessage(err))
+			}
+			writeResult(exportView(e))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, profileNameFlagLong, "", exportProfileNameFlagDesc)
+	return cmd
+}
+
+func profileErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidProfileName) {
+		return "profile names must be letters, numbers, -, _, + or ., and at most 64 characters. Pick another name for an imported profile with --name"
+	} else if errors.Is(err, service.ErrProfileNotFound) {
+		return "profile does not exist"
+	} else if errors.Is(err, service.ErrProfileAlreadyExists) {
+		return "profile already exists"
+	} else if errors.Is(err, service.ErrProfileAlreadyActive) {
+		return "profile is already active"
+	} else if errors.Is(err, service.ErrServerAlreadyRunning) {
+		return "server is running, stop it before switching profiles"
+	} else if errors.Is(err, service.ErrInvalidProfileExport) {
+		return "file isn't a valid r2modman profile"
+	} else if errors.Is(err, service.ErrProfileExportNotFound) {
+		return "r2modman profile file does not exist"
+	} else if errors.Is(err, service.ErrUnableToImportProfile) {
+		return "unable to import r2modman profile"
+	} else if errors.Is(err, service.ErrUnableToExportProfile) {
+		return "unable to export r2modman profile"
+	} else if errors.Is(err, service.ErrUnableToListProfiles) {
+		return "unable to list profiles"
+	} else if errors.Is(err, service.ErrUnableToCreateProfile) {
+		return "unable to create profile"
+	} else if errors.Is(err, service.ErrUnableToSwitchProfile) {
+		return "unable to switch profile"
+	} else if errors.Is(err, service.ErrUnableToDeleteProfile) {
+		return "unable to delete profile"
+	}
+	return err.Error()
+}
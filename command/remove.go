@@ -2,7 +2,7 @@ package command
 
 import (
 	"errors"
-	"fmt"
+	"warden/internal/domain/plan"
 	"warden/internal/service"
 
 	"github.com/spf13/cobra"
@@ -16,13 +16,15 @@ func NewRemoveCommand(fs service.Framework, ms service.Mod) *cobra.Command {
 		Use:   "remove",
 		Short: "Removes the specified mod.",
 		Long:  "Deletes the mod from your mod folder and removes it from the local data storage.",
-		Run: func(cmd *cobra.Command, args []string) {
-			err := ms.RemoveMod(namespace, modPkg)
-			if err != nil {
-				parseRemoveError(err)
-			} else {
-				fmt.Println("... mod successfully removed! ...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeRemovePlan(ms.PlanRemoveMod(namespace, modPkg))
 			}
+			if err := ms.RemoveMod(namespace, modPkg); err != nil {
+				return fail(err, removeErrorMessage(err))
+			}
+			writeMessage("mod successfully removed!")
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, namespaceFlagLong, namespaceFlagShort, "", namespaceFlagDesc)
@@ -31,6 +33,7 @@ func NewRemoveCommand(fs service.Framework, ms service.Mod) *cobra.Command {
 	cmd.MarkFlagRequired(namespaceFlagLong)
 	cmd.MarkFlagRequired(modPackageFlagLong)
 	cmd.MarkFlagsRequiredTogether(namespaceFlagLong, modPackageFlagLong)
+	cmd.PersistentFlags().Bool(dryRunFlagLong, false, dryRunFlagDesc)
 
 	// Add sub-commands
 	cmd.AddCommand(newRemoveAllCommand(ms))
@@ -43,13 +46,15 @@ func newRemoveAllCommand(ms service.Mod) *cobra.Command {
 		Use:   "all",
 		Short: "Removes all mods.",
 		Long:  "Deletes all mods from your mod folder, and removes records of them from the local data storage.",
-		Run: func(cmd *cobra.Command, args []string) {
-			err := ms.RemoveAllMods()
-			if err != nil {
-				parseRemoveAllError(err)
-			} else {
-				fmt.Println("... all mods were removed successfully! ...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeRemovePlan(ms.PlanRemoveAllMods())
+			}
+			if err := ms.RemoveAllMods(); err != nil {
+				return fail(err, removeAllErrorMessage(err))
 			}
+			writeMessage("all mods were removed successfully!")
+			return nil
 		},
 	}
 	return cmd
@@ -60,34 +65,50 @@ func newRemoveBepInEx(fs service.Framework) *cobra.Command {
 		Use:   "bepinex",
 		Short: "Removes BepInEx installation.",
 		Long:  "Removes BepInEx and all mods installed under it.",
-		Run: func(cmd *cobra.Command, args []string) {
-			err := fs.RemoveBepInEx()
-			if err != nil {
-				parseRemoveError(err)
-			} else {
-				fmt.Println("... BepInEx and mods were removed successfully! ...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeRemovePlan(fs.PlanRemoveBepInEx())
+			}
+			if err := fs.RemoveBepInEx(); err != nil {
+				return fail(err, removeErrorMessage(err))
 			}
+			writeMessage("BepInEx and mods were removed successfully!")
+			return nil
 		},
 	}
 	return cmd
 }
 
-func parseRemoveError(err error) {
+func writeRemovePlan(p plan.Plan, err error) error {
+	if err != nil {
+		return fail(err, removeErrorMessage(err))
+	}
+	writeResult(newPlanView(p))
+	return nil
+}
+
+func removeErrorMessage(err error) string {
 	if errors.Is(err, service.ErrUnableToRemoveMod) {
-		fmt.Println("... unable to remove mod ...")
+		return "unable to remove mod"
 	} else if errors.Is(err, service.ErrMaxAttempts) {
-		fmt.Println("... unable to confim mod removal, aborting ...")
+		return "unable to confim mod removal, aborting"
 	} else if errors.Is(err, service.ErrModNotInstalled) {
-		fmt.Println("... mod not installed ...")
+		return "mod not installed"
 	} else if errors.Is(err, service.ErrUnableToRemoveFramework) {
-		fmt.Println("... unable to remove BepInEx ...")
+		return "unable to remove BepInEx"
+	} else if errors.Is(err, service.ErrConfirmationRequired) {
+		return confirmationRequiredMessage
 	}
+	return err.Error()
 }
 
-func parseRemoveAllError(err error) {
+func removeAllErrorMessage(err error) string {
 	if errors.Is(err, service.ErrUnableToRemoveMod) {
-		fmt.Println("... unable to remove mods ...")
+		return "unable to remove mods"
 	} else if errors.Is(err, service.ErrMaxAttempts) {
-		fmt.Println("... unable to confim mod removal, aborting ...")
+		return "unable to confim mod removal, aborting"
+	} else if errors.Is(err, service.ErrConfirmationRequired) {
+		return confirmationRequiredMessage
 	}
+	return err.Error()
 }
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRestartCommand(server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart [vanilla|modded]",
+		Short: "Restarts the Valheim game server.",
+		Long:  "Gracefully stops the game server, then starts it again in the background. If no game type is given, the server is started the same way it was last time.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if len(args) == 0 || server.IsValidGameType(args[0]) {
+				return nil
+			}
+			return service.ErrInvalidGameType
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gameType := ""
+			if len(args) == 1 {
+				gameType = args[0]
+			}
+
+			p, err := server.Restart(gameType)
+			if err != nil {
+				return fail(err, restartErrorMessage(err))
+			}
+			writeResult(processView{p})
+			return nil
+		},
+	}
+	return cmd
+}
+
+func restartErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidGameType) {
+		return "server has not been started before, specify vanilla or modded"
+	} else if errors.Is(err, service.ErrServerSupervised) {
+		return "Valheim server is supervised and can't be restarted this way, use stop and then supervise again"
+	} else if errors.Is(err, service.ErrServerStopFailed) {
+		return stopErrorMessage(err)
+	}
+	return startErrorMessage(err)
+}
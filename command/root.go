@@ -2,22 +2,102 @@ package command
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"warden/internal/config"
+	"warden/internal/format"
+	"warden/internal/service"
 
 	"github.com/spf13/cobra"
 )
 
+const confirmationRequiredMessage = "confirmation required, re-run with --yes or --assume-no"
+
+var (
+	assumeYes    bool
+	assumeNo     bool
+	outputFormat string
+	verbose      bool
+)
+
 var rootCommand = &cobra.Command{
 	Use:   "warden",
 	Short: "Warden is a CLI mod manager for Valheim",
 	Long:  `A fast and friendly CLI mod manager for Valheim. Built with love and Go for handling mods on headless servers <3`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(logo)
-		fmt.Println(runes)
-		fmt.Println(startUpBlurb)
+		fmt.Fprintln(progress, logo)
+		fmt.Fprintln(progress, runes)
+		fmt.Fprintln(progress, startUpBlurb)
 	},
 }
 
-func Execute(cmds ...*cobra.Command) {
+func Execute(c service.Confirmer, cmds ...*cobra.Command) {
+	rootCommand.PersistentFlags().BoolVarP(&assumeYes, yesFlagLong, yesFlagShort, false, yesFlagDesc)
+	rootCommand.PersistentFlags().BoolVar(&assumeNo, assumeNoFlagLong, false, assumeNoFlagDesc)
+	rootCommand.MarkFlagsMutuallyExclusive(yesFlagLong, assumeNoFlagLong)
+	rootCommand.PersistentFlags().StringVarP(&outputFormat, outputFlagLong, outputFlagShort, format.Table, outputFlagDesc)
+	rootCommand.PersistentFlags().BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, verboseFlagDesc)
+	// Only declared so cobra accepts it, since the instance is picked before any command runs
+	rootCommand.PersistentFlags().String(instanceFlagLong, "", instanceFlagDesc)
+
+	rootCommand.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		c.SetMode(confirmMode(os.Getenv(nonInteractiveEnv)))
+		if err := setUpOutput(outputFormat); err != nil {
+			return err
+		}
+		// Flags and arguments are valid by now, so any later error isn't a usage mistake
+		rootCommand.SilenceUsage = true
+		return nil
+	}
+
+	// Errors are reported by Warden instead of cobra, so they follow the --output format
+	rootCommand.SilenceErrors = true
 	rootCommand.AddCommand(cmds...)
-	cobra.CheckErr(rootCommand.Execute())
+
+	if err := rootCommand.Execute(); err != nil {
+		code := exitCode(err)
+		reportError(err, code)
+		os.Exit(code)
+	}
+}
+
+// Instance returns the server instance picked with the --instance flag, or WARDEN_INSTANCE. Every
+// service is set up with the instance's config before cobra parses any flags, so it's picked out
+// of the arguments here instead.
+func Instance(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+instanceFlagLong+"="); ok {
+			return value
+		}
+		if arg == "--"+instanceFlagLong && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(config.InstanceEnv)
+}
+
+// confirmMode picks how confirmation prompts are answered. Flags take priority over the
+// WARDEN_NONINTERACTIVE environment variable, which accepts "yes", "no", or any other
+// non-empty value to fail instead of prompting.
+func confirmMode(env string) service.ConfirmMode {
+	if assumeYes {
+		return service.AssumeYes
+	}
+	if assumeNo {
+		return service.AssumeNo
+	}
+
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "", "0", "false":
+		return service.Interactive
+	case "yes", "y":
+		return service.AssumeYes
+	case "no", "n":
+		return service.AssumeNo
+	default:
+		return service.NonInteractive
+	}
 }
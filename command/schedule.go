@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewScheduleCommand(scheduler service.Scheduler) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Lists the scheduled maintenance jobs.",
+		Long:  "Lists every maintenance job that has a schedule, along with when it runs next. Jobs are scheduled with cron expressions in the config, e.g. 'warden config set schedule-backup \"0 4 * * *\"', and are run by 'warden daemon'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := scheduler.Jobs(time.Now())
+			if err != nil {
+				return fail(err, scheduleErrorMessage(err))
+			}
+			writeResult(jobList(jobs))
+			return nil
+		},
+	}
+	cmd.AddCommand(newScheduleRunCommand(scheduler))
+	cmd.AddCommand(newScheduleHistoryCommand(scheduler))
+	return cmd
+}
+
+func newScheduleRunCommand(scheduler service.Scheduler) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [backup|update|restart]",
+		Short: "Runs a maintenance job right away.",
+		Long:  "Runs a maintenance job right away, whether or not it has a schedule, and records it in the job history.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			run, err := scheduler.RunJob(ctx, args[0])
+			if err != nil {
+				return fail(err, scheduleErrorMessage(err))
+			}
+			writeResult(newRunView(run))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newScheduleHistoryCommand(scheduler service.Scheduler) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Lists recent maintenance job runs.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runs, err := scheduler.History(limit)
+			if err != nil {
+				return fail(err, scheduleErrorMessage(err))
+			}
+			writeResult(runList(runs))
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, limitFlagLong, 20, limitFlagDesc)
+	return cmd
+}
+
+func NewDaemonCommand(scheduler service.Scheduler) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Runs scheduled maintenance jobs.",
+		Long:  "Runs in the foreground, backing up worlds, updating mods, and restarting the game server whenever their schedules are due. Every run is recorded in the job history. Jobs never ask for confirmation. Press Ctrl+C to stop.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := scheduler.Daemon(ctx); err != nil {
+				return fail(err, scheduleErrorMessage(err))
+			}
+			writeMessage("daemon stopped")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func scheduleErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidSchedule) {
+		return err.Error() + ", fix it with 'warden config set'"
+	} else if errors.Is(err, service.ErrNoJobsScheduled) {
+		return "no jobs are scheduled, set one with e.g. 'warden config set schedule-backup \"0 4 * * *\"'"
+	} else if errors.Is(err, service.ErrJobNotFound) {
+		return "unknown job, must be backup, update or restart"
+	} else if errors.Is(err, service.ErrUnableToRecordJobRun) {
+		return "unable to record job in the job history"
+	} else if errors.Is(err, service.ErrJobFailed) {
+		return "job failed: " + errors.Unwrap(err).Error()
+	} else if errors.Is(err, service.ErrUnableToListJobRuns) {
+		return "unable to list job history"
+	}
+	return err.Error()
+}
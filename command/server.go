@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewServerCommand(steam service.SteamCMD) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Installs and updates the Valheim dedicated server.",
+		Long:  "Installs, updates and validates the Valheim dedicated server in the Valheim directory using SteamCMD. Set 'steam-beta' in the config to use a beta branch, e.g. public-test. The game server has to be stopped first.",
+	}
+	cmd.AddCommand(newSteamCMDCommand("install", "Installs the Valheim dedicated server.", steam.Install, "Valheim server installed!"))
+	cmd.AddCommand(newSteamCMDCommand("update", "Updates the Valheim dedicated server.", steam.Update, "Valheim server is up-to-date!"))
+	cmd.AddCommand(newSteamCMDCommand("validate", "Checks every file of the Valheim dedicated server, repairing any that are broken.", steam.Validate, "Valheim server validated!"))
+	return cmd
+}
+
+func newSteamCMDCommand(use, short string, run func(ctx context.Context) error, message string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := run(ctx); err != nil {
+				return fail(err, steamCMDErrorMessage(err))
+			}
+			writeMessage(message)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func steamCMDErrorMessage(err error) string {
+	if errors.Is(err, service.ErrServerAlreadyRunning) {
+		return "Valheim server is running, stop it first"
+	} else if errors.Is(err, service.ErrServerAlreadyInstalled) {
+		return "Valheim server is already installed, update it with 'warden server update'"
+	} else if errors.Is(err, service.ErrServerNotInstalled) {
+		return "Valheim server is not installed, install it with 'warden server install'"
+	} else if errors.Is(err, service.ErrUnsupportedPlatform) {
+		return "Valheim has no dedicated server for this platform, run warden on Linux or Windows"
+	} else if errors.Is(err, service.ErrSteamCMDNotFound) {
+		return "steamcmd not found, install it or set 'steamcmd-path' with 'warden config set'"
+	}
+	return err.Error()
+}
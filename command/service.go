@@ -0,0 +1,106 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/domain/systemd"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewServiceCommand(units service.Systemd, server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Manages a systemd unit for the Valheim game server.",
+		Long:  "Generates, removes, and checks a systemd unit that runs the game server under 'warden supervise'. Warden only writes the unit file and prints the systemctl commands to run, so systemd isn't needed to use it.",
+	}
+	cmd.PersistentFlags().String(scopeFlagLong, systemd.UserScope, scopeFlagDesc)
+
+	cmd.AddCommand(newServiceInstallCommand(units, server))
+	cmd.AddCommand(newServiceUninstallCommand(units))
+	cmd.AddCommand(newServiceStatusCommand(units))
+	return cmd
+}
+
+func newServiceInstallCommand(units service.Systemd, server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install [vanilla|modded]",
+		Short: "Installs a systemd unit for the game server.",
+		Long:  "Writes a systemd unit that runs the given game server type under 'warden supervise', using the current config. An existing unit is replaced.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if server.IsValidGameType(args[0]) {
+				return nil
+			}
+			return service.ErrInvalidGameType
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := units.Install(args[0], scope(cmd))
+			if err != nil {
+				return fail(err, unitErrorMessage(err))
+			}
+			writeResult(newUnitView(status, systemd.InstallCommands(status.Scope, status.Name)))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newServiceUninstallCommand(units service.Systemd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Removes the game server's systemd unit.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := units.Uninstall(scope(cmd))
+			if err != nil {
+				return fail(err, unitErrorMessage(err))
+			}
+			writeResult(newUnitView(status, systemd.UninstallCommands(status.Scope, status.Name)))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newServiceStatusCommand(units service.Systemd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows whether the game server's systemd unit is installed.",
+		Long:  "Shows whether the systemd unit is installed and enabled, and whether it still matches the current config. Run install again to update it.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := units.Status(scope(cmd))
+			if err != nil {
+				return fail(err, unitErrorMessage(err))
+			}
+			writeResult(newUnitView(status, nil))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func scope(cmd *cobra.Command) string {
+	scope, _ := cmd.Flags().GetString(scopeFlagLong)
+	return scope
+}
+
+func unitErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidGameType) {
+		return "invalid game type"
+	} else if errors.Is(err, service.ErrInvalidUnitScope) {
+		return "invalid scope, must be user or system"
+	} else if errors.Is(err, service.ErrUnitNotInstalled) {
+		return "systemd unit is not installed"
+	} else if errors.Is(err, service.ErrUnitInstallFailed) {
+		return "unable to install systemd unit"
+	} else if errors.Is(err, service.ErrUnitUninstallFailed) {
+		return "unable to uninstall systemd unit"
+	} else if errors.Is(err, service.ErrUnitStatusFailed) {
+		return "unable to check systemd unit status"
+	}
+	return err.Error()
+}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"errors"
-	"fmt"
 	"warden/internal/service"
 
 	"github.com/spf13/cobra"
@@ -12,7 +11,7 @@ func NewStartCommand(server service.Server) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Starts the Valheim game server.",
-		Long:  "Starts the Valheim game server using the given configurattion, either vanilla or modded.",
+		Long:  "Starts the Valheim game server in the background using the given configurattion, either vanilla or modded. Use stop, restart and status to manage it afterwards.",
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return err
@@ -20,26 +19,31 @@ func NewStartCommand(server service.Server) *cobra.Command {
 			if server.IsValidGameType(args[0]) {
 				return nil
 			}
-			return service.ErrServerStartFailed
+			return service.ErrInvalidGameType
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			output, err := server.Start(args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := server.Start(args[0])
 			if err != nil {
-				parseStartError(err)
-				fmt.Println(output)
-			} else {
-				fmt.Println("... successfully started server! ...")
-				fmt.Println(output)
+				return fail(err, startErrorMessage(err))
 			}
+			writeResult(processView{p})
+			return nil
 		},
 	}
 	return cmd
 }
 
-func parseStartError(err error) {
-	if !errors.Is(err, service.ErrInvalidGameType) {
-		fmt.Println("... invalid game type ...")
-	} else if !errors.Is(err, service.ErrServerStartFailed) {
-		fmt.Println("... Valheim server failed to start ...")
+func startErrorMessage(err error) string {
+	if errors.Is(err, service.ErrInvalidGameType) {
+		return "invalid game type"
+	} else if errors.Is(err, service.ErrInvalidServerSettings) {
+		return err.Error() + ", fix it with 'warden config set'"
+	} else if errors.Is(err, service.ErrServerAlreadyRunning) {
+		return "Valheim server is already running"
+	} else if errors.Is(err, service.ErrUnsupportedPlatform) {
+		return "Valheim has no dedicated server for this platform, run warden on Linux or Windows"
+	} else if errors.Is(err, service.ErrServerStartFailed) {
+		return "Valheim server failed to start"
 	}
+	return err.Error()
 }
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"time"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewStatusCommand(server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows whether the Valheim game server is running.",
+		Long:  "Shows the PID, uptime, and game type of the game server started by Warden, along with the BepInEx version it's using.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := server.Status()
+			if err != nil {
+				return fail(err, "unable to check Valheim server status")
+			}
+			writeResult(newStatusView(s, time.Now()))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"errors"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewStopCommand(server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stops the Valheim game server.",
+		Long:  "Gracefully stops the game server started by Warden, giving it time to save the world before it exits.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.Stop(); err != nil {
+				return fail(err, stopErrorMessage(err))
+			}
+			writeMessage("server stopped")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func stopErrorMessage(err error) string {
+	if errors.Is(err, service.ErrServerNotRunning) {
+		return "Valheim server is not running"
+	} else if errors.Is(err, service.ErrServerStopTimeout) {
+		return "Valheim server did not stop in time, it may still be saving"
+	} else if errors.Is(err, service.ErrServerStopFailed) {
+		return "unable to stop Valheim server"
+	}
+	return err.Error()
+}
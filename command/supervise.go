@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewSuperviseCommand(server service.Server) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supervise [vanilla|modded]",
+		Short: "Runs the Valheim game server and restarts it if it crashes.",
+		Long:  "Runs the Valheim game server in the foreground, writing its output to rotating log files. If the server crashes it's restarted after a backoff, until it crashes too many times within the configured crash window. Press Ctrl+C, or run stop, to shut the server down.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if server.IsValidGameType(args[0]) {
+				return nil
+			}
+			return service.ErrInvalidGameType
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := server.Supervise(ctx, args[0]); err != nil {
+				return fail(err, superviseErrorMessage(err))
+			}
+			writeMessage("server stopped, no longer supervising")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func superviseErrorMessage(err error) string {
+	if errors.Is(err, service.ErrServerCrashLoop) {
+		return "Valheim server keeps crashing, giving up"
+	}
+	return startErrorMessage(err)
+}
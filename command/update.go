@@ -1,84 +1,163 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"warden/internal/domain/plan"
 	"warden/internal/service"
 
 	"github.com/spf13/cobra"
 )
 
-func NewUpdateCommand(fs service.Framework, ms service.Mod) *cobra.Command {
-	var modPkg string
+func NewUpdateCommand(fs service.Framework, ms service.Mod, ws service.World, vs service.Verifier) *cobra.Command {
+	var (
+		modPkg      string
+		verifyStart bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Updates the targetted mod.",
 		Long:  "Finds the latest version of the mod on Thunderstore and updates the currently installed version with the new one.",
-		Run: func(cmd *cobra.Command, args []string) {
-			err := ms.UpdateMod(modPkg)
-			if err != nil {
-				parseUpdateError(err)
-			} else {
-				fmt.Println("... mod successfully updated! ...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeUpdatePlan(ms.PlanUpdateMod(modPkg))
 			}
+			if err := backupWorlds(ws); err != nil {
+				return err
+			}
+			update := func() error { return ms.UpdateMod(modPkg) }
+			if verifyStart {
+				return verifiedUpdate(vs, update, "mod successfully updated, and the server started cleanly!")
+			}
+			if err := update(); err != nil {
+				return fail(err, updateErrorMessage(err))
+			}
+			writeMessage("mod successfully updated!")
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&modPkg, modPackageFlagLong, modPackageFlagShort, "", modPackageFlagDesc)
 	cmd.MarkFlagRequired(modPackageFlagLong)
+	cmd.Flags().BoolVar(&verifyStart, verifyStartFlagLong, false, verifyStartFlagDesc)
+	cmd.PersistentFlags().Bool(dryRunFlagLong, false, dryRunFlagDesc)
 
 	// Add sub-commands
-	cmd.AddCommand(newUpdateAllCommand(ms))
-	cmd.AddCommand(newUpdateBepInEx(fs))
+	cmd.AddCommand(newUpdateAllCommand(ms, ws, vs))
+	cmd.AddCommand(newUpdateBepInEx(fs, ws))
 	return cmd
 }
 
-func newUpdateAllCommand(ms service.Mod) *cobra.Command {
+func newUpdateAllCommand(ms service.Mod, ws service.World, vs service.Verifier) *cobra.Command {
+	var verifyStart bool
+
 	cmd := &cobra.Command{
 		Use:   "all",
 		Short: "Updates all mods",
 		Long:  "Installs the latest version of every mod that is currently installed",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeUpdatePlan(ms.PlanUpdateAllMods())
+			}
+			if err := backupWorlds(ws); err != nil {
+				return err
+			}
+			if verifyStart {
+				return verifiedUpdate(vs, ms.UpdateAllMods, "all mods are up-to-date, and the server started cleanly!")
+			}
 			if err := ms.UpdateAllMods(); err != nil {
-				parseUpdateError(err)
+				return fail(err, updateErrorMessage(err))
 			}
+			writeMessage("all mods are up-to-date!")
+			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&verifyStart, verifyStartFlagLong, false, verifyStartFlagDesc)
 	return cmd
 }
 
-func newUpdateBepInEx(fs service.Framework) *cobra.Command {
+func newUpdateBepInEx(fs service.Framework, ws service.World) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bepinex",
 		Short: "Updates BepInEx.",
 		Long:  "Updates the current BepInEx installation.",
-		Run: func(cmd *cobra.Command, args []string) {
-			err := fs.UpdateBepInEx()
-			if err != nil {
-				parseUpdateError(err)
-			} else {
-				fmt.Println("...successfully updated BepInEx!...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isDryRun(cmd) {
+				return writeUpdatePlan(fs.PlanUpdateBepInEx())
+			}
+			if err := backupWorlds(ws); err != nil {
+				return err
+			}
+			if err := fs.UpdateBepInEx(); err != nil {
+				return fail(err, updateErrorMessage(err))
 			}
+			writeMessage("successfully updated BepInEx!")
+			return nil
 		},
 	}
 	return cmd
 }
 
-func parseUpdateError(err error) {
-	if errors.Is(err, service.ErrModNotInstalled) {
-		fmt.Println("... mod not installed, update stopped ...")
+// verifiedUpdate runs an update, then starts the modded server to check it, rolling the update back
+// if it fails or the server doesn't start cleanly
+func verifiedUpdate(vs service.Verifier, update func() error, message string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	v, err := vs.VerifyUpdate(ctx, update)
+	if err != nil {
+		message := updateErrorMessage(err)
+		// An update that fails is rolled back before it's checked, which its error doesn't say
+		if v.RolledBack && !errors.Is(err, service.ErrUpdateVerificationFailed) {
+			message += ", the update was rolled back"
+		}
+		return fail(err, message)
+	}
+	writeMessage(message)
+	return nil
+}
+
+func writeUpdatePlan(p plan.Plan, err error) error {
+	if err != nil {
+		return fail(err, updateErrorMessage(err))
+	}
+	if !p.IsEmpty() {
+		fmt.Fprintln(progress, "... worlds will be backed up before updating ...")
+	}
+	writeResult(newPlanView(p))
+	return nil
+}
+
+func updateErrorMessage(err error) string {
+	if errors.Is(err, service.ErrUpdateVerificationFailed) {
+		return err.Error() + ", the update was rolled back"
+	} else if errors.Is(err, service.ErrUpdateRollbackFailed) {
+		return "the update failed or the server did not start cleanly after it, and rolling it back failed. Restore the mods by hand"
+	} else if errors.Is(err, service.ErrUnableToVerifyUpdate) && errors.Is(err, service.ErrServerAlreadyRunning) {
+		return "stop the server before verifying an update, since it has to be started to check it"
+	} else if errors.Is(err, service.ErrUnableToVerifyUpdate) {
+		return "unable to verify the update"
+	} else if errors.Is(err, service.ErrModNotInstalled) {
+		return "mod not installed, update stopped"
 	} else if errors.Is(err, service.ErrUnableToUpdateMod) {
-		fmt.Println("... unable to update mod ...")
+		return "unable to update mod"
 	} else if errors.Is(err, service.ErrModNotFound) {
-		fmt.Println("... could not find mod on Thunderstore, stopping update ...")
+		return "could not find mod on Thunderstore, stopping update"
 	} else if errors.Is(err, service.ErrAddDependenciesFailed) {
-		fmt.Println("... unable to update mod's depedencies, stopping update ...")
+		return "unable to update mod's depedencies, stopping update"
 	} else if errors.Is(err, service.ErrMaxAttempts) {
-		fmt.Println("... unable to confim update, aborting ...")
+		return "unable to confim update, aborting"
 	} else if errors.Is(err, service.ErrFrameworkNotInstalled) {
-		fmt.Println("... BepInEx is not installed ...")
+		return "BepInEx is not installed"
 	} else if errors.Is(err, service.ErrUnableToUpdateFramework) {
-		fmt.Println("... unable to update BepInEx ...")
+		return "unable to update BepInEx"
+	} else if errors.Is(err, service.ErrConfirmationRequired) {
+		return confirmationRequiredMessage
 	}
+	return err.Error()
 }
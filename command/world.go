@@ -0,0 +1,102 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"warden/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func NewWorldCommand(ws service.World) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "world",
+		Short: "Manages backups of your Valheim worlds.",
+		Long:  "Backs up, lists, and restores the world save files (.db and .fwl) in your Valheim save directory.",
+	}
+	cmd.AddCommand(newWorldBackupCommand(ws))
+	cmd.AddCommand(newWorldListCommand(ws))
+	cmd.AddCommand(newWorldRestoreCommand(ws))
+	return cmd
+}
+
+func newWorldBackupCommand(ws service.World) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backs up every world.",
+		Long:  "Creates a new backup of every world in the save directory, then removes old backups based on the configured retention policy.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := ws.BackupWorlds()
+			if err != nil {
+				return fail(err, worldErrorMessage(err))
+			}
+			writeResult(snapshotList(snapshots))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newWorldListCommand(ws service.World) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists all world backups.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := ws.ListBackups()
+			if err != nil {
+				return fail(err, worldErrorMessage(err))
+			}
+			writeResult(snapshotList(snapshots))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newWorldRestoreCommand(ws service.World) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [backup]",
+		Short: "Restores a world backup.",
+		Long:  "Overwrites a world's save files with the given backup. The current save files are backed up first.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ws.RestoreBackup(args[0]); err != nil {
+				return fail(err, worldErrorMessage(err))
+			}
+			writeMessage("world successfully restored!")
+			return nil
+		},
+	}
+	return cmd
+}
+
+// backupWorlds is a helper for taking a world backup before any change that could break the
+// server. If no backup can be made, the change is stopped.
+func backupWorlds(ws service.World) error {
+	fmt.Fprintln(progress, "... backing up worlds ...")
+	if _, err := ws.BackupWorlds(); err != nil {
+		return fail(err, worldErrorMessage(err))
+	}
+	return nil
+}
+
+func worldErrorMessage(err error) string {
+	if errors.Is(err, service.ErrUnableToBackupWorld) {
+		return "unable to back up worlds"
+	} else if errors.Is(err, service.ErrUnableToPruneBackups) {
+		return "unable to remove old world backups"
+	} else if errors.Is(err, service.ErrUnableToListBackups) {
+		return "unable to list world backups"
+	} else if errors.Is(err, service.ErrWorldBackupNotFound) {
+		return "world backup not found"
+	} else if errors.Is(err, service.ErrServerAlreadyRunning) {
+		return "Valheim server is running, stop it before restoring a backup"
+	} else if errors.Is(err, service.ErrUnableToRestoreWorld) {
+		return "unable to restore world backup"
+	} else if errors.Is(err, service.ErrMaxAttempts) {
+		return "unable to confim restore, aborting"
+	} else if errors.Is(err, service.ErrConfirmationRequired) {
+		return confirmationRequiredMessage
+	}
+	return err.Error()
+}
@@ -15,4 +15,6 @@ var (
 // http.Client struct and mock.HTTPClient
 type HTTPClient interface {
 	Get(url string) (resp *http.Response, err error)
+	Head(url string) (resp *http.Response, err error)
+	Do(req *http.Request) (resp *http.Response, err error)
 }
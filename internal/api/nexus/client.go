@@ -0,0 +1,226 @@
+package nexus
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+	"warden/internal/api"
+	"warden/internal/api/source"
+)
+
+const (
+	API = "https://api.nexusmods.com/v1"
+
+	// Nexus Mods calls each game it hosts mods for a domain
+	GameDomain = "valheim"
+
+	website      = "https://www.nexusmods.com/" + GameDomain + "/mods"
+	apiKeyHeader = "apikey"
+
+	// Only a mod's main files are installed. Optional files, old versions, etc. are left alone.
+	mainFileCategory = "MAIN"
+	publishedStatus  = "published"
+)
+
+var (
+	ErrMissingAPIKey = errors.New("Nexus Mods API key is missing, set nexus-api-key in the config")
+	ErrInvalidAPIKey = errors.New("Nexus Mods rejected the API key")
+	ErrInvalidModID  = errors.New("Nexus Mods mods are identified by their mod ID, e.g. 4")
+	ErrModNotFound   = errors.New("mod was not found on Nexus Mods")
+	ErrNexusAPI      = errors.New("Nexus Mods API returned an unexpected error")
+	ErrNoDownload    = errors.New("Nexus Mods didn't return a download link")
+)
+
+var (
+	// Nexus Mods authors and versions are free text, but they end up in the names of mod
+	// directories, where Thunderstore only allows these characters
+	invalidNamespace = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	invalidVersion   = regexp.MustCompile(`[^a-zA-Z0-9_.]`)
+)
+
+// nexus installs Valheim mods from Nexus Mods. See docs: https://app.swaggerhub.com/apis-docs/NexusMods/nexus-mods_public_api_params_in_form_data/1.0
+//
+// Mods are identified by their mod ID alone, so it's used as their name. A mod's namespace is its
+// author.
+type nexus struct {
+	client api.HTTPClient
+	url    string
+	apiKey string
+}
+
+// New creates a Nexus Mods source that talks to the API at the given URL, usually API. Every
+// request needs an API key, which Nexus Mods hands out on each account's settings page.
+func New(c api.HTTPClient, url, apiKey string) source.Source {
+	return &nexus{
+		client: c,
+		url:    url,
+		apiKey: apiKey,
+	}
+}
+
+func (n *nexus) GetPackage(namespace, name string) (source.Package, error) {
+	info, files, err := n.getMod(name)
+	if err != nil {
+		return source.Package{}, err
+	}
+
+	// The newest main file is the latest release
+	latest := -1
+	for i, f := range files {
+		if f.CategoryName == mainFileCategory && (latest < 0 || f.UploadedTimestamp > files[latest].UploadedTimestamp) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return source.Package{}, fmt.Errorf("%w: mod %s has no main files", source.ErrReleaseNotFound, name)
+	}
+
+	return source.Package{
+		Source:     source.Nexus,
+		Namespace:  modNamespace(info),
+		Name:       name,
+		Latest:     toRelease(info, files[latest]),
+		UpdatedAt:  time.Unix(info.UpdatedTimestamp, 0).UTC(),
+		Categories: []string{},
+		Deprecated: !info.Available || info.Status != publishedStatus,
+	}, nil
+}
+
+func (n *nexus) GetRelease(namespace, name, version string) (source.Release, error) {
+	info, files, err := n.getMod(name)
+	if err != nil {
+		return source.Release{}, err
+	}
+
+	// A version can be uploaded more than once, e.g. to fix a broken archive
+	slices.SortFunc(files, func(a, b modFile) int {
+		return cmp.Compare(b.UploadedTimestamp, a.UploadedTimestamp)
+	})
+	for _, f := range files {
+		if release := toRelease(info, f); release.Version == version {
+			return release, nil
+		}
+	}
+	return source.Release{}, fmt.Errorf("%w: %s %s", source.ErrReleaseNotFound, name, version)
+}
+
+func (n *nexus) DownloadURL(release source.Release) (string, error) {
+	links := []downloadLink{}
+	path := fmt.Sprintf("/games/%s/mods/%s/files/%s/download_link.json", GameDomain, release.Name, release.ID)
+	if err := n.get(path, &links); err != nil {
+		return "", err
+	}
+	if len(links) == 0 || links[0].URI == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoDownload, release.FullName)
+	}
+	return links[0].URI, nil
+}
+
+func (n *nexus) GetDownloadSize(release source.Release) (int64, error) {
+	_, files, err := n.getMod(release.Name)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if strconv.FormatInt(f.FileID, 10) == release.ID {
+			return f.SizeInBytes, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", source.ErrReleaseNotFound, release.FullName)
+}
+
+// getMod fetches a mod and every file uploaded for it
+func (n *nexus) getMod(id string) (modInfo, []modFile, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		return modInfo{}, nil, fmt.Errorf("%w: %q", ErrInvalidModID, id)
+	}
+
+	info := modInfo{}
+	if err := n.get(fmt.Sprintf("/games/%s/mods/%s.json", GameDomain, id), &info); err != nil {
+		return modInfo{}, nil, err
+	}
+	files := fileList{}
+	if err := n.get(fmt.Sprintf("/games/%s/mods/%s/files.json", GameDomain, id), &files); err != nil {
+		return modInfo{}, nil, err
+	}
+	return info, files.Files, nil
+}
+
+// get sends an authenticated request to the API, and decodes the response into obj
+func (n *nexus) get(path string, obj any) error {
+	if n.apiKey == "" {
+		return ErrMissingAPIKey
+	}
+
+	req, err := http.NewRequest(http.MethodGet, n.url+path, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
+	}
+	req.Header.Set(apiKeyHeader, n.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	response, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrByteIO, err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		if err := json.Unmarshal(data, obj); err != nil {
+			return fmt.Errorf("%w: %w", api.ErrJSONParse, err)
+		}
+		return nil
+	case http.StatusUnauthorized:
+		return ErrInvalidAPIKey
+	case http.StatusNotFound:
+		return ErrModNotFound
+	default:
+		return fmt.Errorf("%w: status %d", ErrNexusAPI, response.StatusCode)
+	}
+}
+
+// toRelease describes a mod's file as a release. Files without a version of their own are
+// versioned like the mod.
+func toRelease(info modInfo, f modFile) source.Release {
+	version := f.Version
+	if version == "" {
+		version = info.Version
+	}
+	version = invalidVersion.ReplaceAllString(version, "_")
+
+	namespace, name := modNamespace(info), strconv.Itoa(info.ModID)
+	page := fmt.Sprintf("%s/%d", website, info.ModID)
+	return source.Release{
+		Namespace:    namespace,
+		Name:         name,
+		Version:      version,
+		FullName:     namespace + "-" + name + "-" + version,
+		Description:  info.Summary,
+		WebsiteURL:   page,
+		Dependencies: []string{},
+		DownloadURL:  fmt.Sprintf("%s?tab=files&file_id=%d", page, f.FileID),
+		ID:           strconv.FormatInt(f.FileID, 10),
+	}
+}
+
+// modNamespace is a mod's author, made safe to use in a directory name
+func modNamespace(info modInfo) string {
+	author := info.Author
+	if author == "" {
+		author = info.UploadedBy
+	}
+	return invalidNamespace.ReplaceAllString(author, "_")
+}
@@ -0,0 +1,191 @@
+package nexus_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"warden/internal/api/nexus"
+	"warden/internal/api/source"
+)
+
+const testAPIKey = "test-key"
+
+func TestGetPackage_Happy(t *testing.T) {
+	server := newTestNexus(t)
+	n := nexus.New(server.Client(), server.URL, testAPIKey)
+
+	pkg, err := n.GetPackage("", "4")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	if pkg.Source != source.Nexus || pkg.Name != "4" || pkg.Namespace != "Grantapher_and_co" || pkg.Deprecated {
+		t.Errorf("expected the mod's package, received: %+v", pkg)
+	}
+	if !pkg.UpdatedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected the mod's update time, received: %s", pkg.UpdatedAt)
+	}
+	expected := source.Release{
+		Namespace:   "Grantapher_and_co",
+		Name:        "4",
+		Version:     "0.9.9",
+		FullName:    "Grantapher_and_co-4-0.9.9",
+		Description: "Improves the game",
+		WebsiteURL:  "https://www.nexusmods.com/valheim/mods/4",
+		DownloadURL: "https://www.nexusmods.com/valheim/mods/4?tab=files&file_id=11",
+		ID:          "11",
+	}
+	l := pkg.Latest
+	if l.Namespace != expected.Namespace || l.Name != expected.Name || l.Version != expected.Version || l.FullName != expected.FullName ||
+		l.Description != expected.Description || l.WebsiteURL != expected.WebsiteURL || l.DownloadURL != expected.DownloadURL || l.ID != expected.ID {
+		t.Errorf("expected the newest main file: %+v, received: %+v", expected, l)
+	}
+}
+
+func TestGetPackage_Sad(t *testing.T) {
+	server := newTestNexus(t)
+
+	tests := map[string]struct {
+		key      string
+		id       string
+		expected error
+	}{
+		"return an error if the API key is missing": {
+			key:      "",
+			id:       "4",
+			expected: nexus.ErrMissingAPIKey,
+		},
+		"return an error if the API key is rejected": {
+			key:      "wrong-key",
+			id:       "4",
+			expected: nexus.ErrInvalidAPIKey,
+		},
+		"return an error if the mod ID isn't a number": {
+			key:      testAPIKey,
+			id:       "Sleepover",
+			expected: nexus.ErrInvalidModID,
+		},
+		"return an error if the mod doesn't exist": {
+			key:      testAPIKey,
+			id:       "5",
+			expected: nexus.ErrModNotFound,
+		},
+		"return an error if the API fails": {
+			key:      testAPIKey,
+			id:       "500",
+			expected: nexus.ErrNexusAPI,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := nexus.New(server.Client(), server.URL, test.key)
+
+			_, err := n.GetPackage("", test.id)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestGetRelease_Happy(t *testing.T) {
+	server := newTestNexus(t)
+	n := nexus.New(server.Client(), server.URL, testAPIKey)
+
+	release, err := n.GetRelease("Grantapher_and_co", "4", "0.9.8")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if release.ID != "10" || release.FullName != "Grantapher_and_co-4-0.9.8" {
+		t.Errorf("expected the release's file, received: %+v", release)
+	}
+}
+
+func TestGetRelease_Sad(t *testing.T) {
+	server := newTestNexus(t)
+	n := nexus.New(server.Client(), server.URL, testAPIKey)
+
+	_, err := n.GetRelease("Grantapher_and_co", "4", "2.0.0")
+	if !errors.Is(err, source.ErrReleaseNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", source.ErrReleaseNotFound, err)
+	}
+}
+
+func TestDownloadURL_Happy(t *testing.T) {
+	server := newTestNexus(t)
+	n := nexus.New(server.Client(), server.URL, testAPIKey)
+
+	pkg, err := n.GetPackage("", "4")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	url, err := n.DownloadURL(pkg.Latest)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if url != server.URL+"/cdn/ValheimPlus.zip" {
+		t.Errorf("expected the file's download link, received: %s", url)
+	}
+
+	size, err := n.GetDownloadSize(pkg.Latest)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if size != 2048 {
+		t.Errorf("expected size: 2048, received: %d", size)
+	}
+}
+
+// newTestNexus stands in for the Nexus Mods API, with a single mod that has two releases
+func newTestNexus(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	responses := map[string]any{
+		"/games/valheim/mods/4.json": map[string]any{
+			"mod_id":            4,
+			"name":              "Valheim Plus",
+			"summary":           "Improves the game",
+			"version":           "0.9.9",
+			"author":            "Grantapher and co",
+			"updated_timestamp": 1700000000,
+			"available":         true,
+			"status":            "published",
+		},
+		"/games/valheim/mods/4/files.json": map[string]any{
+			"files": []map[string]any{
+				{"file_id": 10, "version": "0.9.8", "category_name": "OLD_VERSION", "uploaded_timestamp": 100, "size_in_bytes": 1024},
+				{"file_id": 11, "version": "0.9.9", "category_name": "MAIN", "uploaded_timestamp": 200, "size_in_bytes": 2048},
+				{"file_id": 12, "version": "1.0.0 beta", "category_name": "OPTIONAL", "uploaded_timestamp": 300, "size_in_bytes": 4096},
+			},
+		},
+		"/games/valheim/mods/4/files/11/download_link.json": func() any {
+			return []map[string]string{{"name": "Nexus CDN", "short_name": "Nexus", "URI": server.URL + "/cdn/ValheimPlus.zip"}}
+		},
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("apikey") != testAPIKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/games/valheim/mods/500.json" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if f, ok := response.(func() any); ok {
+			response = f()
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
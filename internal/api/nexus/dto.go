@@ -0,0 +1,38 @@
+package nexus
+
+// modInfo is a mod's page on Nexus Mods. Its files are listed separately.
+type modInfo struct {
+	ModID            int    `json:"mod_id"`
+	Name             string `json:"name"`
+	Summary          string `json:"summary"`
+	Version          string `json:"version"`
+	Author           string `json:"author"`
+	UploadedBy       string `json:"uploaded_by"`
+	UpdatedTimestamp int64  `json:"updated_timestamp"`
+	Available        bool   `json:"available"`
+	Status           string `json:"status"`
+}
+
+type fileList struct {
+	Files []modFile `json:"files"`
+}
+
+// modFile is a file uploaded for a mod. Every release of a mod is a file, and mods can have
+// optional files alongside their main one.
+type modFile struct {
+	FileID            int64  `json:"file_id"`
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	CategoryName      string `json:"category_name"`
+	IsPrimary         bool   `json:"is_primary"`
+	SizeInBytes       int64  `json:"size_in_bytes"`
+	FileName          string `json:"file_name"`
+	UploadedTimestamp int64  `json:"uploaded_timestamp"`
+}
+
+// downloadLink is one of the CDN links a file can be downloaded from
+type downloadLink struct {
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	URI       string `json:"URI"`
+}
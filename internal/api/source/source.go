@@ -0,0 +1,89 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The sources mods can be installed from
+const (
+	Thunderstore = "thunderstore"
+	Nexus        = "nexus"
+
+	// Mods that aren't listed anywhere, e.g. private plugins, are added straight from their
+	// archive. Those added from a local file can't be updated, but those added from a URL are
+	// fetched again to check for a new version.
+	File = "file"
+	URL  = "url"
+)
+
+var (
+	ErrUnknownSource   = errors.New("unknown mod source, must be thunderstore or nexus")
+	ErrReleaseNotFound = errors.New("mod release was not found")
+)
+
+// Source is anywhere mods can be looked up and downloaded from. Every mod records the source it
+// was installed from, so it's updated from the same place.
+type Source interface {
+	// Looks up a mod along with its latest release. How a mod is identified depends on the
+	// source, e.g. Nexus Mods only needs the mod's ID as its name.
+	GetPackage(namespace, name string) (Package, error)
+
+	// Looks up a specific release of a mod
+	GetRelease(namespace, name, version string) (Release, error)
+
+	// Returns where a release's files can be downloaded from. Some sources only hand out links
+	// that expire, so this is called right before downloading.
+	DownloadURL(release Release) (string, error)
+
+	// Returns the size in bytes of a release's download, without downloading it
+	GetDownloadSize(release Release) (int64, error)
+}
+
+// Sources are every source mods can be installed from, by name
+type Sources map[string]Source
+
+// Get returns the named source. Mods recorded before sources existed don't have one, and were
+// all installed from Thunderstore.
+func (s Sources) Get(name string) (Source, error) {
+	if name == "" {
+		name = Thunderstore
+	}
+	src, ok := s[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSource, name)
+	}
+	return src, nil
+}
+
+// A Package is a mod, as it's listed by its source
+type Package struct {
+	Source     string
+	Namespace  string
+	Name       string
+	Latest     Release
+	UpdatedAt  time.Time
+	Categories []string
+	Deprecated bool
+}
+
+// A Release is a specific version of a Package
+type Release struct {
+	Namespace   string
+	Name        string
+	Version     string
+	FullName    string
+	Description string
+	WebsiteURL  string
+
+	// Other mods the release needs, as Thunderstore namespace-name-version strings
+	Dependencies []string
+
+	// Where the release is downloaded from. Sources that only hand out expiring links use the
+	// release's page instead, and the link is looked up with Source.DownloadURL.
+	DownloadURL string
+
+	// Identifies the release to its source, e.g. a Nexus Mods file ID
+	ID string
+}
@@ -0,0 +1,54 @@
+package source_test
+
+import (
+	"errors"
+	"testing"
+	"warden/internal/api/source"
+)
+
+type testSource struct {
+	source.Source
+	name string
+}
+
+func TestGet_Happy(t *testing.T) {
+	sources := source.Sources{
+		source.Thunderstore: &testSource{name: source.Thunderstore},
+		source.Nexus:        &testSource{name: source.Nexus},
+	}
+
+	tests := map[string]struct {
+		name     string
+		expected string
+	}{
+		"return the named source": {
+			name:     source.Nexus,
+			expected: source.Nexus,
+		},
+		"return Thunderstore for mods recorded without a source": {
+			name:     "",
+			expected: source.Thunderstore,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			src, err := sources.Get(test.name)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if src.(*testSource).name != test.expected {
+				t.Errorf("expected source: %s, received: %s", test.expected, src.(*testSource).name)
+			}
+		})
+	}
+}
+
+func TestGet_Sad(t *testing.T) {
+	sources := source.Sources{source.Thunderstore: &testSource{name: source.Thunderstore}}
+
+	_, err := sources.Get("curseforge")
+	if !errors.Is(err, source.ErrUnknownSource) {
+		t.Errorf("expected error: %+v, received: %+v", source.ErrUnknownSource, err)
+	}
+}
@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"warden/internal/api"
 )
 
 const (
-	thunderstoreAPI = "https://thunderstore.io/api"
+	// Thunderstore itself. Self-hosted Thunderstore instances and mirrors serve the same API from
+	// their own URL.
+	URL = "https://thunderstore.io"
+
+	thunderstoreAPI = "/api"
 	experimental    = "/experimental"
 	packageAPI      = "/package"
 )
@@ -18,57 +24,97 @@ const (
 var (
 	ErrPackageNotFound = errors.New("mod package was not found")
 	ErrThunderstoreAPI = errors.New("Thunderstore API returned an unexpected error")
+
+	ErrUnknownDownloadSize = errors.New("download size was not provided")
 )
 
 // Interface for Thunderstore's API for Valheim mods. See docs: https://thunderstore.io/c/valheim/create/docs/
 type Thunderstore interface {
 	GetPackage(namespace, name string) (Package, error)
+
+	// Returns a specific release of a package, which doesn't have to be the latest one
+	GetRelease(namespace, name, version string) (Release, error)
+
+	// Returns the size in bytes of a release's download, without downloading it
+	GetDownloadSize(url string) (int64, error)
 }
 
 type thunderstore struct {
 	client api.HTTPClient
+	url    string
 }
 
-func New(c api.HTTPClient) Thunderstore {
+// New creates a client for the Thunderstore registry at the given base URL, usually URL. Private
+// registries can need a token, which is only ever sent to the registry's own host. Leave it empty
+// for public ones.
+func New(c api.HTTPClient, baseURL, token string) Thunderstore {
+	baseURL = strings.TrimSuffix(baseURL, "/")
 	return &thunderstore{
-		client: c,
+		client: NewTokenClient(c, map[string]string{baseURL: token}),
+		url:    baseURL,
 	}
 }
 
 func (ts *thunderstore) GetPackage(namespace, name string) (Package, error) {
-	url := fmt.Sprintf(thunderstoreAPI+experimental+packageAPI+"/%s/%s", namespace, name)
+	url := fmt.Sprintf(ts.url+thunderstoreAPI+experimental+packageAPI+"/%s/%s", namespace, name)
+	return get(ts, url, Package{})
+}
+
+func (ts *thunderstore) GetRelease(namespace, name, version string) (Release, error) {
+	url := fmt.Sprintf(ts.url+thunderstoreAPI+experimental+packageAPI+"/%s/%s/%s", namespace, name, version)
+	return get(ts, url, Release{})
+}
+
+func (ts *thunderstore) GetDownloadSize(url string) (int64, error) {
+	response, err := ts.client.Head(url)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%w: status %d", ErrThunderstoreAPI, response.StatusCode)
+	}
+	if response.ContentLength < 0 {
+		return 0, ErrUnknownDownloadSize
+	}
+	return response.ContentLength, nil
+}
 
+// get fetches a package or release from the API. Both are decoded into obj.
+func get[T any](ts *thunderstore, url string, obj T) (T, error) {
+	var empty T
 	response, err := ts.client.Get(url)
 	if err != nil {
-		return Package{}, api.ErrHTTPClient
+		return empty, fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
 	}
 	defer response.Body.Close()
 
 	data, err := io.ReadAll(response.Body)
 	if err != nil {
-		return Package{}, api.ErrByteIO
+		return empty, fmt.Errorf("%w: %w", api.ErrByteIO, err)
 	}
 
 	switch response.StatusCode {
 	case http.StatusOK:
-		pkg, err := deserializeJSON(data, Package{})
+		result, err := deserializeJSON(data, obj)
 		if err != nil {
-			return Package{}, api.ErrJSONParse
+			return empty, fmt.Errorf("%w: %w", api.ErrJSONParse, err)
 		}
-		return pkg, nil
+		return result, nil
 	case http.StatusNotFound:
 		// API currently doesn't return any useful data, so we'll ignore the error response body for now
-		return Package{}, ErrPackageNotFound
+		return empty, ErrPackageNotFound
 	default:
 		// API currently doesn't return any useful data, so we'll ignore the error response body for now
-		return Package{}, ErrThunderstoreAPI
+		return empty, fmt.Errorf("%w: status %d", ErrThunderstoreAPI, response.StatusCode)
 	}
 }
 
 func deserializeJSON[T any](data []byte, obj T) (T, error) {
 	err := json.Unmarshal(data, &obj)
 	if err != nil {
-		fmt.Println("... ERROR: unable to deserialize Thunderstore API response from JSON...")
+		fmt.Fprintln(os.Stderr, "... ERROR: unable to deserialize Thunderstore API response from JSON...")
 		return obj, err
 	}
 	return obj, nil
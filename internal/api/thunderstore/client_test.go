@@ -31,7 +31,7 @@ func TestGetPackage_Happy(t *testing.T) {
 		},
 	}
 
-	ts := thunderstore.New(&client)
+	ts := thunderstore.New(&client, thunderstore.URL, "")
 
 	result, err := ts.GetPackage(namespace, name)
 	if err != nil {
@@ -101,7 +101,7 @@ func TestGetPackage_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ts := thunderstore.New(test.client)
+			ts := thunderstore.New(test.client, thunderstore.URL, "")
 
 			result, err := ts.GetPackage("Azumatt", "Sleepover")
 			if !errors.Is(err, test.expectedErr) {
@@ -114,3 +114,151 @@ func TestGetPackage_Sad(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRelease_Happy(t *testing.T) {
+	expected := thunderstore.Release{
+		Namespace:     "Azumatt",
+		Name:          "Sleepover",
+		VersionNumber: "1.0.0",
+		FullName:      "Azumatt-Sleepover-1.0.0",
+	}
+	body, err := mock.ResponseBodyToReader(expected)
+	if err != nil {
+		t.Errorf("failed to mock JSON response, received error: %v", err)
+	}
+
+	var requested string
+	client := mock.HTTPClient{
+		GetFunc: func(url string) (*http.Response, error) {
+			requested = url
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       body,
+			}, nil
+		},
+	}
+	ts := thunderstore.New(&client, thunderstore.URL, "")
+
+	result, err := ts.GetRelease("Azumatt", "Sleepover", "1.0.0")
+	if err != nil {
+		t.Errorf("expected a nil error, got: %v", err)
+	}
+	if !result.Equals(&expected) {
+		t.Errorf("expected Release: %+v, received: %+v", expected, result)
+	}
+	if requested != "https://thunderstore.io/api/experimental/package/Azumatt/Sleepover/1.0.0" {
+		t.Errorf("expected the release to be requested, received: %s", requested)
+	}
+}
+
+func TestGetDownloadSize_Happy(t *testing.T) {
+	client := mock.HTTPClient{
+		HeadFunc: func(_ string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				ContentLength: 98600,
+				Body:          io.NopCloser(bytes.NewReader([]byte{})),
+			}, nil
+		},
+	}
+	ts := thunderstore.New(&client, thunderstore.URL, "")
+
+	size, err := ts.GetDownloadSize("testurl.com/file")
+	if err != nil {
+		t.Errorf("expected a nil error, got: %v", err)
+	}
+	if size != 98600 {
+		t.Errorf("expected size: 98600, received: %d", size)
+	}
+}
+
+func TestGetDownloadSize_Sad(t *testing.T) {
+	tests := map[string]struct {
+		client      api.HTTPClient
+		expectedErr error
+	}{
+		"HTTP client fails to send request": {
+			client: &mock.HTTPClient{
+				HeadFunc: func(_ string) (*http.Response, error) {
+					return &http.Response{}, http.ErrBodyNotAllowed
+				},
+			},
+			expectedErr: api.ErrHTTPClient,
+		},
+		"Thunderstore returns a non-2xx response": {
+			client: &mock.HTTPClient{
+				HeadFunc: func(_ string) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(bytes.NewReader([]byte{})),
+					}, nil
+				},
+			},
+			expectedErr: thunderstore.ErrThunderstoreAPI,
+		},
+		"Thunderstore doesn't provide a content length": {
+			client: &mock.HTTPClient{
+				HeadFunc: func(_ string) (*http.Response, error) {
+					return &http.Response{
+						StatusCode:    http.StatusOK,
+						ContentLength: -1,
+						Body:          io.NopCloser(bytes.NewReader([]byte{})),
+					}, nil
+				},
+			},
+			expectedErr: thunderstore.ErrUnknownDownloadSize,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := thunderstore.New(test.client, thunderstore.URL, "")
+
+			_, err := ts.GetDownloadSize("testurl.com/file")
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("expected error: %+v, got: %+v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGetPackage_Token(t *testing.T) {
+	body, err := mock.ResponseBodyToReader(thunderstore.Package{Namespace: "Azumatt", Name: "Sleepover"})
+	if err != nil {
+		t.Errorf("failed to mock JSON response, received error: %v", err)
+	}
+
+	var requested *http.Request
+	client := mock.HTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requested = req
+			return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+		},
+	}
+	ts := thunderstore.New(&client, "https://mods.example.com/", "secret")
+
+	if _, err := ts.GetPackage("Azumatt", "Sleepover"); err != nil {
+		t.Fatalf("expected a nil error, got: %v", err)
+	}
+	if requested.URL.String() != "https://mods.example.com/api/experimental/package/Azumatt/Sleepover" {
+		t.Errorf("expected the package to be requested from the registry, received: %s", requested.URL)
+	}
+	if requested.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expected the registry's token to be sent, received: %q", requested.Header.Get("Authorization"))
+	}
+}
+
+func TestGetDownloadSize_TokenNotSent(t *testing.T) {
+	client := mock.HTTPClient{
+		// A request with the token would go through Do, which panics
+		HeadFunc: func(_ string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, ContentLength: 1024, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		},
+	}
+	ts := thunderstore.New(&client, "https://mods.example.com", "secret")
+
+	size, err := ts.GetDownloadSize("https://cdn.example.com/Azumatt-Sleepover-1.0.0.zip")
+	if err != nil || size != 1024 {
+		t.Errorf("expected the size of a download hosted elsewhere, received: %d, %v", size, err)
+	}
+}
@@ -2,6 +2,9 @@ package thunderstore
 
 import "slices"
 
+// Community is the name Thunderstore lists Valheim mods under
+const Community = "valheim"
+
 // Package is the top level definition of a mod. It contains data about the mod, its different releases, user ratings, etc..
 type Package struct {
 	Namespace         string    `json:"namespace"`
@@ -0,0 +1,53 @@
+package thunderstore
+
+import "errors"
+
+// registries looks packages up in several Thunderstore registries, e.g. an internal mirror before
+// Thunderstore itself
+type registries []Thunderstore
+
+// NewRegistries combines registries into one, which tries each of them in the given order until
+// one has the package. A registry that can't be reached is skipped just like one that doesn't have
+// the package, and the errors from every registry are returned if none of them do.
+func NewRegistries(r ...Thunderstore) Thunderstore {
+	if len(r) == 1 {
+		return r[0]
+	}
+	return registries(r)
+}
+
+func (r registries) GetPackage(namespace, name string) (Package, error) {
+	return first(r, func(ts Thunderstore) (Package, error) {
+		return ts.GetPackage(namespace, name)
+	})
+}
+
+func (r registries) GetRelease(namespace, name, version string) (Release, error) {
+	return first(r, func(ts Thunderstore) (Release, error) {
+		return ts.GetRelease(namespace, name, version)
+	})
+}
+
+func (r registries) GetDownloadSize(url string) (int64, error) {
+	return first(r, func(ts Thunderstore) (int64, error) {
+		return ts.GetDownloadSize(url)
+	})
+}
+
+// first returns the result from the first registry that doesn't fail
+func first[T any](r registries, f func(ts Thunderstore) (T, error)) (T, error) {
+	var empty T
+	if len(r) == 0 {
+		return empty, ErrPackageNotFound
+	}
+
+	errs := []error{}
+	for _, ts := range r {
+		result, err := f(ts)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, err)
+	}
+	return empty, errors.Join(errs...)
+}
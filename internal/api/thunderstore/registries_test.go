@@ -0,0 +1,61 @@
+package thunderstore_test
+
+import (
+	"errors"
+	"testing"
+	"warden/internal/api"
+	"warden/internal/api/thunderstore"
+	"warden/internal/test/mock"
+)
+
+func TestRegistries_Happy(t *testing.T) {
+	tests := map[string]error{
+		"fall through to the next registry if the package isn't found": thunderstore.ErrPackageNotFound,
+		"fall through to the next registry if one can't be reached":    api.ErrHTTPClient,
+	}
+
+	for name, failure := range tests {
+		t.Run(name, func(t *testing.T) {
+			mirror := &mock.Thunderstore{
+				GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+					return thunderstore.Package{}, failure
+				},
+			}
+			public := &mock.Thunderstore{
+				GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+					return thunderstore.Package{Namespace: namespace, Name: name}, nil
+				},
+			}
+			ts := thunderstore.NewRegistries(mirror, public)
+
+			pkg, err := ts.GetPackage("Azumatt", "Sleepover")
+			if err != nil {
+				t.Fatalf("expected a nil error, received: %+v", err)
+			}
+			if pkg.Name != "Sleepover" {
+				t.Errorf("expected the package from the second registry, received: %+v", pkg)
+			}
+		})
+	}
+}
+
+func TestRegistries_Sad(t *testing.T) {
+	calls := 0
+	registry := func(err error) *mock.Thunderstore {
+		return &mock.Thunderstore{
+			GetReleaseFunc: func(namespace, name, version string) (thunderstore.Release, error) {
+				calls++
+				return thunderstore.Release{}, err
+			},
+		}
+	}
+	ts := thunderstore.NewRegistries(registry(thunderstore.ErrPackageNotFound), registry(thunderstore.ErrThunderstoreAPI))
+
+	_, err := ts.GetRelease("Azumatt", "Sleepover", "1.0.0")
+	if !errors.Is(err, thunderstore.ErrPackageNotFound) || !errors.Is(err, thunderstore.ErrThunderstoreAPI) {
+		t.Errorf("expected the errors from every registry, received: %+v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected every registry to be tried, received: %d calls", calls)
+	}
+}
@@ -0,0 +1,82 @@
+package thunderstore
+
+import (
+	"time"
+	"warden/internal/api/source"
+)
+
+// thunderstoreSource installs mods from Thunderstore, the source every mod came from before there
+// were others
+type thunderstoreSource struct {
+	ts Thunderstore
+}
+
+// NewSource lets mods be installed from Thunderstore as a source.Source
+func NewSource(ts Thunderstore) source.Source {
+	return &thunderstoreSource{ts: ts}
+}
+
+func (s *thunderstoreSource) GetPackage(namespace, name string) (source.Package, error) {
+	pkg, err := s.ts.GetPackage(namespace, name)
+	if err != nil {
+		return source.Package{}, err
+	}
+	return toSourcePackage(pkg), nil
+}
+
+func (s *thunderstoreSource) GetRelease(namespace, name, version string) (source.Release, error) {
+	release, err := s.ts.GetRelease(namespace, name, version)
+	if err != nil {
+		return source.Release{}, err
+	}
+	return toSourceRelease(release), nil
+}
+
+func (s *thunderstoreSource) DownloadURL(release source.Release) (string, error) {
+	return release.DownloadURL, nil
+}
+
+func (s *thunderstoreSource) GetDownloadSize(release source.Release) (int64, error) {
+	return s.ts.GetDownloadSize(release.DownloadURL)
+}
+
+// toSourcePackage converts a package from the Thunderstore API into a source.Package
+func toSourcePackage(pkg Package) source.Package {
+	// The update time is only used to warn about outdated mods, so an unknown one is left empty
+	updatedAt, err := time.Parse(time.RFC3339, pkg.DateUpdated)
+	if err != nil {
+		updatedAt = time.Time{}
+	}
+
+	categories := []string{}
+	for _, l := range pkg.CommunityListings {
+		if l.Community == Community {
+			categories = l.Categories
+			break
+		}
+	}
+
+	return source.Package{
+		Source:     source.Thunderstore,
+		Namespace:  pkg.Namespace,
+		Name:       pkg.Name,
+		Latest:     toSourceRelease(pkg.Latest),
+		UpdatedAt:  updatedAt,
+		Categories: categories,
+		Deprecated: pkg.IsDeprecated,
+	}
+}
+
+// toSourceRelease converts a release from the Thunderstore API into a source.Release
+func toSourceRelease(r Release) source.Release {
+	return source.Release{
+		Namespace:    r.Namespace,
+		Name:         r.Name,
+		Version:      r.VersionNumber,
+		FullName:     r.FullName,
+		Description:  r.Description,
+		WebsiteURL:   r.WebsiteURL,
+		Dependencies: r.Dependencies,
+		DownloadURL:  r.DownloadURL,
+	}
+}
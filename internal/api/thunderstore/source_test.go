@@ -0,0 +1,56 @@
+package thunderstore_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"warden/internal/api/source"
+	"warden/internal/api/thunderstore"
+	"warden/internal/test/mock"
+)
+
+func TestSourceGetPackage_Happy(t *testing.T) {
+	ts := &mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			return thunderstore.Package{
+				Namespace:    namespace,
+				Name:         name,
+				DateUpdated:  "2024-02-03T18:30:00.000000Z",
+				IsDeprecated: true,
+				Latest: thunderstore.Release{
+					Namespace:     namespace,
+					Name:          name,
+					VersionNumber: "1.0.1",
+					FullName:      "Azumatt-Sleepover-1.0.1",
+					Dependencies:  []string{"Azumatt-AzuClock-1.0.0"},
+					DownloadURL:   "https://thunderstore.io/package/download/Azumatt/Sleepover/1.0.1/",
+				},
+				CommunityListings: []thunderstore.Listing{
+					{Community: "lethal-company", Categories: []string{"Suits"}},
+					{Community: thunderstore.Community, Categories: []string{"Server-side"}},
+				},
+			}, nil
+		},
+	}
+	src := thunderstore.NewSource(ts)
+
+	pkg, err := src.GetPackage("Azumatt", "Sleepover")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	if pkg.Source != source.Thunderstore || !pkg.Deprecated || !slices.Equal(pkg.Categories, []string{"Server-side"}) {
+		t.Errorf("expected the package's Valheim listing, received: %+v", pkg)
+	}
+	if !pkg.UpdatedAt.Equal(time.Date(2024, 2, 3, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected the package's update time, received: %s", pkg.UpdatedAt)
+	}
+	if pkg.Latest.Version != "1.0.1" || pkg.Latest.FullName != "Azumatt-Sleepover-1.0.1" || !slices.Equal(pkg.Latest.Dependencies, []string{"Azumatt-AzuClock-1.0.0"}) {
+		t.Errorf("expected the latest release, received: %+v", pkg.Latest)
+	}
+
+	url, err := src.DownloadURL(pkg.Latest)
+	if err != nil || url != pkg.Latest.DownloadURL {
+		t.Errorf("expected the release to be downloaded from %s, received: %s, %+v", pkg.Latest.DownloadURL, url, err)
+	}
+}
@@ -0,0 +1,67 @@
+package thunderstore
+
+import (
+	"net/http"
+	"net/url"
+	"warden/internal/api"
+)
+
+// tokenClient sends each registry's token with requests to the registry's own host. Requests
+// anywhere else, e.g. downloads hosted on a CDN, are never given a token.
+type tokenClient struct {
+	client api.HTTPClient
+	tokens map[string]string
+}
+
+// NewTokenClient wraps an HTTP client so requests to a private registry are sent with its token,
+// including downloads of the registry's archives. Tokens are given by the registry's base URL.
+func NewTokenClient(c api.HTTPClient, tokens map[string]string) api.HTTPClient {
+	byOrigin := map[string]string{}
+	for baseURL, token := range tokens {
+		if token != "" {
+			byOrigin[origin(baseURL)] = token
+		}
+	}
+	return &tokenClient{client: c, tokens: byOrigin}
+}
+
+func (tc *tokenClient) Get(target string) (*http.Response, error) {
+	return tc.send(http.MethodGet, target)
+}
+
+func (tc *tokenClient) Head(target string) (*http.Response, error) {
+	return tc.send(http.MethodHead, target)
+}
+
+func (tc *tokenClient) Do(req *http.Request) (*http.Response, error) {
+	if token, ok := tc.tokens[origin(req.URL.String())]; ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return tc.client.Do(req)
+}
+
+// send makes a request, only building one by hand when it needs the token
+func (tc *tokenClient) send(method, target string) (*http.Response, error) {
+	if _, ok := tc.tokens[origin(target)]; !ok {
+		if method == http.MethodHead {
+			return tc.client.Head(target)
+		}
+		return tc.client.Get(target)
+	}
+
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tc.Do(req)
+}
+
+// origin returns the scheme, host and port of a URL, which a token is scoped to. URLs that can't
+// be parsed have no origin, so they're never given a token.
+func origin(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -22,11 +25,68 @@ const (
 	DefaultSteamLinuxInstallPath   = "~/.steam/SteamApps/common/Valheim dedicated server"
 	DefaultSteamMacOSInstallPath   = "/Library/Application Support/Steam/steamapps/common/Valheim dedicated server"
 	DefaultSteamWindowsInstallPath = "C:\\Program Files (x86)\\Steam\\steamapps\\common\\Valheim Dedicated Server"
+
+	// Valheim stores its worlds, admin lists, etc. in a per-user save directory outside of the
+	// server installation
+	DefaultLinuxSavePath   = "~/.config/unity3d/IronGate/Valheim"
+	DefaultMacOSSavePath   = "~/Library/Application Support/unity.IronGate.Valheim"
+	DefaultWindowsSavePath = "~\\AppData\\LocalLow\\IronGate\\Valheim"
+
+	// Warden keeps its world backups, database and cache next to the config file
+	DefaultBackupDirectory = ".warden-backups"
+	DefaultDatabaseFile    = ".warden.db"
+	DefaultCacheDirectory  = ".warden-cache"
+
+	// The supervised server's PID and output are kept next to the config file too
+	PIDFile       = ".warden.pid"
+	ServerLogFile = ".warden-server.log"
+
+	// Mod profiles, and the config files saved with them, are kept in this directory next to the
+	// config file
+	ProfileDirectory = ".warden-profiles"
+
+	// BepInEx loads mods from this sub-directory of the Valheim directory
+	DefaultModDirectory = "BepInEx/plugins"
+
+	// Set to keep Warden's config file, and everything stored next to it by default, somewhere
+	// other than the home directory
+	ConfigDirectoryEnv = "WARDEN_CONFIG_DIR"
+
+	// Set to pick which server instance Warden manages, when the --instance flag isn't used
+	InstanceEnv = "WARDEN_INSTANCE"
+
+	DefaultBackupKeepLast   = 10
+	DefaultBackupKeepDaily  = 7
+	DefaultBackupKeepWeekly = 4
+
+	// The supervisor gives up if the server crashes this many times within the crash window
+	DefaultSuperviseMaxCrashes  = 5
+	DefaultSuperviseCrashWindow = 10 * time.Minute
+	DefaultSuperviseBackoff     = 5 * time.Second
+
+	// Supervised server logs are rotated once they reach the max size, in megabytes
+	DefaultLogMaxSize  = 10
+	DefaultLogMaxFiles = 5
+
+	// The Valheim server is private by default, so it can start without a password
+	DefaultServerName  = "My server"
+	DefaultServerPort  = 2456
+	DefaultServerWorld = "Dedicated"
+
+	// Scheduled restarts are announced this long before they happen
+	DefaultScheduleRestartDelay = 5 * time.Minute
+
+	// Modded servers can take a few minutes to load every plugin and the world
+	DefaultVerifyTimeout = 5 * time.Minute
+
+	// SteamCMD is found on the PATH unless it's configured
+	DefaultSteamCMDPath = "steamcmd"
 )
 
 var (
 	ErrUnableToWriteConfig = errors.New("unable to write to config file")
 	ErrFailedToReadConfig  = errors.New("unable to read config from YAML file")
+	ErrInvalidRegistry     = errors.New("every thunderstore registry needs a url")
 )
 
 // Config represents all configurable values needed to make warden work, e.g. the directory
@@ -35,46 +95,249 @@ type Config struct {
 	// The directory containing all of the Valheim server files
 	ValheimDirectory string `mapstructure:"valheim-directory"`
 
+	// The directory mods are installed to. A relative path is inside the Valheim directory, and an
+	// absolute one has to be inside it too, since BepInEx only loads plugins from there.
+	ModDirectory string `mapstructure:"mod-directory"`
+
+	// The directory the config file is in. It's picked with WARDEN_CONFIG_DIR rather than stored
+	// in the file itself.
+	ConfigDirectory string `mapstructure:"-"`
+
+	// The name of the server instance the config belongs to, or an empty string for the default
+	// instance. Like the config directory, it's picked rather than stored.
+	Instance string `mapstructure:"-"`
+
+	// The SQLite database mods and job runs are recorded in
+	DatabasePath string `mapstructure:"database-path"`
+
+	// The directory mods are downloaded to, and files are copied to while they're being changed
+	CacheDirectory string `mapstructure:"cache-directory"`
+
 	// The type of operating system the server is running on, e.g. Windows, Linux, or macOS
 	Platform string `mapstructure:"platform"`
+
+	// The directory Valheim saves worlds and player lists to
+	SaveDirectory string `mapstructure:"save-directory"`
+
+	// The directory world backups are stored in
+	BackupDirectory string `mapstructure:"backup-directory"`
+
+	// How many of the most recent world backups to keep
+	BackupKeepLast int `mapstructure:"backup-keep-last"`
+
+	// How many days to keep the newest daily world backup for
+	BackupKeepDaily int `mapstructure:"backup-keep-daily"`
+
+	// How many weeks to keep the newest weekly world backup for
+	BackupKeepWeekly int `mapstructure:"backup-keep-weekly"`
+
+	// How many times the supervised server can crash within the crash window before the
+	// supervisor gives up. Zero means it never gives up.
+	SuperviseMaxCrashes int `mapstructure:"supervise-max-crashes"`
+
+	// How far back crashes are counted towards the crash-loop limit, e.g. "10m"
+	SuperviseCrashWindow time.Duration `mapstructure:"supervise-crash-window"`
+
+	// How long to wait before the first restart after a crash. It doubles after each
+	// crash in a row.
+	SuperviseBackoff time.Duration `mapstructure:"supervise-backoff"`
+
+	// The size in megabytes a supervised server log can grow to before it's rotated
+	LogMaxSize int `mapstructure:"log-max-size"`
+
+	// How many server log files to keep, including the current one
+	LogMaxFiles int `mapstructure:"log-max-files"`
+
+	// The name the Valheim server is listed under
+	ServerName string `mapstructure:"server-name"`
+
+	// The UDP port the Valheim server listens on. It also uses the port after it.
+	ServerPort int `mapstructure:"server-port"`
+
+	// The name of the world the Valheim server loads, or creates if it doesn't exist
+	ServerWorld string `mapstructure:"server-world"`
+
+	// The password players need to join. Required if the server is public.
+	ServerPassword string `mapstructure:"server-password"`
+
+	// Whether the server is listed in the public server browser
+	ServerPublic bool `mapstructure:"server-public"`
+
+	// Whether players on other platforms, e.g. Xbox, can join
+	ServerCrossplay bool `mapstructure:"server-crossplay"`
+
+	// The world preset, e.g. "hard". Valheim's default is used if it's empty.
+	ServerPreset string `mapstructure:"server-preset"`
+
+	// Individual world modifiers, e.g. "combat=veryhard,raids=none"
+	ServerModifiers []string `mapstructure:"server-modifiers"`
+
+	// Cron expressions for when `warden daemon` backs up worlds, updates every mod, and restarts
+	// the server, e.g. "0 4 * * *" for 4am every day. Jobs without an expression never run.
+	ScheduleBackup  string `mapstructure:"schedule-backup"`
+	ScheduleUpdate  string `mapstructure:"schedule-update"`
+	ScheduleRestart string `mapstructure:"schedule-restart"`
+
+	// How long a scheduled restart waits after it's announced, giving players time to log off
+	ScheduleRestartDelay time.Duration `mapstructure:"schedule-restart-delay"`
+
+	// How long `update --verify-start` waits for the world to load before rolling the update back
+	VerifyTimeout time.Duration `mapstructure:"verify-timeout"`
+
+	// The SteamCMD executable that installs and updates the Valheim server
+	SteamCMDPath string `mapstructure:"steamcmd-path"`
+
+	// The beta branch of the Valheim server to install, e.g. "public-test". The public release is
+	// installed if it's empty.
+	SteamBeta string `mapstructure:"steam-beta"`
+
+	// The personal API key mods are installed from Nexus Mods with. Nexus Mods can't be used
+	// without one.
+	NexusAPIKey string `mapstructure:"nexus-api-key"`
+
+	// Thunderstore-compatible registries to look mods up in, e.g. an internal mirror. Thunderstore
+	// itself is used if there aren't any.
+	ThunderstoreRegistries []Registry `mapstructure:"thunderstore-registries"`
+}
+
+// A Registry is a Thunderstore-compatible API that mods can be installed from, e.g. a self-hosted
+// Thunderstore instance
+type Registry struct {
+	// The registry's base URL, e.g. https://thunderstore.io
+	URL string `mapstructure:"url" yaml:"url"`
+
+	// Sent with every request to a private registry. Public ones don't need one.
+	Token string `mapstructure:"token" yaml:"token,omitempty"`
+
+	// Registries with a higher priority are tried first
+	Priority int `mapstructure:"priority" yaml:"priority"`
+}
+
+// Registries returns the Thunderstore registries in the order they're tried in: highest priority
+// first, then in the order they're listed.
+func (c *Config) Registries() []Registry {
+	registries := slices.Clone(c.ThunderstoreRegistries)
+	slices.SortStableFunc(registries, func(a, b Registry) int {
+		return b.Priority - a.Priority
+	})
+	return registries
 }
 
 // Load creates a new instance of Config, based on a configuration YAML file at the given
 // path. If one doesn't exist, a new file is created with default values
 func Load(path string) (*Config, error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName(configName)
-	viper.SetConfigType(configType)
+	return load(viper.GetViper(), path, true)
+}
 
-	err := viper.ReadInConfig()
+// Read loads the configuration YAML file at the given path, without making it the config that
+// `warden config` shows and changes. The file has to exist already.
+func Read(path string) (*Config, error) {
+	return load(viper.New(), path, false)
+}
+
+// Create writes a new configuration file with the given settings, to the config's directory
+func Create(cfg Config) error {
+	return createConfigFile(viper.New(), &cfg, cfg.ConfigDirectory)
+}
+
+// Default returns the settings a new configuration file at the given path starts with
+func Default(path string) Config {
 	os := runtime.GOOS
 
-	cfg := &Config{
+	return Config{
 		ValheimDirectory: GetInstallPath(os),
+		ModDirectory:     DefaultModDirectory,
+		ConfigDirectory:  path,
+		DatabasePath:     filepath.Join(path, DefaultDatabaseFile),
+		CacheDirectory:   filepath.Join(path, DefaultCacheDirectory),
 		Platform:         os,
+		SaveDirectory:    GetSavePath(os),
+		BackupDirectory:  filepath.Join(path, DefaultBackupDirectory),
+		BackupKeepLast:   DefaultBackupKeepLast,
+		BackupKeepDaily:  DefaultBackupKeepDaily,
+		BackupKeepWeekly: DefaultBackupKeepWeekly,
+
+		SuperviseMaxCrashes:  DefaultSuperviseMaxCrashes,
+		SuperviseCrashWindow: DefaultSuperviseCrashWindow,
+		SuperviseBackoff:     DefaultSuperviseBackoff,
+		LogMaxSize:           DefaultLogMaxSize,
+		LogMaxFiles:          DefaultLogMaxFiles,
+
+		ServerName:  DefaultServerName,
+		ServerPort:  DefaultServerPort,
+		ServerWorld: DefaultServerWorld,
+
+		ScheduleRestartDelay: DefaultScheduleRestartDelay,
+		VerifyTimeout:        DefaultVerifyTimeout,
+
+		SteamCMDPath: DefaultSteamCMDPath,
 	}
+}
+
+func load(v *viper.Viper, path string, create bool) (*Config, error) {
+	v.AddConfigPath(path)
+	v.SetConfigName(configName)
+	v.SetConfigType(configType)
+
+	err := v.ReadInConfig()
+	cfg := Default(path)
 
 	// If config doesn't exist, create the file and add default values
-	if errors.As(err, &viper.ConfigFileNotFoundError{}) {
-		createConfigFile(cfg, path)
+	if errors.As(err, &viper.ConfigFileNotFoundError{}) && create {
+		createConfigFile(v, &cfg, path)
 	} else if err != nil {
 		return nil, ErrFailedToReadConfig
 	}
 
 	// Load in settings from YAML file into Config struct
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, ErrFailedToReadConfig
 	}
-	return cfg, nil
+	for _, r := range cfg.ThunderstoreRegistries {
+		if r.URL == "" {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToReadConfig, ErrInvalidRegistry)
+		}
+	}
+	return &cfg, nil
 }
 
 // Creates a new configuration file called .warden.yaml, with the given settings
-func createConfigFile(cfg *Config, path string) error {
-	viper.Set("valheim-directory", cfg.ValheimDirectory)
-	viper.Set("platform", cfg.Platform)
+func createConfigFile(v *viper.Viper, cfg *Config, path string) error {
+	v.Set("valheim-directory", cfg.ValheimDirectory)
+	v.Set("mod-directory", cfg.ModDirectory)
+	v.Set("database-path", cfg.DatabasePath)
+	v.Set("cache-directory", cfg.CacheDirectory)
+	v.Set("platform", cfg.Platform)
+	v.Set("save-directory", cfg.SaveDirectory)
+	v.Set("backup-directory", cfg.BackupDirectory)
+	v.Set("backup-keep-last", cfg.BackupKeepLast)
+	v.Set("backup-keep-daily", cfg.BackupKeepDaily)
+	v.Set("backup-keep-weekly", cfg.BackupKeepWeekly)
+	v.Set("supervise-max-crashes", cfg.SuperviseMaxCrashes)
+	v.Set("supervise-crash-window", cfg.SuperviseCrashWindow.String())
+	v.Set("supervise-backoff", cfg.SuperviseBackoff.String())
+	v.Set("log-max-size", cfg.LogMaxSize)
+	v.Set("log-max-files", cfg.LogMaxFiles)
+	v.Set("server-name", cfg.ServerName)
+	v.Set("server-port", cfg.ServerPort)
+	v.Set("server-world", cfg.ServerWorld)
+	v.Set("server-password", cfg.ServerPassword)
+	v.Set("server-public", cfg.ServerPublic)
+	v.Set("server-crossplay", cfg.ServerCrossplay)
+	v.Set("server-preset", cfg.ServerPreset)
+	v.Set("server-modifiers", cfg.ServerModifiers)
+	v.Set("schedule-backup", cfg.ScheduleBackup)
+	v.Set("schedule-update", cfg.ScheduleUpdate)
+	v.Set("schedule-restart", cfg.ScheduleRestart)
+	v.Set("schedule-restart-delay", cfg.ScheduleRestartDelay.String())
+	v.Set("verify-timeout", cfg.VerifyTimeout.String())
+	v.Set("steamcmd-path", cfg.SteamCMDPath)
+	v.Set("steam-beta", cfg.SteamBeta)
+	v.Set("nexus-api-key", cfg.NexusAPIKey)
+	v.Set("thunderstore-registries", cfg.ThunderstoreRegistries)
 
 	file := filepath.Join(path, WardenConfigFile)
-	if err := viper.WriteConfigAs(file); err != nil {
+	if err := v.WriteConfigAs(file); err != nil {
 		return ErrUnableToWriteConfig
 	}
 	return nil
@@ -97,3 +360,17 @@ func GetInstallPath(os string) string {
 	}
 	return "" // figure out how to handle a default value
 }
+
+// GetSavePath returns the default location of Valheim's save directory for the given OS
+func GetSavePath(os string) string {
+	switch os {
+	case Windows:
+		return DefaultWindowsSavePath
+	case Linux:
+		return DefaultLinuxSavePath
+	case MacOS:
+		return DefaultMacOSSavePath
+	default:
+		return ""
+	}
+}
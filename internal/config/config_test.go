@@ -6,8 +6,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 	"warden/internal/config"
 
 	"github.com/spf13/viper"
@@ -29,15 +31,26 @@ func TestLoad_Happy(t *testing.T) {
 			expected: config.Config{
 				ValheimDirectory: config.GetInstallPath(os),
 				Platform:         os,
+				SaveDirectory:    config.GetSavePath(os),
+				BackupKeepLast:   config.DefaultBackupKeepLast,
+
+				SuperviseCrashWindow: config.DefaultSuperviseCrashWindow,
+				ServerPort:           config.DefaultServerPort,
 			},
 		},
 		"if config file does exist, load existing values and return success": {
 			setUp: func() error {
-				return createTestConfigFile(t, "valheim-directory: ./test/file\nplatform: linux\n")
+				return createTestConfigFile(t, "valheim-directory: ./test/file\nplatform: linux\nbackup-keep-last: 3\nsupervise-crash-window: 30s\nserver-port: \"2458\"\nserver-modifiers: combat=hard,raids=none\n")
 			},
 			expected: config.Config{
 				ValheimDirectory: "./test/file",
 				Platform:         config.Linux,
+				SaveDirectory:    config.GetSavePath(os),
+				BackupKeepLast:   3,
+
+				SuperviseCrashWindow: 30 * time.Second,
+				ServerPort:           2458,
+				ServerModifiers:      []string{"combat=hard", "raids=none"},
 			},
 		},
 	}
@@ -76,6 +89,12 @@ func TestLoad_Sad(t *testing.T) {
 			},
 			expected: config.ErrFailedToReadConfig,
 		},
+		"if a thunderstore registry has no url, return an error": {
+			setUp: func() error {
+				return createTestConfigFile(t, "thunderstore-registries:\n  - token: secret\n")
+			},
+			expected: config.ErrInvalidRegistry,
+		},
 	}
 
 	for name, test := range tests {
@@ -100,6 +119,37 @@ func TestLoad_Sad(t *testing.T) {
 	}
 }
 
+func TestRegistries(t *testing.T) {
+	t.Cleanup(func() {
+		resetTestConfig(t)
+	})
+	content := `thunderstore-registries:
+  - url: https://thunderstore.io
+  - url: https://mirror.example.com
+    token: secret
+    priority: 10
+  - url: https://mods.example.com
+    priority: 10
+`
+	if err := createTestConfigFile(t, content); err != nil {
+		t.Fatalf("unexpected error on test set-up, received error: %+v", err)
+	}
+
+	cfg, err := config.Load(testConfigPath)
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	expected := []config.Registry{
+		{URL: "https://mirror.example.com", Token: "secret", Priority: 10},
+		{URL: "https://mods.example.com", Priority: 10},
+		{URL: "https://thunderstore.io"},
+	}
+	if registries := cfg.Registries(); !slices.Equal(registries, expected) {
+		t.Errorf("expected registries: %+v, received: %+v", expected, registries)
+	}
+}
+
 func createTestConfigFile(t *testing.T, content string) error {
 	resetTestConfig(t)
 
@@ -138,5 +188,17 @@ func doConfigsMatch(a, b config.Config) bool {
 	if a.Platform != b.Platform {
 		return false
 	}
+	if a.SaveDirectory != b.SaveDirectory {
+		return false
+	}
+	if a.BackupKeepLast != b.BackupKeepLast {
+		return false
+	}
+	if a.SuperviseCrashWindow != b.SuperviseCrashWindow {
+		return false
+	}
+	if a.ServerPort != b.ServerPort || !slices.Equal(a.ServerModifiers, b.ServerModifiers) {
+		return false
+	}
 	return true
 }
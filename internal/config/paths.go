@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+var (
+	ErrPathNotFound       = errors.New("path does not exist")
+	ErrPathNotDirectory   = errors.New("path is not a directory")
+	ErrPathNotWritable    = errors.New("path is not writable")
+	ErrPathOutsideValheim = errors.New("path is not inside the Valheim directory")
+)
+
+// IsPathKey checks if a config key is one of the paths Warden reads and writes files in
+func IsPathKey(key string) bool {
+	switch key {
+	case "valheim-directory", "mod-directory", "save-directory", "backup-directory", "cache-directory", "database-path":
+		return true
+	default:
+		return false
+	}
+}
+
+// Expand returns a copy of the config with ~ expanded in every path, and the mod directory
+// resolved against the Valheim directory. The paths are passed on to other programs, e.g. the
+// game server, which don't expand ~ themselves.
+func (c Config) Expand() (Config, error) {
+	paths := []*string{
+		&c.ValheimDirectory,
+		&c.ConfigDirectory,
+		&c.DatabasePath,
+		&c.CacheDirectory,
+		&c.SaveDirectory,
+		&c.BackupDirectory,
+		&c.SteamCMDPath,
+	}
+	for _, p := range paths {
+		expanded, err := homedir.Expand(*p)
+		if err != nil {
+			return Config{}, err
+		}
+		*p = expanded
+	}
+
+	modDirectory, err := c.resolveModDirectory(c.ModDirectory)
+	if err != nil {
+		return Config{}, err
+	}
+	c.ModDirectory = modDirectory
+	return c, nil
+}
+
+// ValidatePath checks that a new value for a path setting can be used. Directories don't have to
+// exist yet, e.g. Valheim creates its save directory the first time it runs, but Warden has to be
+// able to create them. The database's directory does have to exist, and mods have to be installed
+// somewhere inside the Valheim directory for BepInEx to load them.
+func ValidatePath(key, value string, cfg Config) error {
+	path, err := homedir.Expand(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPathNotFound, err)
+	}
+
+	switch key {
+	case "mod-directory":
+		path, err = cfg.resolveModDirectory(value)
+		if err != nil {
+			return err
+		}
+	case "valheim-directory":
+		// A mod directory that was set as an absolute path has to stay inside the new one
+		if filepath.IsAbs(cfg.ModDirectory) {
+			cfg.ValheimDirectory = value
+			if _, err := cfg.resolveModDirectory(cfg.ModDirectory); err != nil {
+				return err
+			}
+		}
+	case "database-path":
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			return fmt.Errorf("%w: %s is a directory", ErrPathNotWritable, path)
+		}
+		dir := filepath.Dir(path)
+		if info, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("%w: %s", ErrPathNotFound, dir)
+		} else if !info.IsDir() {
+			return fmt.Errorf("%w: %s", ErrPathNotDirectory, dir)
+		}
+		return checkWritable(dir)
+	}
+	return checkDirectory(path)
+}
+
+// resolveModDirectory returns the absolute path of a mod directory, which must be inside the
+// Valheim directory
+func (c Config) resolveModDirectory(modDirectory string) (string, error) {
+	valheimDirectory, err := homedir.Expand(c.ValheimDirectory)
+	if err != nil {
+		return "", err
+	}
+	path, err := homedir.Expand(modDirectory)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(valheimDirectory, path)
+	}
+
+	rel, err := filepath.Rel(valheimDirectory, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrPathOutsideValheim, modDirectory)
+	}
+	return filepath.Clean(path), nil
+}
+
+// checkDirectory checks that a path is a writable directory, or that it can be created
+func checkDirectory(path string) error {
+	info, err := os.Stat(path)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("%w: %s", ErrPathNotDirectory, path)
+		}
+		return checkWritable(path)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrPathNotWritable, err)
+	}
+
+	// Find the closest directory that does exist, which it would be created in
+	parent := filepath.Dir(path)
+	if parent == path {
+		return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+	return checkDirectory(parent)
+}
+
+// checkWritable checks that files can be created in a directory, by creating one
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".warden-check")
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPathNotWritable, dir)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
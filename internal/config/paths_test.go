@@ -0,0 +1,135 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"warden/internal/config"
+)
+
+func TestValidatePath_Happy(t *testing.T) {
+	dir := t.TempDir()
+	valheim := filepath.Join(dir, "valheim")
+	if err := os.MkdirAll(valheim, os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating test directory, received: %+v", err)
+	}
+	cfg := config.Config{ValheimDirectory: valheim, ModDirectory: config.DefaultModDirectory}
+
+	tests := map[string]struct {
+		key   string
+		value string
+	}{
+		"accept an existing directory": {
+			key:   "save-directory",
+			value: dir,
+		},
+		"accept a directory that can be created": {
+			key:   "cache-directory",
+			value: filepath.Join(dir, "cache", "mods"),
+		},
+		"accept a mod directory relative to the Valheim directory": {
+			key:   "mod-directory",
+			value: "BepInEx/plugins/warden",
+		},
+		"accept an absolute mod directory inside the Valheim directory": {
+			key:   "mod-directory",
+			value: filepath.Join(valheim, "BepInEx", "plugins"),
+		},
+		"accept a database in an existing directory": {
+			key:   "database-path",
+			value: filepath.Join(dir, "warden.db"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := config.ValidatePath(test.key, test.value, cfg); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePath_Sad(t *testing.T) {
+	dir := t.TempDir()
+	valheim := filepath.Join(dir, "valheim")
+	if err := os.MkdirAll(valheim, os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating test directory, received: %+v", err)
+	}
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte{}, 0644); err != nil {
+		t.Fatalf("unexpected error creating test file, received: %+v", err)
+	}
+
+	tests := map[string]struct {
+		key      string
+		value    string
+		cfg      config.Config
+		expected error
+	}{
+		"return an error if the mod directory is outside the Valheim directory": {
+			key:      "mod-directory",
+			value:    "../plugins",
+			expected: config.ErrPathOutsideValheim,
+		},
+		"return an error if the mod directory is the Valheim directory": {
+			key:      "mod-directory",
+			value:    valheim,
+			expected: config.ErrPathOutsideValheim,
+		},
+		"return an error if the Valheim directory moves away from an absolute mod directory": {
+			key:      "valheim-directory",
+			value:    filepath.Join(dir, "other"),
+			cfg:      config.Config{ValheimDirectory: valheim, ModDirectory: filepath.Join(valheim, "plugins")},
+			expected: config.ErrPathOutsideValheim,
+		},
+		"return an error if a directory is a file": {
+			key:      "backup-directory",
+			value:    file,
+			expected: config.ErrPathNotDirectory,
+		},
+		"return an error if the database's directory doesn't exist": {
+			key:      "database-path",
+			value:    filepath.Join(dir, "missing", "warden.db"),
+			expected: config.ErrPathNotFound,
+		},
+		"return an error if the database is a directory": {
+			key:      "database-path",
+			value:    dir,
+			expected: config.ErrPathNotWritable,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := test.cfg
+			if cfg.ValheimDirectory == "" {
+				cfg = config.Config{ValheimDirectory: valheim, ModDirectory: config.DefaultModDirectory}
+			}
+
+			if err := config.ValidatePath(test.key, test.value, cfg); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	cfg := config.Config{
+		ValheimDirectory: "/srv/valheim",
+		ModDirectory:     config.DefaultModDirectory,
+		SaveDirectory:    "/srv/saves",
+	}
+
+	expanded, err := cfg.Expand()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if expected := filepath.Join("/srv/valheim", "BepInEx", "plugins"); expanded.ModDirectory != expected {
+		t.Errorf("expected mod directory: %s, received: %s", expected, expanded.ModDirectory)
+	}
+	if expanded.SaveDirectory != cfg.SaveDirectory {
+		t.Errorf("expected save directory to be unchanged, received: %s", expanded.SaveDirectory)
+	}
+}
@@ -2,8 +2,8 @@ package file
 
 import (
 	"errors"
+	"fmt"
 	"os"
-	"path/filepath"
 )
 
 var (
@@ -29,45 +29,37 @@ type Backup interface {
 }
 
 type backup struct {
-	location *string
+	directory string
+	location  *string
 }
 
+// NewBackup creates a Backup that's stored in the system's temporary directory
 func NewBackup() Backup {
 	return &backup{}
 }
 
+// NewBackupIn creates a Backup that's stored in the given directory. Backups are moved back into
+// place when they're restored, which only works if they're on the same filesystem.
+func NewBackupIn(directory string) Backup {
+	return &backup{directory: directory}
+}
+
 func (b *backup) Create(source string) error {
-	tmp, err := os.MkdirTemp("", "warden-backup")
+	if b.directory != "" {
+		if err := os.MkdirAll(b.directory, os.ModePerm); err != nil {
+			return fmt.Errorf("%w: %w", ErrBackupCreateFailed, err)
+		}
+	}
+	tmp, err := os.MkdirTemp(b.directory, "warden-backup")
 	if err != nil {
-		return ErrBackupCreateFailed
+		return fmt.Errorf("%w: %w", ErrBackupCreateFailed, err)
 	}
 	b.location = &tmp
 
-	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return ErrBackupCreateFailed
-		}
-
-		// Create the destination path
-		relPath, err := filepath.Rel(source, path)
-		if err != nil {
-			return ErrBackupCreateFailed
-		}
-		dstPath := filepath.Join(tmp, relPath)
-
-		if info.IsDir() {
-			// Create the directory in the destination path
-			if err := os.MkdirAll(dstPath, info.Mode()); err != nil {
-				return ErrBackupCreateFailed
-			}
-		} else {
-			// Copy the file to the destination path
-			if err := copyFile(path, dstPath); err != nil {
-				return ErrBackupCreateFailed
-			}
-		}
-		return nil
-	})
+	if err := copyDirectory(source, tmp); err != nil {
+		return fmt.Errorf("%w: %w", ErrBackupCreateFailed, err)
+	}
+	return nil
 }
 
 func (b *backup) Restore(destination string) error {
@@ -77,20 +69,20 @@ func (b *backup) Restore(destination string) error {
 
 	// Remove existing files at destination
 	if err := os.RemoveAll(destination); err != nil {
-		return ErrBackupRestoreFailed
+		return fmt.Errorf("%w: %w", ErrBackupRestoreFailed, err)
 	}
 	if err := os.MkdirAll(destination, os.ModePerm); err != nil {
-		return ErrBackupRestoreFailed
+		return fmt.Errorf("%w: %w", ErrBackupRestoreFailed, err)
 	}
 
 	// Move backed up files to destination
 	if err := moveFiles(*b.location, destination); err != nil {
-		return ErrBackupRestoreFailed
+		return fmt.Errorf("%w: %w", ErrBackupRestoreFailed, err)
 	}
 
 	// Delete back-up once successfuly moved over
 	if err := os.RemoveAll(*b.location); err != nil {
-		return ErrBackupDeleteFailed
+		return fmt.Errorf("%w: %w", ErrBackupDeleteFailed, err)
 	}
 	b.location = nil
 	return nil
@@ -101,7 +93,7 @@ func (b *backup) Remove() error {
 		return ErrBackupMissing
 	}
 	if err := os.RemoveAll(*b.location); err != nil {
-		return ErrBackupDeleteFailed
+		return fmt.Errorf("%w: %w", ErrBackupDeleteFailed, err)
 	}
 	b.location = nil
 	return nil
@@ -3,6 +3,7 @@ package file
 import (
 	"archive/zip"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -30,53 +31,69 @@ func Unzip(source, destination string) error {
 	// Create the destination directory for all files
 	err := os.MkdirAll(destination, os.ModePerm)
 	if err != nil {
-		return ErrDirectoryCreateFailed
+		return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
 	}
 
 	// Open zip archive for reading
 	archive, err := zip.OpenReader(source)
 	if err != nil {
-		return ErrZipReadFailed
+		return fmt.Errorf("%w: %w", ErrZipReadFailed, err)
 	}
 	defer archive.Close()
 
 	// Loop through each file inside of the zip
 	for _, f := range archive.File {
+		// Archives can come from anywhere, so an entry can't be written outside the destination
+		if !filepath.IsLocal(f.Name) {
+			return fmt.Errorf("%w: %s is outside the archive", ErrZipReadFailed, f.Name)
+		}
 		filePath := filepath.Join(destination, f.Name)
 
 		// Check if the file is a directory and create one if it is
 		if f.FileInfo().IsDir() {
 			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
-				return ErrDirectoryCreateFailed
+				return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
 			}
 			continue
 		}
 
-		// Open the file in the zip and copy its contents to the destination file
-		srcFile, err := f.Open()
-		if err != nil {
-			return ErrFileOpenFailed
+		if err := unzipFile(f, filePath); err != nil {
+			return err
 		}
-		defer srcFile.Close()
-
-		createFile(filePath, srcFile)
 	}
 	return nil
 }
 
+// unzipFile is a helper function that copies a single file out of a zip archive, creating any
+// directories it's in that the archive doesn't list
+func unzipFile(f *zip.File, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+
+	// Open the file in the zip and copy its contents to the destination file
+	srcFile, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileOpenFailed, err)
+	}
+	defer srcFile.Close()
+
+	return createFile(filePath, srcFile)
+}
+
 // createFile is a helper function that creates a new file and writes data from io.Reader into it
 func createFile(filePath string, fileSource io.Reader) error {
 	// Create the empty file
 	out, err := os.Create(filePath)
 	if err != nil {
-		return ErrFileCreateFailed
+		return fmt.Errorf("%w: %w", ErrFileCreateFailed, err)
 	}
 	defer out.Close()
 
 	// Write the body to file
 	_, err = io.Copy(out, fileSource)
 	if err != nil {
-		return ErrFileWriteFailed
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
 	}
 	return nil
 }
@@ -85,7 +102,7 @@ func createFile(filePath string, fileSource io.Reader) error {
 func moveFiles(source, destination string) error {
 	entries, err := os.ReadDir(source)
 	if err != nil {
-		return ErrDirectoryOpenFailed
+		return fmt.Errorf("%w: %w", ErrDirectoryOpenFailed, err)
 	}
 
 	for _, e := range entries {
@@ -93,7 +110,7 @@ func moveFiles(source, destination string) error {
 		dest := filepath.Join(destination, e.Name())
 
 		if err := os.Rename(src, dest); err != nil {
-			return ErrFileRenameFailed
+			return fmt.Errorf("%w: %w", ErrFileRenameFailed, err)
 		}
 	}
 	return nil
@@ -102,18 +119,18 @@ func moveFiles(source, destination string) error {
 func copyFile(source, destination string) error {
 	src, err := os.Open(source)
 	if err != nil {
-		return ErrFileOpenFailed
+		return fmt.Errorf("%w: %w", ErrFileOpenFailed, err)
 	}
 	defer src.Close()
 
 	dst, err := os.Create(destination)
 	if err != nil {
-		return ErrFileCreateFailed
+		return fmt.Errorf("%w: %w", ErrFileCreateFailed, err)
 	}
 	defer dst.Close()
 
 	if _, err := io.Copy(dst, src); err != nil {
-		return ErrFileCopyFailed
+		return fmt.Errorf("%w: %w", ErrFileCopyFailed, err)
 	}
 
 	info, err := src.Stat()
@@ -122,3 +139,121 @@ func copyFile(source, destination string) error {
 	}
 	return os.Chmod(dst.Name(), info.Mode())
 }
+
+// copyDirectory is a helper function that copies a directory, and everything inside it, to the
+// destination
+func copyDirectory(source, destination string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Create the destination path
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(destination, relPath)
+
+		if info.IsDir() {
+			// Create the directory in the destination path
+			if err := os.MkdirAll(dstPath, info.Mode()); err != nil {
+				return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+			}
+			return nil
+		}
+		// Copy the file to the destination path
+		return copyFile(path, dstPath)
+	})
+}
+
+// Zip is a helper function that writes the given files into a new zip archive at destination.
+// Files are stored flat, using only their base names.
+func Zip(destination string, files []string) error {
+	entries := make([]ZipEntry, len(files))
+	for i, f := range files {
+		entries[i] = ZipEntry{Name: filepath.Base(f), Source: f}
+	}
+	return WriteZip(destination, entries)
+}
+
+// A ZipEntry is a file to write into a zip archive, either from memory or copied from a file
+type ZipEntry struct {
+	Name string
+	Data []byte
+
+	// The file to copy into the archive instead of Data, if set
+	Source string
+}
+
+// WriteZip is a helper function that writes the given entries into a new zip archive at
+// destination. A partly written archive is removed.
+func WriteZip(destination string, entries []ZipEntry) error {
+	out, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileCreateFailed, err)
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	for _, e := range entries {
+		var err error
+		if e.Source != "" {
+			err = addToZip(archive, e.Source, e.Name)
+		} else {
+			err = writeToZip(archive, e.Name, e.Data)
+		}
+		if err != nil {
+			archive.Close()
+			os.Remove(destination)
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		os.Remove(destination)
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	return nil
+}
+
+// addToZip is a helper function that copies a single file into an open zip archive under the
+// given name
+func addToZip(archive *zip.Writer, source, name string) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileOpenFailed, err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileOpenFailed, err)
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+
+	dst, err := archive.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	return nil
+}
+
+// writeToZip is a helper function that writes data from memory into an open zip archive
+func writeToZip(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWriteFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"warden/internal/data/file"
+)
+
+func TestUnzip_Happy(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "test.zip")
+	writeTestZip(t, source, map[string]string{"Azumatt-Sleepover/plugins/Sleepover.dll": "data"})
+	destination := filepath.Join(t.TempDir(), "plugins")
+
+	if err := file.Unzip(source, destination); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	// Directories the archive doesn't list are still created
+	if _, err := os.Stat(filepath.Join(destination, "Azumatt-Sleepover", "plugins", "Sleepover.dll")); err != nil {
+		t.Errorf("expected the file to be extracted, received: %+v", err)
+	}
+}
+
+func TestUnzip_Sad(t *testing.T) {
+	tests := map[string]string{
+		"return an error if an entry is outside the destination": "../../BepInEx/core/Sleepover.dll",
+		"return an error if an entry is an absolute path":        "/etc/Sleepover.dll",
+	}
+
+	for name, entry := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			destination := filepath.Join(dir, "BepInEx", "plugins", "Azumatt-Sleepover")
+
+			source := filepath.Join(dir, "test.zip")
+			writeTestZip(t, source, map[string]string{entry: "data"})
+
+			err := file.Unzip(source, destination)
+			if !errors.Is(err, file.ErrZipReadFailed) {
+				t.Errorf("expected error: %+v, received: %+v", file.ErrZipReadFailed, err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "BepInEx", "core", "Sleepover.dll")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected nothing written outside the destination, received: %+v", err)
+			}
+		})
+	}
+}
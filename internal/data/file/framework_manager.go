@@ -2,6 +2,7 @@ package file
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"warden/internal/api"
@@ -18,13 +19,16 @@ type frameworkManager interface {
 
 	// Removes all BepInEx files
 	RemoveBepInEx() error
+
+	// Returns every file and folder that belongs to a BepInEx installation
+	BepInExFiles() []string
 }
 
 func (m *manager) InstallBepInEx(url, fullName string) (string, error) {
 	// Get the data
 	resp, err := m.client.Get(url)
 	if err != nil {
-		return "", api.ErrHTTPClient
+		return "", fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
 	}
 	defer resp.Body.Close()
 
@@ -46,13 +50,13 @@ func (m *manager) InstallBepInEx(url, fullName string) (string, error) {
 	// Remove zip file after finishing extractio
 	if err = os.Remove(zipPath); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return "", ErrZipDeleteFailed
+		return "", fmt.Errorf("%w: %w", ErrZipDeleteFailed, err)
 	}
 
 	// Move BepInEx files to Valheim installation directory and remove top level folder
 	if err := m.moveBepInExFiles(); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return "", ErrFrameworkInstallFailed
+		return "", fmt.Errorf("%w: %w", ErrFrameworkInstallFailed, err)
 	}
 	m.backup.Remove()
 	return m.valheimDirectory, nil
@@ -62,39 +66,58 @@ func (m *manager) UpdateBepInEx(url, fullName string) error {
 	m.backup.Create(m.valheimDirectory)
 
 	// Move BepInEx mods to /tmp
-	tmp, err := os.MkdirTemp("", "warden")
+	// Mods are moved out of the way, so they have to stay on the same filesystem
+	if err := os.MkdirAll(m.cacheDirectory, os.ModePerm); err != nil {
+		m.backup.Restore(m.valheimDirectory)
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
+	}
+	tmp, err := os.MkdirTemp(m.cacheDirectory, "warden")
 	if err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 	defer os.RemoveAll(tmp)
 
 	if err := moveFiles(m.modDirectory, tmp); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 
 	// Update BepInEx
 	if err := m.RemoveBepInEx(); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 	if _, err := m.InstallBepInEx(url, fullName); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 
 	// Move mods back to BepInEx mods folder
 	if err := moveFiles(tmp, m.modDirectory); err != nil {
 		m.backup.Restore(m.valheimDirectory)
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 	m.backup.Remove()
 	return nil
 }
 
 func (m *manager) RemoveBepInEx() error {
-	files := []string{
+	m.backup.Create(m.valheimDirectory)
+
+	for _, f := range m.BepInExFiles() {
+		err := os.RemoveAll(f)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			m.backup.Restore(m.valheimDirectory)
+			return fmt.Errorf("%w: %w", ErrFrameworkDeleteFailed, err)
+		}
+	}
+	m.backup.Remove()
+	return nil
+}
+
+func (m *manager) BepInExFiles() []string {
+	return []string{
 		filepath.Join(m.valheimDirectory, "BepInEx"),                 // core BepInEx files
 		filepath.Join(m.valheimDirectory, "doorstop_libs"),           // dynamic libraries
 		filepath.Join(m.valheimDirectory, "doorstop_config.ini"),     // dynamic library config
@@ -107,17 +130,6 @@ func (m *manager) RemoveBepInEx() error {
 		filepath.Join(m.valheimDirectory, "CHANGELOG.md"),            // BepInEx markdown change log
 		filepath.Join(m.valheimDirectory, "changelog.txt"),           // BepInEx plain text change log
 	}
-	m.backup.Create(m.valheimDirectory)
-
-	for _, f := range files {
-		err := os.RemoveAll(f)
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			m.backup.Restore(m.valheimDirectory)
-			return ErrFrameworkDeleteFailed
-		}
-	}
-	m.backup.Remove()
-	return nil
 }
 
 func (m *manager) moveBepInExFiles() error {
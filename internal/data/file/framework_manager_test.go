@@ -29,7 +29,7 @@ func TestInstallBepInEx_Happy(t *testing.T) {
 			}, nil
 		},
 	}
-	m := file.NewManager(&client, th.GetValheimDirectory())
+	m := newTestManager(t, &client, th.GetValheimDirectory())
 
 	path, err := m.InstallBepInEx(helper.TestDownloadURL, helper.TestBepInExFullName)
 	if err != nil {
@@ -77,7 +77,7 @@ func TestInstallBepInEx_Sad(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			manager := file.NewManager(tt.client, th.GetValheimDirectory())
+			manager := newTestManager(t, tt.client, th.GetValheimDirectory())
 
 			path, err := manager.InstallBepInEx(helper.TestDownloadURL, tt.fullName)
 			if !errors.Is(err, tt.expected) {
@@ -137,7 +137,7 @@ func TestRemoveBepInEx_Happy(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			test.setUp(t)
 
-			m := file.NewManager(&mock.HTTPClient{}, th.GetValheimDirectory())
+			m := newTestManager(t, &mock.HTTPClient{}, th.GetValheimDirectory())
 
 			if err := m.RemoveBepInEx(); err != nil {
 				t.Errorf("expected a nil error, received: %+v", err)
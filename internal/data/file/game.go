@@ -0,0 +1,46 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"warden/internal/domain/game"
+	"warden/internal/domain/steamcmd"
+)
+
+var ErrGameBuildNotFound = errors.New("unable to find the installed game build")
+
+// An interface for reading details about the installed game itself
+type gameManager interface {
+	// Reads the installed Valheim build from the app manifest Steam keeps alongside the server.
+	// Servers that weren't installed through Steam or SteamCMD don't have one.
+	GameBuild() (game.Build, error)
+}
+
+func (m *manager) GameBuild() (game.Build, error) {
+	manifest := "appmanifest_" + steamcmd.AppID + ".acf"
+	paths := []string{
+		// SteamCMD keeps it inside the directory the server was installed to
+		filepath.Join(m.valheimDirectory, "steamapps", manifest),
+		// Steam keeps it in the library, i.e. steamapps/common/<server>/../../
+		filepath.Join(m.valheimDirectory, "..", "..", manifest),
+	}
+
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return game.Build{}, fmt.Errorf("%w: %w", ErrGameBuildNotFound, err)
+		}
+
+		build, err := game.ParseManifest(string(contents))
+		if err != nil {
+			return game.Build{}, fmt.Errorf("%w: %w", ErrGameBuildNotFound, err)
+		}
+		return build, nil
+	}
+	return game.Build{}, fmt.Errorf("%w: no app manifest in %s", ErrGameBuildNotFound, m.valheimDirectory)
+}
@@ -0,0 +1,96 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"warden/internal/data/file"
+	"warden/internal/domain/game"
+	"warden/internal/test/mock"
+)
+
+const testAppManifest = `"AppState"
+{
+	"appid"		"896660"
+	"LastUpdated"		"1706985000"
+	"buildid"		"13385436"
+}
+`
+
+func TestGameBuild_Happy(t *testing.T) {
+	expected := game.Build{ID: "13385436", UpdatedAt: time.Unix(1706985000, 0)}
+
+	tests := map[string]struct {
+		server   string
+		manifest string
+	}{
+		"read the manifest SteamCMD installs into the server directory": {
+			server:   "server",
+			manifest: filepath.Join("server", "steamapps", "appmanifest_896660.acf"),
+		},
+		"read the manifest from the Steam library the server is in": {
+			server:   filepath.Join("steamapps", "common", "Valheim dedicated server"),
+			manifest: filepath.Join("steamapps", "appmanifest_896660.acf"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifest := filepath.Join(dir, tt.manifest)
+			if err := os.MkdirAll(filepath.Dir(manifest), os.ModePerm); err != nil {
+				t.Fatalf("unexpected error creating test manifest, received: %+v", err)
+			}
+			if err := os.WriteFile(manifest, []byte(testAppManifest), 0644); err != nil {
+				t.Fatalf("unexpected error creating test manifest, received: %+v", err)
+			}
+
+			m := newTestManager(t, &mock.HTTPClient{}, filepath.Join(dir, tt.server))
+			build, err := m.GameBuild()
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if build.ID != expected.ID || !build.UpdatedAt.Equal(expected.UpdatedAt) {
+				t.Errorf("expected build: %+v, received: %+v", expected, build)
+			}
+		})
+	}
+}
+
+func TestGameBuild_Sad(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		expected error
+	}{
+		"return an error if there is no manifest": {
+			expected: file.ErrGameBuildNotFound,
+		},
+		"return an error if the manifest has no build": {
+			manifest: `"AppState" { "appid" "896660" }`,
+			expected: game.ErrInvalidManifest,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.manifest != "" {
+				manifest := filepath.Join(dir, "steamapps", "appmanifest_896660.acf")
+				if err := os.MkdirAll(filepath.Dir(manifest), os.ModePerm); err != nil {
+					t.Fatalf("unexpected error creating test manifest, received: %+v", err)
+				}
+				if err := os.WriteFile(manifest, []byte(tt.manifest), 0644); err != nil {
+					t.Fatalf("unexpected error creating test manifest, received: %+v", err)
+				}
+			}
+
+			m := newTestManager(t, &mock.HTTPClient{}, dir)
+			_, err := m.GameBuild()
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
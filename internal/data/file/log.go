@@ -0,0 +1,110 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	ErrLogOpenFailed   = errors.New("unable to open log file")
+	ErrLogRotateFailed = errors.New("unable to rotate log file")
+)
+
+type rotatingLog struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+// NewRotatingLog opens a log file that's rotated once it grows past maxSize bytes. Old logs are
+// renamed with a numbered suffix, e.g. server.log.1 is the most recent, and only maxFiles logs
+// are kept in total. It's safe to write to from multiple goroutines, e.g. a process's stdout
+// and stderr.
+func NewRotatingLog(path string, maxSize int64, maxFiles int) (io.WriteCloser, error) {
+	l := &rotatingLog{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: max(maxFiles, 1),
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLogOpenFailed, err)
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+func (l *rotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// open appends to the current log file, creating it if needed
+func (l *rotatingLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLogOpenFailed, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("%w: %w", ErrLogOpenFailed, err)
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// rotate shifts every old log up by one, dropping the oldest, then starts a new log file
+func (l *rotatingLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrLogRotateFailed, err)
+	}
+
+	for i := l.maxFiles - 1; i > 0; i-- {
+		src := l.rotatedPath(i - 1)
+		if err := os.Rename(src, l.rotatedPath(i)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %w", ErrLogRotateFailed, err)
+		}
+	}
+	if l.maxFiles == 1 {
+		if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %w", ErrLogRotateFailed, err)
+		}
+	}
+	return l.open()
+}
+
+// rotatedPath returns where the Nth most recent log is kept. The current log has no suffix.
+func (l *rotatingLog) rotatedPath(n int) string {
+	if n == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
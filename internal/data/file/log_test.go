@@ -0,0 +1,94 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"warden/internal/data/file"
+)
+
+func TestRotatingLog_Happy(t *testing.T) {
+	tests := map[string]struct {
+		writes   []string
+		maxFiles int
+		expected map[string]string
+	}{
+		"write to a single file while it's under the max size": {
+			writes:   []string{"abc", "def"},
+			maxFiles: 3,
+			expected: map[string]string{
+				"server.log": "abcdef",
+			},
+		},
+		"rotate the log once it would grow past the max size": {
+			writes:   []string{"abcdefgh", "ijk"},
+			maxFiles: 3,
+			expected: map[string]string{
+				"server.log":   "ijk",
+				"server.log.1": "abcdefgh",
+			},
+		},
+		"drop the oldest log once max files is reached": {
+			writes:   []string{"aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd"},
+			maxFiles: 3,
+			expected: map[string]string{
+				"server.log":   "dddddddd",
+				"server.log.1": "cccccccc",
+				"server.log.2": "bbbbbbbb",
+			},
+		},
+		"keep only the current log if max files is one": {
+			writes:   []string{"aaaaaaaa", "bbbbbbbb"},
+			maxFiles: 1,
+			expected: map[string]string{
+				"server.log": "bbbbbbbb",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			l, err := file.NewRotatingLog(filepath.Join(dir, "server.log"), 10, test.maxFiles)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			for _, w := range test.writes {
+				if _, err := l.Write([]byte(w)); err != nil {
+					t.Errorf("expected a nil error, received: %+v", err)
+				}
+			}
+			if err := l.Close(); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Errorf("unexpected error reading log directory, received: %+v", err)
+			}
+			if len(entries) != len(test.expected) {
+				t.Errorf("expected %d log files, received: %d", len(test.expected), len(entries))
+			}
+			for name, content := range test.expected {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil || string(data) != content {
+					t.Errorf("expected %s to contain: %s, received: %s, error: %+v", name, content, data, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatingLog_Sad(t *testing.T) {
+	// A regular file can't be used as a directory
+	parent := filepath.Join(t.TempDir(), "not-a-directory")
+	if err := os.WriteFile(parent, []byte{}, 0644); err != nil {
+		t.Errorf("unexpected error setting up test, received: %+v", err)
+	}
+
+	_, err := file.NewRotatingLog(filepath.Join(parent, "server.log"), 10, 3)
+	if !errors.Is(err, file.ErrLogOpenFailed) {
+		t.Errorf("expected error: %+v, received: %+v", file.ErrLogOpenFailed, err)
+	}
+}
@@ -2,17 +2,22 @@ package file
 
 import (
 	"errors"
-	"path/filepath"
 	"warden/internal/api"
 )
 
 const (
 	// BepInEx is required by practically every mod for Valheim, so we use it
-	// for the default path
+	// for the default mod directory
 	BepInExPluginDirectory = "/BepInEx/plugins"
 
 	// A sub-directory containing the files and libraries needed for BepInEx to work
 	BepInExContentsDirectory = "/BepInExPack_Valheim"
+
+	// BepInEx and most mods keep their settings in here
+	BepInExConfigDirectory = "/BepInEx/config"
+
+	// Downloaded mod archives are kept in this sub-directory of the cache directory
+	ArchiveDirectory = "archives"
 )
 
 var (
@@ -22,12 +27,14 @@ var (
 	ErrFrameworkInstallFailed = errors.New("unable to install framework")
 	ErrFrameworkDeleteFailed  = errors.New("unable to delete framework")
 	ErrFrameworkUpdateFailed  = errors.New("unable to update framework")
+	ErrInvalidManifest        = errors.New("package archive doesn't have a valid manifest.json")
 )
 
 // Manager provides an interface for all file-related mod operations, e.g. installing and deleting mods.
 type Manager interface {
 	modManager
 	frameworkManager
+	gameManager
 }
 
 type manager struct {
@@ -35,13 +42,18 @@ type manager struct {
 	client           api.HTTPClient
 	valheimDirectory string
 	modDirectory     string
+	cacheDirectory   string
 }
 
-func NewManager(c api.HTTPClient, vd string) Manager {
+// NewManager creates a Manager that installs BepInEx into the Valheim directory and mods into the
+// mod directory. Files are copied into the cache directory while they're being changed, so they
+// can be put back if anything goes wrong.
+func NewManager(c api.HTTPClient, valheimDirectory, modDirectory, cacheDirectory string) Manager {
 	return &manager{
-		backup:           NewBackup(),
+		backup:           NewBackupIn(cacheDirectory),
 		client:           c,
-		valheimDirectory: vd,
-		modDirectory:     filepath.Join(vd, BepInExPluginDirectory),
+		valheimDirectory: valheimDirectory,
+		modDirectory:     modDirectory,
+		cacheDirectory:   cacheDirectory,
 	}
 }
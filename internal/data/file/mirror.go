@@ -0,0 +1,178 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"warden/internal/api/thunderstore"
+)
+
+// Mirrored packages are indexed in this sub-directory of the cache directory, with their archives
+// kept in the archive cache
+const MirrorDirectory = "mirror"
+
+var (
+	ErrMirrorPackageNotFound = errors.New("package is not in the mirror")
+	ErrMirrorReadFailed      = errors.New("unable to read mirror index")
+	ErrMirrorWriteFailed     = errors.New("unable to write mirror index")
+)
+
+var (
+	// Only names Thunderstore allows are looked up, since they're used in file names
+	mirrorName    = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	mirrorVersion = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+)
+
+// Mirror provides an interface for the index of packages mirrored in the cache directory, so
+// they can be served to other hosts without Thunderstore. Every release in it has its archive in
+// the archive cache.
+type Mirror interface {
+	// Records a release of a package, along with the package's details. Releases of a package that
+	// were recorded before are kept.
+	SaveRelease(pkg thunderstore.Package, release thunderstore.Release) error
+
+	// Returns a mirrored package. Its latest release is the newest one that's mirrored, which isn't
+	// necessarily the newest on Thunderstore.
+	GetPackage(namespace, name string) (thunderstore.Package, error)
+
+	// Returns a specific mirrored release of a package
+	GetRelease(namespace, name, version string) (thunderstore.Release, error)
+
+	// Returns where a mirrored release's archive is kept
+	ArchivePath(namespace, name, version string) (string, error)
+}
+
+type mirror struct {
+	cacheDirectory string
+}
+
+// mirrorIndex is how a mirrored package is saved, with every release of it that's mirrored
+type mirrorIndex struct {
+	Package  thunderstore.Package   `json:"package"`
+	Releases []thunderstore.Release `json:"releases"`
+}
+
+// NewMirror creates a Mirror that indexes packages in the given cache directory
+func NewMirror(cacheDirectory string) Mirror {
+	return &mirror{cacheDirectory: cacheDirectory}
+}
+
+func (m *mirror) SaveRelease(pkg thunderstore.Package, release thunderstore.Release) error {
+	if !mirrorName.MatchString(pkg.Namespace) || !mirrorName.MatchString(pkg.Name) || !mirrorVersion.MatchString(release.VersionNumber) {
+		return fmt.Errorf("%w: invalid release %q", ErrMirrorWriteFailed, release.FullName)
+	}
+
+	index, err := m.read(pkg.Namespace, pkg.Name)
+	if err != nil && !errors.Is(err, ErrMirrorPackageNotFound) {
+		return err
+	}
+	index.Package = pkg
+
+	releases := []thunderstore.Release{release}
+	for _, r := range index.Releases {
+		if r.VersionNumber != release.VersionNumber {
+			releases = append(releases, r)
+		}
+	}
+	index.Releases = releases
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMirrorWriteFailed, err)
+	}
+	if err := os.MkdirAll(filepath.Join(m.cacheDirectory, MirrorDirectory), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+	if err := os.WriteFile(m.indexPath(pkg.Namespace, pkg.Name), data, 0644); err != nil {
+		return fmt.Errorf("%w: %w", ErrMirrorWriteFailed, err)
+	}
+	return nil
+}
+
+func (m *mirror) GetPackage(namespace, name string) (thunderstore.Package, error) {
+	index, err := m.read(namespace, name)
+	if err != nil {
+		return thunderstore.Package{}, err
+	}
+
+	pkg := index.Package
+	for i, r := range index.Releases {
+		if i == 0 || newerVersion(r.VersionNumber, pkg.Latest.VersionNumber) {
+			pkg.Latest = r
+		}
+	}
+	return pkg, nil
+}
+
+func (m *mirror) GetRelease(namespace, name, version string) (thunderstore.Release, error) {
+	index, err := m.read(namespace, name)
+	if err != nil {
+		return thunderstore.Release{}, err
+	}
+	for _, r := range index.Releases {
+		if r.VersionNumber == version {
+			return r, nil
+		}
+	}
+	return thunderstore.Release{}, fmt.Errorf("%w: %s-%s-%s", ErrMirrorPackageNotFound, namespace, name, version)
+}
+
+func (m *mirror) ArchivePath(namespace, name, version string) (string, error) {
+	release, err := m.GetRelease(namespace, name, version)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(m.cacheDirectory, ArchiveDirectory, release.FullName+ZipFileExtension)
+	if _, err := os.Stat(path); err != nil {
+		// The archive cache can be cleared without the index
+		return "", fmt.Errorf("%w: %w", ErrMirrorPackageNotFound, err)
+	}
+	return path, nil
+}
+
+// read loads a package's index, if it's been mirrored
+func (m *mirror) read(namespace, name string) (mirrorIndex, error) {
+	if !mirrorName.MatchString(namespace) || !mirrorName.MatchString(name) {
+		return mirrorIndex{}, fmt.Errorf("%w: %s-%s", ErrMirrorPackageNotFound, namespace, name)
+	}
+
+	data, err := os.ReadFile(m.indexPath(namespace, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return mirrorIndex{}, fmt.Errorf("%w: %s-%s", ErrMirrorPackageNotFound, namespace, name)
+	}
+	if err != nil {
+		return mirrorIndex{}, fmt.Errorf("%w: %w", ErrMirrorReadFailed, err)
+	}
+
+	index := mirrorIndex{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return mirrorIndex{}, fmt.Errorf("%w: %w", ErrMirrorReadFailed, err)
+	}
+	return index, nil
+}
+
+func (m *mirror) indexPath(namespace, name string) string {
+	return filepath.Join(m.cacheDirectory, MirrorDirectory, namespace+"-"+name+".json")
+}
+
+// newerVersion checks if version a is newer than b, comparing each part of major.minor.patch as a
+// number so 1.0.10 is newer than 1.0.9
+func newerVersion(a, b string) bool {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		x, errX := strconv.Atoi(partsA[i])
+		y, errY := strconv.Atoi(partsB[i])
+		if errX != nil || errY != nil {
+			return a > b
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return len(partsA) > len(partsB)
+}
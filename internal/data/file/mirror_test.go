@@ -0,0 +1,84 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/file"
+)
+
+func TestMirror_Happy(t *testing.T) {
+	cache := t.TempDir()
+	m := file.NewMirror(cache)
+
+	pkg := thunderstore.Package{Namespace: "Azumatt", Name: "Sleepover", IsDeprecated: true}
+	for _, version := range []string{"1.0.9", "1.0.10", "1.0.2"} {
+		release := thunderstore.Release{Namespace: "Azumatt", Name: "Sleepover", VersionNumber: version, FullName: "Azumatt-Sleepover-" + version}
+		if err := m.SaveRelease(pkg, release); err != nil {
+			t.Fatalf("expected a nil error, received: %+v", err)
+		}
+	}
+
+	// Versions are compared by number, not as text
+	mirrored, err := m.GetPackage("Azumatt", "Sleepover")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if mirrored.Latest.VersionNumber != "1.0.10" || !mirrored.IsDeprecated {
+		t.Errorf("expected the newest mirrored release, received: %+v", mirrored)
+	}
+
+	release, err := m.GetRelease("Azumatt", "Sleepover", "1.0.2")
+	if err != nil || release.FullName != "Azumatt-Sleepover-1.0.2" {
+		t.Errorf("expected an older mirrored release, received: %+v, %+v", release, err)
+	}
+
+	archive := filepath.Join(cache, file.ArchiveDirectory, "Azumatt-Sleepover-1.0.2"+file.ZipFileExtension)
+	if err := os.MkdirAll(filepath.Dir(archive), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error setting up archive cache, received: %+v", err)
+	}
+	if err := os.WriteFile(archive, []byte{}, 0644); err != nil {
+		t.Fatalf("unexpected error setting up archive cache, received: %+v", err)
+	}
+	path, err := m.ArchivePath("Azumatt", "Sleepover", "1.0.2")
+	if err != nil || path != archive {
+		t.Errorf("expected archive: %s, received: %s, %+v", archive, path, err)
+	}
+}
+
+func TestMirror_Sad(t *testing.T) {
+	m := file.NewMirror(t.TempDir())
+	release := thunderstore.Release{Namespace: "Azumatt", Name: "Sleepover", VersionNumber: "1.0.1", FullName: "Azumatt-Sleepover-1.0.1"}
+	if err := m.SaveRelease(thunderstore.Package{Namespace: "Azumatt", Name: "Sleepover"}, release); err != nil {
+		t.Fatalf("unexpected error on test set-up, received: %+v", err)
+	}
+
+	tests := map[string]func() error{
+		"return an error if the package isn't mirrored": func() error {
+			_, err := m.GetPackage("Azumatt", "AzuClock")
+			return err
+		},
+		"return an error if the release isn't mirrored": func() error {
+			_, err := m.GetRelease("Azumatt", "Sleepover", "1.0.0")
+			return err
+		},
+		"return an error if the release's archive isn't cached": func() error {
+			_, err := m.ArchivePath("Azumatt", "Sleepover", "1.0.1")
+			return err
+		},
+		"return an error if the name isn't a package name": func() error {
+			_, err := m.GetPackage("..", "Sleepover")
+			return err
+		},
+	}
+
+	for name, run := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := run(); !errors.Is(err, file.ErrMirrorPackageNotFound) {
+				t.Errorf("expected error: %+v, received: %+v", file.ErrMirrorPackageNotFound, err)
+			}
+		})
+	}
+}
@@ -1,21 +1,48 @@
 package file
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"warden/internal/api"
+	"warden/internal/domain/modpack"
 )
 
+// BepInEx plugins are .NET assemblies
+const pluginExtension = ".dll"
+
+// Manifests saved by Windows tools often start with a byte order mark, which JSON can't parse
+var byteOrderMark = []byte("\xef\xbb\xbf")
+
 // An interface for all mod file operations
 type modManager interface {
 	// Downloads the targetted mod, unzips it, and adds it to the mod
-	// folder.
+	// folder. The download is kept in the archive cache, so the same release is only ever
+	// downloaded once.
 	//
 	// URL is the download link for a specific release.
 	// FullName is the namespace + mod name + version string that Thunderstore provides.
 	InstallMod(url, fullName string) (string, error)
 
+	// Downloads a mod release into the archive cache without installing it, unless it's already
+	// cached
+	CacheMod(url, fullName string) error
+
+	// Checks if a mod release is already in the archive cache
+	IsCached(fullName string) bool
+
+	// Downloads a mod release into the archive cache, unless it's already cached, and checks if it
+	// has any plugin DLLs in it. Modpacks don't have any, since they're only made up of
+	// dependencies.
+	HasPlugins(url, fullName string) (bool, error)
+
 	// Deletes the folder and contents for a mod. `FullName` is a
 	// value provided by Thunderstore that contains the name, namespace, and version of a
 	// specific mod release.
@@ -23,43 +50,73 @@ type modManager interface {
 
 	// Deletes the parent mod folder and all of its contents, then recreates an empty one.
 	RemoveAllMods() error
+
+	// Returns the folder a mod release is installed to
+	ModPath(fullName string) string
+
+	// Downloads a package archive from a URL, or copies it from an absolute local path, into the
+	// archive cache and reads its manifest.json. The archive is cached under the namespace + the
+	// manifest's name and version, so installing it afterwards doesn't fetch it again. It replaces
+	// any archive cached under the same name, since packages that aren't published can be rebuilt
+	// without a new version.
+	CachePackage(location, namespace string) (modpack.Manifest, error)
 }
 
 func (m *manager) InstallMod(url, fullName string) (string, error) {
-	// Get the data
-	resp, err := m.client.Get(url)
+	archive, err := m.cacheArchive(url, fullName)
 	if err != nil {
-		return "", api.ErrHTTPClient
+		return "", err
 	}
-	defer resp.Body.Close()
 
 	m.backup.Create(m.modDirectory)
 
-	// Create the zip archive
-	zipPath := filepath.Join(m.modDirectory, fullName+".zip")
-	if err := createFile(zipPath, resp.Body); err != nil {
-		m.backup.Restore(m.modDirectory)
-		return "", err
-	}
-
 	// Extract zip files into a new folder for the mod
 	destination := filepath.Join(m.modDirectory, fullName)
-	if err := Unzip(zipPath, destination); err != nil {
+	if err := Unzip(archive, destination); err != nil {
 		m.backup.Restore(m.modDirectory)
-		return "", err
-	}
 
-	// Remove zip file after finishing extraction
-	if err := os.Remove(zipPath); err != nil {
-		m.backup.Restore(m.modDirectory)
-		return "", ErrZipDeleteFailed
+		// The archive might be broken, so it's downloaded again next time
+		os.Remove(archive)
+		return "", err
 	}
 	m.backup.Remove()
 	return destination, nil
 }
 
+func (m *manager) CacheMod(url, fullName string) error {
+	_, err := m.cacheArchive(url, fullName)
+	return err
+}
+
+func (m *manager) IsCached(fullName string) bool {
+	_, err := os.Stat(m.archivePath(fullName))
+	return err == nil
+}
+
+func (m *manager) HasPlugins(url, fullName string) (bool, error) {
+	path, err := m.cacheArchive(url, fullName)
+	if err != nil {
+		return false, err
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		// The archive might be broken, so it's downloaded again next time
+		os.Remove(path)
+		return false, fmt.Errorf("%w: %w", ErrZipReadFailed, err)
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if strings.EqualFold(filepath.Ext(f.Name), pluginExtension) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (m *manager) RemoveMod(fullName string) error {
-	modPath := filepath.Join(m.modDirectory, fullName)
+	modPath := m.ModPath(fullName)
 
 	m.backup.Create(m.modDirectory)
 	err := os.RemoveAll(modPath)
@@ -68,7 +125,7 @@ func (m *manager) RemoveMod(fullName string) error {
 	// any other error, return that the delete failed.
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		m.backup.Restore(m.modDirectory)
-		return ErrModDeleteFailed
+		return fmt.Errorf("%w: %w", ErrModDeleteFailed, err)
 	}
 	m.backup.Remove()
 	return nil
@@ -81,15 +138,128 @@ func (m *manager) RemoveAllMods() error {
 	err := os.RemoveAll(m.modDirectory)
 	if err != nil {
 		m.backup.Restore(m.modDirectory)
-		return ErrDeleteAllModsFailed
+		return fmt.Errorf("%w: %w", ErrDeleteAllModsFailed, err)
 	}
 
 	// Recreate parent folder for all mods
 	err = os.MkdirAll(m.modDirectory, os.ModePerm)
 	if err != nil {
 		m.backup.Restore(m.modDirectory)
-		return ErrDirectoryCreateFailed
+		return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
 	}
 	m.backup.Remove()
 	return nil
 }
+
+func (m *manager) ModPath(fullName string) string {
+	return filepath.Join(m.modDirectory, fullName)
+}
+
+func (m *manager) CachePackage(location, namespace string) (modpack.Manifest, error) {
+	dir := filepath.Join(m.cacheDirectory, ArchiveDirectory)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+
+	// The package's name isn't known until its manifest is read
+	tmp, err := os.CreateTemp(dir, "package-*.tmp")
+	if err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrFileCreateFailed, err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := m.fetchArchive(location, tmp.Name()); err != nil {
+		return modpack.Manifest{}, err
+	}
+	manifest, err := readManifest(tmp.Name())
+	if err != nil {
+		return modpack.Manifest{}, err
+	}
+
+	path := filepath.Join(dir, namespace+"-"+manifest.Name+"-"+manifest.VersionNumber+ZipFileExtension)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrFileRenameFailed, err)
+	}
+	return manifest, nil
+}
+
+// cacheArchive returns where a mod release's archive is kept in the archive cache, downloading it
+// first if it isn't there yet. Releases never change once they're published, so a cached archive
+// doesn't have to be checked against the download.
+func (m *manager) cacheArchive(url, fullName string) (string, error) {
+	path := m.archivePath(fullName)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	// The archive is only moved into the cache once it's fully downloaded
+	if err := os.MkdirAll(filepath.Join(m.cacheDirectory, ArchiveDirectory), os.ModePerm); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+	tmp := path + ".tmp"
+	if err := m.fetchArchive(url, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("%w: %w", ErrFileRenameFailed, err)
+	}
+	return path, nil
+}
+
+// archivePath is where a mod release's archive is kept in the archive cache
+func (m *manager) archivePath(fullName string) string {
+	return filepath.Join(m.cacheDirectory, ArchiveDirectory, fullName+ZipFileExtension)
+}
+
+// fetchArchive writes an archive to the destination, copying it if the location is an absolute
+// local path or downloading it otherwise
+func (m *manager) fetchArchive(location, destination string) error {
+	if filepath.IsAbs(location) {
+		return copyFile(location, destination)
+	}
+
+	// Get the data
+	resp, err := m.client.Get(location)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrHTTPClient, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", api.ErrHTTPClient, resp.StatusCode)
+	}
+	return createFile(destination, resp.Body)
+}
+
+// readManifest reads and validates the manifest.json at the root of a package archive
+func readManifest(path string) (modpack.Manifest, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrZipReadFailed, err)
+	}
+	defer archive.Close()
+
+	f, err := archive.Open(modpack.ManifestFileName)
+	if err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrZipReadFailed, err)
+	}
+
+	manifest := modpack.Manifest{}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, byteOrderMark), &manifest); err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	if err := manifest.ValidatePackage(); err != nil {
+		return modpack.Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	if manifest.Dependencies == nil {
+		manifest.Dependencies = []string{}
+	}
+	return manifest, nil
+}
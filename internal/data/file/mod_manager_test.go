@@ -37,7 +37,7 @@ func TestInstallMod_Happy(t *testing.T) {
 			}, nil
 		},
 	}
-	manager := file.NewManager(&client, th.GetValheimDirectory())
+	manager := newTestManager(t, &client, th.GetValheimDirectory())
 
 	path, err := manager.InstallMod(helper.TestDownloadURL, helper.TestModFullName)
 	if err != nil {
@@ -52,6 +52,43 @@ func TestInstallMod_Happy(t *testing.T) {
 	})
 }
 
+func TestInstallMod_Cached(t *testing.T) {
+	th := helper.NewHelper(t)
+	t.Cleanup(func() {
+		th.RemoveServerFiles()
+	})
+
+	downloads := 0
+	client := mock.HTTPClient{
+		GetFunc: func(_ string) (*http.Response, error) {
+			downloads++
+			archive, err := os.Open(filepath.Join(th.GetDataDirectory(), helper.TestModFullName+file.ZipFileExtension))
+			return &http.Response{StatusCode: http.StatusOK, Body: archive}, err
+		},
+	}
+	manager := newTestManager(t, &client, th.GetValheimDirectory())
+
+	if manager.IsCached(helper.TestModFullName) {
+		t.Errorf("expected the release not to be cached before it's installed")
+	}
+
+	// Installing the same release again, e.g. when switching back to a profile, uses the cached archive
+	for range 2 {
+		if _, err := manager.InstallMod(helper.TestDownloadURL, helper.TestModFullName); err != nil {
+			t.Fatalf("expected a nil error, received: %+v", err)
+		}
+		if err := manager.RemoveMod(helper.TestModFullName); err != nil {
+			t.Fatalf("unexpected error removing test mod, received: %+v", err)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("expected the release to be downloaded once, received: %d downloads", downloads)
+	}
+	if !manager.IsCached(helper.TestModFullName) {
+		t.Errorf("expected the release to be cached")
+	}
+}
+
 func TestInstallMod_Sad(t *testing.T) {
 	th := helper.NewHelper(t)
 
@@ -85,7 +122,7 @@ func TestInstallMod_Sad(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			manager := file.NewManager(tt.client, th.GetValheimDirectory())
+			manager := newTestManager(t, tt.client, th.GetValheimDirectory())
 
 			path, err := manager.InstallMod(helper.TestDownloadURL, tt.fullName)
 			if !errors.Is(err, tt.expectedErr) {
@@ -119,7 +156,7 @@ func TestRemoveMod_Happy(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			manager := file.NewManager(&mock.HTTPClient{}, th.GetValheimDirectory())
+			manager := newTestManager(t, &mock.HTTPClient{}, th.GetValheimDirectory())
 
 			err := manager.RemoveMod(test.name)
 			if err != nil {
@@ -151,7 +188,7 @@ func TestRemoveAllMods_Happy(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			test.setUp(t)
-			manager := file.NewManager(&mock.HTTPClient{}, th.GetValheimDirectory())
+			manager := newTestManager(t, &mock.HTTPClient{}, th.GetValheimDirectory())
 
 			err := manager.RemoveAllMods()
 			if err != nil {
@@ -163,3 +200,130 @@ func TestRemoveAllMods_Happy(t *testing.T) {
 		th.RemoveServerFiles()
 	})
 }
+
+// newTestManager creates a Manager that installs mods to the default mod directory inside the
+// given Valheim directory
+func newTestManager(t *testing.T, c api.HTTPClient, valheimDirectory string) file.Manager {
+	return file.NewManager(c, valheimDirectory, filepath.Join(valheimDirectory, file.BepInExPluginDirectory), t.TempDir())
+}
+
+func TestHasPlugins(t *testing.T) {
+	th := helper.NewHelper(t)
+
+	// A modpack is only a manifest, icon and README
+	modpack := filepath.Join(t.TempDir(), "modpack.zip")
+	writeTestZip(t, modpack, map[string]string{"manifest.json": "{}", "icon.png": "", "README.md": ""})
+
+	tests := map[string]struct {
+		archive  string
+		expected bool
+	}{
+		"return true if the release has a plugin DLL": {
+			archive:  filepath.Join(th.GetDataDirectory(), helper.TestModFullName+file.ZipFileExtension),
+			expected: true,
+		},
+		"return false if the release is a modpack": {
+			archive:  modpack,
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := mock.HTTPClient{
+				GetFunc: func(_ string) (*http.Response, error) {
+					archive, err := os.Open(test.archive)
+					return &http.Response{StatusCode: http.StatusOK, Body: archive}, err
+				},
+			}
+			manager := newTestManager(t, &client, t.TempDir())
+
+			hasPlugins, err := manager.HasPlugins(helper.TestDownloadURL, helper.TestModFullName)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if hasPlugins != test.expected {
+				t.Errorf("expected %t, received: %t", test.expected, hasPlugins)
+			}
+		})
+	}
+}
+
+func TestCachePackage_Happy(t *testing.T) {
+	manifest := "\xef\xbb\xbf" + `{"name": "In_House", "version_number": "1.2.0", "description": "Our plugin", "dependencies": ["Azumatt-Sleepover-1.0.1"]}`
+	archive := filepath.Join(t.TempDir(), "In_House.zip")
+	writeTestZip(t, archive, map[string]string{"manifest.json": manifest, "plugins/InHouse.dll": ""})
+
+	tests := map[string]struct {
+		location string
+		client   *mock.HTTPClient
+	}{
+		"read the manifest of a local archive": {
+			location: archive,
+			client:   &mock.HTTPClient{},
+		},
+		"read the manifest of a downloaded archive": {
+			location: "https://example.com/In_House.zip",
+			client: &mock.HTTPClient{
+				GetFunc: func(_ string) (*http.Response, error) {
+					f, err := os.Open(archive)
+					return &http.Response{StatusCode: http.StatusOK, Body: f}, err
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			valheimDir := t.TempDir()
+			manager := file.NewManager(test.client, valheimDir, filepath.Join(valheimDir, file.BepInExPluginDirectory), cacheDir)
+
+			m, err := manager.CachePackage(test.location, "Local")
+			if err != nil {
+				t.Fatalf("expected a nil error, received: %+v", err)
+			}
+			if m.Name != "In_House" || m.VersionNumber != "1.2.0" || len(m.Dependencies) != 1 {
+				t.Errorf("expected the archive's manifest, received: %+v", m)
+			}
+
+			cached := filepath.Join(cacheDir, file.ArchiveDirectory, "Local-In_House-1.2.0"+file.ZipFileExtension)
+			if _, err := os.Stat(cached); err != nil {
+				t.Errorf("expected the archive to be cached at %s, received: %+v", cached, err)
+			}
+		})
+	}
+}
+
+func TestCachePackage_Sad(t *testing.T) {
+	tests := map[string]struct {
+		files    map[string]string
+		expected error
+	}{
+		"return an error if the archive has no manifest": {
+			files:    map[string]string{"InHouse.dll": ""},
+			expected: file.ErrInvalidManifest,
+		},
+		"return an error if the manifest isn't JSON": {
+			files:    map[string]string{"manifest.json": "name: In_House"},
+			expected: file.ErrInvalidManifest,
+		},
+		"return an error if the manifest's name can't be a folder name": {
+			files:    map[string]string{"manifest.json": `{"name": "../In_House", "version_number": "1.2.0"}`},
+			expected: file.ErrInvalidManifest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "In_House.zip")
+			writeTestZip(t, archive, test.files)
+			manager := newTestManager(t, &mock.HTTPClient{}, t.TempDir())
+
+			_, err := manager.CachePackage(archive, "Local")
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
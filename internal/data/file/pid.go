@@ -0,0 +1,84 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"warden/internal/domain/server"
+)
+
+var (
+	ErrPIDFileNotFound     = errors.New("PID file not found")
+	ErrPIDFileReadFailed   = errors.New("unable to read PID file")
+	ErrPIDFileWriteFailed  = errors.New("unable to write PID file")
+	ErrPIDFileDeleteFailed = errors.New("unable to delete PID file")
+)
+
+// PIDFile records the game server Warden started in the background, so later commands can find it
+type PIDFile interface {
+	// Returns the recorded game server process, or ErrPIDFileNotFound if there isn't one
+	Read() (server.Process, error)
+
+	// Records a game server process, replacing any previous one
+	Write(p server.Process) error
+
+	// Deletes the PID file. Removing a PID file that doesn't exist isn't an error.
+	Remove() error
+
+	// Returns the location of the PID file
+	Path() string
+}
+
+type pidFile struct {
+	path string
+}
+
+func NewPIDFile(path string) PIDFile {
+	return &pidFile{
+		path: path,
+	}
+}
+
+func (f *pidFile) Read() (server.Process, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return server.Process{}, ErrPIDFileNotFound
+	}
+	if err != nil {
+		return server.Process{}, fmt.Errorf("%w: %w", ErrPIDFileReadFailed, err)
+	}
+
+	p := server.Process{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return server.Process{}, fmt.Errorf("%w: %w", ErrPIDFileReadFailed, err)
+	}
+	return p, nil
+}
+
+func (f *pidFile) Write(p server.Process) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPIDFileWriteFailed, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrPIDFileWriteFailed, err)
+	}
+	if err := os.WriteFile(f.path, data, 0644); err != nil {
+		return fmt.Errorf("%w: %w", ErrPIDFileWriteFailed, err)
+	}
+	return nil
+}
+
+func (f *pidFile) Remove() error {
+	err := os.Remove(f.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrPIDFileDeleteFailed, err)
+	}
+	return nil
+}
+
+func (f *pidFile) Path() string {
+	return f.path
+}
@@ -0,0 +1,75 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"warden/internal/data/file"
+	"warden/internal/domain/server"
+)
+
+func TestPIDFile_Happy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warden.pid")
+	f := file.NewPIDFile(path)
+	expected := server.Process{
+		PID:       4242,
+		GameType:  server.Modded,
+		StartedAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.UTC),
+		LogFile:   "/tmp/valheim.log",
+	}
+
+	if err := f.Write(expected); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	p, err := f.Read()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if !p.Equals(&expected) {
+		t.Errorf("expected process: %+v, received: %+v", expected, p)
+	}
+
+	if err := f.Remove(); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected PID file to be deleted, received: %+v", err)
+	}
+	if err := f.Remove(); err != nil {
+		t.Errorf("expected a nil error removing a missing PID file, received: %+v", err)
+	}
+}
+
+func TestPIDFile_Sad(t *testing.T) {
+	tests := map[string]struct {
+		setUp    func(path string)
+		expected error
+	}{
+		"return an error if the PID file doesn't exist": {
+			setUp:    func(_ string) {},
+			expected: file.ErrPIDFileNotFound,
+		},
+		"return an error if the PID file is malformed": {
+			setUp: func(path string) {
+				if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+					t.Errorf("unexpected error setting up PID file, received: %+v", err)
+				}
+			},
+			expected: file.ErrPIDFileReadFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "warden.pid")
+			test.setUp(path)
+
+			_, err := file.NewPIDFile(path).Read()
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
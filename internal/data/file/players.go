@@ -0,0 +1,149 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"warden/internal/domain/player"
+)
+
+const (
+	// Extensions for the files kept next to a player list while it's edited
+	lockFileExtension   = ".lock"
+	backupFileExtension = ".bak"
+
+	// How long to wait for another edit of a player list to finish
+	playerListLockTimeout = 5 * time.Second
+	playerListLockRetry   = 100 * time.Millisecond
+
+	// A lock older than this was left behind by an edit that never finished, e.g. it was killed
+	playerListStaleLock = time.Minute
+)
+
+var (
+	ErrPlayerListReadFailed  = errors.New("unable to read player list")
+	ErrPlayerListWriteFailed = errors.New("unable to write player list")
+	ErrPlayerListLocked      = errors.New("player list is being edited by another process")
+)
+
+// PlayerLists provides an interface for editing the admin, banned and permitted player lists
+// Valheim reads from its save directory.
+type PlayerLists interface {
+	// Returns every line of a player list. A list that doesn't exist yet has no lines.
+	Read(list string) ([]string, error)
+
+	// Locks a player list, then replaces its lines with the ones returned by edit. The previous
+	// list is kept as a backup next to it. If edit returns an error, nothing is written.
+	Edit(list string, edit func(lines []string) ([]string, error)) error
+}
+
+type playerLists struct {
+	saveDirectory string
+}
+
+func NewPlayerLists(saveDirectory string) PlayerLists {
+	return &playerLists{
+		saveDirectory: saveDirectory,
+	}
+}
+
+func (p *playerLists) Read(list string) ([]string, error) {
+	data, err := os.ReadFile(p.path(list))
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return []string{}, fmt.Errorf("%w: %w", ErrPlayerListReadFailed, err)
+	}
+
+	// Lists edited on Windows have CRLF line endings, which the scanner strips
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return []string{}, fmt.Errorf("%w: %w", ErrPlayerListReadFailed, err)
+	}
+	return lines, nil
+}
+
+func (p *playerLists) Edit(list string, edit func(lines []string) ([]string, error)) error {
+	path := p.path(list)
+	if err := os.MkdirAll(p.saveDirectory, os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrPlayerListWriteFailed, err)
+	}
+
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	lines, err := p.Read(list)
+	if err != nil {
+		return err
+	}
+	lines, err = edit(lines)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if err := copyFile(path, path+backupFileExtension); err != nil {
+			return fmt.Errorf("%w: %w", ErrPlayerListWriteFailed, err)
+		}
+	}
+
+	// The new list is written alongside the old one and renamed over it, so the server never
+	// reads a half written list
+	contents := ""
+	if len(lines) > 0 {
+		contents = strings.Join(lines, "\n") + "\n"
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(contents), 0644); err != nil {
+		return fmt.Errorf("%w: %w", ErrPlayerListWriteFailed, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("%w: %w", ErrPlayerListWriteFailed, err)
+	}
+	return nil
+}
+
+func (p *playerLists) path(list string) string {
+	return filepath.Join(p.saveDirectory, player.FileName(list))
+}
+
+// lockFile creates a lock file next to the given file, waiting for any other process holding it to
+// finish. The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	lock := path + lockFileExtension
+	deadline := time.Now().Add(playerListLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%w: %w", ErrPlayerListWriteFailed, err)
+		}
+
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > playerListStaleLock {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrPlayerListLocked, lock)
+		}
+		time.Sleep(playerListLockRetry)
+	}
+}
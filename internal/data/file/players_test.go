@@ -0,0 +1,99 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	"warden/internal/data/file"
+	"warden/internal/domain/player"
+)
+
+func TestEditPlayerList_Happy(t *testing.T) {
+	tests := map[string]struct {
+		existing  string
+		staleLock bool
+		expected  string
+	}{
+		"create the list if it doesn't exist": {
+			expected: "76561198012345678\n",
+		},
+		"append to an existing list and back it up": {
+			existing: "// List admins here\r\nXbox_2535412345678901\r\n",
+			expected: "// List admins here\nXbox_2535412345678901\n76561198012345678\n",
+		},
+		"ignore a lock left behind by an edit that never finished": {
+			existing:  "Xbox_2535412345678901\n",
+			staleLock: true,
+			expected:  "Xbox_2535412345678901\n76561198012345678\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "adminlist.txt")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatalf("unexpected error creating test list, received: %+v", err)
+				}
+			}
+			if tt.staleLock {
+				lock := path + ".lock"
+				if err := os.WriteFile(lock, []byte("1\n"), 0644); err != nil {
+					t.Fatalf("unexpected error creating test lock, received: %+v", err)
+				}
+				old := time.Now().Add(-time.Hour)
+				if err := os.Chtimes(lock, old, old); err != nil {
+					t.Fatalf("unexpected error aging test lock, received: %+v", err)
+				}
+			}
+
+			pl := file.NewPlayerLists(dir)
+			err := pl.Edit(player.Admin, func(lines []string) ([]string, error) {
+				lines, _ = player.Add(lines, "76561198012345678")
+				return lines, nil
+			})
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+
+			if contents, _ := os.ReadFile(path); string(contents) != tt.expected {
+				t.Errorf("expected list: %q, received: %q", tt.expected, contents)
+			}
+			if backup, err := os.ReadFile(path + ".bak"); tt.existing != "" && string(backup) != tt.existing {
+				t.Errorf("expected the previous list to be backed up, received: %q, %+v", backup, err)
+			}
+			if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected the lock to be released, received: %+v", err)
+			}
+		})
+	}
+}
+
+func TestEditPlayerList_Sad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bannedlist.txt")
+	if err := os.WriteFile(path, []byte("76561198012345678\n"), 0644); err != nil {
+		t.Fatalf("unexpected error creating test list, received: %+v", err)
+	}
+	editErr := errors.New("edit failed")
+
+	pl := file.NewPlayerLists(dir)
+	err := pl.Edit(player.Banned, func(lines []string) ([]string, error) {
+		return []string{}, editErr
+	})
+	if !errors.Is(err, editErr) {
+		t.Errorf("expected error: %+v, received: %+v", editErr, err)
+	}
+
+	lines, err := pl.Read(player.Banned)
+	if err != nil || !slices.Equal(lines, []string{"76561198012345678"}) {
+		t.Errorf("expected the list to be left alone, received: %q, %+v", lines, err)
+	}
+	if _, err := os.Stat(path + ".bak"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no backup to be made, received: %+v", err)
+	}
+}
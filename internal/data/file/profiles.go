@@ -0,0 +1,264 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"warden/internal/domain/profile"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Every profile has its own directory, with its mods listed in this file and its BepInEx config
+	// files in a sub-directory
+	profileFileName            = "profile.yaml"
+	profileConfigDirectoryName = "config"
+
+	// Holds the name of the profile that was switched to last
+	activeProfileFileName = ".active"
+)
+
+var (
+	ErrProfileNotFound      = errors.New("profile not found")
+	ErrProfileReadFailed    = errors.New("unable to read profile")
+	ErrProfileWriteFailed   = errors.New("unable to save profile")
+	ErrProfileDeleteFailed  = errors.New("unable to delete profile")
+	ErrProfileRestoreFailed = errors.New("unable to restore profile's config files")
+	ErrProfileImportFailed  = errors.New("unable to import r2modman profile")
+	ErrProfileExportFailed  = errors.New("unable to export r2modman profile")
+	ErrInvalidExport        = errors.New("file isn't an r2modman profile")
+)
+
+// Profiles provides an interface for storing mod profiles, along with copies of the BepInEx
+// config files they're set up with.
+type Profiles interface {
+	// Returns every saved profile, sorted by name
+	List() ([]profile.Profile, error)
+
+	// Returns a saved profile
+	Get(name string) (profile.Profile, error)
+
+	// Saves a profile, along with a copy of every file in the BepInEx config directory. An
+	// existing profile with the same name is replaced. The returned profile lists the config
+	// files that were saved.
+	Save(p profile.Profile) (profile.Profile, error)
+
+	// Replaces everything in the BepInEx config directory with the files saved in a profile
+	RestoreConfig(name string) error
+
+	// Deletes a saved profile, along with its config files
+	Delete(name string) error
+
+	// Returns the name of the profile that was switched to last, or an empty string if there
+	// isn't one
+	Active() (string, error)
+
+	// Records which profile was switched to last. An empty name clears it.
+	SetActive(name string) error
+
+	// Reads the list of mods in an r2modman profile (.r2z) file
+	ReadExport(path string) (profile.Export, error)
+
+	// Saves a profile with the BepInEx config files in an r2modman profile (.r2z) file, instead of
+	// the ones in the BepInEx config directory
+	Import(path string, p profile.Profile) (profile.Profile, error)
+
+	// Writes an r2modman profile (.r2z) file listing the given mods, along with every file in the
+	// BepInEx config directory
+	Export(path string, e profile.Export) error
+}
+
+type profiles struct {
+	backup          Backup
+	directory       string
+	configDirectory string
+}
+
+// NewProfiles creates a Profiles that stores profiles in the given directory, and saves and
+// restores the config files in the BepInEx config directory. The config directory is copied into
+// the cache directory while it's being replaced, so it can be put back if anything goes wrong.
+func NewProfiles(directory, configDirectory, cacheDirectory string) Profiles {
+	return &profiles{
+		backup:          NewBackupIn(cacheDirectory),
+		directory:       directory,
+		configDirectory: configDirectory,
+	}
+}
+
+func (p *profiles) List() ([]profile.Profile, error) {
+	entries, err := os.ReadDir(p.directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return []profile.Profile{}, nil
+	}
+	if err != nil {
+		return []profile.Profile{}, fmt.Errorf("%w: %w", ErrProfileReadFailed, err)
+	}
+
+	list := []profile.Profile{}
+	for _, e := range entries {
+		// Profiles that are still being saved are hidden
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		pr, err := p.Get(e.Name())
+		if err != nil {
+			return []profile.Profile{}, err
+		}
+		list = append(list, pr)
+	}
+	return list, nil
+}
+
+func (p *profiles) Get(name string) (profile.Profile, error) {
+	data, err := os.ReadFile(filepath.Join(p.path(name), profileFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return profile.Profile{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrProfileReadFailed, err)
+	}
+
+	var pr profile.Profile
+	if err := yaml.Unmarshal(data, &pr); err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %s: %w", ErrProfileReadFailed, name, err)
+	}
+	return pr, nil
+}
+
+func (p *profiles) Save(pr profile.Profile) (profile.Profile, error) {
+	return p.save(pr, func(destination string) error {
+		if _, err := os.Stat(p.configDirectory); err != nil {
+			return nil
+		}
+		return copyDirectory(p.configDirectory, destination)
+	})
+}
+
+// save writes a profile, using copyConfig to fill the directory its config files are kept in
+func (p *profiles) save(pr profile.Profile, copyConfig func(destination string) error) (profile.Profile, error) {
+	// The profile is written next to where it goes, then moved into place, so a profile that
+	// fails to save never replaces the previous one
+	if err := os.MkdirAll(p.directory, os.ModePerm); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	tmp, err := os.MkdirTemp(p.directory, "."+pr.Name)
+	if err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	defer os.RemoveAll(tmp)
+
+	config := filepath.Join(tmp, profileConfigDirectoryName)
+	if err := os.MkdirAll(config, os.ModePerm); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	if err := copyConfig(config); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	files, err := listFiles(config)
+	if err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	pr.ConfigFiles = files
+
+	data, err := yaml.Marshal(pr)
+	if err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, profileFileName), data, 0644); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+
+	if err := os.RemoveAll(p.path(pr.Name)); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	if err := os.Rename(tmp, p.path(pr.Name)); err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	return pr, nil
+}
+
+func (p *profiles) RestoreConfig(name string) error {
+	source := filepath.Join(p.path(name), profileConfigDirectoryName)
+	if _, err := os.Stat(source); err != nil {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	p.backup.Create(p.configDirectory)
+	if err := os.RemoveAll(p.configDirectory); err != nil {
+		p.backup.Restore(p.configDirectory)
+		return fmt.Errorf("%w: %w", ErrProfileRestoreFailed, err)
+	}
+	if err := copyDirectory(source, p.configDirectory); err != nil {
+		p.backup.Restore(p.configDirectory)
+		return fmt.Errorf("%w: %w", ErrProfileRestoreFailed, err)
+	}
+	p.backup.Remove()
+	return nil
+}
+
+func (p *profiles) Delete(name string) error {
+	if _, err := os.Stat(p.path(name)); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err := os.RemoveAll(p.path(name)); err != nil {
+		return fmt.Errorf("%w: %w", ErrProfileDeleteFailed, err)
+	}
+
+	if active, _ := p.Active(); active == name {
+		return p.SetActive("")
+	}
+	return nil
+}
+
+func (p *profiles) Active() (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.directory, activeProfileFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrProfileReadFailed, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (p *profiles) SetActive(name string) error {
+	path := filepath.Join(p.directory, activeProfileFileName)
+	if name == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(p.directory, os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("%w: %w", ErrProfileWriteFailed, err)
+	}
+	return nil
+}
+
+func (p *profiles) path(name string) string {
+	return filepath.Join(p.directory, name)
+}
+
+// listFiles returns every file inside a directory, relative to it
+func listFiles(directory string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,99 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/data/file"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/profile"
+)
+
+func TestSaveProfile_Happy(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "BepInEx", "config")
+	writeTestFile(t, filepath.Join(config, "BepInEx.cfg"), "[Logging]\n")
+	writeTestFile(t, filepath.Join(config, "valheim_plus", "valheim_plus.cfg"), "[Player]\nenabled = true\n")
+
+	profiles := file.NewProfiles(filepath.Join(dir, "profiles"), config, t.TempDir())
+	saved, err := profiles.Save(profile.Profile{
+		Name: "vanilla+",
+		Mods: []mod.Mod{{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9"}},
+	})
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	expected := []string{"BepInEx.cfg", "valheim_plus/valheim_plus.cfg"}
+	if !slices.Equal(saved.ConfigFiles, expected) {
+		t.Errorf("expected config files: %v, received: %v", expected, saved.ConfigFiles)
+	}
+
+	list, err := profiles.List()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(list) != 1 || list[0].Name != "vanilla+" || len(list[0].Mods) != 1 || list[0].Mods[0].Version != "0.9.9" {
+		t.Errorf("expected the saved profile to be listed, received: %+v", list)
+	}
+}
+
+func TestRestoreProfileConfig_Happy(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "BepInEx", "config")
+	writeTestFile(t, filepath.Join(config, "BepInEx.cfg"), "[Logging]\n")
+
+	profiles := file.NewProfiles(filepath.Join(dir, "profiles"), config, t.TempDir())
+	if _, err := profiles.Save(profile.Profile{Name: "hardcore"}); err != nil {
+		t.Fatalf("unexpected error saving test profile, received: %+v", err)
+	}
+
+	// Change the config after the profile was saved
+	writeTestFile(t, filepath.Join(config, "BepInEx.cfg"), "[Logging]\nchanged = true\n")
+	writeTestFile(t, filepath.Join(config, "other.cfg"), "")
+
+	if err := profiles.RestoreConfig("hardcore"); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if contents, _ := os.ReadFile(filepath.Join(config, "BepInEx.cfg")); string(contents) != "[Logging]\n" {
+		t.Errorf("expected the saved config to be restored, received: %q", contents)
+	}
+	if _, err := os.Stat(filepath.Join(config, "other.cfg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected config files that aren't in the profile to be removed, received: %+v", err)
+	}
+}
+
+func TestDeleteProfile(t *testing.T) {
+	dir := t.TempDir()
+	profiles := file.NewProfiles(filepath.Join(dir, "profiles"), filepath.Join(dir, "config"), t.TempDir())
+	if _, err := profiles.Save(profile.Profile{Name: "event"}); err != nil {
+		t.Fatalf("unexpected error saving test profile, received: %+v", err)
+	}
+	if err := profiles.SetActive("event"); err != nil {
+		t.Fatalf("unexpected error activating test profile, received: %+v", err)
+	}
+
+	if err := profiles.Delete("event"); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if active, err := profiles.Active(); active != "" || err != nil {
+		t.Errorf("expected no active profile, received: %q, %+v", active, err)
+	}
+	if _, err := profiles.Get("event"); !errors.Is(err, file.ErrProfileNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", file.ErrProfileNotFound, err)
+	}
+	if err := profiles.Delete("event"); !errors.Is(err, file.ErrProfileNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", file.ErrProfileNotFound, err)
+	}
+}
+
+// writeTestFile creates a file, along with any directories it's in
+func writeTestFile(t *testing.T, path, contents string) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating test directory, received: %+v", err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("unexpected error creating test file, received: %+v", err)
+	}
+}
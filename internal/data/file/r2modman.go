@@ -0,0 +1,117 @@
+package file
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"warden/internal/domain/profile"
+
+	"gopkg.in/yaml.v3"
+)
+
+// r2modman profiles keep their config files under this path, the same place they go in the game's
+// directory
+const exportConfigDirectory = "BepInEx/config/"
+
+func (p *profiles) ReadExport(path string) (profile.Export, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return profile.Export{}, fmt.Errorf("%w: %w", ErrInvalidExport, err)
+	}
+	defer archive.Close()
+
+	f, err := archive.Open(profile.ExportFileName)
+	if err != nil {
+		return profile.Export{}, fmt.Errorf("%w: missing %s", ErrInvalidExport, profile.ExportFileName)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return profile.Export{}, fmt.Errorf("%w: %w", ErrProfileImportFailed, err)
+	}
+	var e profile.Export
+	if err := yaml.Unmarshal(data, &e); err != nil {
+		return profile.Export{}, fmt.Errorf("%w: %w", ErrInvalidExport, err)
+	}
+	return e, nil
+}
+
+func (p *profiles) Import(path string, pr profile.Profile) (profile.Profile, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return pr, fmt.Errorf("%w: %w", ErrInvalidExport, err)
+	}
+	defer archive.Close()
+
+	saved, err := p.save(pr, func(destination string) error {
+		for _, f := range archive.File {
+			if err := extractConfigFile(f, destination); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return saved, fmt.Errorf("%w: %w", ErrProfileImportFailed, err)
+	}
+	return saved, nil
+}
+
+func (p *profiles) Export(path string, e profile.Export) error {
+	data, err := yaml.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrProfileExportFailed, err)
+	}
+
+	// Every config file goes in the archive, under the folder r2modman extracts them from
+	entries := []ZipEntry{{Name: profile.ExportFileName, Data: data}}
+	if _, err := os.Stat(p.configDirectory); err == nil {
+		files, err := listFiles(p.configDirectory)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrProfileExportFailed, err)
+		}
+		for _, f := range files {
+			entries = append(entries, ZipEntry{Name: exportConfigDirectory + f, Source: filepath.Join(p.configDirectory, f)})
+		}
+	}
+
+	// The archive is written next to where it goes, so a failed export never replaces an older one
+	tmp := path + ".tmp"
+	if err := WriteZip(tmp, entries); err != nil {
+		return fmt.Errorf("%w: %w", ErrProfileExportFailed, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("%w: %w", ErrProfileExportFailed, err)
+	}
+	return nil
+}
+
+// extractConfigFile copies a file from an r2modman profile archive into the destination, if it's
+// one of the profile's config files. Profiles are shared between players, so anything that would
+// end up outside the destination is rejected.
+func extractConfigFile(f *zip.File, destination string) error {
+	name := strings.ReplaceAll(f.Name, "\\", "/")
+	rel, ok := strings.CutPrefix(name, exportConfigDirectory)
+	if !ok || rel == "" || f.FileInfo().IsDir() {
+		return nil
+	}
+	if !filepath.IsLocal(rel) {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, f.Name)
+	}
+
+	path := filepath.Join(destination, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileOpenFailed, err)
+	}
+	defer src.Close()
+	return createFile(path, src)
+}
@@ -0,0 +1,110 @@
+package file_test
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/data/file"
+	"warden/internal/domain/profile"
+)
+
+func TestExportProfile_Happy(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "BepInEx", "config")
+	writeTestFile(t, filepath.Join(config, "BepInEx.cfg"), "[Logging]\n")
+	writeTestFile(t, filepath.Join(config, "valheim_plus", "valheim_plus.cfg"), "[Player]\n")
+
+	profiles := file.NewProfiles(filepath.Join(dir, "profiles"), config, t.TempDir())
+	export := profile.Export{
+		ProfileName: "server",
+		Mods: []profile.ExportMod{{
+			Name:    "Azumatt-Sleepover",
+			Version: profile.ExportVersion{Major: 1, Patch: 1},
+			Enabled: true,
+		}},
+	}
+	path := filepath.Join(dir, "server.r2z")
+	if err := profiles.Export(path, export); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	read, err := profiles.ReadExport(path)
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if read.ProfileName != "server" || len(read.Mods) != 1 || read.Mods[0] != export.Mods[0] {
+		t.Errorf("expected export: %+v, received: %+v", export, read)
+	}
+
+	imported, err := profiles.Import(path, profile.Profile{Name: "imported"})
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	expected := []string{"BepInEx.cfg", "valheim_plus/valheim_plus.cfg"}
+	if !slices.Equal(imported.ConfigFiles, expected) {
+		t.Errorf("expected config files: %v, received: %v", expected, imported.ConfigFiles)
+	}
+}
+
+func TestImportProfile_Sad(t *testing.T) {
+	tests := map[string]struct {
+		files    map[string]string
+		expected error
+	}{
+		"return an error if the archive has no export file": {
+			files:    map[string]string{"BepInEx/config/BepInEx.cfg": ""},
+			expected: file.ErrInvalidExport,
+		},
+		"return an error if a config file would end up outside the profile": {
+			files: map[string]string{
+				profile.ExportFileName:         "profileName: server\nmods: []\n",
+				"BepInEx/config/../../evil.sh": "",
+			},
+			expected: file.ErrInvalidExport,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "server.r2z")
+			writeTestZip(t, path, test.files)
+			profiles := file.NewProfiles(filepath.Join(dir, "profiles"), filepath.Join(dir, "config"), t.TempDir())
+
+			_, err := profiles.ReadExport(path)
+			if err == nil {
+				_, err = profiles.Import(path, profile.Profile{Name: "server"})
+			}
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if _, err := profiles.Get("server"); !errors.Is(err, file.ErrProfileNotFound) {
+				t.Errorf("expected the profile not to be saved, received: %+v", err)
+			}
+		})
+	}
+}
+
+// writeTestZip creates a zip archive with the given files in it
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unexpected error creating test archive, received: %+v", err)
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	for name, contents := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("unexpected error creating test archive, received: %+v", err)
+		}
+		w.Write([]byte(contents))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("unexpected error creating test archive, received: %+v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// How often a followed log is checked for new lines
+const tailPollInterval = 250 * time.Millisecond
+
+var (
+	ErrLogNotFound   = errors.New("log file does not exist")
+	ErrLogReadFailed = errors.New("unable to read log file")
+)
+
+// Tail calls fn with the last n lines of a log, or every line if n is negative. If follow is set,
+// it then keeps calling fn with every line added to the log until the context is cancelled. A log
+// that's truncated or replaced, e.g. when it's rotated, is read again from the start.
+func Tail(ctx context.Context, path string, n int, follow bool, fn func(line string)) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrLogNotFound, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLogReadFailed, err)
+	}
+	defer func() { f.Close() }()
+
+	last, offset, err := lastLines(f, n, !follow)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLogReadFailed, err)
+	}
+	for _, l := range last {
+		fn(l)
+	}
+	if !follow {
+		return nil
+	}
+
+	partial := ""
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if replaced(f, path, offset) {
+			if next, err := os.Open(path); err == nil {
+				f.Close()
+				f, offset, partial = next, 0, ""
+			}
+		}
+
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("%w: %w", ErrLogReadFailed, err)
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrLogReadFailed, err)
+		}
+		offset += int64(len(data))
+
+		// Only complete lines are passed on, the rest waits for the next check
+		lines := strings.Split(partial+string(data), "\n")
+		partial = lines[len(lines)-1]
+		for _, l := range lines[:len(lines)-1] {
+			fn(strings.TrimSuffix(l, "\r"))
+		}
+	}
+}
+
+// lastLines reads a whole log, keeping only its last n lines (all of them if n is negative), and
+// returns how far it read. A last line that's still being written is only included if partial is
+// set, otherwise it's left for follow to pick up once it's complete.
+func lastLines(f *os.File, n int, partial bool) ([]string, int64, error) {
+	lines := []string{}
+	var offset int64
+
+	r := bufio.NewReader(f)
+	for {
+		l, err := r.ReadString('\n')
+		if err == io.EOF && (!partial || l == "") {
+			return lines, offset, nil
+		}
+		if err != nil && err != io.EOF {
+			return lines, offset, err
+		}
+		offset += int64(len(l))
+		if n == 0 {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(l, "\r\n"))
+		if n > 0 && len(lines) > n {
+			lines = lines[1:]
+		}
+		if err == io.EOF {
+			return lines, offset, nil
+		}
+	}
+}
+
+// replaced checks if the log at path is no longer the open file, or has shrunk since it was read
+func replaced(f *os.File, path string, offset int64) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	open, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(open, current) || current.Size() < offset
+}
@@ -0,0 +1,116 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+	"warden/internal/data/file"
+)
+
+func TestTail_Happy(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		n        int
+		expected []string
+	}{
+		"return the last lines of the log": {
+			content:  "one\ntwo\nthree\n",
+			n:        2,
+			expected: []string{"two", "three"},
+		},
+		"return every line if there are fewer than asked for": {
+			content:  "one\r\ntwo\n",
+			n:        10,
+			expected: []string{"one", "two"},
+		},
+		"include a last line without a newline": {
+			content:  "one\ntwo",
+			n:        10,
+			expected: []string{"one", "two"},
+		},
+		"return every line if n is negative": {
+			content:  "one\ntwo\nthree\n",
+			n:        -1,
+			expected: []string{"one", "two", "three"},
+		},
+		"return nothing if no lines are asked for": {
+			content:  "one\ntwo\n",
+			n:        0,
+			expected: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "server.log")
+			if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+				t.Errorf("unexpected error creating test log, received: %+v", err)
+			}
+
+			lines := []string{}
+			err := file.Tail(context.Background(), path, test.n, false, func(l string) {
+				lines = append(lines, l)
+			})
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if !slices.Equal(lines, test.expected) {
+				t.Errorf("expected lines: %q, received: %q", test.expected, lines)
+			}
+		})
+	}
+}
+
+func TestTail_Follow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Errorf("unexpected error creating test log, received: %+v", err)
+	}
+
+	var mu sync.Mutex
+	lines := []string{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- file.Tail(ctx, path, 1, true, func(l string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, l)
+		})
+	}()
+
+	// Append a line in two writes, then replace the log like a rotation would
+	time.Sleep(300 * time.Millisecond)
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("appen")
+	time.Sleep(300 * time.Millisecond)
+	f.WriteString("ded\n")
+	f.Close()
+	time.Sleep(300 * time.Millisecond)
+	os.Rename(path, path+".1")
+	os.WriteFile(path, []byte("rotated\n"), 0644)
+	time.Sleep(500 * time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"old", "appended", "rotated"}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("expected lines: %q, received: %q", expected, lines)
+	}
+}
+
+func TestTail_Sad(t *testing.T) {
+	err := file.Tail(context.Background(), filepath.Join(t.TempDir(), "missing.log"), 10, false, func(string) {})
+	if !errors.Is(err, file.ErrLogNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", file.ErrLogNotFound, err)
+	}
+}
@@ -0,0 +1,157 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"warden/internal/domain/world"
+)
+
+const (
+	// Dedicated servers keep their worlds in a sub-directory of the save directory
+	WorldsDirectory = "worlds_local"
+)
+
+var (
+	ErrWorldNotFound         = errors.New("world save files not found")
+	ErrWorldListFailed       = errors.New("unable to list worlds")
+	ErrWorldBackupFailed     = errors.New("unable to back up world")
+	ErrWorldRestoreFailed    = errors.New("unable to restore world backup")
+	ErrSnapshotListFailed    = errors.New("unable to list world backups")
+	ErrSnapshotDeleteFailed  = errors.New("unable to delete world backup")
+	ErrSnapshotAlreadyExists = errors.New("a world backup with the same timestamp already exists")
+)
+
+// Worlds provides an interface for backing up and restoring Valheim world save files.
+type Worlds interface {
+	// Returns the names of every world in the save directory
+	ListWorlds() ([]string, error)
+
+	// Copies the given world's save files into a new zip archive in the backup directory
+	Backup(name string, createdAt time.Time) (world.Snapshot, error)
+
+	// Returns every world backup in the backup directory, oldest first
+	ListSnapshots() ([]world.Snapshot, error)
+
+	// Extracts a world backup over the top of the world's current save files
+	Restore(s world.Snapshot) error
+
+	// Deletes a world backup
+	Delete(s world.Snapshot) error
+}
+
+type worlds struct {
+	worldDirectory  string
+	backupDirectory string
+}
+
+func NewWorlds(saveDirectory, backupDirectory string) Worlds {
+	return &worlds{
+		worldDirectory:  filepath.Join(saveDirectory, WorldsDirectory),
+		backupDirectory: backupDirectory,
+	}
+}
+
+func (w *worlds) ListWorlds() ([]string, error) {
+	entries, err := os.ReadDir(w.worldDirectory)
+	if errors.Is(err, os.ErrNotExist) {
+		// No worlds have been created yet
+		return []string{}, nil
+	}
+	if err != nil {
+		return []string{}, fmt.Errorf("%w: %w", ErrWorldListFailed, err)
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != world.MetadataFileExtension {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), world.MetadataFileExtension))
+	}
+	return names, nil
+}
+
+func (w *worlds) Backup(name string, createdAt time.Time) (world.Snapshot, error) {
+	files := []string{}
+	for _, ext := range []string{world.DataFileExtension, world.MetadataFileExtension} {
+		path := filepath.Join(w.worldDirectory, name+ext)
+		if _, err := os.Stat(path); err != nil {
+			return world.Snapshot{}, fmt.Errorf("%w: %w", ErrWorldNotFound, err)
+		}
+		files = append(files, path)
+	}
+
+	if err := os.MkdirAll(w.backupDirectory, os.ModePerm); err != nil {
+		return world.Snapshot{}, fmt.Errorf("%w: %w", ErrDirectoryCreateFailed, err)
+	}
+
+	s := world.Snapshot{
+		World:     name,
+		CreatedAt: createdAt.Truncate(time.Second),
+	}
+	s.FilePath = filepath.Join(w.backupDirectory, s.ID()+ZipFileExtension)
+
+	if _, err := os.Stat(s.FilePath); err == nil {
+		return world.Snapshot{}, ErrSnapshotAlreadyExists
+	}
+	if err := Zip(s.FilePath, files); err != nil {
+		return world.Snapshot{}, fmt.Errorf("%w: %w", ErrWorldBackupFailed, err)
+	}
+	return s, nil
+}
+
+func (w *worlds) ListSnapshots() ([]world.Snapshot, error) {
+	entries, err := os.ReadDir(w.backupDirectory)
+	if errors.Is(err, os.ErrNotExist) {
+		// Nothing has been backed up yet
+		return []world.Snapshot{}, nil
+	}
+	if err != nil {
+		return []world.Snapshot{}, fmt.Errorf("%w: %w", ErrSnapshotListFailed, err)
+	}
+
+	snapshots := []world.Snapshot{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ZipFileExtension {
+			continue
+		}
+		name, createdAt, ok := world.ParseID(strings.TrimSuffix(e.Name(), ZipFileExtension))
+		if !ok {
+			// Not a world backup, so leave it alone
+			continue
+		}
+		snapshots = append(snapshots, world.Snapshot{
+			World:     name,
+			CreatedAt: createdAt,
+			FilePath:  filepath.Join(w.backupDirectory, e.Name()),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+func (w *worlds) Restore(s world.Snapshot) error {
+	if _, err := os.Stat(s.FilePath); err != nil {
+		return fmt.Errorf("%w: %w", ErrBackupMissing, err)
+	}
+	if err := Unzip(s.FilePath, w.worldDirectory); err != nil {
+		return fmt.Errorf("%w: %w", ErrWorldRestoreFailed, err)
+	}
+	return nil
+}
+
+func (w *worlds) Delete(s world.Snapshot) error {
+	err := os.Remove(s.FilePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrSnapshotDeleteFailed, err)
+	}
+	return nil
+}
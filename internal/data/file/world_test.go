@@ -0,0 +1,171 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"warden/internal/data/file"
+	"warden/internal/domain/world"
+)
+
+func TestListWorlds_Happy(t *testing.T) {
+	tests := map[string]struct {
+		worlds   []string
+		expected int
+	}{
+		"return an empty list if no worlds have been created": {
+			worlds:   []string{},
+			expected: 0,
+		},
+		"return every world in the save directory": {
+			worlds:   []string{"Dedicated", "Other"},
+			expected: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			saveDir := t.TempDir()
+			for _, w := range test.worlds {
+				createTestWorld(t, saveDir, w, "world data")
+			}
+			ws := file.NewWorlds(saveDir, t.TempDir())
+
+			worlds, err := ws.ListWorlds()
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if len(worlds) != test.expected {
+				t.Errorf("expected %d worlds, received: %d", test.expected, len(worlds))
+			}
+		})
+	}
+}
+
+func TestBackup_Happy(t *testing.T) {
+	saveDir, backupDir := t.TempDir(), t.TempDir()
+	createTestWorld(t, saveDir, "Dedicated", "world data")
+	createdAt := time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local)
+
+	ws := file.NewWorlds(saveDir, backupDir)
+
+	s, err := ws.Backup("Dedicated", createdAt)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	expected := world.Snapshot{
+		World:     "Dedicated",
+		CreatedAt: createdAt,
+		FilePath:  filepath.Join(backupDir, "Dedicated-20240302T183005.zip"),
+	}
+	if !s.Equals(&expected) {
+		t.Errorf("expected snapshot: %+v, received: %+v", expected, s)
+	}
+
+	snapshots, err := ws.ListSnapshots()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(snapshots) != 1 || !snapshots[0].Equals(&expected) {
+		t.Errorf("expected snapshots: [%+v], received: %+v", expected, snapshots)
+	}
+}
+
+func TestBackup_Sad(t *testing.T) {
+	saveDir, backupDir := t.TempDir(), t.TempDir()
+	createTestWorld(t, saveDir, "Dedicated", "world data")
+	createdAt := time.Now()
+
+	tests := map[string]struct {
+		world    string
+		expected error
+	}{
+		"return an error if the world doesn't exist": {
+			world:    "Missing",
+			expected: file.ErrWorldNotFound,
+		},
+		"return an error if a backup already exists for the same time": {
+			world:    "Dedicated",
+			expected: file.ErrSnapshotAlreadyExists,
+		},
+	}
+
+	ws := file.NewWorlds(saveDir, backupDir)
+	if _, err := ws.Backup("Dedicated", createdAt); err != nil {
+		t.Errorf("unexpected error during test set-up, received: %+v", err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ws.Backup(test.world, createdAt)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestRestoreWorld_Happy(t *testing.T) {
+	saveDir := t.TempDir()
+	createTestWorld(t, saveDir, "Dedicated", "original")
+
+	ws := file.NewWorlds(saveDir, t.TempDir())
+	s, err := ws.Backup("Dedicated", time.Now())
+	if err != nil {
+		t.Errorf("unexpected error during test set-up, received: %+v", err)
+	}
+	createTestWorld(t, saveDir, "Dedicated", "changed")
+
+	if err := ws.Restore(s); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(saveDir, file.WorldsDirectory, "Dedicated"+world.DataFileExtension))
+	if err != nil {
+		t.Errorf("unexpected error reading restored world, received: %+v", err)
+	}
+	if string(data) != "original" {
+		t.Errorf("expected restored world data: original, received: %s", data)
+	}
+}
+
+func TestRestoreWorld_Sad(t *testing.T) {
+	ws := file.NewWorlds(t.TempDir(), t.TempDir())
+
+	err := ws.Restore(world.Snapshot{FilePath: "missing.zip"})
+	if !errors.Is(err, file.ErrBackupMissing) {
+		t.Errorf("expected error: %+v, received: %+v", file.ErrBackupMissing, err)
+	}
+}
+
+func TestDelete_Happy(t *testing.T) {
+	saveDir := t.TempDir()
+	createTestWorld(t, saveDir, "Dedicated", "world data")
+
+	ws := file.NewWorlds(saveDir, t.TempDir())
+	s, err := ws.Backup("Dedicated", time.Now())
+	if err != nil {
+		t.Errorf("unexpected error during test set-up, received: %+v", err)
+	}
+
+	if err := ws.Delete(s); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if _, err := os.Stat(s.FilePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected backup to be deleted, received: %+v", err)
+	}
+}
+
+func createTestWorld(t *testing.T, saveDir, name, content string) {
+	dir := filepath.Join(saveDir, file.WorldsDirectory)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Errorf("unexpected error creating test world folder, received: %+v", err)
+	}
+	for _, ext := range []string{world.DataFileExtension, world.MetadataFileExtension} {
+		if err := os.WriteFile(filepath.Join(dir, name+ext), []byte(content), 0644); err != nil {
+			t.Errorf("unexpected error creating test world, received: %+v", err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -40,9 +41,30 @@ func CreateModsTable(db Database) {
 		"websiteUrl" TEXT,
 		"description" TEXT,
 		"frameworkId" INTEGER NOT NULL, 
+		"gameBuild" TEXT NOT NULL DEFAULT '',
+		"updatedAt" DATETIME,
+		"categories" TEXT NOT NULL DEFAULT '',
+		"deprecated" BOOLEAN NOT NULL DEFAULT 0,
+		"modpack" BOOLEAN NOT NULL DEFAULT 0,
+		"parent" TEXT NOT NULL DEFAULT '',
+		"source" TEXT NOT NULL DEFAULT 'thunderstore',
+		"location" TEXT NOT NULL DEFAULT '',
 		FOREIGN KEY (frameworkId) REFERENCES frameworks(id)
 	  );`
 	createTable(db, modsTableSQL)
+
+	// Databases created before mods tracked game compatibility need the new columns added
+	addColumn(db, "mods", "gameBuild", `TEXT NOT NULL DEFAULT ''`)
+	addColumn(db, "mods", "updatedAt", `DATETIME`)
+	addColumn(db, "mods", "categories", `TEXT NOT NULL DEFAULT ''`)
+	addColumn(db, "mods", "deprecated", `BOOLEAN NOT NULL DEFAULT 0`)
+
+	// and the ones installed from modpacks
+	addColumn(db, "mods", "modpack", `BOOLEAN NOT NULL DEFAULT 0`)
+	addColumn(db, "mods", "parent", `TEXT NOT NULL DEFAULT ''`)
+	// and where they were installed from. Every mod was installed from Thunderstore before then.
+	addColumn(db, "mods", "source", `TEXT NOT NULL DEFAULT 'thunderstore'`)
+	addColumn(db, "mods", "location", `TEXT NOT NULL DEFAULT ''`)
 }
 
 func CreateFrameworksTable(db Database) {
@@ -57,6 +79,39 @@ func CreateFrameworksTable(db Database) {
 	createTable(db, frameworksTableSQL)
 }
 
+func CreateJobRunsTable(db Database) {
+	jobRunsTableSQL := `CREATE TABLE IF NOT EXISTS jobRuns (
+		"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		"job" TEXT NOT NULL,
+		"startedAt" DATETIME NOT NULL,
+		"finishedAt" DATETIME NOT NULL,
+		"status" TEXT NOT NULL,
+		"message" TEXT
+	  );`
+	createTable(db, jobRunsTableSQL)
+}
+
+// addColumn adds a column to the end of an existing table, unless it's already there
+func addColumn(db Database, table, column, definition string) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			log.Fatal(err.Error())
+		}
+		if name == column {
+			rows.Close()
+			return
+		}
+	}
+	rows.Close()
+	createTable(db, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN "%s" %s;`, table, column, definition))
+}
+
 func createTable(db Database, query string) {
 	statement, err := db.Prepare(query)
 	if err != nil {
@@ -3,6 +3,7 @@ package repo
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"warden/internal/domain/framework"
 )
 
@@ -37,13 +38,13 @@ func NewFrameworksRepo(db Database) Frameworks {
 func (fr *frameworks) GetFramework(name string) (framework.Framework, error) {
 	rows, err := fr.db.Query(`SELECT * FROM frameworks WHERE name = ?`, name)
 	if err != nil {
-		return framework.Framework{}, ErrFrameworkFetchFailed
+		return framework.Framework{}, fmt.Errorf("%w: %w", ErrFrameworkFetchFailed, err)
 	}
 	defer rows.Close()
 
 	frameworks, err := mapRowsToFramework(rows)
 	if err != nil {
-		return framework.Framework{}, ErrFrameworkMappingFailed
+		return framework.Framework{}, fmt.Errorf("%w: %w", ErrFrameworkMappingFailed, err)
 	}
 	if len(frameworks) == 0 {
 		return framework.Framework{}, ErrFrameworkFetchNoResults
@@ -60,20 +61,20 @@ func (fr *frameworks) InsertFramework(f framework.Framework) error {
 
 	tx, err := fr.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := fr.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
 	_, err = statement.Exec(f.Name, f.Namespace, f.Version, f.WebsiteURL, f.Description)
 	if err != nil {
 		tx.Rollback()
-		return ErrFrameworkInsertFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkInsertFailed, err)
 	}
 	return tx.Commit()
 }
@@ -85,20 +86,20 @@ func (fr *frameworks) UpdateFramework(f framework.Framework) error {
 
 	tx, err := fr.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := fr.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
 	_, err = statement.Exec(f.Name, f.Namespace, f.Version, f.WebsiteURL, f.Description, f.ID)
 	if err != nil {
 		tx.Rollback()
-		return ErrFrameworkUpdateFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkUpdateFailed, err)
 	}
 	return tx.Commit()
 }
@@ -108,19 +109,19 @@ func (fr *frameworks) DeleteFramework(name string) error {
 
 	tx, err := fr.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := fr.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 
 	_, err = statement.Exec(name)
 	if err != nil {
 		tx.Rollback()
-		return ErrFrameworkDeleteFailed
+		return fmt.Errorf("%w: %w", ErrFrameworkDeleteFailed, err)
 	}
 	return tx.Commit()
 }
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"warden/internal/domain/schedule"
+)
+
+var (
+	ErrJobRunListFailed    = errors.New("unable to return list of records from jobRuns table")
+	ErrJobRunInsertFailed  = errors.New("unable to insert new record into jobRuns table")
+	ErrJobRunMappingFailed = errors.New("unable to map job run record to struct")
+)
+
+// Jobs stores the history of scheduled job runs
+type Jobs interface {
+	// Lists the most recent job runs, newest first. A limit of zero lists every run.
+	ListJobRuns(limit int) ([]schedule.Run, error)
+	InsertJobRun(r schedule.Run) error
+}
+
+type jobs struct {
+	db Database
+}
+
+func NewJobsRepo(db Database) Jobs {
+	return &jobs{
+		db: db,
+	}
+}
+
+func (r *jobs) ListJobRuns(limit int) ([]schedule.Run, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := r.db.Query(`SELECT * FROM jobRuns ORDER BY startedAt DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return []schedule.Run{}, fmt.Errorf("%w: %w", ErrJobRunListFailed, err)
+	}
+	defer rows.Close()
+
+	runs, err := mapRowsToJobRun(rows)
+	if err != nil {
+		return []schedule.Run{}, fmt.Errorf("%w: %w", ErrJobRunMappingFailed, err)
+	}
+	return runs, nil
+}
+
+func (r *jobs) InsertJobRun(run schedule.Run) error {
+	sql := `INSERT INTO jobRuns(job, startedAt, finishedAt, status, message) VALUES (?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
+	}
+
+	statement, err := r.db.Prepare(sql)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
+	}
+	defer statement.Close()
+
+	// Times are stored in UTC so runs sort correctly across time zone changes
+	_, err = statement.Exec(run.Job, run.StartedAt.UTC(), run.FinishedAt.UTC(), run.Status, run.Message)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%w: %w", ErrJobRunInsertFailed, err)
+	}
+	return tx.Commit()
+}
+
+func mapRowsToJobRun(rows *sql.Rows) ([]schedule.Run, error) {
+	runs := []schedule.Run{}
+
+	for rows.Next() {
+		var id int
+		var job string
+		var startedAt time.Time
+		var finishedAt time.Time
+		var status string
+		var message sql.NullString
+
+		err := rows.Scan(&id, &job, &startedAt, &finishedAt, &status, &message)
+		if err != nil {
+			return []schedule.Run{}, err
+		}
+
+		r := schedule.Run{
+			ID:         id,
+			Job:        job,
+			StartedAt:  startedAt,
+			FinishedAt: finishedAt,
+			Status:     status,
+			Message:    message.String,
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
@@ -0,0 +1,83 @@
+package repo_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"warden/internal/data/repo"
+	"warden/internal/domain/schedule"
+	"warden/internal/test/helper"
+	"warden/internal/test/mock"
+)
+
+func TestListJobRuns_Happy(t *testing.T) {
+	startedAt := time.Date(2024, time.March, 2, 4, 0, 0, 0, time.UTC)
+	runs := []schedule.Run{
+		{Job: schedule.Backup, StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second), Status: schedule.Succeeded},
+		{Job: schedule.Update, StartedAt: startedAt.Add(time.Hour), FinishedAt: startedAt.Add(time.Hour), Status: schedule.Failed, Message: "unable to update mod"},
+		{Job: schedule.Restart, StartedAt: startedAt.Add(2 * time.Hour), FinishedAt: startedAt.Add(2 * time.Hour), Status: schedule.Skipped},
+	}
+
+	tests := map[string]struct {
+		limit    int
+		expected []int
+	}{
+		"list every run, newest first": {
+			limit:    0,
+			expected: []int{3, 2, 1},
+		},
+		"list only the most recent runs": {
+			limit:    2,
+			expected: []int{3, 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			th := helper.NewHelper(t)
+			db := th.CreateDatabase()
+			repo.CreateJobRunsTable(db)
+			jr := repo.NewJobsRepo(db)
+
+			for _, r := range runs {
+				if err := jr.InsertJobRun(r); err != nil {
+					t.Errorf("unexpected error seeding job runs, received: %+v", err)
+				}
+			}
+
+			result, err := jr.ListJobRuns(test.limit)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if len(result) != len(test.expected) {
+				t.Fatalf("expected %d runs, received: %+v", len(test.expected), result)
+			}
+			for i, id := range test.expected {
+				expected := runs[id-1]
+				expected.ID = id
+				if !result[i].Equals(&expected) {
+					t.Errorf("expected run: %+v, received: %+v", expected, result[i])
+				}
+			}
+
+			t.Cleanup(func() {
+				th.DeleteDatabase()
+			})
+		})
+	}
+}
+
+func TestListJobRuns_Sad(t *testing.T) {
+	db := &mock.Database{
+		QueryFunc: func(_ string, _ ...any) (*sql.Rows, error) {
+			return nil, sql.ErrConnDone
+		},
+	}
+	jr := repo.NewJobsRepo(db)
+
+	_, err := jr.ListJobRuns(0)
+	if !errors.Is(err, repo.ErrJobRunListFailed) {
+		t.Errorf("expected error: %+v, received: %+v", repo.ErrJobRunListFailed, err)
+	}
+}
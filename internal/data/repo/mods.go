@@ -3,6 +3,9 @@ package repo
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 	"warden/internal/domain/mod"
 )
 
@@ -46,13 +49,13 @@ func NewModsRepo(db Database) Mods {
 func (r *mods) ListMods() ([]mod.Mod, error) {
 	rows, err := r.db.Query(`SELECT * FROM mods`)
 	if err != nil {
-		return []mod.Mod{}, ErrModListFailed
+		return []mod.Mod{}, fmt.Errorf("%w: %w", ErrModListFailed, err)
 	}
 	defer rows.Close()
 
 	mods, err := mapRowsToMod(rows)
 	if err != nil {
-		return []mod.Mod{}, ErrModMappingFailed
+		return []mod.Mod{}, fmt.Errorf("%w: %w", ErrModMappingFailed, err)
 	}
 	return mods, nil
 }
@@ -60,13 +63,13 @@ func (r *mods) ListMods() ([]mod.Mod, error) {
 func (r *mods) GetMod(name string) (mod.Mod, error) {
 	rows, err := r.db.Query(`SELECT * FROM mods WHERE name = ?`, name)
 	if err != nil {
-		return mod.Mod{}, ErrModFetchFailed
+		return mod.Mod{}, fmt.Errorf("%w: %w", ErrModFetchFailed, err)
 	}
 	defer rows.Close()
 
 	mods, err := mapRowsToMod(rows)
 	if err != nil {
-		return mod.Mod{}, ErrModMappingFailed
+		return mod.Mod{}, fmt.Errorf("%w: %w", ErrModMappingFailed, err)
 	}
 	if len(mods) == 0 {
 		return mod.Mod{}, ErrModFetchNoResults
@@ -79,48 +82,52 @@ func (r *mods) GetMod(name string) (mod.Mod, error) {
 }
 
 func (r *mods) InsertMod(m mod.Mod) error {
-	sql := `INSERT INTO mods(name, namespace, filePath, version, websiteUrl, description, frameworkId) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	sql := `INSERT INTO mods(name, namespace, filePath, version, websiteUrl, description, frameworkId, gameBuild, updatedAt, categories, deprecated, modpack, parent, source, location) 
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	tx, err := r.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 	statement, err := tx.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
-	_, err = statement.Exec(m.Name, m.Namespace, m.FilePath, m.Version, m.WebsiteURL, m.Description, m.FrameworkID)
+	_, err = statement.Exec(m.Name, m.Namespace, m.FilePath, m.Version, m.WebsiteURL, m.Description, m.FrameworkID,
+		m.GameBuild, nullTime(m.UpdatedAt), strings.Join(m.Categories, ","), m.Deprecated, m.Modpack, m.Parent, m.Source, m.Location)
 	if err != nil {
 		tx.Rollback()
-		return ErrModInsertFailed
+		return fmt.Errorf("%w: %w", ErrModInsertFailed, err)
 	}
 	return tx.Commit()
 }
 
 func (r *mods) UpdateMod(m mod.Mod) error {
 	sql := `UPDATE mods 
-			SET name = ?, namespace = ?, filePath = ?, version = ?, websiteUrl = ?, description = ?
+			SET name = ?, namespace = ?, filePath = ?, version = ?, websiteUrl = ?, description = ?,
+				gameBuild = ?, updatedAt = ?, categories = ?, deprecated = ?, modpack = ?, parent = ?, source = ?, location = ?
 			WHERE id = ?`
 
 	tx, err := r.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := r.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
-	_, err = statement.Exec(m.Name, m.Namespace, m.FilePath, m.Version, m.WebsiteURL, m.Description, m.ID)
+	_, err = statement.Exec(m.Name, m.Namespace, m.FilePath, m.Version, m.WebsiteURL, m.Description,
+		m.GameBuild, nullTime(m.UpdatedAt), strings.Join(m.Categories, ","), m.Deprecated, m.Modpack, m.Parent, m.Source, m.Location, m.ID)
 	if err != nil {
 		tx.Rollback()
-		return ErrModUpdateFailed
+		return fmt.Errorf("%w: %w", ErrModUpdateFailed, err)
 	}
 	return tx.Commit()
 }
@@ -143,20 +150,20 @@ func (r *mods) DeleteMod(modName, namespace string) error {
 
 	tx, err := r.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := r.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
 	_, err = statement.Exec(modName, namespace)
 	if err != nil {
 		tx.Rollback()
-		return ErrModDeleteFailed
+		return fmt.Errorf("%w: %w", ErrModDeleteFailed, err)
 	}
 	return tx.Commit()
 }
@@ -168,20 +175,20 @@ func (r *mods) DeleteAllMods() error {
 
 	tx, err := r.db.Begin()
 	if err != nil {
-		return ErrTransactionFailed
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 
 	statement, err := r.db.Prepare(sql)
 	if err != nil {
 		tx.Rollback()
-		return ErrInvalidStatement
+		return fmt.Errorf("%w: %w", ErrInvalidStatement, err)
 	}
 	defer statement.Close()
 
 	_, err = statement.Exec()
 	if err != nil {
 		tx.Rollback()
-		return ErrModDeleteAllFailed
+		return fmt.Errorf("%w: %w", ErrModDeleteAllFailed, err)
 	}
 	return tx.Commit()
 }
@@ -197,8 +204,17 @@ func mapRowsToMod(rows *sql.Rows) ([]mod.Mod, error) {
 		var url string
 		var description string
 		var frameworkId int
-
-		err := rows.Scan(&id, &name, &namespace, &path, &version, &url, &description, &frameworkId)
+		var gameBuild string
+		var updatedAt sql.NullTime
+		var categories string
+		var deprecated bool
+		var modpack bool
+		var parent string
+		var source string
+		var location string
+
+		err := rows.Scan(&id, &name, &namespace, &path, &version, &url, &description, &frameworkId,
+			&gameBuild, &updatedAt, &categories, &deprecated, &modpack, &parent, &source, &location)
 		if err != nil {
 			return []mod.Mod{}, err
 		}
@@ -212,8 +228,23 @@ func mapRowsToMod(rows *sql.Rows) ([]mod.Mod, error) {
 			Version:     version,
 			WebsiteURL:  url,
 			Description: description,
+			GameBuild:   gameBuild,
+			UpdatedAt:   updatedAt.Time,
+			Deprecated:  deprecated,
+			Modpack:     modpack,
+			Parent:      parent,
+			Source:      source,
+			Location:    location,
+		}
+		if categories != "" {
+			m.Categories = strings.Split(categories, ",")
 		}
 		mods = append(mods, m)
 	}
 	return mods, nil
 }
+
+// nullTime stores a zero time as NULL, since it means the time isn't known
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
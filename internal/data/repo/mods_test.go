@@ -175,6 +175,36 @@ func TestInsertMod_Happy(t *testing.T) {
 	})
 }
 
+func TestInsertMod_Modpack(t *testing.T) {
+	th := helper.NewHelper(t)
+
+	db := th.CreateDatabase()
+	repo.CreateModsTable(db)
+	repo.CreateFrameworksTable(db)
+	mr := repo.NewModsRepo(db)
+
+	pack := mod.Mod{ID: 1, Namespace: "Someone", Name: "Server_Modpack", Version: "1.0.0", Modpack: true}
+	member := mod.Mod{ID: 2, Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1", Parent: pack.PackageName(), Source: "thunderstore"}
+	for _, m := range []mod.Mod{pack, member} {
+		if err := mr.InsertMod(m); err != nil {
+			t.Errorf("expected a nil error, received: %+v", err)
+		}
+	}
+
+	for _, expected := range []mod.Mod{pack, member} {
+		m, err := mr.GetMod(expected.Name)
+		if err != nil {
+			t.Errorf("expected a nil error, received: %+v", err)
+		}
+		if !m.Equals(&expected) {
+			t.Errorf("expected mod: %+v, received: %+v", expected, m)
+		}
+	}
+	t.Cleanup(func() {
+		th.DeleteDatabase()
+	})
+}
+
 func TestInsertMod_Sad(t *testing.T) {
 	tests := map[string]struct {
 		db          repo.Database
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidManifest = errors.New("Steam app manifest is missing the build ID")
+
+var (
+	// Steam app manifests are KeyValues files, e.g. "buildid"		"12345678"
+	buildIDKey     = regexp.MustCompile(`"buildid"\s+"(\d+)"`)
+	lastUpdatedKey = regexp.MustCompile(`"LastUpdated"\s+"(\d+)"`)
+)
+
+// A Build is a version of the Valheim dedicated server, as installed by Steam
+type Build struct {
+	ID string `json:"id" yaml:"id"`
+
+	// When Steam installed or last updated the build
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// ParseManifest reads the installed build from the app manifest Steam keeps for the server
+func ParseManifest(contents string) (Build, error) {
+	m := buildIDKey.FindStringSubmatch(contents)
+	if m == nil {
+		return Build{}, ErrInvalidManifest
+	}
+
+	b := Build{ID: m[1]}
+	if u := lastUpdatedKey.FindStringSubmatch(contents); u != nil {
+		if seconds, err := strconv.ParseInt(u[1], 10, 64); err == nil {
+			b.UpdatedAt = time.Unix(seconds, 0)
+		}
+	}
+	return b, nil
+}
+
+// IsZero checks if the build is unknown, e.g. the server wasn't installed by Steam
+func (b Build) IsZero() bool {
+	return b.ID == ""
+}
@@ -0,0 +1,38 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"warden/internal/domain/game"
+)
+
+const testManifest = `"AppState"
+{
+	"appid"		"896660"
+	"name"		"Valheim Dedicated Server"
+	"buildid"		"14468418"
+	"LastUpdated"		"1718200000"
+	"InstalledDepots"
+	{
+	}
+}
+`
+
+func TestParseManifest_Happy(t *testing.T) {
+	b, err := game.ParseManifest(testManifest)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	expected := game.Build{ID: "14468418", UpdatedAt: time.Unix(1718200000, 0)}
+	if b.ID != expected.ID || !b.UpdatedAt.Equal(expected.UpdatedAt) {
+		t.Errorf("expected build: %+v, received: %+v", expected, b)
+	}
+}
+
+func TestParseManifest_Sad(t *testing.T) {
+	_, err := game.ParseManifest(`"AppState" { "appid" "896660" }`)
+	if !errors.Is(err, game.ErrInvalidManifest) {
+		t.Errorf("expected error: %+v, received: %+v", game.ErrInvalidManifest, err)
+	}
+}
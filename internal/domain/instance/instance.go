@@ -0,0 +1,77 @@
+package instance
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+const (
+	// The instance Warden uses when none is picked. Its config is the one in the config directory
+	// itself, so installs from before instances existed keep working.
+	Default = "default"
+
+	// Every other instance has its own directory in here, inside the config directory
+	DirectoryName = ".warden-instances"
+)
+
+var ErrInvalidName = errors.New("instance name must be lowercase letters, numbers, - or _, and at most 32 characters")
+
+// Names end up in directory and systemd unit names, so they're kept to characters that are safe in both
+var name = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// An Instance is a Valheim server managed by Warden, with its own config, database and backups
+type Instance struct {
+	Name string `json:"name" yaml:"name"`
+
+	// The directory the instance's config file, database and backups are kept in
+	ConfigDirectory string `json:"config_directory" yaml:"config_directory"`
+
+	ValheimDirectory string `json:"valheim_directory" yaml:"valheim_directory"`
+	ServerPort       int    `json:"server_port" yaml:"server_port"`
+	ServerWorld      string `json:"server_world" yaml:"server_world"`
+}
+
+// IsDefault checks if a name refers to the default instance. No name also means the default.
+func IsDefault(name string) bool {
+	return name == "" || name == Default
+}
+
+// ValidateName checks that a name can be used for a new instance
+func ValidateName(n string) error {
+	if !name.MatchString(n) || IsDefault(n) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// Directory returns where an instance's config is kept, inside the given config directory
+func Directory(configDirectory, name string) string {
+	if IsDefault(name) {
+		return configDirectory
+	}
+	return filepath.Join(configDirectory, DirectoryName, name)
+}
+
+// Root returns the config directory an instance's directory is kept in, the reverse of Directory
+func Root(directory, name string) string {
+	if IsDefault(name) {
+		return directory
+	}
+	return filepath.Dir(filepath.Dir(directory))
+}
+
+// NextPort returns the first port, counting up from the given one, that no instance is using.
+// Valheim also listens on the port after its own, so ports are handed out two at a time.
+func NextPort(start int, instances []Instance) int {
+	used := []int{}
+	for _, i := range instances {
+		used = append(used, i.ServerPort, i.ServerPort+1)
+	}
+	port := start
+	for slices.Contains(used, port) || slices.Contains(used, port+1) {
+		port += 2
+	}
+	return port
+}
@@ -0,0 +1,77 @@
+package instance_test
+
+import (
+	"errors"
+	"testing"
+	"warden/internal/domain/instance"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		expected error
+	}{
+		"accept a lowercase name": {
+			name: "event",
+		},
+		"accept a name with numbers, dashes and underscores": {
+			name: "test-world_2",
+		},
+		"reject an empty name": {
+			name:     "",
+			expected: instance.ErrInvalidName,
+		},
+		"reject the default instance's name": {
+			name:     instance.Default,
+			expected: instance.ErrInvalidName,
+		},
+		"reject a name with uppercase letters": {
+			name:     "Event",
+			expected: instance.ErrInvalidName,
+		},
+		"reject a name that's a path": {
+			name:     "../event",
+			expected: instance.ErrInvalidName,
+		},
+		"reject a name that starts with a dash": {
+			name:     "-event",
+			expected: instance.ErrInvalidName,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := instance.ValidateName(test.name); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestNextPort(t *testing.T) {
+	tests := map[string]struct {
+		instances []instance.Instance
+		expected  int
+	}{
+		"use the start port if no instance is using it": {
+			instances: []instance.Instance{{ServerPort: 2500}},
+			expected:  2456,
+		},
+		"skip the port after one that's in use": {
+			instances: []instance.Instance{{ServerPort: 2456}},
+			expected:  2458,
+		},
+		"skip a port that overlaps with one that's in use": {
+			instances: []instance.Instance{{ServerPort: 2456}, {ServerPort: 2459}},
+			expected:  2462,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if port := instance.NextPort(2456, test.instances); port != test.expected {
+				t.Errorf("expected port: %d, received: %d", test.expected, port)
+			}
+		})
+	}
+}
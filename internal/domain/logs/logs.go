@@ -0,0 +1,246 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// The logs Warden can read: everything the game server prints, and the log BepInEx writes
+	// into the Valheim directory
+	Server  = "server"
+	BepInEx = "bepinex"
+
+	// The kinds of problem the parser recognizes
+	MissingDependency = "missing_dependency"
+	LoadFailure       = "load_failure"
+	Exception         = "exception"
+)
+
+// Level is how severe a log line is. BepInEx labels every line, while anything else the server
+// prints is treated as info unless it's an exception.
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Message
+	Warning
+	Error
+	Fatal
+)
+
+var levelNames = []string{"debug", "info", "message", "warning", "error", "fatal"}
+
+// ParseLevel reads a level name, e.g. "error" or "Warning"
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(strings.TrimSpace(name), n) {
+			return Level(i), nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level %q, must be one of: %s", name, strings.Join(levelNames, ", "))
+}
+
+func (l Level) String() string {
+	if l < Debug || l > Fatal {
+		return "unknown"
+	}
+	return levelNames[l]
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+const (
+	stackTraceHeader = "Stack trace:"
+
+	// Valheim logs this once the world has loaded and players can join
+	worldLoaded = "Game server connected"
+)
+
+var (
+	// BepInEx prefixes every line with its level and source, e.g. "[Error  : Unity Log] ..."
+	bepInExLine = regexp.MustCompile(`^\[(\w+)\s*:\s*([^\]]*?)\s*\]\s?(.*)$`)
+
+	missingDependency = regexp.MustCompile(`Could not load \[(.+?)\] because it has missing dependencies: (.+)$`)
+	loadFailure       = regexp.MustCompile(`(?:Could not load|Error loading|Skipping) \[(.+?)\]\s*(?:because|:)?\s*(.*)$`)
+	missingAssembly   = regexp.MustCompile(`Could not load file or assembly '([^',]+)`)
+	exception         = regexp.MustCompile(`^((?:[A-Za-z_]\w*\.)*[A-Za-z_]\w*Exception)(?::\s*(.*))?$`)
+	stackFrame        = regexp.MustCompile(`^\s+at (.+)$`)
+	pluginVersion     = regexp.MustCompile(`\s+v?\d+(?:\.\d+)+$`)
+)
+
+// Sources and namespaces that belong to Valheim, Unity, or BepInEx itself rather than a mod
+var (
+	genericSources    = []string{"BepInEx", "Unity Log", "Preloader", "HarmonyX", "Console", "Chainloader"}
+	genericNamespaces = []string{"System", "UnityEngine", "Mono", "HarmonyLib", "MonoMod", "BepInEx"}
+)
+
+// A Line is a single line of a log, along with any problem it reports.
+type Line struct {
+	Text    string   `json:"text" yaml:"text"`
+	Level   Level    `json:"level" yaml:"level"`
+	Source  string   `json:"source,omitempty" yaml:"source,omitempty"`
+	Problem *Problem `json:"problem,omitempty" yaml:"problem,omitempty"`
+}
+
+// A Problem is a mod failing to load, or an exception thrown while the server runs.
+type Problem struct {
+	Kind string `json:"kind" yaml:"kind"`
+
+	// The plugin that failed to load, or the source that logged an exception
+	Plugin string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+
+	// The GUIDs of the plugins a plugin needs but couldn't find
+	Dependency string `json:"dependency,omitempty" yaml:"dependency,omitempty"`
+
+	// The first method in an exception's stack trace that doesn't belong to the game or BepInEx
+	Origin string `json:"origin,omitempty" yaml:"origin,omitempty"`
+
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+
+	// The installed mod the problem comes from, e.g. "Azumatt-Where_You_At", if it's known
+	Mod string `json:"mod,omitempty" yaml:"mod,omitempty"`
+
+	// How many times the same problem was logged
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
+// Key identifies a problem, so repeats of it can be counted instead of listed
+func (p *Problem) Key() string {
+	return p.Kind + "|" + p.Plugin + "|" + p.Dependency + "|" + p.Origin + "|" + p.Detail
+}
+
+// Candidates returns the names a problem could be attributed to a mod by: the plugin, and the
+// namespace its exception was thrown from
+func (p *Problem) Candidates() []string {
+	candidates := []string{}
+	if p.Plugin != "" {
+		candidates = append(candidates, p.Plugin)
+	}
+	if i := strings.LastIndex(p.Origin, "."); i > 0 {
+		// Drop the method name, then the type name, leaving the namespace
+		typeName := p.Origin[:i]
+		candidates = append(candidates, typeName)
+		if j := strings.LastIndex(typeName, "."); j > 0 {
+			candidates = append(candidates, typeName[:j])
+		}
+	}
+	return candidates
+}
+
+// A Parser reads a log one line at a time. Lines of a stack trace don't say how severe they are,
+// so they take the level of the exception they belong to.
+type Parser struct {
+	last Level
+	open *Problem
+}
+
+// InStackTrace checks if the last line parsed was an exception or part of its stack trace, so
+// more of the trace could still follow
+func (p *Parser) InStackTrace() bool {
+	return p.open != nil
+}
+
+func (p *Parser) Parse(text string) Line {
+	text = strings.TrimRight(text, "\r\n")
+	line := Line{Text: text, Level: Info}
+
+	if frame := stackFrame.FindStringSubmatch(text); frame != nil {
+		line.Level = p.last
+		if p.open != nil && p.open.Origin == "" && !isGenericFrame(frame[1]) {
+			p.open.Origin = frameMethod(frame[1])
+		}
+		return line
+	}
+	if text == stackTraceHeader {
+		// Unity logs this between an exception and its stack trace
+		line.Level = p.last
+		return line
+	}
+	p.open = nil
+
+	message := text
+	if m := bepInExLine.FindStringSubmatch(text); m != nil {
+		if level, err := ParseLevel(m[1]); err == nil {
+			line.Level = level
+			line.Source = m[2]
+			message = m[3]
+		}
+	}
+
+	line.Problem = parseProblem(message)
+	if line.Problem != nil && line.Problem.Kind == Exception {
+		line.Level = max(line.Level, Error)
+		if !isGenericSource(line.Source) {
+			line.Problem.Plugin = line.Source
+		}
+		p.open = line.Problem
+	}
+	p.last = line.Level
+	return line
+}
+
+func parseProblem(message string) *Problem {
+	if m := missingDependency.FindStringSubmatch(message); m != nil {
+		return &Problem{Kind: MissingDependency, Plugin: pluginName(m[1]), Dependency: m[2], Detail: message}
+	}
+	if m := loadFailure.FindStringSubmatch(message); m != nil {
+		return &Problem{Kind: LoadFailure, Plugin: pluginName(m[1]), Detail: m[2]}
+	}
+	if m := missingAssembly.FindStringSubmatch(message); m != nil {
+		return &Problem{Kind: LoadFailure, Plugin: m[1], Detail: message}
+	}
+	if m := exception.FindStringSubmatch(strings.TrimSpace(message)); m != nil {
+		return &Problem{Kind: Exception, Detail: message}
+	}
+	return nil
+}
+
+// WorldLoaded checks if a line says the server is ready for players
+func WorldLoaded(text string) bool {
+	return strings.Contains(text, worldLoaded)
+}
+
+// IsLoadProblem checks if a problem stops a plugin from loading at all, rather than something
+// going wrong while it runs
+func (p *Problem) IsLoadProblem() bool {
+	return p.Kind == MissingDependency || p.Kind == LoadFailure
+}
+
+// pluginName drops the version BepInEx adds after a plugin's name, e.g. "Where You At 1.0.9"
+func pluginName(name string) string {
+	return pluginVersion.ReplaceAllString(strings.TrimSpace(name), "")
+}
+
+// frameMethod trims a stack frame down to the method, e.g. "WhereYouAt.Patch.Postfix"
+func frameMethod(frame string) string {
+	if i := strings.IndexAny(frame, " ("); i > 0 {
+		frame = frame[:i]
+	}
+	return frame
+}
+
+func isGenericFrame(frame string) bool {
+	if strings.HasPrefix(frame, "(wrapper") {
+		return true
+	}
+	for _, ns := range genericNamespaces {
+		if strings.HasPrefix(frame, ns+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenericSource(source string) bool {
+	for _, s := range genericSources {
+		if strings.EqualFold(source, s) {
+			return true
+		}
+	}
+	return source == ""
+}
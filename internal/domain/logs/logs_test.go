@@ -0,0 +1,156 @@
+package logs_test
+
+import (
+	"slices"
+	"testing"
+	"warden/internal/domain/logs"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		lines    []string
+		level    logs.Level
+		expected *logs.Problem
+	}{
+		"read the level and source of a BepInEx line": {
+			lines: []string{"[Info   :   BepInEx] Loading [Where You At 1.0.9]"},
+			level: logs.Info,
+		},
+		"treat plain server output as info": {
+			lines: []string{"02/03/2024 18:30:00: Game server connected"},
+			level: logs.Info,
+		},
+		"recognize a missing dependency": {
+			lines: []string{"[Error  :   BepInEx] Could not load [Where You At 1.0.9] because it has missing dependencies: com.jotunn.jotunn"},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:       logs.MissingDependency,
+				Plugin:     "Where You At",
+				Dependency: "com.jotunn.jotunn",
+				Detail:     "Could not load [Where You At 1.0.9] because it has missing dependencies: com.jotunn.jotunn",
+			},
+		},
+		"recognize a plugin that failed to load": {
+			lines: []string{"[Error  :   BepInEx] Error loading [AzuClock 1.0.2] : Method not found"},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:   logs.LoadFailure,
+				Plugin: "AzuClock",
+				Detail: "Method not found",
+			},
+		},
+		"recognize an assembly that couldn't be loaded": {
+			lines: []string{"[Error  :   BepInEx] System.IO.FileNotFoundException: Could not load file or assembly 'Sleepover, Version=1.0.0.0' or one of its dependencies"},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:   logs.LoadFailure,
+				Plugin: "Sleepover",
+				Detail: "System.IO.FileNotFoundException: Could not load file or assembly 'Sleepover, Version=1.0.0.0' or one of its dependencies",
+			},
+		},
+		"attribute an exception to the plugin that logged it": {
+			lines: []string{"[Error  :Where You At] NullReferenceException: Object reference not set to an instance of an object"},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:   logs.Exception,
+				Plugin: "Where You At",
+				Detail: "NullReferenceException: Object reference not set to an instance of an object",
+			},
+		},
+		"find where an exception came from in its stack trace": {
+			lines: []string{
+				"NullReferenceException: Object reference not set to an instance of an object",
+				"  at (wrapper dynamic-method) Player.DMD<Player::Update>(Player)",
+				"  at WhereYouAt.Patches.PlayerPatch.Postfix (Player __instance) [0x00000] in <abc>:0",
+				"  at Player.Update () [0x00000] in <def>:0",
+			},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:   logs.Exception,
+				Origin: "WhereYouAt.Patches.PlayerPatch.Postfix",
+				Detail: "NullReferenceException: Object reference not set to an instance of an object",
+			},
+		},
+		"keep reading a stack trace after Unity's header": {
+			lines: []string{
+				"[Error  : Unity Log] NullReferenceException: Object reference not set to an instance of an object",
+				"Stack trace:",
+				"  at WhereYouAt.Patches.PlayerPatch.Postfix (Player __instance) [0x00000] in <abc>:0",
+			},
+			level: logs.Error,
+			expected: &logs.Problem{
+				Kind:   logs.Exception,
+				Origin: "WhereYouAt.Patches.PlayerPatch.Postfix",
+				Detail: "NullReferenceException: Object reference not set to an instance of an object",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := logs.Parser{}
+			parsed := []logs.Line{}
+			for _, l := range test.lines {
+				parsed = append(parsed, p.Parse(l))
+			}
+
+			for _, l := range parsed {
+				if l.Level != test.level {
+					t.Errorf("expected level: %s, received: %s for line: %q", test.level, l.Level, l.Text)
+				}
+			}
+			problem := parsed[0].Problem
+			if test.expected == nil {
+				if problem != nil {
+					t.Errorf("expected no problem, received: %+v", problem)
+				}
+				return
+			}
+			if problem == nil || *problem != *test.expected {
+				t.Errorf("expected problem: %+v, received: %+v", test.expected, problem)
+			}
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	p := logs.Problem{Plugin: "Where You At", Origin: "WhereYouAt.Patches.PlayerPatch.Postfix"}
+	expected := []string{"Where You At", "WhereYouAt.Patches.PlayerPatch", "WhereYouAt.Patches"}
+
+	if candidates := p.Candidates(); !slices.Equal(candidates, expected) {
+		t.Errorf("expected candidates: %q, received: %q", expected, candidates)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := logs.ParseLevel("Warning"); err != nil || level != logs.Warning {
+		t.Errorf("expected level: %s, received: %s, error: %+v", logs.Warning, level, err)
+	}
+	if _, err := logs.ParseLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestWorldLoaded(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected bool
+	}{
+		"recognize the world loading": {
+			text:     "[Info   : Unity Log] 02/03/2024 18:30:00: Game server connected",
+			expected: true,
+		},
+		"ignore any other line": {
+			text:     "[Info   : Unity Log] 02/03/2024 18:29:00: Load world: Dedicated",
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if loaded := logs.WorldLoaded(test.text); loaded != test.expected {
+				t.Errorf("expected: %t, received: %t", test.expected, loaded)
+			}
+		})
+	}
+}
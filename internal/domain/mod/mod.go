@@ -1,19 +1,49 @@
 package mod
 
-import "slices"
+import (
+	"slices"
+	"time"
+	"warden/internal/domain/game"
+)
+
+// Warnings about whether a mod still works with the installed game
+const (
+	WarningDeprecated = "deprecated"
+	WarningOutdated   = "predates game build"
+)
 
 // A Mod is a single plugin or library that modifies the behavior of a game. This
 // can be anything from gameplay changes, to new settings, to new content, and etc.
 type Mod struct {
-	ID           int
-	FrameworkID  int
-	Name         string
-	Namespace    string
-	FilePath     string
-	Version      string
-	WebsiteURL   string
-	Description  string
-	Dependencies []string
+	ID           int      `json:"id" yaml:"id"`
+	FrameworkID  int      `json:"framework_id" yaml:"framework_id"`
+	Name         string   `json:"name" yaml:"name"`
+	Namespace    string   `json:"namespace" yaml:"namespace"`
+	FilePath     string   `json:"file_path" yaml:"file_path"`
+	Version      string   `json:"version" yaml:"version"`
+	WebsiteURL   string   `json:"website_url" yaml:"website_url"`
+	Description  string   `json:"description" yaml:"description"`
+	Dependencies []string `json:"dependencies" yaml:"dependencies"`
+
+	// The Valheim build that was installed when the mod was, if it's known
+	GameBuild string `json:"game_build,omitempty" yaml:"game_build,omitempty"`
+
+	// When the mod's package was last updated on Thunderstore, as of when it was installed
+	UpdatedAt  time.Time `json:"updated_at" yaml:"updated_at"`
+	Categories []string  `json:"categories" yaml:"categories"`
+	Deprecated bool      `json:"deprecated" yaml:"deprecated"`
+
+	// A modpack has no plugins of its own, and is only installed for the mods it depends on. Its
+	// mods are pinned at the versions it lists, and record the modpack they came with as their
+	// parent, e.g. "Someone-Server_Modpack".
+	Modpack bool   `json:"modpack" yaml:"modpack"`
+	Parent  string `json:"parent,omitempty" yaml:"parent,omitempty"`
+
+	// Where the mod was installed from, and is updated from, e.g. "thunderstore" or "nexus"
+	Source string `json:"source" yaml:"source"`
+
+	// The URL or local path of a mod that was added from an archive, rather than looked up
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
 }
 
 func (m1 *Mod) Equals(m2 *Mod) bool {
@@ -25,9 +55,45 @@ func (m1 *Mod) Equals(m2 *Mod) bool {
 		m1.Version == m2.Version &&
 		m1.WebsiteURL == m2.WebsiteURL &&
 		m1.Description == m2.Description &&
-		slices.Equal(m1.Dependencies, m2.Dependencies)
+		slices.Equal(m1.Dependencies, m2.Dependencies) &&
+		m1.GameBuild == m2.GameBuild &&
+		m1.UpdatedAt.Equal(m2.UpdatedAt) &&
+		slices.Equal(m1.Categories, m2.Categories) &&
+		m1.Deprecated == m2.Deprecated &&
+		m1.Modpack == m2.Modpack &&
+		m1.Parent == m2.Parent &&
+		m1.Source == m2.Source &&
+		m1.Location == m2.Location
 }
 
 func (m *Mod) FullName() string {
 	return m.Namespace + "-" + m.Name + "-" + m.Version
 }
+
+// PackageName is the namespace + mod name, which every version of the mod shares
+func (m *Mod) PackageName() string {
+	return m.Namespace + "-" + m.Name
+}
+
+// Predates checks if a mod might not work with the given game build: it was installed for a
+// different build, and hasn't been updated on Thunderstore since the given build was installed.
+// Steam only records when it installed a build, not when the build was released, so this can't
+// tell a mod that was updated for a patch apart from one that was updated just before it.
+func (m *Mod) Predates(build game.Build) bool {
+	if build.IsZero() || build.UpdatedAt.IsZero() || m.UpdatedAt.IsZero() {
+		return false
+	}
+	return m.GameBuild != build.ID && m.UpdatedAt.Before(build.UpdatedAt)
+}
+
+// Warnings lists every reason the mod might not work with the given game build
+func (m *Mod) Warnings(build game.Build) []string {
+	warnings := []string{}
+	if m.Deprecated {
+		warnings = append(warnings, WarningDeprecated)
+	}
+	if m.Predates(build) {
+		warnings = append(warnings, WarningOutdated+" "+build.ID)
+	}
+	return warnings
+}
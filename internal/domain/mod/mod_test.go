@@ -1,7 +1,10 @@
 package mod_test
 
 import (
+	"slices"
 	"testing"
+	"time"
+	"warden/internal/domain/game"
 	"warden/internal/domain/mod"
 )
 
@@ -76,3 +79,49 @@ func TestFullName(t *testing.T) {
 		t.Errorf("expected mod fullname to be: %s, received: %s", expected, mod.FullName())
 	}
 }
+
+func TestWarnings(t *testing.T) {
+	build := game.Build{ID: "14468418", UpdatedAt: time.Date(2024, time.June, 12, 0, 0, 0, 0, time.UTC)}
+	before := build.UpdatedAt.AddDate(0, -1, 0)
+	after := build.UpdatedAt.AddDate(0, 0, 1)
+
+	tests := map[string]struct {
+		mod      mod.Mod
+		build    game.Build
+		expected []string
+	}{
+		"no warnings for a mod updated after the game": {
+			mod:      mod.Mod{GameBuild: "14000000", UpdatedAt: after},
+			build:    build,
+			expected: []string{},
+		},
+		"no warnings for a mod installed for the current build": {
+			mod:      mod.Mod{GameBuild: build.ID, UpdatedAt: before},
+			build:    build,
+			expected: []string{},
+		},
+		"no warnings if the game build is unknown": {
+			mod:      mod.Mod{UpdatedAt: before},
+			build:    game.Build{},
+			expected: []string{},
+		},
+		"warn about a mod that predates the game build": {
+			mod:      mod.Mod{GameBuild: "14000000", UpdatedAt: before},
+			build:    build,
+			expected: []string{"predates game build 14468418"},
+		},
+		"warn about a deprecated mod": {
+			mod:      mod.Mod{Deprecated: true, UpdatedAt: before},
+			build:    build,
+			expected: []string{"deprecated", "predates game build 14468418"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if warnings := test.mod.Warnings(test.build); !slices.Equal(warnings, test.expected) {
+				t.Errorf("expected warnings: %q, received: %q", test.expected, warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package modpack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"warden/internal/domain/mod"
+)
+
+// Every Thunderstore package is a zip archive with these files at its root
+const (
+	ManifestFileName = "manifest.json"
+	IconFileName     = "icon.png"
+	ReadmeFileName   = "README.md"
+)
+
+// Limits Thunderstore puts on a package's manifest and icon
+const (
+	IconSize             = 256
+	MaxNameLength        = 128
+	MaxDescriptionLength = 250
+	MaxWebsiteURLLength  = 1024
+)
+
+var (
+	ErrInvalidName         = fmt.Errorf("package name must be letters, numbers or _, and at most %d characters", MaxNameLength)
+	ErrInvalidVersion      = errors.New("package version must be major.minor.patch, e.g. 1.0.0")
+	ErrInvalidDescription  = fmt.Errorf("modpack description must be at most %d characters", MaxDescriptionLength)
+	ErrInvalidWebsiteURL   = fmt.Errorf("modpack website URL must be an http or https URL, at most %d characters", MaxWebsiteURLLength)
+	ErrInvalidDependency   = errors.New("dependency must be a Thunderstore namespace, name and version, e.g. Azumatt-Sleepover-1.0.1")
+	ErrDuplicateDependency = errors.New("modpack can only depend on one version of a package")
+	ErrNoDependencies      = errors.New("modpack has no mods in it")
+	ErrInvalidIcon         = fmt.Errorf("modpack icon must be a %dx%d PNG", IconSize, IconSize)
+	ErrSelfDependency      = errors.New("modpack can't depend on itself")
+	ErrInvalidReadme       = errors.New("modpack README can't be empty")
+)
+
+var (
+	name       = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	version    = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	dependency = regexp.MustCompile(`^([a-zA-Z0-9_]+)-([a-zA-Z0-9_]+)-\d+\.\d+\.\d+$`)
+)
+
+// Options are what a modpack is built from. The icon and README are paths to files, and are
+// generated when they're left empty.
+type Options struct {
+	Name        string
+	Version     string
+	Description string
+	WebsiteURL  string
+	Icon        string
+	Readme      string
+
+	// Where the package is written. Defaults to <name>-<version>.zip in the working directory.
+	Path string
+}
+
+// A Manifest describes a Thunderstore package. A modpack is a package with no files of its own,
+// that depends on every mod in it at an exact version.
+type Manifest struct {
+	Name          string   `json:"name" yaml:"name"`
+	VersionNumber string   `json:"version_number" yaml:"version_number"`
+	WebsiteURL    string   `json:"website_url" yaml:"website_url"`
+	Description   string   `json:"description" yaml:"description"`
+	Dependencies  []string `json:"dependencies" yaml:"dependencies"`
+}
+
+// NewManifest creates the manifest for a modpack that depends on the given mods, sorted by name
+func NewManifest(name, version, description, websiteURL string, mods []mod.Mod) Manifest {
+	dependencies := []string{}
+	for _, m := range mods {
+		dependencies = append(dependencies, m.FullName())
+	}
+	slices.Sort(dependencies)
+
+	return Manifest{
+		Name:          name,
+		VersionNumber: version,
+		WebsiteURL:    websiteURL,
+		Description:   description,
+		Dependencies:  dependencies,
+	}
+}
+
+// Validate checks a manifest against the rules Thunderstore checks uploads against
+func (m *Manifest) Validate() error {
+	if err := m.ValidatePackage(); err != nil {
+		return err
+	}
+	if len(m.Description) > MaxDescriptionLength {
+		return ErrInvalidDescription
+	}
+	if m.WebsiteURL != "" {
+		u, err := url.Parse(m.WebsiteURL)
+		if len(m.WebsiteURL) > MaxWebsiteURLLength || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidWebsiteURL, m.WebsiteURL)
+		}
+	}
+
+	if len(m.Dependencies) == 0 {
+		return ErrNoDependencies
+	}
+	packages := map[string]bool{}
+	for _, d := range m.Dependencies {
+		match := dependency.FindStringSubmatch(d)
+		if match == nil {
+			return fmt.Errorf("%w: %q", ErrInvalidDependency, d)
+		}
+		if match[2] == m.Name {
+			return fmt.Errorf("%w: %s", ErrSelfDependency, d)
+		}
+		pkg := match[1] + "-" + match[2]
+		if packages[pkg] {
+			return fmt.Errorf("%w: %s", ErrDuplicateDependency, pkg)
+		}
+		packages[pkg] = true
+	}
+	return nil
+}
+
+// ValidatePackage checks the name and version every package's manifest needs, modpack or not.
+// Packages are installed to a folder named after them, so nothing else is allowed in either.
+func (m *Manifest) ValidatePackage() error {
+	if len(m.Name) > MaxNameLength || !name.MatchString(m.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, m.Name)
+	}
+	if !version.MatchString(m.VersionNumber) {
+		return fmt.Errorf("%w: %q", ErrInvalidVersion, m.VersionNumber)
+	}
+	return nil
+}
+
+// Readme creates a README for a modpack that lists every mod in it
+func (m *Manifest) Readme() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", strings.ReplaceAll(m.Name, "_", " "))
+	if m.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", m.Description)
+	}
+	b.WriteString("## Mods\n\n")
+	for _, d := range m.Dependencies {
+		fmt.Fprintf(&b, "- %s\n", d)
+	}
+	return b.String()
+}
+
+// ValidateReadme checks that a README can be uploaded
+func ValidateReadme(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return ErrInvalidReadme
+	}
+	return nil
+}
+
+// ValidateIcon checks that an icon is a PNG of the size Thunderstore requires
+func ValidateIcon(data []byte) error {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidIcon, err)
+	}
+	if cfg.Width != IconSize || cfg.Height != IconSize {
+		return fmt.Errorf("%w: icon is %dx%d", ErrInvalidIcon, cfg.Width, cfg.Height)
+	}
+	return nil
+}
+
+// DefaultIcon creates a plain icon, for modpacks that aren't given one
+func DefaultIcon() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, IconSize, IconSize))
+	background := color.RGBA{R: 0x2b, G: 0x3a, B: 0x42, A: 0xff}
+	for x := 0; x < IconSize; x++ {
+		for y := 0; y < IconSize; y++ {
+			img.Set(x, y, background)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package modpack_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/modpack"
+)
+
+func TestNewManifest(t *testing.T) {
+	m := modpack.NewManifest("Server_Modpack", "1.0.0", "", "", []mod.Mod{
+		{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9"},
+		{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"},
+	})
+	expected := []string{"Azumatt-Sleepover-1.0.1", "ValheimPlus-ValheimPlus-0.9.9"}
+	if !slices.Equal(m.Dependencies, expected) {
+		t.Errorf("expected dependencies: %v, received: %v", expected, m.Dependencies)
+	}
+	if readme := m.Readme(); !strings.HasPrefix(readme, "# Server Modpack\n") || !strings.Contains(readme, "- Azumatt-Sleepover-1.0.1\n") {
+		t.Errorf("expected the README to list every mod, received: %q", readme)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() modpack.Manifest {
+		return modpack.Manifest{
+			Name:          "Server_Modpack",
+			VersionNumber: "1.0.0",
+			WebsiteURL:    "https://example.com/valheim",
+			Description:   "Everything our server runs",
+			Dependencies:  []string{"Azumatt-Sleepover-1.0.1"},
+		}
+	}
+
+	tests := map[string]struct {
+		change   func(m *modpack.Manifest)
+		expected error
+	}{
+		"accept a valid manifest": {
+			change: func(m *modpack.Manifest) {},
+		},
+		"accept a manifest without a website": {
+			change: func(m *modpack.Manifest) { m.WebsiteURL = "" },
+		},
+		"reject a name with a -": {
+			change:   func(m *modpack.Manifest) { m.Name = "Server-Modpack" },
+			expected: modpack.ErrInvalidName,
+		},
+		"reject a name that's too long": {
+			change:   func(m *modpack.Manifest) { m.Name = strings.Repeat("a", modpack.MaxNameLength+1) },
+			expected: modpack.ErrInvalidName,
+		},
+		"reject a version that isn't major.minor.patch": {
+			change:   func(m *modpack.Manifest) { m.VersionNumber = "1.0" },
+			expected: modpack.ErrInvalidVersion,
+		},
+		"reject a description that's too long": {
+			change:   func(m *modpack.Manifest) { m.Description = strings.Repeat("a", modpack.MaxDescriptionLength+1) },
+			expected: modpack.ErrInvalidDescription,
+		},
+		"reject a website that isn't a URL": {
+			change:   func(m *modpack.Manifest) { m.WebsiteURL = "example.com" },
+			expected: modpack.ErrInvalidWebsiteURL,
+		},
+		"reject a modpack without mods": {
+			change:   func(m *modpack.Manifest) { m.Dependencies = []string{} },
+			expected: modpack.ErrNoDependencies,
+		},
+		"reject a dependency without a version": {
+			change:   func(m *modpack.Manifest) { m.Dependencies = []string{"Azumatt-Sleepover"} },
+			expected: modpack.ErrInvalidDependency,
+		},
+		"reject two versions of the same package": {
+			change: func(m *modpack.Manifest) {
+				m.Dependencies = []string{"Azumatt-Sleepover-1.0.1", "Azumatt-Sleepover-1.0.2"}
+			},
+			expected: modpack.ErrDuplicateDependency,
+		},
+		"reject a dependency on the modpack itself": {
+			change:   func(m *modpack.Manifest) { m.Dependencies = []string{"Someone-Server_Modpack-1.0.0"} },
+			expected: modpack.ErrSelfDependency,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := valid()
+			test.change(&m)
+			if err := m.Validate(); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateIcon(t *testing.T) {
+	icon, err := modpack.DefaultIcon()
+	if err != nil {
+		t.Fatalf("unexpected error creating default icon, received: %+v", err)
+	}
+	var small bytes.Buffer
+	if err := png.Encode(&small, image.NewRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatalf("unexpected error creating test icon, received: %+v", err)
+	}
+
+	tests := map[string]struct {
+		icon     []byte
+		expected error
+	}{
+		"accept the default icon": {
+			icon: icon,
+		},
+		"reject an icon that's the wrong size": {
+			icon:     small.Bytes(),
+			expected: modpack.ErrInvalidIcon,
+		},
+		"reject an icon that isn't a PNG": {
+			icon:     []byte("GIF89a"),
+			expected: modpack.ErrInvalidIcon,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := modpack.ValidateIcon(test.icon); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
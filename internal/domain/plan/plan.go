@@ -0,0 +1,89 @@
+package plan
+
+// The kinds of change a Step can make to a mod or framework
+type Action string
+
+const (
+	Install Action = "install"
+	Update  Action = "update"
+	Remove  Action = "remove"
+)
+
+// The database tables a Step can change
+const (
+	ModsTable       = "mods"
+	FrameworksTable = "frameworks"
+)
+
+// UnknownSize marks a download whose size couldn't be found ahead of time
+const UnknownSize int64 = -1
+
+// A Step is a single change that a command will make to a mod or framework, described before
+// anything is touched on disk or in the database.
+type Step struct {
+	Action    Action `json:"action" yaml:"action"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Name      string `json:"name" yaml:"name"`
+
+	// The currently installed version, if any
+	FromVersion string `json:"from_version,omitempty" yaml:"from_version,omitempty"`
+
+	// The version that will be installed, if any
+	ToVersion string `json:"to_version,omitempty" yaml:"to_version,omitempty"`
+
+	// Where the new version will be downloaded from, and how big it is in bytes
+	DownloadURL string `json:"download_url,omitempty" yaml:"download_url,omitempty"`
+	Size        int64  `json:"size" yaml:"size"`
+
+	// Files and directories that will be deleted
+	Delete []string `json:"delete,omitempty" yaml:"delete,omitempty"`
+
+	// Whether the step was pulled in as a dependency of another mod
+	Dependency bool `json:"dependency" yaml:"dependency"`
+
+	// The database table that will be changed
+	Table string `json:"table" yaml:"table"`
+}
+
+// DatabaseChange describes how the step changes the database, e.g. "insert into mods"
+func (s *Step) DatabaseChange() string {
+	switch s.Action {
+	case Install:
+		return "insert into " + s.Table
+	case Update:
+		return "update " + s.Table
+	case Remove:
+		return "delete from " + s.Table
+	default:
+		return ""
+	}
+}
+
+// A Plan is every change a command will make, in the order they'll be made.
+type Plan struct {
+	Steps []Step `json:"steps" yaml:"steps"`
+}
+
+func (p *Plan) Add(steps ...Step) {
+	p.Steps = append(p.Steps, steps...)
+}
+
+func (p *Plan) Merge(p2 Plan) {
+	p.Steps = append(p.Steps, p2.Steps...)
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.Steps) == 0
+}
+
+// DownloadSize totals the size of every download in the plan. Downloads with an unknown size
+// are skipped, so the total is a lower bound if any exist.
+func (p *Plan) DownloadSize() int64 {
+	var total int64
+	for _, s := range p.Steps {
+		if s.Size > 0 {
+			total += s.Size
+		}
+	}
+	return total
+}
@@ -0,0 +1,66 @@
+package plan_test
+
+import (
+	"testing"
+	"warden/internal/domain/plan"
+)
+
+func TestDatabaseChange(t *testing.T) {
+	tests := map[string]struct {
+		step     plan.Step
+		expected string
+	}{
+		"installs insert a new record": {
+			step:     plan.Step{Action: plan.Install, Table: plan.ModsTable},
+			expected: "insert into mods",
+		},
+		"updates change the existing record": {
+			step:     plan.Step{Action: plan.Update, Table: plan.FrameworksTable},
+			expected: "update frameworks",
+		},
+		"removals delete the record": {
+			step:     plan.Step{Action: plan.Remove, Table: plan.ModsTable},
+			expected: "delete from mods",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if change := test.step.DatabaseChange(); change != test.expected {
+				t.Errorf("expected: %s, received: %s", test.expected, change)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := plan.Plan{}
+	if !p.IsEmpty() {
+		t.Error("expected a new plan to be empty")
+	}
+
+	p.Add(plan.Step{Name: "BepInExPack_Valheim"})
+	p.Merge(plan.Plan{Steps: []plan.Step{{Name: "Sleepover"}, {Name: "AzuClock"}}})
+
+	if len(p.Steps) != 3 {
+		t.Errorf("expected 3 steps, received: %d", len(p.Steps))
+	}
+	if p.Steps[0].Name != "BepInExPack_Valheim" {
+		t.Errorf("expected steps to keep their order, received: %+v", p.Steps)
+	}
+}
+
+func TestDownloadSize(t *testing.T) {
+	p := plan.Plan{
+		Steps: []plan.Step{
+			{Size: 1024},
+			{Size: plan.UnknownSize},
+			{Size: 512},
+			{Action: plan.Remove},
+		},
+	}
+
+	if size := p.DownloadSize(); size != 1536 {
+		t.Errorf("expected a download size of 1536, received: %d", size)
+	}
+}
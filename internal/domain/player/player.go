@@ -0,0 +1,96 @@
+package player
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The player lists Valheim reads from its save directory
+const (
+	Admin     = "admin"
+	Banned    = "ban"
+	Permitted = "permit"
+)
+
+var ErrInvalidID = errors.New("player ID must be a Steam64 ID, or a Steam_ or Xbox_ prefixed platform ID")
+
+var (
+	// Steam64 IDs are 17 digits, and every individual account's starts with the same prefix
+	steamID = regexp.MustCompile(`^7656119\d{10}$`)
+
+	// Crossplay servers prefix IDs with the player's platform. Xbox user IDs (XUIDs) are 16 digits.
+	platformSteamID = regexp.MustCompile(`^Steam_7656119\d{10}$`)
+	platformXboxID  = regexp.MustCompile(`^Xbox_\d{16}$`)
+)
+
+// Valheim ignores lines in its list files that start with this, so they're kept as they are
+const commentPrefix = "//"
+
+// Lists returns the name of every player list
+func Lists() []string {
+	return []string{Admin, Banned, Permitted}
+}
+
+// FileName returns the file Valheim reads a player list from, e.g. adminlist.txt
+func FileName(list string) string {
+	switch list {
+	case Banned:
+		return "bannedlist.txt"
+	case Permitted:
+		return "permittedlist.txt"
+	default:
+		return list + "list.txt"
+	}
+}
+
+// ValidateID checks that an ID is one Valheim could list a player by
+func ValidateID(id string) error {
+	if steamID.MatchString(id) || platformSteamID.MatchString(id) || platformXboxID.MatchString(id) {
+		return nil
+	}
+	return ErrInvalidID
+}
+
+// IDs returns every player ID in the lines of a list file, skipping blank lines and comments
+func IDs(lines []string) []string {
+	ids := []string{}
+	for _, l := range lines {
+		if id, ok := parseLine(l); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Add appends a player ID to the lines of a list file. It reports false if the ID is already listed.
+func Add(lines []string, id string) ([]string, bool) {
+	if slices.Contains(IDs(lines), id) {
+		return lines, false
+	}
+	return append(slices.Clone(lines), id), true
+}
+
+// Remove deletes every line listing a player ID from a list file, keeping comments and every other
+// line as they are. It reports false if the ID isn't listed.
+func Remove(lines []string, id string) ([]string, bool) {
+	kept := []string{}
+	removed := false
+	for _, l := range lines {
+		if listed, ok := parseLine(l); ok && listed == id {
+			removed = true
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept, removed
+}
+
+func parseLine(line string) (string, bool) {
+	id := strings.TrimSpace(line)
+	if id == "" || strings.HasPrefix(id, commentPrefix) {
+		return "", false
+	}
+	return id, true
+}
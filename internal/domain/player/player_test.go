@@ -0,0 +1,116 @@
+package player_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"warden/internal/domain/player"
+)
+
+func TestValidateID(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		expected error
+	}{
+		"accept a Steam64 ID": {
+			id: "76561198012345678",
+		},
+		"accept a platform prefixed Steam ID": {
+			id: "Steam_76561198012345678",
+		},
+		"accept a platform prefixed Xbox ID": {
+			id: "Xbox_2535412345678901",
+		},
+		"reject a Steam ID that's too short": {
+			id:       "7656119801234567",
+			expected: player.ErrInvalidID,
+		},
+		"reject a number that isn't a Steam64 ID": {
+			id:       "12345678901234567",
+			expected: player.ErrInvalidID,
+		},
+		"reject an Xbox ID without its prefix": {
+			id:       "2535412345678901",
+			expected: player.ErrInvalidID,
+		},
+		"reject a player name": {
+			id:       "Viking",
+			expected: player.ErrInvalidID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := player.ValidateID(test.id); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	lines := []string{"// List admins here", "76561198012345678"}
+
+	tests := map[string]struct {
+		id       string
+		expected []string
+		added    bool
+	}{
+		"append a new ID": {
+			id:       "Xbox_2535412345678901",
+			expected: []string{"// List admins here", "76561198012345678", "Xbox_2535412345678901"},
+			added:    true,
+		},
+		"leave the list alone if the ID is already listed": {
+			id:       "76561198012345678",
+			expected: lines,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, added := player.Add(lines, test.id)
+			if added != test.added || !slices.Equal(result, test.expected) {
+				t.Errorf("expected lines: %q (added: %t), received: %q (added: %t)", test.expected, test.added, result, added)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	lines := []string{"// List banned players here", "76561198012345678 ", "", "Xbox_2535412345678901"}
+
+	tests := map[string]struct {
+		id       string
+		expected []string
+		removed  bool
+	}{
+		"remove a listed ID, keeping every other line": {
+			id:       "76561198012345678",
+			expected: []string{"// List banned players here", "", "Xbox_2535412345678901"},
+			removed:  true,
+		},
+		"leave the list alone if the ID isn't listed": {
+			id:       "76561198087654321",
+			expected: lines,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, removed := player.Remove(lines, test.id)
+			if removed != test.removed || !slices.Equal(result, test.expected) {
+				t.Errorf("expected lines: %q (removed: %t), received: %q (removed: %t)", test.expected, test.removed, result, removed)
+			}
+		})
+	}
+}
+
+func TestIDs(t *testing.T) {
+	lines := []string{"// List permitted players here", "", " 76561198012345678", "Xbox_2535412345678901"}
+	expected := []string{"76561198012345678", "Xbox_2535412345678901"}
+
+	if ids := player.IDs(lines); !slices.Equal(ids, expected) {
+		t.Errorf("expected IDs: %q, received: %q", expected, ids)
+	}
+}
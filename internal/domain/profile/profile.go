@@ -0,0 +1,60 @@
+package profile
+
+import (
+	"errors"
+	"regexp"
+	"time"
+	"warden/internal/domain/mod"
+)
+
+var ErrInvalidName = errors.New("profile name must be letters, numbers, -, _, + or ., and at most 64 characters")
+
+// Names are used as directory names, so they're kept to characters that are safe in one
+var name = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_+.-]{0,63}$`)
+
+// A Profile is a named set of mods, at specific versions, along with the BepInEx config files
+// they're set up with. Switching to a profile installs exactly its mods and config files.
+type Profile struct {
+	Name      string    `json:"name" yaml:"name"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	Mods      []mod.Mod `json:"mods" yaml:"mods"`
+
+	// Every config file saved with the profile, relative to the BepInEx config directory
+	ConfigFiles []string `json:"config_files" yaml:"config_files"`
+}
+
+// ValidateName checks that a name can be used for a profile
+func ValidateName(n string) error {
+	if !name.MatchString(n) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// Changes compares the installed mods to a profile's, and returns the mods that have to be removed
+// and installed to switch to it. A mod installed at a different version is replaced.
+func (p *Profile) Changes(installed []mod.Mod) (remove []mod.Mod, install []mod.Mod) {
+	remove, install = []mod.Mod{}, []mod.Mod{}
+
+	for _, m := range installed {
+		if !contains(p.Mods, m) {
+			remove = append(remove, m)
+		}
+	}
+	for _, m := range p.Mods {
+		if !contains(installed, m) {
+			install = append(install, m)
+		}
+	}
+	return remove, install
+}
+
+// contains checks if a mod is in a list at the same version
+func contains(mods []mod.Mod, target mod.Mod) bool {
+	for _, m := range mods {
+		if m.Namespace == target.Namespace && m.Name == target.Name && m.Version == target.Version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package profile_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/profile"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		expected error
+	}{
+		"accept a simple name": {
+			name: "hardcore",
+		},
+		"accept a name with a plus": {
+			name: "vanilla+",
+		},
+		"reject an empty name": {
+			name:     "",
+			expected: profile.ErrInvalidName,
+		},
+		"reject a name with spaces": {
+			name:     "building event",
+			expected: profile.ErrInvalidName,
+		},
+		"reject a name that's a path": {
+			name:     "../hardcore",
+			expected: profile.ErrInvalidName,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := profile.ValidateName(test.name); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestChanges(t *testing.T) {
+	valheimPlus := mod.Mod{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9"}
+	jotunn := mod.Mod{Namespace: "ValheimModding", Name: "Jotunn", Version: "2.20.0"}
+	newJotunn := mod.Mod{Namespace: "ValheimModding", Name: "Jotunn", Version: "2.21.0"}
+	planBuild := mod.Mod{Namespace: "MathiasDecrock", Name: "PlanBuild", Version: "0.16.0"}
+
+	p := profile.Profile{Name: "building", Mods: []mod.Mod{newJotunn, planBuild}}
+	remove, install := p.Changes([]mod.Mod{valheimPlus, jotunn})
+
+	if names := fullNames(remove); !slices.Equal(names, []string{valheimPlus.FullName(), jotunn.FullName()}) {
+		t.Errorf("expected the mods missing from the profile and old versions to be removed, received: %v", names)
+	}
+	if names := fullNames(install); !slices.Equal(names, []string{newJotunn.FullName(), planBuild.FullName()}) {
+		t.Errorf("expected the profile's missing mods and new versions to be installed, received: %v", names)
+	}
+}
+
+func fullNames(mods []mod.Mod) []string {
+	names := []string{}
+	for _, m := range mods {
+		names = append(names, m.FullName())
+	}
+	return names
+}
@@ -0,0 +1,104 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"warden/internal/domain/mod"
+)
+
+const (
+	// r2modman and Thunderstore Mod Manager share profiles as a zip archive with this extension.
+	// It holds an export file listing the profile's mods, and the profile's BepInEx config files
+	// under BepInEx/config.
+	ExportExtension = ".r2z"
+	ExportFileName  = "export.r2x"
+)
+
+var (
+	ErrInvalidModName = errors.New("mod name must be a Thunderstore namespace and name, e.g. Azumatt-Sleepover")
+	ErrInvalidVersion = errors.New("mod version must be major.minor.patch, e.g. 1.0.1")
+)
+
+// An Export is the list of mods in an r2modman profile
+type Export struct {
+	ProfileName string      `json:"profileName" yaml:"profileName"`
+	Mods        []ExportMod `json:"mods" yaml:"mods"`
+}
+
+// An ExportMod is a mod in an r2modman profile. Its name is the mod's Thunderstore namespace and
+// name, joined with a -.
+type ExportMod struct {
+	Name    string        `json:"name" yaml:"name"`
+	Version ExportVersion `json:"version" yaml:"version"`
+	Enabled bool          `json:"enabled" yaml:"enabled"`
+}
+
+type ExportVersion struct {
+	Major int `json:"major" yaml:"major"`
+	Minor int `json:"minor" yaml:"minor"`
+	Patch int `json:"patch" yaml:"patch"`
+}
+
+func (v ExportVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// NewExport lists mods in an r2modman profile, all of them enabled
+func NewExport(name string, mods []mod.Mod) (Export, error) {
+	e := Export{ProfileName: name, Mods: []ExportMod{}}
+	for _, m := range mods {
+		v, err := parseVersion(m.Version)
+		if err != nil {
+			return Export{}, fmt.Errorf("%w: %s", err, m.FullName())
+		}
+		e.Mods = append(e.Mods, ExportMod{
+			Name:    m.Namespace + "-" + m.Name,
+			Version: v,
+			Enabled: true,
+		})
+	}
+	return e, nil
+}
+
+// EnabledMods returns every mod that's enabled in an r2modman profile. r2modman keeps disabled
+// mods installed, but BepInEx doesn't load them, so they're left out.
+func (e *Export) EnabledMods() ([]mod.Mod, error) {
+	mods := []mod.Mod{}
+	for _, em := range e.Mods {
+		if !em.Enabled {
+			continue
+		}
+
+		// Thunderstore namespaces can't contain a -, but mod names can
+		namespace, name, ok := strings.Cut(em.Name, "-")
+		if !ok || namespace == "" || name == "" {
+			return []mod.Mod{}, fmt.Errorf("%w: %s", ErrInvalidModName, em.Name)
+		}
+		mods = append(mods, mod.Mod{
+			Namespace: namespace,
+			Name:      name,
+			Version:   em.Version.String(),
+		})
+	}
+	return mods, nil
+}
+
+// parseVersion splits a Thunderstore version into its parts
+func parseVersion(version string) (ExportVersion, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return ExportVersion{}, ErrInvalidVersion
+	}
+
+	numbers := [3]int{}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return ExportVersion{}, ErrInvalidVersion
+		}
+		numbers[i] = n
+	}
+	return ExportVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
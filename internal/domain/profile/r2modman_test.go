@@ -0,0 +1,88 @@
+package profile_test
+
+import (
+	"errors"
+	"testing"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/profile"
+)
+
+func TestNewExport(t *testing.T) {
+	tests := map[string]struct {
+		mods     []mod.Mod
+		expected []profile.ExportMod
+		err      error
+	}{
+		"list every mod as enabled": {
+			mods: []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}},
+			expected: []profile.ExportMod{{
+				Name:    "Azumatt-Sleepover",
+				Version: profile.ExportVersion{Major: 1, Minor: 0, Patch: 1},
+				Enabled: true,
+			}},
+		},
+		"return an error if a version isn't major.minor.patch": {
+			mods: []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0"}},
+			err:  profile.ErrInvalidVersion,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := profile.NewExport("server", test.mods)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error: %+v, received: %+v", test.err, err)
+			}
+			if len(e.Mods) != len(test.expected) {
+				t.Fatalf("expected mods: %+v, received: %+v", test.expected, e.Mods)
+			}
+			for i := range e.Mods {
+				if e.Mods[i] != test.expected[i] {
+					t.Errorf("expected mod: %+v, received: %+v", test.expected[i], e.Mods[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEnabledMods(t *testing.T) {
+	tests := map[string]struct {
+		mods     []profile.ExportMod
+		expected []string
+		err      error
+	}{
+		"return enabled mods only": {
+			mods: []profile.ExportMod{
+				{Name: "Azumatt-Sleepover", Version: profile.ExportVersion{Major: 1, Patch: 1}, Enabled: true},
+				{Name: "ValheimPlus-ValheimPlus", Version: profile.ExportVersion{Minor: 9, Patch: 9}},
+			},
+			expected: []string{"Azumatt-Sleepover-1.0.1"},
+		},
+		"keep a - in the mod's name": {
+			mods:     []profile.ExportMod{{Name: "RandyKnapp-Equipment_and_Quick_Slots-Fork", Version: profile.ExportVersion{Major: 2}, Enabled: true}},
+			expected: []string{"RandyKnapp-Equipment_and_Quick_Slots-Fork-2.0.0"},
+		},
+		"return an error if a name has no namespace": {
+			mods: []profile.ExportMod{{Name: "Sleepover", Enabled: true}},
+			err:  profile.ErrInvalidModName,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := profile.Export{Mods: test.mods}
+			mods, err := e.EnabledMods()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error: %+v, received: %+v", test.err, err)
+			}
+			if len(mods) != len(test.expected) {
+				t.Fatalf("expected mods: %v, received: %+v", test.expected, mods)
+			}
+			for i, m := range mods {
+				if m.FullName() != test.expected[i] {
+					t.Errorf("expected mod: %s, received: %s", test.expected[i], m.FullName())
+				}
+			}
+		})
+	}
+}
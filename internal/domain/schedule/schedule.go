@@ -0,0 +1,231 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// The jobs Warden can run on a schedule
+	Backup  = "backup"
+	Update  = "update"
+	Restart = "restart"
+
+	// How a job run ended
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Skipped   = "skipped"
+
+	// Next gives up looking for a matching time this far ahead, e.g. for "0 0 31 2 *"
+	maxLookahead = 5
+)
+
+// Jobs lists every job in the order they run in when they're due at the same time, so worlds are
+// backed up before mods are updated and the server is restarted last.
+var Jobs = []string{Backup, Update, Restart}
+
+// Shorthands for common expressions, as supported by most cron implementations
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// The range of values each of the 5 fields accepts: minute, hour, day of month, month and day
+// of week. Sunday is both 0 and 7.
+var bounds = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// An Expression is a parsed cron expression, e.g. "30 4 * * 1-5" for 04:30 on weekdays. Each
+// field is a bitset of the values it matches.
+type Expression struct {
+	minute, hour, dom, month, dow uint64
+
+	// Cron matches either day field if both are restricted, instead of requiring both
+	domAny, dowAny bool
+
+	spec string
+}
+
+// Parse reads a standard 5 field cron expression. Fields can be "*", a value, a range like
+// "1-5", a step like "*/15" or "0-30/10", or a comma-separated list of any of them.
+func Parse(spec string) (Expression, error) {
+	spec = strings.TrimSpace(spec)
+	expanded := spec
+	if m, ok := macros[strings.ToLower(spec)]; ok {
+		expanded = m
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != len(bounds) {
+		return Expression{}, fmt.Errorf("expected %d fields in cron expression %q, found %d", len(bounds), spec, len(fields))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, f := range fields {
+		set, err := parseField(f, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return Expression{}, fmt.Errorf("invalid %s in cron expression %q: %w", bounds[i].name, spec, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday can be written as either 0 or 7
+	dow := sets[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+	return Expression{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    dow,
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+		spec:   spec,
+	}, nil
+}
+
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepText)
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = s
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+			} else if hasStep {
+				// "5/15" means every 15 starting at 5
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside of %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	if set == 0 {
+		return 0, errors.New("matches nothing")
+	}
+	return set, nil
+}
+
+// String returns the expression as it was written
+func (e Expression) String() string {
+	return e.spec
+}
+
+// MarshalText writes the expression as it was written, so it can be included in structured output
+func (e Expression) MarshalText() ([]byte, error) {
+	return []byte(e.spec), nil
+}
+
+// Next returns the first time after the given one that the expression matches, to the minute.
+// If nothing matches within the next few years, e.g. for February 31st, it returns a zero time.
+func (e Expression) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Year() + maxLookahead
+
+	for t.Year() <= limit {
+		if !has(e.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !e.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(e.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(e.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (e Expression) matchesDay(t time.Time) bool {
+	dom := has(e.dom, t.Day())
+	dow := has(e.dow, int(t.Weekday()))
+	if e.domAny || e.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<v) != 0
+}
+
+// A Job is a recurring maintenance task, along with when it runs next.
+type Job struct {
+	Name       string     `json:"name" yaml:"name"`
+	Expression Expression `json:"schedule" yaml:"schedule"`
+	Next       time.Time  `json:"next_run" yaml:"next_run"`
+}
+
+// A Run is a single run of a job, as recorded in the job history.
+type Run struct {
+	ID         int       `json:"id,omitempty" yaml:"id,omitempty"`
+	Job        string    `json:"job" yaml:"job"`
+	StartedAt  time.Time `json:"started_at" yaml:"started_at"`
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+	Status     string    `json:"status" yaml:"status"`
+
+	// Why the job failed or was skipped
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (r1 *Run) Equals(r2 *Run) bool {
+	return r1.ID == r2.ID &&
+		r1.Job == r2.Job &&
+		r1.StartedAt.Equal(r2.StartedAt) &&
+		r1.FinishedAt.Equal(r2.FinishedAt) &&
+		r1.Status == r2.Status &&
+		r1.Message == r2.Message
+}
+
+// Duration is how long the run took, rounded to the second
+func (r *Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt).Round(time.Second)
+}
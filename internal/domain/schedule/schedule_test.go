@@ -0,0 +1,91 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+	"warden/internal/domain/schedule"
+)
+
+func TestNext_Happy(t *testing.T) {
+	// A Saturday
+	now := time.Date(2024, time.March, 2, 18, 30, 20, 0, time.UTC)
+
+	tests := map[string]struct {
+		spec     string
+		expected time.Time
+	}{
+		"run every minute": {
+			spec:     "* * * * *",
+			expected: time.Date(2024, time.March, 2, 18, 31, 0, 0, time.UTC),
+		},
+		"run nightly at 4am": {
+			spec:     "0 4 * * *",
+			expected: time.Date(2024, time.March, 3, 4, 0, 0, 0, time.UTC),
+		},
+		"run on the next quarter hour": {
+			spec:     "*/15 * * * *",
+			expected: time.Date(2024, time.March, 2, 18, 45, 0, 0, time.UTC),
+		},
+		"run weekly on Monday": {
+			spec:     "30 5 * * 1",
+			expected: time.Date(2024, time.March, 4, 5, 30, 0, 0, time.UTC),
+		},
+		"treat 7 as Sunday": {
+			spec:     "0 12 * * 7",
+			expected: time.Date(2024, time.March, 3, 12, 0, 0, 0, time.UTC),
+		},
+		"run on weekdays from a list and range": {
+			spec:     "0 9,17 * * 1-5",
+			expected: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+		},
+		"match either day field when both are restricted": {
+			spec:     "0 0 10 * 0",
+			expected: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+		},
+		"run on leap day": {
+			spec:     "0 0 29 2 *",
+			expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"expand macros": {
+			spec:     "@weekly",
+			expected: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+		},
+		"return a zero time if the expression never matches": {
+			spec:     "0 0 31 2 *",
+			expected: time.Time{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := schedule.Parse(test.spec)
+			if err != nil {
+				t.Errorf("unexpected error, received: %+v", err)
+			}
+			if next := e.Next(now); !next.Equal(test.expected) {
+				t.Errorf("expected next run: %s, received: %s", test.expected, next)
+			}
+		})
+	}
+}
+
+func TestParse_Sad(t *testing.T) {
+	tests := map[string]struct {
+		spec string
+	}{
+		"return an error if there are too few fields": {spec: "0 4 * *"},
+		"return an error if a value is out of range":  {spec: "60 4 * * *"},
+		"return an error if a range is backwards":     {spec: "0 10-5 * * *"},
+		"return an error if a step is zero":           {spec: "*/0 * * * *"},
+		"return an error if a value isn't a number":   {spec: "0 4 * JAN *"},
+		"return an error for an unknown macro":        {spec: "@fortnightly"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := schedule.Parse(test.spec); err == nil {
+				t.Errorf("expected an error for expression: %q", test.spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import "time"
+
+const (
+	// The types of game server Warden can run. Modded servers are launched through BepInEx.
+	Vanilla = "vanilla"
+	Modded  = "modded"
+)
+
+// A Process is a game server running in the background, as recorded in Warden's PID file.
+type Process struct {
+	PID       int       `json:"pid" yaml:"pid"`
+	GameType  string    `json:"game_type" yaml:"game_type"`
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+	LogFile   string    `json:"log_file" yaml:"log_file"`
+
+	// The PID of the Warden process supervising the server, if it's supervised
+	SupervisorPID int `json:"supervisor_pid,omitempty" yaml:"supervisor_pid,omitempty"`
+}
+
+func (p1 *Process) Equals(p2 *Process) bool {
+	return p1.PID == p2.PID &&
+		p1.GameType == p2.GameType &&
+		p1.StartedAt.Equal(p2.StartedAt) &&
+		p1.LogFile == p2.LogFile &&
+		p1.SupervisorPID == p2.SupervisorPID
+}
+
+// Supervised checks if the server was started by a Warden supervisor, which restarts it when it crashes
+func (p *Process) Supervised() bool {
+	return p.SupervisorPID != 0
+}
+
+// Uptime is how long the process has been running for, rounded to the second
+func (p *Process) Uptime(now time.Time) time.Duration {
+	if p.StartedAt.IsZero() || now.Before(p.StartedAt) {
+		return 0
+	}
+	return now.Sub(p.StartedAt).Round(time.Second)
+}
+
+// Status describes the game server as it is right now. If the server isn't running, only
+// Running is set.
+type Status struct {
+	Running bool
+	Process Process
+
+	// The version of BepInEx the server was started with, if it's modded
+	BepInExVersion string
+}
@@ -0,0 +1,41 @@
+package server_test
+
+import (
+	"testing"
+	"time"
+	"warden/internal/domain/server"
+)
+
+func TestUptime(t *testing.T) {
+	startedAt := time.Date(2024, time.March, 2, 18, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		process  server.Process
+		now      time.Time
+		expected time.Duration
+	}{
+		"return time since the process started, rounded to the second": {
+			process:  server.Process{StartedAt: startedAt},
+			now:      startedAt.Add(90*time.Minute + 400*time.Millisecond),
+			expected: 90 * time.Minute,
+		},
+		"return zero if the start time is unknown": {
+			process:  server.Process{},
+			now:      startedAt,
+			expected: 0,
+		},
+		"return zero if the start time is in the future": {
+			process:  server.Process{StartedAt: startedAt},
+			now:      startedAt.Add(-time.Hour),
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if uptime := test.process.Uptime(test.now); uptime != test.expected {
+				t.Errorf("expected uptime: %s, received: %s", test.expected, uptime)
+			}
+		})
+	}
+}
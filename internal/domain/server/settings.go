@@ -0,0 +1,115 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Valheim refuses passwords shorter than this
+	MinPasswordLength = 5
+
+	// Valheim listens on the game port and the one after it, and privileged ports need root
+	MinPort = 1024
+	MaxPort = 65534
+)
+
+// The world presets Valheim accepts, each setting every modifier at once
+var Presets = []string{"normal", "casual", "easy", "hard", "hardcore", "immersive", "hammer"}
+
+// The world modifiers Valheim accepts, along with the values each one can be set to
+var Modifiers = map[string][]string{
+	"combat":       {"veryeasy", "easy", "hard", "veryhard"},
+	"deathpenalty": {"casual", "veryeasy", "easy", "hard", "hardcore"},
+	"resources":    {"muchless", "less", "more", "muchmore", "most"},
+	"raids":        {"none", "muchless", "less", "more", "muchmore"},
+	"portals":      {"casual", "hard", "veryhard"},
+}
+
+// Settings are the arguments the Valheim server binary is launched with.
+type Settings struct {
+	Name      string
+	Port      int
+	World     string
+	Password  string
+	Public    bool
+	Crossplay bool
+
+	// Where Valheim saves worlds and player lists. Valheim's own default is used if it's empty.
+	SaveDirectory string
+
+	// A world preset, e.g. "hard", and individual modifiers in the form "combat=veryhard"
+	Preset    string
+	Modifiers []string
+}
+
+// Validate checks the settings against the rules Valheim enforces when it starts, so a bad
+// setting is reported before the server is launched instead of in its log.
+func (s *Settings) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("server name can't be empty")
+	}
+	if s.Port < MinPort || s.Port > MaxPort {
+		return fmt.Errorf("server port must be between %d and %d", MinPort, MaxPort)
+	}
+	if strings.TrimSpace(s.World) == "" {
+		return errors.New("world name can't be empty")
+	}
+	if s.Password != "" || s.Public {
+		if len(s.Password) < MinPasswordLength {
+			return fmt.Errorf("server password must be at least %d characters", MinPasswordLength)
+		}
+		if strings.Contains(s.Name, s.Password) {
+			return errors.New("server password can't be part of the server name")
+		}
+	}
+	if s.Preset != "" && !slices.Contains(Presets, s.Preset) {
+		return fmt.Errorf("unknown world preset %q, must be one of: %s", s.Preset, strings.Join(Presets, ", "))
+	}
+	for _, m := range s.Modifiers {
+		key, value, ok := strings.Cut(m, "=")
+		values, known := Modifiers[key]
+		if !ok || !known {
+			return fmt.Errorf("unknown world modifier %q, must be in the form combat=hard", m)
+		}
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("unknown value for world modifier %s, must be one of: %s", key, strings.Join(values, ", "))
+		}
+	}
+	return nil
+}
+
+// Args returns the command line arguments for the Valheim server binary
+func (s *Settings) Args() []string {
+	args := []string{
+		"-nographics",
+		"-batchmode",
+		"-name", s.Name,
+		"-port", strconv.Itoa(s.Port),
+		"-world", s.World,
+		"-public", "0",
+	}
+	if s.Public {
+		args[len(args)-1] = "1"
+	}
+	if s.Password != "" {
+		args = append(args, "-password", s.Password)
+	}
+	if s.Crossplay {
+		args = append(args, "-crossplay")
+	}
+	if s.SaveDirectory != "" {
+		args = append(args, "-savedir", s.SaveDirectory)
+	}
+	if s.Preset != "" {
+		args = append(args, "-preset", s.Preset)
+	}
+	for _, m := range s.Modifiers {
+		key, value, _ := strings.Cut(m, "=")
+		args = append(args, "-modifier", key, value)
+	}
+	return args
+}
@@ -0,0 +1,122 @@
+package server_test
+
+import (
+	"slices"
+	"testing"
+	"warden/internal/domain/server"
+)
+
+func TestValidate_Happy(t *testing.T) {
+	tests := map[string]struct {
+		settings server.Settings
+	}{
+		"a private server doesn't need a password": {
+			settings: server.Settings{Name: "My server", Port: 2456, World: "Dedicated"},
+		},
+		"a public server with a password, preset and modifiers": {
+			settings: server.Settings{
+				Name:      "My server",
+				Port:      2456,
+				World:     "Dedicated",
+				Password:  "secret",
+				Public:    true,
+				Preset:    "hard",
+				Modifiers: []string{"combat=veryhard", "raids=none"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.settings.Validate(); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_Sad(t *testing.T) {
+	valid := server.Settings{Name: "My server", Port: 2456, World: "Dedicated", Password: "secret"}
+
+	tests := map[string]struct {
+		change func(s *server.Settings)
+	}{
+		"return an error if the server name is empty": {
+			change: func(s *server.Settings) { s.Name = " " },
+		},
+		"return an error if the port is out of range": {
+			change: func(s *server.Settings) { s.Port = 80 },
+		},
+		"return an error if the world name is empty": {
+			change: func(s *server.Settings) { s.World = "" },
+		},
+		"return an error if the password is too short": {
+			change: func(s *server.Settings) { s.Password = "abc" },
+		},
+		"return an error if a public server has no password": {
+			change: func(s *server.Settings) {
+				s.Password = ""
+				s.Public = true
+			},
+		},
+		"return an error if the password is part of the server name": {
+			change: func(s *server.Settings) { s.Password = "My se" },
+		},
+		"return an error if the preset is unknown": {
+			change: func(s *server.Settings) { s.Preset = "nightmare" },
+		},
+		"return an error if a modifier is unknown": {
+			change: func(s *server.Settings) { s.Modifiers = []string{"weather=stormy"} },
+		},
+		"return an error if a modifier value is unknown": {
+			change: func(s *server.Settings) { s.Modifiers = []string{"combat=nightmare"} },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := valid
+			test.change(&s)
+			if err := s.Validate(); err == nil {
+				t.Errorf("expected an error for settings: %+v", s)
+			}
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := map[string]struct {
+		settings server.Settings
+		expected []string
+	}{
+		"only pass the settings that are set": {
+			settings: server.Settings{Name: "My server", Port: 2456, World: "Dedicated"},
+			expected: []string{"-nographics", "-batchmode", "-name", "My server", "-port", "2456", "-world", "Dedicated", "-public", "0"},
+		},
+		"pass every setting": {
+			settings: server.Settings{
+				Name:          "My server",
+				Port:          2458,
+				World:         "Midgard",
+				Password:      "secret",
+				Public:        true,
+				Crossplay:     true,
+				SaveDirectory: "/srv/valheim",
+				Preset:        "hard",
+				Modifiers:     []string{"raids=none"},
+			},
+			expected: []string{
+				"-nographics", "-batchmode", "-name", "My server", "-port", "2458", "-world", "Midgard", "-public", "1",
+				"-password", "secret", "-crossplay", "-savedir", "/srv/valheim", "-preset", "hard", "-modifier", "raids", "none",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if args := test.settings.Args(); !slices.Equal(args, test.expected) {
+				t.Errorf("expected args: %q, received: %q", test.expected, args)
+			}
+		})
+	}
+}
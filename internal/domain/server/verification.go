@@ -0,0 +1,15 @@
+package server
+
+import "warden/internal/domain/logs"
+
+// A Verification is the outcome of starting the server to check that an update didn't break it
+type Verification struct {
+	Passed     bool `json:"passed" yaml:"passed"`
+	RolledBack bool `json:"rolled_back" yaml:"rolled_back"`
+
+	// Why the check failed, e.g. the server crashed or the world didn't load in time
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Every plugin that failed to load
+	Problems []logs.Problem `json:"problems,omitempty" yaml:"problems,omitempty"`
+}
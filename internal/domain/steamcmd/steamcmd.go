@@ -0,0 +1,71 @@
+package steamcmd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The Steam app for the Valheim dedicated server. It can be downloaded anonymously.
+const AppID = "896660"
+
+var (
+	// SteamCMD reports progress as e.g. "Update state (0x61) downloading, progress: 45.12 (1234 / 2734)"
+	progressLine = regexp.MustCompile(`Update state \(0x[0-9a-fA-F]+\) ([\w ]+), progress: ([\d.]+) \((\d+) / (\d+)\)`)
+	successLine  = regexp.MustCompile(`Success! App '\d+' (.+?)\.?$`)
+	errorLine    = regexp.MustCompile(`(?i)^\s*ERROR! (.+)$`)
+	loginFailed  = regexp.MustCompile(`Logging in .*FAILED \((.+)\)`)
+)
+
+// Args returns the arguments that make SteamCMD install or update the dedicated server into the
+// given directory. Validating checks every installed file and downloads any that don't match.
+func Args(directory, beta string, validate bool) []string {
+	args := []string{"+force_install_dir", directory, "+login", "anonymous", "+app_update", AppID}
+	if beta != "" {
+		args = append(args, "-beta", beta)
+	}
+	if validate {
+		args = append(args, "validate")
+	}
+	return append(args, "+quit")
+}
+
+// Progress is how far along SteamCMD is with a step of an update, e.g. downloading or verifying
+type Progress struct {
+	State   string
+	Percent float64
+	Current int64
+	Total   int64
+}
+
+// A Line is a line of SteamCMD output that Warden cares about. Anything else is left empty.
+type Line struct {
+	Progress *Progress
+
+	// Set once the app is installed and up to date, e.g. "fully installed"
+	Success string
+
+	// Set if SteamCMD gave up, e.g. "Failed to install app '896660' (No subscription)"
+	Error string
+}
+
+// Parse reads a single line of SteamCMD output
+func Parse(text string) Line {
+	text = strings.TrimSpace(text)
+	if m := progressLine.FindStringSubmatch(text); m != nil {
+		percent, _ := strconv.ParseFloat(m[2], 64)
+		current, _ := strconv.ParseInt(m[3], 10, 64)
+		total, _ := strconv.ParseInt(m[4], 10, 64)
+		return Line{Progress: &Progress{State: m[1], Percent: percent, Current: current, Total: total}}
+	}
+	if m := successLine.FindStringSubmatch(text); m != nil {
+		return Line{Success: m[1]}
+	}
+	if m := errorLine.FindStringSubmatch(text); m != nil {
+		return Line{Error: m[1]}
+	}
+	if m := loginFailed.FindStringSubmatch(text); m != nil {
+		return Line{Error: "unable to log in to Steam (" + m[1] + ")"}
+	}
+	return Line{}
+}
@@ -0,0 +1,82 @@
+package steamcmd_test
+
+import (
+	"slices"
+	"testing"
+	"warden/internal/domain/steamcmd"
+)
+
+func TestArgs(t *testing.T) {
+	tests := map[string]struct {
+		beta     string
+		validate bool
+		expected []string
+	}{
+		"update the public branch": {
+			expected: []string{"+force_install_dir", "/valheim", "+login", "anonymous", "+app_update", "896660", "+quit"},
+		},
+		"update a beta branch and validate it": {
+			beta:     "public-test",
+			validate: true,
+			expected: []string{"+force_install_dir", "/valheim", "+login", "anonymous", "+app_update", "896660", "-beta", "public-test", "validate", "+quit"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if args := steamcmd.Args("/valheim", test.beta, test.validate); !slices.Equal(args, test.expected) {
+				t.Errorf("expected args: %q, received: %q", test.expected, args)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected steamcmd.Line
+	}{
+		"read download progress": {
+			text: " Update state (0x61) downloading, progress: 45.12 (1234567 / 2736201)",
+			expected: steamcmd.Line{Progress: &steamcmd.Progress{
+				State:   "downloading",
+				Percent: 45.12,
+				Current: 1234567,
+				Total:   2736201,
+			}},
+		},
+		"read a finished install": {
+			text:     "Success! App '896660' fully installed.",
+			expected: steamcmd.Line{Success: "fully installed"},
+		},
+		"read an app that's already up to date": {
+			text:     "Success! App '896660' already up to date.",
+			expected: steamcmd.Line{Success: "already up to date"},
+		},
+		"read a failed update": {
+			text:     "Error! App '896660' state is 0x202 after update job.",
+			expected: steamcmd.Line{Error: "App '896660' state is 0x202 after update job."},
+		},
+		"read a failed login": {
+			text:     "Logging in user 'anonymous' to Steam Public...FAILED (No Connection)",
+			expected: steamcmd.Line{Error: "unable to log in to Steam (No Connection)"},
+		},
+		"ignore anything else": {
+			text:     "Loading Steam API...OK",
+			expected: steamcmd.Line{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			l := steamcmd.Parse(test.text)
+			if l.Success != test.expected.Success || l.Error != test.expected.Error {
+				t.Errorf("expected line: %+v, received: %+v", test.expected, l)
+			}
+			if (l.Progress == nil) != (test.expected.Progress == nil) ||
+				(l.Progress != nil && *l.Progress != *test.expected.Progress) {
+				t.Errorf("expected progress: %+v, received: %+v", test.expected.Progress, l.Progress)
+			}
+		})
+	}
+}
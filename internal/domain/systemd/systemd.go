@@ -0,0 +1,194 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Units are installed either for the current user, or system-wide. System units need root to
+	// install, but also run when the user isn't logged in.
+	UserScope   = "user"
+	SystemScope = "system"
+
+	// The name of the unit file Warden installs for the default server instance
+	UnitName = "warden-valheim.service"
+
+	// Written at the top of every unit, so it's obvious where it came from
+	header = "# Generated by Warden. Changes are overwritten by 'warden service install'."
+
+	// How long systemd waits for the server to stop when a unit doesn't say, long enough for
+	// Valheim to save a large world
+	DefaultStopTimeout = 150 * time.Second
+)
+
+// A Unit is a systemd service that runs the Valheim game server through a Warden supervisor.
+type Unit struct {
+	Scope    string
+	GameType string
+
+	// The server instance the unit runs, or an empty string for the default instance
+	Instance string
+
+	// The Warden executable, and the user and home directory it runs with. The user is only
+	// set for system units, since user units always run as their owner.
+	Executable string
+	User       string
+	Home       string
+
+	// Any other environment variables Warden needs, e.g. KEY=value
+	Environment []string
+
+	// Where the server is installed
+	WorkingDirectory string
+
+	// How long systemd waits for the server to save the world and exit before killing it.
+	// DefaultStopTimeout is used if it isn't set.
+	StopTimeout time.Duration
+}
+
+// IsValidScope checks if a scope is either user or system
+func IsValidScope(scope string) bool {
+	return scope == UserScope || scope == SystemScope
+}
+
+// Render returns the contents of the unit file. The server is supervised by Warden, which
+// restarts it after crashes, so systemd only steps in if the supervisor itself gives up.
+func (u *Unit) Render() string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+
+	line(header)
+	line("[Unit]")
+	if u.Instance != "" {
+		line("Description=Valheim %s server (%s), supervised by Warden", u.GameType, u.Instance)
+	} else {
+		line("Description=Valheim %s server, supervised by Warden", u.GameType)
+	}
+	if u.Scope == SystemScope {
+		line("Wants=network-online.target")
+	}
+	line("After=network-online.target")
+	line("")
+	line("[Service]")
+	line("Type=simple")
+	if u.Scope == SystemScope && u.User != "" {
+		line("User=%s", u.User)
+	}
+	line("Environment=%s", quote("HOME="+u.Home))
+	for _, env := range u.Environment {
+		line("Environment=%s", quote(env))
+	}
+	line("WorkingDirectory=%s", escape(u.WorkingDirectory))
+	line("ExecStart=%s supervise %s", quote(u.Executable), u.GameType)
+	// Only the supervisor is sent SIGTERM, so it can interrupt the server and wait for it to save.
+	// Anything still running once the supervisor exits is killed.
+	line("KillSignal=SIGTERM")
+	line("KillMode=mixed")
+	line("TimeoutStopSec=%d", int(u.stopTimeout().Seconds()))
+	line("Restart=on-failure")
+	line("RestartSec=30")
+	line("")
+	line("[Install]")
+	line("WantedBy=%s", WantedBy(u.Scope))
+	return b.String()
+}
+
+func (u *Unit) stopTimeout() time.Duration {
+	if u.StopTimeout <= 0 {
+		return DefaultStopTimeout
+	}
+	return u.StopTimeout
+}
+
+// FileName returns the name of the unit file for a server instance, so every instance can have
+// its own unit. The default instance's unit keeps the name it had before instances existed.
+func FileName(instance string) string {
+	if instance == "" {
+		return UnitName
+	}
+	return "warden-valheim-" + instance + ".service"
+}
+
+// WantedBy returns the target the unit is started with when it's enabled
+func WantedBy(scope string) string {
+	if scope == SystemScope {
+		return "multi-user.target"
+	}
+	return "default.target"
+}
+
+// GameType returns the game type a rendered unit runs, or an empty string if it isn't a unit
+// generated by Warden
+func GameType(contents string) string {
+	if !strings.HasPrefix(contents, header) {
+		return ""
+	}
+	for _, l := range strings.Split(contents, "\n") {
+		if args, ok := strings.CutPrefix(l, "ExecStart="); ok {
+			fields := strings.Fields(args)
+			return fields[len(fields)-1]
+		}
+	}
+	return ""
+}
+
+// Systemctl returns a systemctl command line for the unit in the given scope
+func Systemctl(scope string, args ...string) string {
+	cmd := []string{"systemctl"}
+	if scope == UserScope {
+		cmd = append(cmd, "--user")
+	}
+	return strings.Join(append(cmd, args...), " ")
+}
+
+// InstallCommands are the systemctl commands that load a newly installed unit and start it
+func InstallCommands(scope, name string) []string {
+	return []string{
+		Systemctl(scope, "daemon-reload"),
+		Systemctl(scope, "enable", "--now", name),
+	}
+}
+
+// UninstallCommands are the systemctl commands that stop a removed unit and forget about it
+func UninstallCommands(scope, name string) []string {
+	return []string{
+		Systemctl(scope, "stop", name),
+		Systemctl(scope, "daemon-reload"),
+	}
+}
+
+// escape stops systemd from expanding specifiers like %h in a value
+func escape(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+// quote wraps a value in double quotes if it contains anything systemd would split it on
+func quote(value string) string {
+	value = escape(value)
+	if !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// Status describes a unit file as it's installed right now
+type Status struct {
+	Scope string
+	Name  string
+	Path  string
+
+	Installed bool
+	GameType  string
+
+	// Whether the unit is started at boot, or when the user logs in
+	Enabled bool
+
+	// Whether the installed unit matches what Warden would generate from the current config
+	UpToDate bool
+}
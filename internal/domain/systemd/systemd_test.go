@@ -0,0 +1,174 @@
+package systemd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"warden/internal/domain/systemd"
+)
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		unit     systemd.Unit
+		expected []string
+		excluded []string
+	}{
+		"render a user unit": {
+			unit: systemd.Unit{
+				Scope:            systemd.UserScope,
+				GameType:         "vanilla",
+				Executable:       "/usr/local/bin/warden",
+				User:             "viking",
+				Home:             "/home/viking",
+				WorkingDirectory: "/srv/valheim",
+				StopTimeout:      180 * time.Second,
+			},
+			expected: []string{
+				"ExecStart=/usr/local/bin/warden supervise vanilla\n",
+				"WorkingDirectory=/srv/valheim\n",
+				"Environment=HOME=/home/viking\n",
+				"KillMode=mixed\n",
+				"TimeoutStopSec=180\n",
+				"WantedBy=default.target\n",
+			},
+			excluded: []string{"User=", "Wants="},
+		},
+		"render a system unit that runs as the given user": {
+			unit: systemd.Unit{
+				Scope:       systemd.SystemScope,
+				GameType:    "modded",
+				Executable:  "/usr/local/bin/warden",
+				User:        "viking",
+				Home:        "/home/viking",
+				Environment: []string{"WARDEN_CONFIG_DIR=/etc/warden"},
+			},
+			expected: []string{
+				"User=viking\n",
+				"Environment=WARDEN_CONFIG_DIR=/etc/warden\n",
+				"KillMode=mixed\n",
+				// Long enough for the world to save, even though the unit doesn't say
+				"TimeoutStopSec=150\n",
+				"Wants=network-online.target\n",
+				"WantedBy=multi-user.target\n",
+			},
+		},
+		"render a unit for a named instance": {
+			unit: systemd.Unit{
+				Scope:       systemd.UserScope,
+				GameType:    "vanilla",
+				Instance:    "event",
+				Executable:  "/usr/local/bin/warden",
+				Home:        "/home/viking",
+				Environment: []string{"WARDEN_INSTANCE=event"},
+			},
+			expected: []string{
+				"Description=Valheim vanilla server (event), supervised by Warden\n",
+				"Environment=WARDEN_INSTANCE=event\n",
+			},
+		},
+		"quote paths with spaces and escape specifiers": {
+			unit: systemd.Unit{
+				Scope:            systemd.UserScope,
+				GameType:         "vanilla",
+				Executable:       "/opt/my tools/warden",
+				Home:             "/home/100%",
+				WorkingDirectory: "/srv/Valheim dedicated server",
+			},
+			expected: []string{
+				"ExecStart=\"/opt/my tools/warden\" supervise vanilla\n",
+				"Environment=HOME=/home/100%%\n",
+				"WorkingDirectory=/srv/Valheim dedicated server\n",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			contents := test.unit.Render()
+			for _, e := range test.expected {
+				if !strings.Contains(contents, e) {
+					t.Errorf("expected unit to contain: %q, received: %s", e, contents)
+				}
+			}
+			for _, e := range test.excluded {
+				if strings.Contains(contents, e) {
+					t.Errorf("expected unit not to contain: %q, received: %s", e, contents)
+				}
+			}
+		})
+	}
+}
+
+func TestGameType(t *testing.T) {
+	u := systemd.Unit{Scope: systemd.UserScope, GameType: "modded", Executable: "/opt/my tools/warden"}
+
+	tests := map[string]struct {
+		contents string
+		expected string
+	}{
+		"return the game type of a unit generated by Warden": {
+			contents: u.Render(),
+			expected: "modded",
+		},
+		"return nothing for a hand-written unit": {
+			contents: "[Service]\nExecStart=/usr/local/bin/warden supervise vanilla\n",
+			expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if gameType := systemd.GameType(test.contents); gameType != test.expected {
+				t.Errorf("expected game type: %q, received: %q", test.expected, gameType)
+			}
+		})
+	}
+}
+
+func TestSystemctl(t *testing.T) {
+	tests := map[string]struct {
+		scope    string
+		expected string
+	}{
+		"pass --user for user units": {
+			scope:    systemd.UserScope,
+			expected: "systemctl --user enable --now warden-valheim.service",
+		},
+		"leave out --user for system units": {
+			scope:    systemd.SystemScope,
+			expected: "systemctl enable --now warden-valheim.service",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if cmd := systemd.Systemctl(test.scope, "enable", "--now", systemd.UnitName); cmd != test.expected {
+				t.Errorf("expected command: %q, received: %q", test.expected, cmd)
+			}
+		})
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]struct {
+		instance string
+		expected string
+	}{
+		"keep the original name for the default instance": {
+			instance: "",
+			expected: "warden-valheim.service",
+		},
+		"name the unit after a named instance": {
+			instance: "event",
+			expected: "warden-valheim-event.service",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if unit := systemd.FileName(test.instance); unit != test.expected {
+				t.Errorf("expected unit: %q, received: %q", test.expected, unit)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package world
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Valheim saves each world as a pair of files: the world data itself (.db) and
+	// its metadata, e.g. name and seed (.fwl)
+	DataFileExtension     = ".db"
+	MetadataFileExtension = ".fwl"
+
+	// The format used for timestamps in snapshot IDs and file names
+	TimestampFormat = "20060102T150405"
+)
+
+// A Snapshot is a point-in-time copy of a world's save files.
+type Snapshot struct {
+	World     string    `json:"world" yaml:"world"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	FilePath  string    `json:"file_path" yaml:"file_path"`
+}
+
+func (s1 *Snapshot) Equals(s2 *Snapshot) bool {
+	return s1.World == s2.World &&
+		s1.CreatedAt.Equal(s2.CreatedAt) &&
+		s1.FilePath == s2.FilePath
+}
+
+// ID uniquely identifies a snapshot by its world name and creation time, e.g. "Dedicated-20240101T120000"
+func (s *Snapshot) ID() string {
+	return s.World + "-" + s.CreatedAt.Format(TimestampFormat)
+}
+
+// ParseID splits a snapshot ID into its world name and creation time. World names can contain
+// dashes, so the timestamp is always taken from after the last one.
+func ParseID(id string) (string, time.Time, bool) {
+	i := strings.LastIndex(id, "-")
+	if i <= 0 {
+		return "", time.Time{}, false
+	}
+	createdAt, err := time.ParseInLocation(TimestampFormat, id[i+1:], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return id[:i], createdAt, true
+}
+
+// Retention decides which snapshots are kept when old backups are pruned. Each rule is applied
+// separately and a snapshot is kept if any of them selects it. If every rule is zero, nothing
+// is pruned.
+type Retention struct {
+	// Keep the N most recent snapshots
+	KeepLast int
+
+	// Keep the most recent snapshot for each of the last N days that have one
+	KeepDaily int
+
+	// Keep the most recent snapshot for each of the last N weeks that have one
+	KeepWeekly int
+}
+
+// Expired returns every snapshot that falls outside of the retention policy. Snapshots are
+// grouped by world, so backups of one world never push out backups of another.
+func (r *Retention) Expired(snapshots []Snapshot) []Snapshot {
+	if r.KeepLast <= 0 && r.KeepDaily <= 0 && r.KeepWeekly <= 0 {
+		return []Snapshot{}
+	}
+
+	worlds := map[string][]Snapshot{}
+	for _, s := range snapshots {
+		worlds[s.World] = append(worlds[s.World], s)
+	}
+
+	expired := []Snapshot{}
+	for _, group := range worlds {
+		// Newest first, so each rule keeps the latest snapshot in its period
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].CreatedAt.After(group[j].CreatedAt)
+		})
+
+		keep := make([]bool, len(group))
+		for i := 0; i < len(group) && i < r.KeepLast; i++ {
+			keep[i] = true
+		}
+		keepPerPeriod(group, keep, r.KeepDaily, func(t time.Time) string {
+			return t.Format("2006-01-02")
+		})
+		keepPerPeriod(group, keep, r.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		})
+
+		for i, s := range group {
+			if !keep[i] {
+				expired = append(expired, s)
+			}
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].CreatedAt.Before(expired[j].CreatedAt)
+	})
+	return expired
+}
+
+// keepPerPeriod marks the newest snapshot in each of the first n distinct periods as kept. The
+// snapshots must already be sorted newest first.
+func keepPerPeriod(snapshots []Snapshot, keep []bool, n int, period func(time.Time) string) {
+	seen := map[string]bool{}
+	for i, s := range snapshots {
+		if len(seen) >= n {
+			return
+		}
+		p := period(s.CreatedAt)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		keep[i] = true
+	}
+}
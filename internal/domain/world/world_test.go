@@ -0,0 +1,138 @@
+package world_test
+
+import (
+	"testing"
+	"time"
+	"warden/internal/domain/world"
+)
+
+func TestID(t *testing.T) {
+	s := world.Snapshot{
+		World:     "Dedicated",
+		CreatedAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local),
+	}
+	expected := "Dedicated-20240302T183005"
+
+	if s.ID() != expected {
+		t.Errorf("expected ID: %s, received: %s", expected, s.ID())
+	}
+}
+
+func TestParseID_Happy(t *testing.T) {
+	tests := map[string]struct {
+		id        string
+		world     string
+		createdAt time.Time
+	}{
+		"parse world name and timestamp from ID": {
+			id:        "Dedicated-20240302T183005",
+			world:     "Dedicated",
+			createdAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local),
+		},
+		"parse world names that contain dashes": {
+			id:        "my-cool-world-20240302T183005",
+			world:     "my-cool-world",
+			createdAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w, createdAt, ok := world.ParseID(test.id)
+			if !ok {
+				t.Fatal("expected ID to be parsed, received false")
+			}
+			if w != test.world {
+				t.Errorf("expected world: %s, received: %s", test.world, w)
+			}
+			if !createdAt.Equal(test.createdAt) {
+				t.Errorf("expected timestamp: %v, received: %v", test.createdAt, createdAt)
+			}
+		})
+	}
+}
+
+func TestParseID_Sad(t *testing.T) {
+	tests := map[string]struct {
+		id string
+	}{
+		"missing timestamp":   {id: "Dedicated"},
+		"missing world name":  {id: "-20240302T183005"},
+		"malformed timestamp": {id: "Dedicated-yesterday"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, ok := world.ParseID(test.id); ok {
+				t.Error("expected false, received true")
+			}
+		})
+	}
+}
+
+func TestExpired(t *testing.T) {
+	// Two snapshots a day at 06:00 and 18:00, for 21 days ending on Sunday 2024-03-31
+	end := time.Date(2024, time.March, 31, 18, 0, 0, 0, time.UTC)
+	snapshots := []world.Snapshot{}
+	for i := 0; i < 42; i++ {
+		snapshots = append(snapshots, world.Snapshot{
+			World:     "Dedicated",
+			CreatedAt: end.Add(-time.Duration(i) * 12 * time.Hour),
+		})
+	}
+	other := world.Snapshot{
+		World:     "Other",
+		CreatedAt: end.AddDate(-1, 0, 0),
+	}
+
+	tests := map[string]struct {
+		retention world.Retention
+		snapshots []world.Snapshot
+		expected  int
+	}{
+		"keep everything if no retention rules are set": {
+			retention: world.Retention{},
+			snapshots: snapshots,
+			expected:  0,
+		},
+		"keep only the most recent snapshots": {
+			retention: world.Retention{KeepLast: 5},
+			snapshots: snapshots,
+			expected:  37,
+		},
+		"keep the newest snapshot of each day": {
+			retention: world.Retention{KeepDaily: 7},
+			snapshots: snapshots,
+			expected:  35,
+		},
+		"keep the newest snapshot of each week": {
+			retention: world.Retention{KeepWeekly: 2},
+			snapshots: snapshots,
+			expected:  40,
+		},
+		"rules overlap instead of adding up": {
+			retention: world.Retention{KeepLast: 2, KeepDaily: 2, KeepWeekly: 2},
+			snapshots: snapshots,
+			expected:  38,
+		},
+		"each world is pruned separately": {
+			retention: world.Retention{KeepLast: 1},
+			snapshots: append([]world.Snapshot{other}, snapshots[:3]...),
+			expected:  2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expired := test.retention.Expired(test.snapshots)
+			if len(expired) != test.expected {
+				t.Errorf("expected %d expired snapshots, received: %d", test.expected, len(expired))
+			}
+			for _, e := range expired {
+				if e.World == other.World {
+					t.Errorf("expected the only snapshot of %s to be kept", other.World)
+				}
+			}
+		})
+	}
+}
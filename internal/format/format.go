@@ -0,0 +1,168 @@
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The output formats Warden supports. Table is meant for humans, and is the default.
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown output format")
+	ErrWriteFailed   = errors.New("unable to write output")
+)
+
+// Tabular is implemented by results that are shown as a table, e.g. a list of mods.
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Text is implemented by results that have their own layout when shown to humans.
+type Text interface {
+	WriteText(w io.Writer) error
+}
+
+// A Message is the result of a command that doesn't return any data, e.g. a mod was removed.
+type Message struct {
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// An Error is the result of a failed command. Codes are stable, so scripts can match on them
+// instead of on the message.
+type Error struct {
+	Code     string `json:"code" yaml:"code"`
+	Message  string `json:"message" yaml:"message"`
+	Detail   string `json:"detail,omitempty" yaml:"detail,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty" yaml:"exit_code,omitempty"`
+}
+
+// Formatter renders command results in the format the user asked for.
+type Formatter interface {
+	// The name of the format, e.g. "json"
+	Format() string
+
+	// Writes a single result
+	Write(v any) error
+}
+
+func New(format string, w io.Writer) (Formatter, error) {
+	switch strings.ToLower(format) {
+	case Table:
+		return &tableFormatter{w: w}, nil
+	case JSON:
+		return &jsonFormatter{w: w}, nil
+	case YAML:
+		return &yamlFormatter{w: w}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// errorEnvelope wraps errors in structured output, so they can't be mistaken for a result
+type errorEnvelope struct {
+	Error Error `json:"error" yaml:"error"`
+}
+
+func envelope(v any) any {
+	if e, ok := v.(Error); ok {
+		return errorEnvelope{Error: e}
+	}
+	return v
+}
+
+type jsonFormatter struct {
+	w io.Writer
+}
+
+func (f *jsonFormatter) Format() string {
+	return JSON
+}
+
+func (f *jsonFormatter) Write(v any) error {
+	encoder := json.NewEncoder(f.w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(envelope(v)); err != nil {
+		return ErrWriteFailed
+	}
+	return nil
+}
+
+type yamlFormatter struct {
+	w io.Writer
+}
+
+func (f *yamlFormatter) Format() string {
+	return YAML
+}
+
+func (f *yamlFormatter) Write(v any) error {
+	encoder := yaml.NewEncoder(f.w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(envelope(v)); err != nil {
+		return ErrWriteFailed
+	}
+	if err := encoder.Close(); err != nil {
+		return ErrWriteFailed
+	}
+	return nil
+}
+
+type tableFormatter struct {
+	w io.Writer
+}
+
+func (f *tableFormatter) Format() string {
+	return Table
+}
+
+func (f *tableFormatter) Write(v any) error {
+	var err error
+	switch r := v.(type) {
+	case Text:
+		err = r.WriteText(f.w)
+	case Tabular:
+		err = f.writeTable(r)
+	case Message:
+		_, err = fmt.Fprintf(f.w, "... %s ...\n", r.Message)
+	case Error:
+		_, err = fmt.Fprintf(f.w, "... %s ...\n", r.Message)
+	default:
+		_, err = fmt.Fprintln(f.w, v)
+	}
+	if err != nil {
+		return ErrWriteFailed
+	}
+	return nil
+}
+
+func (f *tableFormatter) writeTable(t Tabular) error {
+	rows := t.Rows()
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(f.w, "... no results ...")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(f.w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(t.Header()))
+	for i, h := range t.Header() {
+		header[i] = strings.ToUpper(h)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
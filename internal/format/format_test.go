@@ -0,0 +1,107 @@
+package format_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"warden/internal/format"
+)
+
+type testTable struct {
+	rows [][]string
+}
+
+func (t testTable) Header() []string {
+	return []string{"name", "version"}
+}
+
+func (t testTable) Rows() [][]string {
+	return t.rows
+}
+
+type testText struct{}
+
+func (t testText) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, "custom layout\n")
+	return err
+}
+
+func TestNew_Sad(t *testing.T) {
+	_, err := format.New("xml", &bytes.Buffer{})
+	if !errors.Is(err, format.ErrUnknownFormat) {
+		t.Errorf("expected error: %+v, received: %+v", format.ErrUnknownFormat, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	mod := struct {
+		Name    string `json:"name" yaml:"name"`
+		Version string `json:"version" yaml:"version"`
+	}{Name: "Sleepover", Version: "1.0.1"}
+
+	tests := map[string]struct {
+		format   string
+		result   any
+		expected string
+	}{
+		"JSON results are indented": {
+			format:   format.JSON,
+			result:   mod,
+			expected: "{\n  \"name\": \"Sleepover\",\n  \"version\": \"1.0.1\"\n}\n",
+		},
+		"JSON errors are wrapped": {
+			format:   format.JSON,
+			result:   format.Error{Code: "mod_not_found", Message: "mod not found"},
+			expected: "{\n  \"error\": {\n    \"code\": \"mod_not_found\",\n    \"message\": \"mod not found\"\n  }\n}\n",
+		},
+		"YAML results": {
+			format:   format.YAML,
+			result:   mod,
+			expected: "name: Sleepover\nversion: 1.0.1\n",
+		},
+		"YAML errors are wrapped": {
+			format:   format.YAML,
+			result:   format.Error{Code: "mod_not_found", Message: "mod not found"},
+			expected: "error:\n  code: mod_not_found\n  message: mod not found\n",
+		},
+		"tables are aligned with an upper case header": {
+			format:   format.Table,
+			result:   testTable{rows: [][]string{{"Sleepover", "1.0.1"}, {"AzuClock", "1.0.10"}}},
+			expected: "NAME       VERSION\nSleepover  1.0.1\nAzuClock   1.0.10\n",
+		},
+		"empty tables print a message": {
+			format:   format.Table,
+			result:   testTable{},
+			expected: "... no results ...\n",
+		},
+		"results with their own layout are written as-is": {
+			format:   format.Table,
+			result:   testText{},
+			expected: "custom layout\n",
+		},
+		"messages are shown to humans": {
+			format:   format.Table,
+			result:   format.Message{Status: "ok", Message: "mod successfully removed!"},
+			expected: "... mod successfully removed! ...\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			f, err := format.New(strings.ToUpper(test.format), buf)
+			if err != nil {
+				t.Fatalf("unexpected error creating formatter, received: %+v", err)
+			}
+
+			if err := f.Write(test.result); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if buf.String() != test.expected {
+				t.Errorf("expected output:\n%q\nreceived:\n%q", test.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"warden/internal/api/source"
+	"warden/internal/data/file"
+)
+
+// Archives don't say who made them, so their mods are given this namespace unless another one is
+// picked when they're added
+const archiveNamespace = "Local"
+
+var ErrInvalidNamespace = errors.New("namespace must be letters, numbers or _")
+
+var (
+	errUnknownArchiveSize = errors.New("archive size isn't known until it's fetched")
+
+	namespacePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+)
+
+// archiveSource adds mods straight from a package archive, for mods that aren't listed anywhere.
+// Packages are looked up by the URL or path of their archive, which is fetched into the archive
+// cache and described by its manifest.json.
+type archiveSource struct {
+	fm   file.Manager
+	name string
+}
+
+func newArchiveSource(fm file.Manager, name string) source.Source {
+	return &archiveSource{
+		fm:   fm,
+		name: name,
+	}
+}
+
+func (as *archiveSource) GetPackage(namespace, location string) (source.Package, error) {
+	if namespace == "" {
+		namespace = archiveNamespace
+	}
+	if !namespacePattern.MatchString(namespace) {
+		return source.Package{}, fmt.Errorf("%w: %q", ErrInvalidNamespace, namespace)
+	}
+
+	manifest, err := as.fm.CachePackage(location, namespace)
+	if err != nil {
+		return source.Package{}, err
+	}
+
+	fullName := namespace + "-" + manifest.Name + "-" + manifest.VersionNumber
+	return source.Package{
+		Source:    as.name,
+		Namespace: namespace,
+		Name:      manifest.Name,
+		Latest: source.Release{
+			Namespace:    namespace,
+			Name:         manifest.Name,
+			Version:      manifest.VersionNumber,
+			FullName:     fullName,
+			Description:  manifest.Description,
+			WebsiteURL:   manifest.WebsiteURL,
+			Dependencies: manifest.Dependencies,
+			DownloadURL:  location,
+			ID:           location,
+		},
+		Categories: []string{},
+	}, nil
+}
+
+// GetRelease can only return the release that's in the archive right now
+func (as *archiveSource) GetRelease(namespace, location, version string) (source.Release, error) {
+	pkg, err := as.GetPackage(namespace, location)
+	if err != nil {
+		return source.Release{}, err
+	}
+	if pkg.Latest.Version != version {
+		return source.Release{}, fmt.Errorf("%w: %s is %s", source.ErrReleaseNotFound, location, pkg.Latest.Version)
+	}
+	return pkg.Latest, nil
+}
+
+func (as *archiveSource) DownloadURL(release source.Release) (string, error) {
+	return release.DownloadURL, nil
+}
+
+func (as *archiveSource) GetDownloadSize(release source.Release) (int64, error) {
+	return 0, errUnknownArchiveSize
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"warden/internal/api/source"
+)
+
+// UnknownErrorCode is used for any error that isn't returned by a service
+const UnknownErrorCode = "unknown"
+
+// errorCodes maps every error a service returns to a stable, machine-readable code. These
+// codes are part of Warden's structured output, so existing ones must never change.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	// Checked before mod_not_found, which a namespace that can't be used ends up wrapped in
+	{ErrInvalidNamespace, "invalid_namespace"},
+	{ErrModNotFound, "mod_not_found"},
+	{ErrModNotInstalled, "mod_not_installed"},
+	{ErrModAlreadyInstalled, "mod_already_installed"},
+	{ErrModInstallFailed, "mod_install_failed"},
+	{ErrAddDependenciesFailed, "dependencies_failed"},
+	{ErrInvalidDependency, "invalid_dependency"},
+	{ErrUnableToListMods, "mod_list_failed"},
+	{ErrUnableToUpdateMod, "mod_update_failed"},
+	{ErrUnableToRemoveMod, "mod_remove_failed"},
+	{ErrModInModpack, "mod_in_modpack"},
+	{source.ErrUnknownSource, "unknown_source"},
+
+	{ErrFrameworkNotFound, "framework_not_found"},
+	{ErrFrameworkNotInstalled, "framework_not_installed"},
+	{ErrUnableToInstallFramework, "framework_install_failed"},
+	{ErrUnableToUpdateFramework, "framework_update_failed"},
+	{ErrUnableToRemoveFramework, "framework_remove_failed"},
+
+	{ErrWorldBackupNotFound, "world_backup_not_found"},
+	{ErrUnableToBackupWorld, "world_backup_failed"},
+	{ErrUnableToListBackups, "world_backup_list_failed"},
+	{ErrUnableToPruneBackups, "world_backup_prune_failed"},
+	{ErrUnableToRestoreWorld, "world_restore_failed"},
+
+	{ErrInvalidGameType, "invalid_game_type"},
+	{ErrServerStartFailed, "server_start_failed"},
+	{ErrServerAlreadyRunning, "server_already_running"},
+	{ErrServerNotRunning, "server_not_running"},
+	{ErrServerStopTimeout, "server_stop_timeout"},
+	{ErrServerStopFailed, "server_stop_failed"},
+	{ErrServerStatusFailed, "server_status_failed"},
+	{ErrServerSupervised, "server_supervised"},
+	{ErrServerCrashLoop, "server_crash_loop"},
+	{ErrInvalidServerSettings, "invalid_server_settings"},
+	{ErrUnsupportedPlatform, "unsupported_platform"},
+	{ErrServerNotInstalled, "server_not_installed"},
+	{ErrServerAlreadyInstalled, "server_already_installed"},
+	{ErrSteamCMDNotFound, "steamcmd_not_found"},
+	{ErrSteamCMDFailed, "steamcmd_failed"},
+
+	{ErrInvalidUnitScope, "invalid_unit_scope"},
+	{ErrUnitNotInstalled, "unit_not_installed"},
+	{ErrUnitInstallFailed, "unit_install_failed"},
+	{ErrUnitUninstallFailed, "unit_uninstall_failed"},
+	{ErrUnitStatusFailed, "unit_status_failed"},
+
+	{ErrInvalidSchedule, "invalid_schedule"},
+	{ErrJobNotFound, "job_not_found"},
+	{ErrNoJobsScheduled, "no_jobs_scheduled"},
+	{ErrUnableToRecordJobRun, "job_record_failed"},
+	{ErrUnableToListJobRuns, "job_history_failed"},
+	{ErrJobFailed, "job_failed"},
+
+	{ErrInvalidLogSource, "invalid_log_source"},
+	{ErrUnableToReadLog, "log_read_failed"},
+
+	{ErrInvalidPlayerList, "invalid_player_list"},
+	{ErrInvalidPlayerID, "invalid_player_id"},
+	{ErrPlayerAlreadyListed, "player_already_listed"},
+	{ErrPlayerNotListed, "player_not_listed"},
+	{ErrUnableToListPlayers, "player_list_failed"},
+	{ErrUnableToEditPlayerList, "player_edit_failed"},
+	{ErrUnableToReloadPlayers, "player_reload_failed"},
+
+	{ErrInvalidInstanceName, "invalid_instance_name"},
+	{ErrInstanceNotFound, "instance_not_found"},
+	{ErrInstanceAlreadyExists, "instance_already_exists"},
+	{ErrInstanceInUse, "instance_in_use"},
+	{ErrInstanceRunning, "instance_running"},
+	{ErrValheimDirectoryInUse, "valheim_directory_in_use"},
+	{ErrUnableToListInstances, "instance_list_failed"},
+	{ErrUnableToCreateInstance, "instance_create_failed"},
+	{ErrUnableToRemoveInstance, "instance_remove_failed"},
+
+	{ErrInvalidProfileName, "invalid_profile_name"},
+	{ErrProfileNotFound, "profile_not_found"},
+	{ErrProfileAlreadyExists, "profile_already_exists"},
+	{ErrProfileAlreadyActive, "profile_already_active"},
+	{ErrUnableToListProfiles, "profile_list_failed"},
+	{ErrUnableToCreateProfile, "profile_create_failed"},
+	{ErrUnableToSwitchProfile, "profile_switch_failed"},
+	{ErrUnableToDeleteProfile, "profile_delete_failed"},
+	{ErrInvalidProfileExport, "invalid_profile_export"},
+	{ErrProfileExportNotFound, "profile_export_not_found"},
+	{ErrUnableToImportProfile, "profile_import_failed"},
+	{ErrUnableToExportProfile, "profile_export_failed"},
+
+	{ErrInvalidModpack, "invalid_modpack"},
+	{ErrModpackFileNotFound, "modpack_file_not_found"},
+	{ErrUnableToBuildModpack, "modpack_build_failed"},
+
+	{ErrInvalidMirrorManifest, "invalid_mirror_manifest"},
+	{ErrMirrorManifestNotFound, "mirror_manifest_not_found"},
+	{ErrUnableToSyncMirror, "mirror_sync_failed"},
+
+	{ErrUpdateVerificationFailed, "update_verification_failed"},
+	{ErrUpdateRollbackFailed, "update_rollback_failed"},
+	{ErrUnableToVerifyUpdate, "update_verify_failed"},
+
+	{ErrMaxAttempts, "confirmation_failed"},
+	{ErrConfirmationRequired, "confirmation_required"},
+	{ErrAborted, "aborted"},
+}
+
+// Code returns the machine-readable code for an error returned by a service
+func Code(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return UnknownErrorCode
+}
@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"warden/internal/service"
+)
+
+func TestCode(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected string
+	}{
+		"return the code for a service error": {
+			err:      service.ErrModNotFound,
+			expected: "mod_not_found",
+		},
+		"return the code for a wrapped service error": {
+			err:      fmt.Errorf("adding Azumatt-Sleepover: %w", service.ErrModAlreadyInstalled),
+			expected: "mod_already_installed",
+		},
+		"return the code for a service error that wraps its cause": {
+			err:      fmt.Errorf("%w: %w", service.ErrModNotFound, errors.New("connection refused")),
+			expected: "mod_not_found",
+		},
+		"return the code when the user aborts": {
+			err:      service.ErrAborted,
+			expected: "aborted",
+		},
+		"return unknown for any other error": {
+			err:      errors.New("something else"),
+			expected: service.UnknownErrorCode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if code := service.Code(test.err); code != test.expected {
+				t.Errorf("expected code: %s, received: %s", test.expected, code)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package service
 
-import "errors"
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
 
 const (
 	yes     = "Y"
@@ -12,6 +18,112 @@ const (
 	yesOrNoLong = "[" + yesLong + "/" + noLong + "]"
 )
 
+// ConfirmMode controls how confirmation prompts are answered.
+type ConfirmMode int
+
+const (
+	// Ask the user and wait for their answer on stdin
+	Interactive ConfirmMode = iota
+
+	// Answer yes to every prompt without asking
+	AssumeYes
+
+	// Answer no to every prompt without asking
+	AssumeNo
+
+	// Never ask, and fail any action that needs confirming
+	NonInteractive
+)
+
 var (
-	ErrMaxAttempts = errors.New("reached max confirmation attempts")
+	ErrMaxAttempts          = errors.New("reached max confirmation attempts")
+	ErrConfirmationRequired = errors.New("confirmation is required, but input is non-interactive")
+	ErrAborted              = errors.New("aborted by user")
 )
+
+// Confirmer asks the user to confirm an action before a service carries it out. Services never
+// read from stdin directly, so they can be run from scripts and cron jobs.
+type Confirmer interface {
+	// Prints the question and returns whether the user accepted. Strict questions guard
+	// destructive actions and need the long form of yes, e.g. "YES I AM"
+	Confirm(question string, strict bool) (bool, error)
+
+	// Changes how future questions are answered
+	SetMode(mode ConfirmMode)
+}
+
+type confirmer struct {
+	mode        ConfirmMode
+	in          *bufio.Scanner
+	interactive bool
+}
+
+// NewConfirmer creates a Confirmer that reads answers from the given reader. If the reader is a
+// file that isn't a terminal (e.g. stdin piped from /dev/null), it won't wait for answers that
+// will never come.
+func NewConfirmer(reader io.Reader) Confirmer {
+	return &confirmer{
+		mode:        Interactive,
+		in:          bufio.NewScanner(reader),
+		interactive: isTerminal(reader),
+	}
+}
+
+func (c *confirmer) Confirm(question string, strict bool) (bool, error) {
+	accept, decline, options := yes, no, yesOrNo
+	if strict {
+		accept, decline, options = yesLong, noLong, yesOrNoLong
+	}
+	fmt.Fprintf(progress, "%s %s\n", question, options)
+
+	switch c.mode {
+	case AssumeYes:
+		fmt.Fprintln(progress, accept)
+		return true, nil
+	case AssumeNo:
+		fmt.Fprintln(progress, decline)
+		return false, nil
+	case NonInteractive:
+		return false, ErrConfirmationRequired
+	}
+	if !c.interactive {
+		return false, ErrConfirmationRequired
+	}
+
+	tries := 0
+	for c.in.Scan() && tries < 2 {
+		if c.in.Text() == accept {
+			return true, nil
+		} else if c.in.Text() == decline {
+			return false, nil
+		} else {
+			tries++
+		}
+	}
+	if tries >= 2 {
+		return false, ErrMaxAttempts
+	}
+	return false, nil
+}
+
+func (c *confirmer) SetMode(mode ConfirmMode) {
+	c.mode = mode
+}
+
+// isTerminal reports whether the reader is an interactive terminal. Pipes, regular files and
+// /dev/null (what cron and systemd give as stdin) aren't. Anything that isn't a file, e.g. a
+// strings.Reader in tests, is treated as interactive.
+func isTerminal(reader io.Reader) bool {
+	f, ok := reader.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"warden/internal/service"
+)
+
+func TestConfirm_Happy(t *testing.T) {
+	tests := map[string]struct {
+		rd       io.Reader
+		mode     service.ConfirmMode
+		strict   bool
+		expected bool
+	}{
+		"return true if user confirms": {
+			rd:       strings.NewReader("Y"),
+			mode:     service.Interactive,
+			expected: true,
+		},
+		"return false if user declines": {
+			rd:       strings.NewReader("n"),
+			mode:     service.Interactive,
+			expected: false,
+		},
+		"return true if user confirms a strict question with the long answer": {
+			rd:       strings.NewReader("YES I AM"),
+			mode:     service.Interactive,
+			strict:   true,
+			expected: true,
+		},
+		"retry if user answers a strict question with the short answer": {
+			rd:       strings.NewReader("Y\nYES I AM"),
+			mode:     service.Interactive,
+			strict:   true,
+			expected: true,
+		},
+		"return false if input ends without an answer": {
+			rd:       strings.NewReader(""),
+			mode:     service.Interactive,
+			expected: false,
+		},
+		"return true without reading input when assuming yes": {
+			rd:       strings.NewReader("n"),
+			mode:     service.AssumeYes,
+			expected: true,
+		},
+		"return false without reading input when assuming no": {
+			rd:       strings.NewReader("Y"),
+			mode:     service.AssumeNo,
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := service.NewConfirmer(test.rd)
+			c.SetMode(test.mode)
+
+			ok, err := c.Confirm("are you sure?", test.strict)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if ok != test.expected {
+				t.Errorf("expected: %t, received: %t", test.expected, ok)
+			}
+		})
+	}
+}
+
+func TestConfirm_Sad(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("unexpected error during test set-up, received: %+v", err)
+	}
+	defer devNull.Close()
+
+	tests := map[string]struct {
+		rd       io.Reader
+		mode     service.ConfirmMode
+		expected error
+	}{
+		"return an error if user fails to confirm": {
+			rd:       strings.NewReader("TEST\nRANDOM\nINPUTS\nTEST\n"),
+			mode:     service.Interactive,
+			expected: service.ErrMaxAttempts,
+		},
+		"return an error if running non-interactively": {
+			rd:       strings.NewReader("Y"),
+			mode:     service.NonInteractive,
+			expected: service.ErrConfirmationRequired,
+		},
+		"return an error instead of waiting if input isn't a terminal": {
+			rd:       devNull,
+			mode:     service.Interactive,
+			expected: service.ErrConfirmationRequired,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := service.NewConfirmer(test.rd)
+			c.SetMode(test.mode)
+
+			ok, err := c.Confirm("are you sure?", false)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if ok {
+				t.Error("expected false, received true")
+			}
+		})
+	}
+}
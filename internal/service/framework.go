@@ -1,14 +1,13 @@
 package service
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"io"
 	"warden/internal/api/thunderstore"
 	"warden/internal/data/file"
 	"warden/internal/data/repo"
 	"warden/internal/domain/framework"
+	"warden/internal/domain/plan"
 )
 
 var (
@@ -17,7 +16,7 @@ var (
 	ErrUnableToRemoveFramework  = errors.New("unable to remove mod framework")
 
 	ErrFrameworkNotInstalled = errors.New("framework is not installed")
-	ErrFrameworkNotFound     = errors.New("unable to delete record from frameworks table")
+	ErrFrameworkNotFound     = errors.New("framework not found")
 )
 
 // Encapsulates all the business logic for managing frameworks, namely BepInEx. It coordinates
@@ -27,21 +26,25 @@ type Framework interface {
 	InstallBepInEx() error
 	UpdateBepInEx() error
 	RemoveBepInEx() error
+
+	PlanInstallBepInEx() (plan.Plan, error)
+	PlanUpdateBepInEx() (plan.Plan, error)
+	PlanRemoveBepInEx() (plan.Plan, error)
 }
 
 type frameworkService struct {
 	fr repo.Frameworks
 	fm file.Manager
 	ts thunderstore.Thunderstore
-	in *bufio.Scanner
+	c  Confirmer
 }
 
-func NewFrameworkService(fr repo.Frameworks, fm file.Manager, ts thunderstore.Thunderstore, reader io.Reader) Framework {
+func NewFrameworkService(fr repo.Frameworks, fm file.Manager, ts thunderstore.Thunderstore, c Confirmer) Framework {
 	return &frameworkService{
 		fr: fr,
 		fm: fm,
 		ts: ts,
-		in: bufio.NewScanner(reader),
+		c:  c,
 	}
 }
 
@@ -51,47 +54,40 @@ func (fs *frameworkService) InstallBepInEx() error {
 		return nil
 	}
 
-	fmt.Println("... BepInEx installation is missing ...")
-	fmt.Printf("did you want to install BepInEx? %s\n", yesOrNo)
-
-	tries := 0
-	for fs.in.Scan() && tries < 2 {
-		if fs.in.Text() == yes {
-			// Install BepInEx
-			pkg, err := fs.ts.GetPackage(framework.BepInExNamespace, framework.BepInEx)
-			if err != nil {
-				return ErrFrameworkNotFound
-			}
-
-			_, err = fs.fm.InstallBepInEx(pkg.Latest.DownloadURL, pkg.Latest.FullName)
-			if err != nil {
-				return ErrUnableToInstallFramework
-			}
-
-			f := framework.Framework{
-				Name:        pkg.Latest.Name,
-				Namespace:   pkg.Latest.Namespace,
-				Version:     pkg.Latest.VersionNumber,
-				WebsiteURL:  pkg.Latest.WebsiteURL,
-				Description: pkg.Latest.Description,
-			}
-			err = fs.fr.InsertFramework(f)
-			if err != nil {
-				return ErrUnableToInstallFramework
-			}
-
-			fmt.Println("... successfully installed BepInEx ...")
-			return nil
-		} else if fs.in.Text() == no {
-			fmt.Println("... aborting ...")
-			return nil
-		} else {
-			tries++
-		}
-	}
-	if tries >= 2 {
-		return ErrMaxAttempts
+	fmt.Fprintln(progress, "... BepInEx installation is missing ...")
+
+	ok, err := fs.c.Confirm("did you want to install BepInEx?", false)
+	if err != nil {
+		return err
 	}
+	if !ok {
+		return ErrAborted
+	}
+
+	// Install BepInEx
+	pkg, err := fs.ts.GetPackage(framework.BepInExNamespace, framework.BepInEx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFrameworkNotFound, err)
+	}
+
+	_, err = fs.fm.InstallBepInEx(pkg.Latest.DownloadURL, pkg.Latest.FullName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToInstallFramework, err)
+	}
+
+	f := framework.Framework{
+		Name:        pkg.Latest.Name,
+		Namespace:   pkg.Latest.Namespace,
+		Version:     pkg.Latest.VersionNumber,
+		WebsiteURL:  pkg.Latest.WebsiteURL,
+		Description: pkg.Latest.Description,
+	}
+	err = fs.fr.InsertFramework(f)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToInstallFramework, err)
+	}
+
+	fmt.Fprintln(progress, "... successfully installed BepInEx ...")
 	return nil
 }
 
@@ -99,80 +95,65 @@ func (fs *frameworkService) UpdateBepInEx() error {
 	// Check if BepInEx is installed
 	current, err := fs.fr.GetFramework(framework.BepInEx)
 	if err != nil && errors.Is(err, repo.ErrFrameworkFetchNoResults) {
-		return ErrFrameworkNotInstalled
+		return fmt.Errorf("%w: %w", ErrFrameworkNotInstalled, err)
 	}
 	if err != nil {
-		return ErrUnableToUpdateFramework
+		return fmt.Errorf("%w: %w", ErrUnableToUpdateFramework, err)
 	}
 	// Check if current version is the latest
 	pkg, err := fs.ts.GetPackage(framework.BepInExNamespace, framework.BepInEx)
 	if err != nil {
-		return ErrUnableToUpdateFramework
-	}
-
-	if pkg.Latest.VersionNumber > current.Version {
-		fmt.Printf("... a new version of BepInEx was found (%s) ...\n", pkg.Latest.VersionNumber)
-		fmt.Printf("did you want to update BepInEx? %s\n", yesOrNo)
-
-		// If new version is found, confirm with the user if they want to update
-		tries := 0
-		for fs.in.Scan() && tries < 2 {
-			if fs.in.Text() == yes {
-				if err := fs.fm.UpdateBepInEx(pkg.Latest.DownloadURL, pkg.Latest.FullName); err != nil {
-					return ErrUnableToUpdateFramework
-				}
-
-				f := framework.Framework{
-					ID:          current.ID,
-					Name:        pkg.Latest.Name,
-					Namespace:   pkg.Latest.Namespace,
-					Version:     pkg.Latest.VersionNumber,
-					WebsiteURL:  pkg.Latest.WebsiteURL,
-					Description: pkg.Latest.Description,
-				}
-				err = fs.fr.UpdateFramework(f)
-				if err != nil {
-					return ErrUnableToInstallFramework
-				}
-				return nil
-			} else if fs.in.Text() == no {
-				fmt.Println("... aborting ...")
-				return nil
-			} else {
-				tries++
-			}
-		}
-		if tries >= 2 {
-			return ErrMaxAttempts
-		}
-	} else {
-		fmt.Println("... BepInEx is up-to-date! ...")
+		return fmt.Errorf("%w: %w", ErrUnableToUpdateFramework, err)
+	}
+
+	if pkg.Latest.VersionNumber <= current.Version {
+		fmt.Fprintln(progress, "... BepInEx is up-to-date! ...")
+		return nil
+	}
+	fmt.Fprintf(progress, "... a new version of BepInEx was found (%s) ...\n", pkg.Latest.VersionNumber)
+
+	// If new version is found, confirm with the user if they want to update
+	ok, err := fs.c.Confirm("did you want to update BepInEx?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	if err := fs.fm.UpdateBepInEx(pkg.Latest.DownloadURL, pkg.Latest.FullName); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToUpdateFramework, err)
+	}
+
+	f := framework.Framework{
+		ID:          current.ID,
+		Name:        pkg.Latest.Name,
+		Namespace:   pkg.Latest.Namespace,
+		Version:     pkg.Latest.VersionNumber,
+		WebsiteURL:  pkg.Latest.WebsiteURL,
+		Description: pkg.Latest.Description,
+	}
+	err = fs.fr.UpdateFramework(f)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToInstallFramework, err)
 	}
 	return nil
 }
 
 func (fs *frameworkService) RemoveBepInEx() error {
-	fmt.Printf("are you sure you want to remove BepInEx? %s\n", yesOrNoLong)
-
-	tries := 0
-	for fs.in.Scan() && tries < 2 {
-		if fs.in.Text() == yesLong {
-			if err := fs.fm.RemoveBepInEx(); err != nil {
-				return ErrUnableToRemoveFramework
-			}
-			if err := fs.fr.DeleteFramework(framework.BepInEx); err != nil {
-				return ErrUnableToRemoveFramework
-			}
-			return nil
-		} else if fs.in.Text() == noLong {
-			fmt.Println("... aborting ...")
-			return nil
-		} else {
-			tries++
-		}
-	}
-	if tries >= 2 {
-		return ErrMaxAttempts
+	ok, err := fs.c.Confirm("are you sure you want to remove BepInEx?", true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	if err := fs.fm.RemoveBepInEx(); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveFramework, err)
+	}
+	if err := fs.fr.DeleteFramework(framework.BepInEx); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveFramework, err)
 	}
 	return nil
 }
@@ -64,7 +64,7 @@ func TestInstallBepInEx_Happy(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			fs := service.NewFrameworkService(test.r, test.fm, test.ts, test.rd)
+			fs := service.NewFrameworkService(test.r, test.fm, test.ts, service.NewConfirmer(test.rd))
 
 			if err := fs.InstallBepInEx(); err != nil {
 				t.Errorf("expected a nil error, received: %+v", err)
@@ -165,7 +165,7 @@ func TestInstallBepInEx_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			fs := service.NewFrameworkService(test.r, test.fm, test.ts, test.rd)
+			fs := service.NewFrameworkService(test.r, test.fm, test.ts, service.NewConfirmer(test.rd))
 
 			err := fs.InstallBepInEx()
 			if !errors.Is(err, test.expected) {
@@ -187,7 +187,7 @@ func TestRemoveBepInEx_Happy(t *testing.T) {
 		},
 	}
 	rd := strings.NewReader("YES I AM")
-	fs := service.NewFrameworkService(r, fm, &mock.Thunderstore{}, rd)
+	fs := service.NewFrameworkService(r, fm, &mock.Thunderstore{}, service.NewConfirmer(rd))
 
 	if err := fs.RemoveBepInEx(); err != nil {
 		t.Errorf("expected a nil error, received: %+v", err)
@@ -232,7 +232,7 @@ func TestRemoveBepInEx_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			fs := service.NewFrameworkService(test.r, test.fm, &mock.Thunderstore{}, test.rd)
+			fs := service.NewFrameworkService(test.r, test.fm, &mock.Thunderstore{}, service.NewConfirmer(test.rd))
 
 			if err := fs.RemoveBepInEx(); !errors.Is(err, test.expected) {
 				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/domain/instance"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// New instances keep their worlds and player lists in here, inside the instance's directory, so
+// they never load or overwrite another instance's world
+const instanceSaveDirectory = "saves"
+
+var (
+	ErrInvalidInstanceName    = errors.New("invalid instance name")
+	ErrInstanceNotFound       = errors.New("instance does not exist")
+	ErrInstanceAlreadyExists  = errors.New("instance already exists")
+	ErrInstanceInUse          = errors.New("instance is the one being managed")
+	ErrInstanceRunning        = errors.New("instance's server is running")
+	ErrValheimDirectoryInUse  = errors.New("valheim directory is used by another instance")
+	ErrUnableToListInstances  = errors.New("unable to list instances")
+	ErrUnableToCreateInstance = errors.New("unable to create instance")
+	ErrUnableToRemoveInstance = errors.New("unable to remove instance")
+)
+
+// Exposes all methods for managing the Valheim server instances Warden looks after. Each named
+// instance has its own config file, database, cache and world backups in its own directory, and
+// the default instance uses the ones in the config directory itself.
+type Instances interface {
+	// Returns the default instance, followed by every named one
+	ListInstances() ([]instance.Instance, error)
+
+	// Creates an instance with the default settings, for the server in the given Valheim
+	// directory, which no other instance can use. It gets its own save directory and a server
+	// port no other instance uses.
+	AddInstance(name, valheimDirectory string) (instance.Instance, error)
+
+	// Creates an instance with a copy of another one's settings, for the server in the given
+	// Valheim directory. Its database, cache, backups and saves still start out empty, and it gets
+	// its own server port.
+	CloneInstance(source, name, valheimDirectory string) (instance.Instance, error)
+
+	// Deletes a named instance's directory, along with its config, database and world backups
+	RemoveInstance(name string) error
+}
+
+type instanceService struct {
+	config.Config
+
+	// The config directory every instance's directory is kept in
+	directory string
+	c         Confirmer
+}
+
+// NewInstanceService creates an Instances that keeps instances inside the given config
+// directory. The config is the one for the instance being managed right now.
+func NewInstanceService(cfg config.Config, directory string, c Confirmer) Instances {
+	return &instanceService{
+		Config:    cfg,
+		directory: directory,
+		c:         c,
+	}
+}
+
+func (is *instanceService) ListInstances() ([]instance.Instance, error) {
+	cfg, err := config.Read(is.directory)
+	if err != nil {
+		// The default instance doesn't have a config file until it's first used
+		if _, statErr := os.Stat(filepath.Join(is.directory, config.WardenConfigFile)); !errors.Is(statErr, os.ErrNotExist) {
+			return []instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToListInstances, err)
+		}
+		d := config.Default(is.directory)
+		cfg = &d
+	}
+	instances := []instance.Instance{newInstance(instance.Default, *cfg)}
+
+	entries, err := os.ReadDir(filepath.Join(is.directory, instance.DirectoryName))
+	if errors.Is(err, os.ErrNotExist) {
+		return instances, nil
+	}
+	if err != nil {
+		return []instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToListInstances, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() || instance.ValidateName(e.Name()) != nil {
+			continue
+		}
+		cfg, err := config.Read(instance.Directory(is.directory, e.Name()))
+		if err != nil {
+			return []instance.Instance{}, fmt.Errorf("%w: %s: %w", ErrUnableToListInstances, e.Name(), err)
+		}
+		instances = append(instances, newInstance(e.Name(), *cfg))
+	}
+	return instances, nil
+}
+
+func (is *instanceService) AddInstance(name, valheimDirectory string) (instance.Instance, error) {
+	cfg := config.Default(instance.Directory(is.directory, name))
+	cfg.ValheimDirectory = valheimDirectory
+	return is.create(name, cfg)
+}
+
+func (is *instanceService) CloneInstance(source, name, valheimDirectory string) (instance.Instance, error) {
+	if !instance.IsDefault(source) && instance.ValidateName(source) != nil {
+		return instance.Instance{}, fmt.Errorf("%w: %s", ErrInvalidInstanceName, source)
+	}
+	if !is.exists(source) {
+		return instance.Instance{}, fmt.Errorf("%w: %s", ErrInstanceNotFound, source)
+	}
+	cfg, err := config.Read(instance.Directory(is.directory, source))
+	if err != nil {
+		return instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToCreateInstance, err)
+	}
+
+	// Everything Warden keeps for an instance is moved into the clone's own directory
+	dir := instance.Directory(is.directory, name)
+	defaults := config.Default(dir)
+	cfg.ConfigDirectory = defaults.ConfigDirectory
+	cfg.DatabasePath = defaults.DatabasePath
+	cfg.CacheDirectory = defaults.CacheDirectory
+	cfg.BackupDirectory = defaults.BackupDirectory
+	cfg.ValheimDirectory = valheimDirectory
+	return is.create(name, *cfg)
+}
+
+func (is *instanceService) RemoveInstance(name string) error {
+	if err := instance.ValidateName(name); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstanceName, err)
+	}
+	if !is.exists(name) {
+		return fmt.Errorf("%w: %s", ErrInstanceNotFound, name)
+	}
+	if name == is.Instance {
+		return fmt.Errorf("%w: %s", ErrInstanceInUse, name)
+	}
+
+	dir := instance.Directory(is.directory, name)
+	if p, err := file.NewPIDFile(filepath.Join(dir, config.PIDFile)).Read(); err == nil && isRunning(p.PID) {
+		return fmt.Errorf("%w: %s", ErrInstanceRunning, name)
+	}
+
+	question := fmt.Sprintf("are you sure you want to remove %s? Its config, database and world backups in %s will be deleted", name, dir)
+	ok, err := is.c.Confirm(question, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveInstance, err)
+	}
+	return nil
+}
+
+// create writes a new instance's config file, giving it its own save directory and server port.
+// Its Valheim directory can't be one another instance already uses.
+func (is *instanceService) create(name string, cfg config.Config) (instance.Instance, error) {
+	if err := instance.ValidateName(name); err != nil {
+		return instance.Instance{}, fmt.Errorf("%w: %w", ErrInvalidInstanceName, err)
+	}
+	if is.exists(name) {
+		return instance.Instance{}, fmt.Errorf("%w: %s", ErrInstanceAlreadyExists, name)
+	}
+	if err := config.ValidatePath("valheim-directory", cfg.ValheimDirectory, cfg); err != nil {
+		return instance.Instance{}, err
+	}
+
+	instances, err := is.ListInstances()
+	if err != nil {
+		return instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToCreateInstance, err)
+	}
+	// Instances sharing a Valheim directory would install mods into each other's plugins
+	for _, i := range instances {
+		if sameDirectory(i.ValheimDirectory, cfg.ValheimDirectory) {
+			return instance.Instance{}, fmt.Errorf("%w: %s uses %s", ErrValheimDirectoryInUse, i.Name, cfg.ValheimDirectory)
+		}
+	}
+	cfg.SaveDirectory = filepath.Join(cfg.ConfigDirectory, instanceSaveDirectory)
+	cfg.ServerPort = instance.NextPort(cfg.ServerPort, instances)
+
+	if err := os.MkdirAll(cfg.ConfigDirectory, os.ModePerm); err != nil {
+		return instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToCreateInstance, err)
+	}
+	if err := config.Create(cfg); err != nil {
+		os.RemoveAll(cfg.ConfigDirectory)
+		return instance.Instance{}, fmt.Errorf("%w: %w", ErrUnableToCreateInstance, err)
+	}
+	return newInstance(name, cfg), nil
+}
+
+// sameDirectory checks if two paths are the same directory once ~ is expanded, e.g. ~/valheim and
+// /home/me/valheim
+func sameDirectory(a, b string) bool {
+	return expandPath(a) == expandPath(b)
+}
+
+// expandPath cleans a path with ~ expanded. A path that can't be expanded is only cleaned.
+func expandPath(path string) string {
+	if expanded, err := homedir.Expand(path); err == nil {
+		path = expanded
+	}
+	return filepath.Clean(path)
+}
+
+// exists checks if an instance has a config file. The default instance always exists.
+func (is *instanceService) exists(name string) bool {
+	if instance.IsDefault(name) {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(instance.Directory(is.directory, name), config.WardenConfigFile))
+	return err == nil
+}
+
+func newInstance(name string, cfg config.Config) instance.Instance {
+	return instance.Instance{
+		Name:             name,
+		ConfigDirectory:  cfg.ConfigDirectory,
+		ValheimDirectory: cfg.ValheimDirectory,
+		ServerPort:       cfg.ServerPort,
+		ServerWorld:      cfg.ServerWorld,
+	}
+}
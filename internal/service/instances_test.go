@@ -0,0 +1,197 @@
+package service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"warden/internal/config"
+	"warden/internal/domain/instance"
+	"warden/internal/service"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestAddInstance_Happy(t *testing.T) {
+	dir := t.TempDir()
+	is := service.NewInstanceService(config.Config{}, dir, service.NewConfirmer(strings.NewReader("")))
+
+	added, err := is.AddInstance("test", filepath.Join(dir, "valheim-test"))
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if added.ServerPort != config.DefaultServerPort+2 {
+		t.Errorf("expected the port after the default instance's, received: %d", added.ServerPort)
+	}
+
+	cfg, err := config.Read(instance.Directory(dir, "test"))
+	if err != nil {
+		t.Fatalf("expected the instance's config to be readable, received: %+v", err)
+	}
+	if cfg.ValheimDirectory != filepath.Join(dir, "valheim-test") {
+		t.Errorf("expected Valheim directory: %s, received: %s", filepath.Join(dir, "valheim-test"), cfg.ValheimDirectory)
+	}
+	if !strings.HasPrefix(cfg.DatabasePath, added.ConfigDirectory) || !strings.HasPrefix(cfg.SaveDirectory, added.ConfigDirectory) {
+		t.Errorf("expected the database and saves to be in %s, received: %s, %s", added.ConfigDirectory, cfg.DatabasePath, cfg.SaveDirectory)
+	}
+
+	instances, err := is.ListInstances()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(instances) != 2 || instances[0].Name != instance.Default || instances[1].Name != "test" {
+		t.Errorf("expected the default and test instances, received: %+v", instances)
+	}
+}
+
+func TestAddInstance_Sad(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		valheim  string
+		expected error
+	}{
+		"return an error if the name is invalid": {
+			name:     "Test World",
+			expected: service.ErrInvalidInstanceName,
+		},
+		"return an error if the name is the default instance's": {
+			name:     instance.Default,
+			expected: service.ErrInvalidInstanceName,
+		},
+		"return an error if the instance already exists": {
+			name:     "test",
+			expected: service.ErrInstanceAlreadyExists,
+		},
+		"return an error if another instance uses the Valheim directory": {
+			name:     "other",
+			expected: service.ErrValheimDirectoryInUse,
+		},
+		"return an error if another instance uses the Valheim directory under ~": {
+			name:     "other",
+			valheim:  "~/valheim",
+			expected: service.ErrValheimDirectoryInUse,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("HOME", dir)
+			homedir.DisableCache = true
+			t.Cleanup(func() { homedir.DisableCache = false })
+
+			is := service.NewInstanceService(config.Config{}, dir, service.NewConfirmer(strings.NewReader("")))
+			if _, err := is.AddInstance("test", filepath.Join(dir, "valheim")); err != nil {
+				t.Fatalf("unexpected error creating test instance, received: %+v", err)
+			}
+
+			valheim := filepath.Join(dir, "valheim")
+			if test.valheim != "" {
+				valheim = test.valheim
+			}
+			_, err := is.AddInstance(test.name, valheim)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCloneInstance(t *testing.T) {
+	dir := t.TempDir()
+	is := service.NewInstanceService(config.Config{}, dir, service.NewConfirmer(strings.NewReader("")))
+	if _, err := is.AddInstance("main", filepath.Join(dir, "valheim")); err != nil {
+		t.Fatalf("unexpected error creating test instance, received: %+v", err)
+	}
+
+	cloned, err := is.CloneInstance("main", "event", filepath.Join(dir, "valheim-event"))
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if cloned.ValheimDirectory != filepath.Join(dir, "valheim-event") {
+		t.Errorf("expected Valheim directory: %s, received: %s", filepath.Join(dir, "valheim-event"), cloned.ValheimDirectory)
+	}
+	if cloned.ServerPort != config.DefaultServerPort+4 {
+		t.Errorf("expected a port no other instance uses, received: %d", cloned.ServerPort)
+	}
+	cfg, err := config.Read(cloned.ConfigDirectory)
+	if err != nil {
+		t.Fatalf("expected the clone's config to be readable, received: %+v", err)
+	}
+	if cfg.DatabasePath != filepath.Join(cloned.ConfigDirectory, config.DefaultDatabaseFile) {
+		t.Errorf("expected the clone to have its own database, received: %s", cfg.DatabasePath)
+	}
+
+	if _, err := is.CloneInstance("missing", "other", filepath.Join(dir, "valheim-other")); !errors.Is(err, service.ErrInstanceNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrInstanceNotFound, err)
+	}
+	if _, err := is.CloneInstance("main", "other", filepath.Join(dir, "valheim")+string(filepath.Separator)); !errors.Is(err, service.ErrValheimDirectoryInUse) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrValheimDirectoryInUse, err)
+	}
+}
+
+func TestRemoveInstance_Happy(t *testing.T) {
+	dir := t.TempDir()
+	is := service.NewInstanceService(config.Config{}, dir, service.NewConfirmer(strings.NewReader("YES I AM\n")))
+	added, err := is.AddInstance("test", filepath.Join(dir, "valheim"))
+	if err != nil {
+		t.Fatalf("unexpected error creating test instance, received: %+v", err)
+	}
+
+	if err := is.RemoveInstance("test"); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if _, err := os.Stat(added.ConfigDirectory); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the instance's directory to be deleted, received: %+v", err)
+	}
+}
+
+func TestRemoveInstance_Sad(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		current  string
+		input    string
+		expected error
+	}{
+		"return an error if the instance is the default": {
+			name:     instance.Default,
+			input:    "yes\n",
+			expected: service.ErrInvalidInstanceName,
+		},
+		"return an error if the instance doesn't exist": {
+			name:     "missing",
+			input:    "yes\n",
+			expected: service.ErrInstanceNotFound,
+		},
+		"return an error if the instance is the one being managed": {
+			name:     "test",
+			current:  "test",
+			input:    "yes\n",
+			expected: service.ErrInstanceInUse,
+		},
+		"return an error if user denies removal": {
+			name:     "test",
+			input:    "no\n",
+			expected: service.ErrAborted,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			is := service.NewInstanceService(config.Config{Instance: test.current}, dir, service.NewConfirmer(strings.NewReader(test.input)))
+			added, err := is.AddInstance("test", filepath.Join(dir, "valheim"))
+			if err != nil {
+				t.Fatalf("unexpected error creating test instance, received: %+v", err)
+			}
+
+			if err := is.RemoveInstance(test.name); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if _, err := os.Stat(added.ConfigDirectory); err != nil {
+				t.Errorf("expected the instance's directory to be kept, received: %+v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"warden/internal/config"
+	"warden/internal/domain/server"
+)
+
+const (
+	// Valheim only ships a dedicated server for Linux and Windows
+	linuxServerBinary   = "valheim_server.x86_64"
+	windowsServerBinary = "valheim_server.exe"
+
+	// The server has to be told it's running as the Valheim client's app, not the dedicated server's
+	valheimSteamAppID = "892970"
+
+	// BepInEx is loaded into the server by Doorstop, the same way BepInEx's own start script does it
+	bepInExPreloader = "./BepInEx/core/BepInEx.Preloader.dll"
+	doorstopLibs     = "./doorstop_libs"
+	doorstopLib      = "libdoorstop_x64.so"
+	unstrippedCorlib = "./unstripped_corlib"
+)
+
+// serverSettings returns the arguments the Valheim server is launched with, as configured
+func (s *serverService) serverSettings() server.Settings {
+	return server.Settings{
+		Name:          s.ServerName,
+		Port:          s.ServerPort,
+		World:         s.ServerWorld,
+		Password:      s.ServerPassword,
+		Public:        s.ServerPublic,
+		Crossplay:     s.ServerCrossplay,
+		SaveDirectory: s.SaveDirectory,
+		Preset:        s.ServerPreset,
+		Modifiers:     s.ServerModifiers,
+	}
+}
+
+// serverCommand builds the command that runs the Valheim server binary directly, with the
+// configured settings as arguments. This replaces the start scripts that ship with Valheim and
+// BepInEx, which hard-code them.
+func (s *serverService) serverCommand(gameType string) (*exec.Cmd, error) {
+	settings := s.serverSettings()
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidServerSettings, err)
+	}
+
+	binary, err := serverBinary(s.Platform)
+	if err != nil {
+		return nil, err
+	}
+	binary = filepath.Join(s.ValheimDirectory, binary)
+	if _, err := os.Stat(binary); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+
+	env := append(os.Environ(), "SteamAppId="+valheimSteamAppID)
+	if gameType == modded {
+		modEnv, err := s.bepInExEnv()
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, modEnv...)
+	} else if s.Platform == config.Linux {
+		env = append(env, "LD_LIBRARY_PATH="+joinPath("./linux64", os.Getenv("LD_LIBRARY_PATH")))
+	}
+
+	cmd := exec.Command(binary, settings.Args()...)
+	cmd.Dir = s.ValheimDirectory
+	cmd.Env = env
+	return cmd, nil
+}
+
+// bepInExEnv returns the environment variables that make Doorstop load BepInEx into the
+// server. On Windows, Doorstop is loaded by the server itself, so only the install is checked.
+func (s *serverService) bepInExEnv() ([]string, error) {
+	if _, err := os.Stat(filepath.Join(s.ValheimDirectory, bepInExPreloader)); err != nil {
+		return nil, fmt.Errorf("%w: BepInEx is not installed: %w", ErrServerStartFailed, err)
+	}
+	if s.Platform != config.Linux {
+		return nil, nil
+	}
+	if _, err := os.Stat(filepath.Join(s.ValheimDirectory, doorstopLibs, doorstopLib)); err != nil {
+		return nil, fmt.Errorf("%w: BepInEx is not installed: %w", ErrServerStartFailed, err)
+	}
+
+	return []string{
+		"DOORSTOP_ENABLE=TRUE",
+		"DOORSTOP_INVOKE_DLL_PATH=" + bepInExPreloader,
+		"DOORSTOP_CORLIB_OVERRIDE_PATH=" + unstrippedCorlib,
+		"LD_LIBRARY_PATH=" + joinPath(doorstopLibs, "./linux64", os.Getenv("LD_LIBRARY_PATH")),
+		"LD_PRELOAD=" + joinPath(doorstopLib, os.Getenv("LD_PRELOAD")),
+	}, nil
+}
+
+// serverBinary returns the name of the Valheim server executable on the given platform. Valheim
+// only ships a dedicated server for Linux and Windows, so any other platform, e.g. macOS, fails.
+func serverBinary(platform string) (string, error) {
+	switch platform {
+	case config.Linux:
+		return linuxServerBinary, nil
+	case config.Windows:
+		return windowsServerBinary, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedPlatform, platform)
+	}
+}
+
+// joinPath joins non-empty entries into a colon-separated search path
+func joinPath(entries ...string) string {
+	path := ""
+	for _, e := range entries {
+		if e == "" {
+			continue
+		}
+		if path != "" {
+			path += ":"
+		}
+		path += e
+	}
+	return path
+}
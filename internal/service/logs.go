@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/logs"
+)
+
+var (
+	ErrInvalidLogSource = errors.New("invalid log source")
+	ErrUnableToReadLog  = errors.New("unable to read log")
+)
+
+// Exposes all methods for reading the game server's logs
+type Logs interface {
+	// Calls fn with the last lines of a log that are at least as severe as level, then keeps
+	// calling it with new lines until the context is cancelled if follow is set
+	Tail(ctx context.Context, source string, lines int, follow bool, level logs.Level, fn func(logs.Line)) error
+
+	// Returns every problem in a log, each listed once along with how often it was logged
+	Problems(source string) ([]logs.Problem, error)
+
+	// Removes a log, so that reading it only returns what's logged from now on
+	Reset(source string) error
+
+	IsValidSource(source string) bool
+}
+
+type logService struct {
+	r     repo.Mods
+	paths map[string]string
+}
+
+// NewLogService creates a Logs that reads each source from the given path. Problems are
+// attributed to the mods installed in the repo.
+func NewLogService(mr repo.Mods, paths map[string]string) Logs {
+	return &logService{
+		r:     mr,
+		paths: paths,
+	}
+}
+
+func (ls *logService) IsValidSource(source string) bool {
+	_, ok := ls.paths[source]
+	return ok
+}
+
+func (ls *logService) Tail(ctx context.Context, source string, lines int, follow bool, level logs.Level, fn func(logs.Line)) error {
+	path, ok := ls.paths[source]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidLogSource, source)
+	}
+	plugins := ls.plugins()
+
+	// An exception is held back along with its stack trace until the trace ends, since the
+	// trace is where the method it was thrown from, and so its mod, comes from
+	p := logs.Parser{}
+	pending := []logs.Line{}
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		pending[0].Problem.Mod = plugins.attribute(pending[0].Problem)
+		for _, l := range pending {
+			fn(l)
+		}
+		pending = []logs.Line{}
+	}
+
+	err := file.Tail(ctx, path, lines, follow, func(text string) {
+		l := p.Parse(text)
+		if l.Problem != nil || !p.InStackTrace() {
+			flush()
+		}
+		if l.Level < level {
+			return
+		}
+		if len(pending) > 0 || (l.Problem != nil && l.Problem.Kind == logs.Exception) {
+			pending = append(pending, l)
+			return
+		}
+		if l.Problem != nil {
+			l.Problem.Mod = plugins.attribute(l.Problem)
+		}
+		fn(l)
+	})
+	flush()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToReadLog, err)
+	}
+	return nil
+}
+
+func (ls *logService) Problems(source string) ([]logs.Problem, error) {
+	path, ok := ls.paths[source]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLogSource, source)
+	}
+	plugins := ls.plugins()
+
+	// The stack trace of an exception follows it, so problems are only collected once the whole
+	// log has been parsed
+	p := logs.Parser{}
+	found := []*logs.Problem{}
+	err := file.Tail(context.Background(), path, -1, false, func(text string) {
+		if l := p.Parse(text); l.Problem != nil {
+			found = append(found, l.Problem)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnableToReadLog, err)
+	}
+
+	problems := []logs.Problem{}
+	seen := map[string]int{}
+	for _, f := range found {
+		if i, ok := seen[f.Key()]; ok {
+			problems[i].Count++
+			continue
+		}
+		f.Mod = plugins.attribute(f)
+		f.Count = 1
+		seen[f.Key()] = len(problems)
+		problems = append(problems, *f)
+	}
+	return problems, nil
+}
+
+func (ls *logService) Reset(source string) error {
+	path, ok := ls.paths[source]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidLogSource, source)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrUnableToReadLog, err)
+	}
+	return nil
+}
+
+// A plugin is an installed mod, along with the DLLs it installed
+type plugin struct {
+	name    string
+	modName string
+	dlls    []string
+}
+
+// A pluginIndex is every installed mod. DLLs are only read when a problem has to be searched for
+// in them, and then kept, so a log full of problems doesn't read them over and over.
+type pluginIndex struct {
+	plugins  []plugin
+	contents map[string][]byte
+}
+
+// plugins indexes every installed mod. Logs are still worth reading without the mods, so
+// anything that can't be listed is just left out.
+func (ls *logService) plugins() *pluginIndex {
+	index := &pluginIndex{plugins: []plugin{}, contents: map[string][]byte{}}
+	mods, err := ls.r.ListMods()
+	if err != nil {
+		return index
+	}
+
+	for _, m := range mods {
+		p := plugin{name: m.Namespace + "-" + m.Name, modName: m.Name}
+		filepath.WalkDir(m.FilePath, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".dll") {
+				p.dlls = append(p.dlls, path)
+			}
+			return nil
+		})
+		index.plugins = append(index.plugins, p)
+	}
+	return index
+}
+
+// attribute finds the installed mod a problem comes from. BepInEx logs plugins by their display
+// name and exceptions by namespace, neither of which has to match the mod's package name, so
+// candidates are first matched against mod and DLL names, then searched for inside the DLLs. A
+// search only counts if exactly one mod matches.
+func (pi *pluginIndex) attribute(p *logs.Problem) string {
+	candidates := p.Candidates()
+	for _, c := range candidates {
+		n := normalizePluginName(c)
+		if n == "" {
+			continue
+		}
+		for _, pl := range pi.plugins {
+			if normalizePluginName(pl.modName) == n {
+				return pl.name
+			}
+			for _, d := range pl.dlls {
+				if normalizePluginName(strings.TrimSuffix(filepath.Base(d), filepath.Ext(d))) == n {
+					return pl.name
+				}
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		// Short plugin names match too much by accident
+		if !strings.Contains(c, ".") && len(c) < 4 {
+			continue
+		}
+		matches := []string{}
+		for _, pl := range pi.plugins {
+			if pi.contains(pl, c) && !slices.Contains(matches, pl.name) {
+				matches = append(matches, pl.name)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// contains checks whether any of a plugin's DLLs mention the given name
+func (pi *pluginIndex) contains(pl plugin, name string) bool {
+	for _, d := range pl.dlls {
+		b, ok := pi.contents[d]
+		if !ok {
+			// A DLL that can't be read is left empty, rather than tried again
+			b, _ = os.ReadFile(d)
+			pi.contents[d] = b
+		}
+		if bytes.Contains(b, []byte(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePluginName lowercases a name and drops anything that isn't a letter or digit, so names like
+// "Where You At", "Where_You_At" and "WhereYouAt" all match
+func normalizePluginName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
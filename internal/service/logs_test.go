@@ -0,0 +1,189 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/data/file"
+	"warden/internal/domain/logs"
+	"warden/internal/domain/mod"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const testBepInExLog = `[Info   :   BepInEx] Loading [Where You At 1.0.9]
+[Error  :   BepInEx] Could not load [Sleepover 1.0.1] because it has missing dependencies: com.jotunn.jotunn
+[Error  : Unity Log] NullReferenceException: Object reference not set to an instance of an object
+Stack trace:
+  at UnityEngine.Object.Instantiate (UnityEngine.Object original) [0x00000] in <filename unknown>:0
+  at Azumatt.RaidWarnings.Patch.Postfix (Player __instance) [0x00010] in <filename unknown>:0
+[Error  : Unity Log] NullReferenceException: Object reference not set to an instance of an object
+Stack trace:
+  at Azumatt.RaidWarnings.Patch.Postfix (Player __instance) [0x00010] in <filename unknown>:0
+[Warning:   BepInEx] Skipping [Unknown Thing 2.0.0] because a newer version exists
+`
+
+func TestTailLogs_Happy(t *testing.T) {
+	tests := map[string]struct {
+		lines    int
+		level    logs.Level
+		expected []string
+	}{
+		"return the last lines of the log": {
+			lines: 2,
+			level: logs.Debug,
+			expected: []string{
+				"  at Azumatt.RaidWarnings.Patch.Postfix (Player __instance) [0x00010] in <filename unknown>:0",
+				"[Warning:   BepInEx] Skipping [Unknown Thing 2.0.0] because a newer version exists",
+			},
+		},
+		"return only lines at or above the level": {
+			lines: 4,
+			level: logs.Error,
+			expected: []string{
+				"[Error  : Unity Log] NullReferenceException: Object reference not set to an instance of an object",
+				"Stack trace:",
+				"  at Azumatt.RaidWarnings.Patch.Postfix (Player __instance) [0x00010] in <filename unknown>:0",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ls := newTestLogService(t)
+
+			lines := []string{}
+			err := ls.Tail(context.Background(), logs.BepInEx, test.lines, false, test.level, func(l logs.Line) {
+				lines = append(lines, l.Text)
+			})
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if !slices.Equal(lines, test.expected) {
+				t.Errorf("expected lines: %q, received: %q", test.expected, lines)
+			}
+		})
+	}
+}
+
+func TestTailLogs_Exceptions(t *testing.T) {
+	ls := newTestLogService(t)
+
+	problems := []logs.Problem{}
+	err := ls.Tail(context.Background(), logs.BepInEx, -1, false, logs.Error, func(l logs.Line) {
+		if l.Problem != nil && l.Problem.Kind == logs.Exception {
+			problems = append(problems, *l.Problem)
+		}
+	})
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 exceptions, received: %+v", problems)
+	}
+	for _, p := range problems {
+		if p.Origin != "Azumatt.RaidWarnings.Patch.Postfix" || p.Mod != "Azumatt-Raid_Warnings" {
+			t.Errorf("expected the exception to be matched to its stack trace's mod, received: %+v", p)
+		}
+	}
+}
+
+func TestTailLogs_Sad(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected error
+	}{
+		"return an error if the source is invalid": {
+			source:   "steam",
+			expected: service.ErrInvalidLogSource,
+		},
+		"return an error if the log does not exist": {
+			source:   logs.Server,
+			expected: file.ErrLogNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ls := newTestLogService(t)
+
+			err := ls.Tail(context.Background(), test.source, 10, false, logs.Debug, func(logs.Line) {})
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestProblems_Happy(t *testing.T) {
+	ls := newTestLogService(t)
+
+	problems, err := ls.Problems(logs.BepInEx)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+
+	expected := []logs.Problem{
+		{Kind: logs.MissingDependency, Plugin: "Sleepover", Mod: "Azumatt-Sleepover", Count: 1},
+		{Kind: logs.Exception, Origin: "Azumatt.RaidWarnings.Patch.Postfix", Mod: "Azumatt-Raid_Warnings", Count: 2},
+		{Kind: logs.LoadFailure, Plugin: "Unknown Thing", Count: 1},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, received: %+v", len(expected), problems)
+	}
+	for i, e := range expected {
+		p := problems[i]
+		if p.Kind != e.Kind || p.Plugin != e.Plugin || p.Origin != e.Origin || p.Mod != e.Mod || p.Count != e.Count {
+			t.Errorf("expected problem: %+v, received: %+v", e, p)
+		}
+	}
+}
+
+func TestProblems_Sad(t *testing.T) {
+	ls := newTestLogService(t)
+
+	_, err := ls.Problems("steam")
+	if !errors.Is(err, service.ErrInvalidLogSource) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrInvalidLogSource, err)
+	}
+}
+
+// newTestLogService creates a BepInEx log, along with two installed mods. Sleepover is matched by
+// its name, while Raid_Warnings can only be matched by the namespace inside its DLL.
+func newTestLogService(t *testing.T) service.Logs {
+	dir := t.TempDir()
+	mods := []mod.Mod{
+		{Name: "Sleepover", Namespace: "Azumatt", FilePath: filepath.Join(dir, "Azumatt-Sleepover")},
+		{Name: "Raid_Warnings", Namespace: "Azumatt", FilePath: filepath.Join(dir, "Azumatt-Raid_Warnings")},
+	}
+	dlls := map[string]string{
+		filepath.Join(mods[0].FilePath, "Sleepover.dll"): "MZ Azumatt.Sleepover",
+		filepath.Join(mods[1].FilePath, "RW.dll"):        "MZ Azumatt.RaidWarnings.Patch",
+	}
+	for path, contents := range dlls {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatalf("unexpected error creating test mod, received: %+v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("unexpected error creating test mod, received: %+v", err)
+		}
+	}
+
+	bepInExLog := filepath.Join(dir, "LogOutput.log")
+	if err := os.WriteFile(bepInExLog, []byte(testBepInExLog), 0644); err != nil {
+		t.Fatalf("unexpected error creating test log, received: %+v", err)
+	}
+
+	mr := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return mods, nil
+		},
+	}
+	return service.NewLogService(mr, map[string]string{
+		logs.Server:  filepath.Join(dir, "missing.log"),
+		logs.BepInEx: bepInExLog,
+	})
+}
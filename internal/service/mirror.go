@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/file"
+	"warden/internal/domain/modpack"
+)
+
+var (
+	ErrInvalidMirrorManifest  = errors.New("mirror manifest isn't a valid Thunderstore manifest.json")
+	ErrMirrorManifestNotFound = errors.New("mirror manifest not found")
+	ErrUnableToSyncMirror     = errors.New("unable to sync mirror")
+)
+
+// Exposes all methods for mirroring Thunderstore packages, so hosts that can't reach Thunderstore
+// can install mods from another Warden host instead
+type Mirror interface {
+	// Mirrors every release a Thunderstore manifest.json depends on, and every release those
+	// depend on in turn, by downloading them into the archive cache and indexing them. Returns the
+	// full name of every release that was mirrored, including the ones mirrored before a failure.
+	SyncMirror(manifestPath string) ([]string, error)
+
+	// Returns an HTTP handler that serves the mirrored packages in the shape of Thunderstore's
+	// API, so other hosts can use it as a Thunderstore registry. Download links point to the base
+	// URL, or to the host each request was sent to if it's empty.
+	MirrorHandler(baseURL string) http.Handler
+}
+
+type mirrorService struct {
+	ts thunderstore.Thunderstore
+	fm file.Manager
+	m  file.Mirror
+}
+
+// NewMirrorService creates a Mirror service that mirrors packages from the given Thunderstore
+// registries
+func NewMirrorService(ts thunderstore.Thunderstore, fm file.Manager, m file.Mirror) Mirror {
+	return &mirrorService{
+		ts: ts,
+		fm: fm,
+		m:  m,
+	}
+}
+
+func (mrs *mirrorService) SyncMirror(manifestPath string) ([]string, error) {
+	data, err := os.ReadFile(manifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrMirrorManifestNotFound, manifestPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnableToSyncMirror, err)
+	}
+
+	// Manifests saved by Windows tools often start with a byte order mark
+	manifest := modpack.Manifest{}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMirrorManifest, err)
+	}
+	if len(manifest.Dependencies) == 0 {
+		return nil, fmt.Errorf("%w: it has no dependencies", ErrInvalidMirrorManifest)
+	}
+
+	synced := []string{}
+	seen := map[string]bool{}
+	queue := slices.Clone(manifest.Dependencies)
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+
+		release, err := mrs.syncRelease(dep)
+		if err != nil {
+			return synced, err
+		}
+		synced = append(synced, release.FullName)
+		queue = append(queue, release.Dependencies...)
+	}
+	return synced, nil
+}
+
+// syncRelease mirrors the release a dependency string, e.g. Azumatt-Sleepover-1.0.1, pins
+func (mrs *mirrorService) syncRelease(dep string) (thunderstore.Release, error) {
+	namespace, name, version, err := parseDependency(dep)
+	if err != nil {
+		return thunderstore.Release{}, fmt.Errorf("%w: %w", ErrInvalidMirrorManifest, err)
+	}
+
+	pkg, err := mrs.ts.GetPackage(namespace, name)
+	if err != nil {
+		return thunderstore.Release{}, fmt.Errorf("%w: %s: %w", ErrUnableToSyncMirror, dep, err)
+	}
+	release := pkg.Latest
+	if release.VersionNumber != version {
+		release, err = mrs.ts.GetRelease(namespace, name, version)
+		if err != nil {
+			return thunderstore.Release{}, fmt.Errorf("%w: %s: %w", ErrUnableToSyncMirror, dep, err)
+		}
+	}
+
+	fmt.Fprintf(progress, "... mirroring %s ...\n", release.FullName)
+	if err := mrs.fm.CacheMod(release.DownloadURL, release.FullName); err != nil {
+		return thunderstore.Release{}, fmt.Errorf("%w: %s: %w", ErrUnableToSyncMirror, dep, err)
+	}
+	if err := mrs.m.SaveRelease(pkg, release); err != nil {
+		return thunderstore.Release{}, fmt.Errorf("%w: %s: %w", ErrUnableToSyncMirror, dep, err)
+	}
+	return release, nil
+}
+
+func (mrs *mirrorService) MirrorHandler(baseURL string) http.Handler {
+	h := &mirrorHandler{m: mrs.m, baseURL: strings.TrimSuffix(baseURL, "/")}
+
+	// Thunderstore's own URLs end with a /, but Warden's client leaves it off
+	mux := http.NewServeMux()
+	for _, suffix := range []string{"", "/{$}"} {
+		mux.HandleFunc("GET /api/experimental/package/{namespace}/{name}"+suffix, h.servePackage)
+		mux.HandleFunc("GET /api/experimental/package/{namespace}/{name}/{version}"+suffix, h.serveRelease)
+		mux.HandleFunc("GET /package/download/{namespace}/{name}/{version}"+suffix, h.serveArchive)
+	}
+	return mux
+}
+
+// mirrorHandler serves mirrored packages over HTTP
+type mirrorHandler struct {
+	m       file.Mirror
+	baseURL string
+}
+
+func (h *mirrorHandler) servePackage(w http.ResponseWriter, r *http.Request) {
+	pkg, err := h.m.GetPackage(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		h.serveError(w, err)
+		return
+	}
+	pkg.Latest = h.mirrored(r, pkg.Latest)
+	h.serveJSON(w, pkg)
+}
+
+func (h *mirrorHandler) serveRelease(w http.ResponseWriter, r *http.Request) {
+	release, err := h.m.GetRelease(r.PathValue("namespace"), r.PathValue("name"), r.PathValue("version"))
+	if err != nil {
+		h.serveError(w, err)
+		return
+	}
+	h.serveJSON(w, h.mirrored(r, release))
+}
+
+func (h *mirrorHandler) serveArchive(w http.ResponseWriter, r *http.Request) {
+	path, err := h.m.ArchivePath(r.PathValue("namespace"), r.PathValue("name"), r.PathValue("version"))
+	if err != nil {
+		h.serveError(w, err)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// mirrored points a release's download link at the mirror instead of where it was mirrored from
+func (h *mirrorHandler) mirrored(r *http.Request, release thunderstore.Release) thunderstore.Release {
+	base := h.baseURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	release.DownloadURL = fmt.Sprintf("%s/package/download/%s/%s/%s/", base, release.Namespace, release.Name, release.VersionNumber)
+	return release
+}
+
+func (h *mirrorHandler) serveJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// serveError responds the way Thunderstore does, which is all Warden's client checks
+func (h *mirrorHandler) serveError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, file.ErrMirrorPackageNotFound) {
+		status = http.StatusNotFound
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(thunderstore.ErrorResponse{Detail: http.StatusText(status)})
+}
@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/file"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestSyncMirror_Happy(t *testing.T) {
+	cache := t.TempDir()
+	mrs := newTestMirror(t, cache)
+	manifest := writeTestManifest(t, `{"name": "Server_Pack", "version_number": "1.0.0", "dependencies": ["Azumatt-Sleepover-1.0.1", "Azumatt-AzuClock-1.0.3"]}`)
+
+	synced, err := mrs.SyncMirror(manifest)
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	// Sleepover depends on BepInEx, which is mirrored too
+	expected := []string{"Azumatt-Sleepover-1.0.1", "Azumatt-AzuClock-1.0.3", "denikson-BepInExPack_Valheim-5.4.2202"}
+	if !slices.Equal(synced, expected) {
+		t.Errorf("expected releases: %+v, received: %+v", expected, synced)
+	}
+
+	// Other hosts use the mirror just like Thunderstore
+	server := httptest.NewServer(mrs.MirrorHandler(""))
+	t.Cleanup(server.Close)
+	ts := thunderstore.New(server.Client(), server.URL, "")
+
+	pkg, err := ts.GetPackage("Azumatt", "Sleepover")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if pkg.Latest.VersionNumber != "1.0.1" || pkg.Latest.DownloadURL != server.URL+"/package/download/Azumatt/Sleepover/1.0.1/" {
+		t.Errorf("expected the mirrored release, received: %+v", pkg.Latest)
+	}
+	release, err := ts.GetRelease("Azumatt", "AzuClock", "1.0.3")
+	if err != nil || release.FullName != "Azumatt-AzuClock-1.0.3" {
+		t.Errorf("expected the mirrored release, received: %+v, %+v", release, err)
+	}
+
+	response, err := server.Client().Get(pkg.Latest.DownloadURL)
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	defer response.Body.Close()
+	data, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(data) != "Azumatt-Sleepover-1.0.1" {
+		t.Errorf("expected the mirrored archive, received: %d %q", response.StatusCode, data)
+	}
+
+	if _, err := ts.GetPackage("Azumatt", "Where_You_At"); !errors.Is(err, thunderstore.ErrPackageNotFound) {
+		t.Errorf("expected error: %+v, received: %+v", thunderstore.ErrPackageNotFound, err)
+	}
+}
+
+func TestSyncMirror_Sad(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		synced   []string
+		expected error
+	}{
+		"return an error if the manifest doesn't exist": {
+			manifest: filepath.Join(t.TempDir(), "manifest.json"),
+			expected: service.ErrMirrorManifestNotFound,
+		},
+		"return an error if the manifest isn't JSON": {
+			manifest: writeTestManifest(t, "name: Server_Pack"),
+			expected: service.ErrInvalidMirrorManifest,
+		},
+		"return an error if a dependency has no version": {
+			manifest: writeTestManifest(t, `{"name": "Server_Pack", "dependencies": ["Azumatt-Sleepover"]}`),
+			expected: service.ErrInvalidMirrorManifest,
+		},
+		"return an error if a dependency can't be found": {
+			manifest: writeTestManifest(t, `{"name": "Server_Pack", "dependencies": ["Azumatt-Missing-1.0.0"]}`),
+			expected: service.ErrUnableToSyncMirror,
+		},
+		"return the packages mirrored before a dependency can't be found": {
+			manifest: writeTestManifest(t, `{"name": "Server_Pack", "dependencies": ["Azumatt-AzuClock-1.0.3", "Azumatt-Missing-1.0.0"]}`),
+			synced:   []string{"Azumatt-AzuClock-1.0.3"},
+			expected: service.ErrUnableToSyncMirror,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mrs := newTestMirror(t, t.TempDir())
+
+			synced, err := mrs.SyncMirror(test.manifest)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if len(synced) != len(test.synced) || !slices.Equal(synced, test.synced) {
+				t.Errorf("expected releases: %+v, received: %+v", test.synced, synced)
+			}
+		})
+	}
+}
+
+// newTestMirror mirrors packages from a mock Thunderstore into the given cache directory. Every
+// archive it caches only holds its release's full name.
+func newTestMirror(t *testing.T, cache string) service.Mirror {
+	ts := &mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			if name == "Missing" {
+				return thunderstore.Package{}, thunderstore.ErrPackageNotFound
+			}
+			release := testRelease(namespace, name, "1.0.3")
+			return thunderstore.Package{Namespace: namespace, Name: name, Latest: release}, nil
+		},
+		GetReleaseFunc: func(namespace, name, version string) (thunderstore.Release, error) {
+			release := testRelease(namespace, name, version)
+			if name == "Sleepover" {
+				release.Dependencies = []string{"denikson-BepInExPack_Valheim-5.4.2202"}
+			}
+			return release, nil
+		},
+	}
+	fm := &mock.Manager{
+		CacheModFunc: func(url, fullName string) error {
+			dir := filepath.Join(cache, file.ArchiveDirectory)
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dir, fullName+file.ZipFileExtension), []byte(fullName), 0644)
+		},
+	}
+	return service.NewMirrorService(ts, fm, file.NewMirror(cache))
+}
+
+func writeTestManifest(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("unexpected error writing test manifest, received: %+v", err)
+	}
+	return path
+}
@@ -1,16 +1,16 @@
 package service
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"io"
 	"strings"
-	"warden/internal/api/thunderstore"
+	"warden/internal/api/source"
 	"warden/internal/data/file"
 	"warden/internal/data/repo"
 	"warden/internal/domain/framework"
+	"warden/internal/domain/game"
 	"warden/internal/domain/mod"
+	"warden/internal/domain/plan"
 )
 
 var (
@@ -24,32 +24,56 @@ var (
 	ErrModNotFound         = errors.New("mod not found")
 
 	ErrAddDependenciesFailed = errors.New("unable to install mod's dependencies")
+	ErrInvalidDependency     = errors.New("invalid dependency")
+
+	ErrModInModpack = errors.New("mod was installed by a modpack, update or remove the modpack instead")
 )
 
 // Encapsulates all the business logic for managing mods. It coordinates both the mods
 // database and file management to make sure they're updated together.
 type Mod interface {
 	ListMods() ([]mod.Mod, error)
-	AddMod(namespace, name string) error
+	// Installs a mod from the named source, e.g. "thunderstore". Mods from the "file" and "url"
+	// sources are named by the path or URL of their archive instead.
+	AddMod(src, namespace, name string) error
 	UpdateMod(name string) error
 	UpdateAllMods() error
 	RemoveMod(namespace, name string) error
 	RemoveAllMods() error
+
+	// Returns the installed Valheim build, which mods are checked against for compatibility
+	GameBuild() (game.Build, error)
+
+	PlanAddMod(src, namespace, name string) (plan.Plan, error)
+	PlanUpdateMod(name string) (plan.Plan, error)
+	PlanUpdateAllMods() (plan.Plan, error)
+	PlanRemoveMod(namespace, name string) (plan.Plan, error)
+	PlanRemoveAllMods() (plan.Plan, error)
 }
 
 type modService struct {
-	r  repo.Mods
-	fm file.Manager
-	ts thunderstore.Thunderstore
-	in *bufio.Scanner
+	r       repo.Mods
+	fm      file.Manager
+	sources source.Sources
+	c       Confirmer
 }
 
-func NewModService(r repo.Mods, fm file.Manager, ts thunderstore.Thunderstore, reader io.Reader) Mod {
+// NewModService creates a Mod service that installs mods from the given sources. Mods can always
+// be added straight from an archive too.
+func NewModService(r repo.Mods, fm file.Manager, sources source.Sources, c Confirmer) Mod {
+	all := source.Sources{
+		source.File: newArchiveSource(fm, source.File),
+		source.URL:  newArchiveSource(fm, source.URL),
+	}
+	for name, src := range sources {
+		all[name] = src
+	}
+
 	return &modService{
-		r:  r,
-		fm: fm,
-		ts: ts,
-		in: bufio.NewScanner(reader),
+		r:       r,
+		fm:      fm,
+		sources: all,
+		c:       c,
 	}
 }
 
@@ -57,38 +81,57 @@ func (ms *modService) ListMods() ([]mod.Mod, error) {
 	return ms.r.ListMods()
 }
 
-func (ms *modService) AddMod(namespace, name string) error {
+func (ms *modService) GameBuild() (game.Build, error) {
+	return ms.fm.GameBuild()
+}
+
+func (ms *modService) AddMod(sourceName, namespace, name string) error {
 	// Check if the mod is already installed
-	current, err := ms.r.GetMod(name)
+	if err := ms.checkNotInstalled(name); err != nil {
+		return err
+	}
 
-	if err == nil && !current.Equals(&mod.Mod{}) {
-		// Mod already installed
-		return ErrModAlreadyInstalled
+	// Find the requested mod online
+	src, err := ms.sources.Get(sourceName)
+	if err != nil {
+		return err
 	}
-	if err != nil && !errors.Is(err, repo.ErrModFetchNoResults) {
-		// If repo fetch returns any error BESIDES no results,
-		// return mod install failure
-		return ErrModInstallFailed
+	pkg, err := src.GetPackage(namespace, name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+	// An archive's mod is only named once its manifest is read
+	if pkg.Name != name {
+		if err := ms.checkNotInstalled(pkg.Name); err != nil {
+			return err
+		}
 	}
 
-	// Find the requested mod online
-	pkg, err := ms.ts.GetPackage(namespace, name)
+	// A modpack only pins other mods, so those are installed instead of it
+	modpack, err := ms.isModpack(pkg.Latest)
 	if err != nil {
-		return ErrModNotFound
+		return fmt.Errorf("%w: %w", ErrModInstallFailed, err)
+	}
+	if modpack {
+		err = ms.installModpack(pkg)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrModInstallFailed, err)
+		}
+		return nil
 	}
 
 	// Install the mod and it's dependencies
-	err = ms.installMod(pkg.Latest)
+	err = ms.installMod(pkg)
 	if err != nil {
-		return ErrModInstallFailed
+		return fmt.Errorf("%w: %w", ErrModInstallFailed, err)
 	}
 
 	if len(pkg.Latest.Dependencies) > 0 {
-		fmt.Printf("... mod has %d dependencies, installing them ...\n", len(pkg.Latest.Dependencies))
+		fmt.Fprintf(progress, "... mod has %d dependencies, installing them ...\n", len(pkg.Latest.Dependencies))
 
 		err = ms.addDependencies(pkg.Latest.Dependencies)
 		if err != nil {
-			return ErrAddDependenciesFailed
+			return fmt.Errorf("%w: %w", ErrAddDependenciesFailed, err)
 		}
 	}
 	return nil
@@ -98,177 +141,184 @@ func (ms *modService) UpdateMod(name string) error {
 	// Find the current installation of the mod
 	current, err := ms.r.GetMod(name)
 	if err != nil && errors.Is(err, repo.ErrModFetchNoResults) {
-		return ErrModNotInstalled
+		return fmt.Errorf("%w: %w", ErrModNotInstalled, err)
 	}
 	if err != nil {
-		return ErrUnableToUpdateMod
+		return fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+	}
+	if current.Parent != "" {
+		return fmt.Errorf("%w: %s", ErrModInModpack, current.Parent)
+	}
+	if current.Source == source.File {
+		skipFileMod(current)
+		return nil
 	}
 
 	// Fetch the latest version from online
-	pkg, err := ms.ts.GetPackage(current.Namespace, current.Name)
+	pkg, err := ms.getPackage(current)
 	if err != nil {
-		return ErrModNotFound
-	}
-
-	if current.Version < pkg.Latest.VersionNumber {
-		fmt.Printf("... found a new version (%s) of %s %s ...\n", pkg.Latest.VersionNumber, current.Namespace, current.Name)
-		fmt.Printf("did you want to update this mod? %s\n", yesOrNo)
-
-		tries := 0
-		for ms.in.Scan() && tries < 2 {
-			if ms.in.Text() == yes {
-				err = ms.updateMod(current.FullName(), pkg.Latest)
-				if err != nil {
-					return ErrUnableToUpdateMod
-				}
-				err = ms.addDependencies(pkg.Latest.Dependencies)
-				if err != nil {
-					return ErrAddDependenciesFailed
-				}
-				return nil
-			} else if ms.in.Text() == no {
-				fmt.Println("... aborting ...")
-				return nil
-			} else {
-				tries++
-			}
-		}
-		if tries >= 2 {
-			return ErrMaxAttempts
+		return fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+
+	build := ms.gameBuild()
+	if current.Version >= pkg.Latest.Version {
+		fmt.Fprintf(progress, "... latest version of %s %s already installed (%s) ...\n", current.Namespace, current.Name, current.Version)
+		warnIncompatible(current, pkg, build)
+		if err := ms.refreshMetadata(current, pkg); err != nil {
+			return fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
 		}
-	} else {
-		fmt.Printf("... latest version of %s %s already installed (%s) ...\n", current.Namespace, current.Name, current.Version)
+		return nil
 	}
-	return nil
+	fmt.Fprintf(progress, "... found a new version (%s) of %s %s ...\n", pkg.Latest.Version, current.Namespace, current.Name)
+	warnIncompatible(current, pkg, build)
+
+	ok, err := ms.c.Confirm("did you want to update this mod?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+	return ms.updateRelease(current, pkg)
 }
 
 func (ms *modService) UpdateAllMods() error {
-	fmt.Printf("are you sure you wanted to update ALL mods? %s\n", yesOrNo)
-
-	tries := 0
-	for ms.in.Scan() && tries < 2 {
-		if ms.in.Text() == yes {
-			// Get all installed mods
-			mods, err := ms.r.ListMods()
-			if err != nil {
-				return ErrUnableToListMods
-			}
+	ok, err := ms.c.Confirm("are you sure you wanted to update ALL mods?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	// Get all installed mods
+	mods, err := ms.r.ListMods()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToListMods, err)
+	}
+
+	// For each one, check if there's an update and install it if there is
+	build := ms.gameBuild()
+	for _, m := range mods {
+		// Mods in a modpack are updated along with it
+		if m.Parent != "" {
+			continue
+		}
+		if m.Source == source.File {
+			skipFileMod(m)
+			continue
+		}
 
-			// For each one, check if there's an update and install it if there is
-			for _, m := range mods {
-				pkg, err := ms.ts.GetPackage(m.Namespace, m.Name)
-				if err != nil {
-					return ErrModNotFound
-				}
-
-				if m.Version < pkg.Latest.VersionNumber {
-					err = ms.updateMod(m.FullName(), pkg.Latest)
-					if err != nil {
-						return ErrUnableToUpdateMod
-					}
-					err = ms.addDependencies(pkg.Latest.Dependencies)
-					if err != nil {
-						return ErrAddDependenciesFailed
-					}
-				} else {
-					fmt.Printf("... latest version of %s %s already installed (%s) ...\n", m.Namespace, m.Name, m.Version)
-				}
+		pkg, err := ms.getPackage(m)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrModNotFound, err)
+		}
+		warnIncompatible(m, pkg, build)
+
+		if m.Version < pkg.Latest.Version {
+			if err := ms.updateRelease(m, pkg); err != nil {
+				return err
 			}
-			return nil
-		} else if ms.in.Text() == no {
-			fmt.Println("... aborting ...")
-			return nil
 		} else {
-			tries++
+			fmt.Fprintf(progress, "... latest version of %s %s already installed (%s) ...\n", m.Namespace, m.Name, m.Version)
+			if err := ms.refreshMetadata(m, pkg); err != nil {
+				return fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+			}
 		}
 	}
-	if tries >= 2 {
-		return ErrMaxAttempts
-	}
 	return nil
 }
 
 func (ms *modService) RemoveMod(namespace, name string) error {
-	fmt.Printf("are you sure you want to remove this mod? %s\n", yesOrNo)
-
-	tries := 0
-	for ms.in.Scan() && tries < 2 {
-		if ms.in.Text() == yes {
-			// Find the current installation of the mod
-			current, err := ms.r.GetMod(name)
-			if err != nil && errors.Is(err, repo.ErrModFetchNoResults) {
-				return ErrModNotInstalled
-			}
-			if err != nil {
-				return ErrUnableToRemoveMod
-			}
-
-			// Remove mod record
-			err = ms.r.DeleteMod(name, namespace)
-			if err != nil {
-				return ErrUnableToRemoveMod
-			}
+	ok, err := ms.c.Confirm("are you sure you want to remove this mod?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
 
-			// Remove mod files
-			err = ms.fm.RemoveMod(current.FullName())
-			if err != nil {
-				return ErrUnableToRemoveMod
-			}
-			return nil
-		} else if ms.in.Text() == no {
-			fmt.Println("... aborting ...")
-			return nil
-		} else {
-			tries++
+	// Find the current installation of the mod
+	current, err := ms.r.GetMod(name)
+	if err != nil && errors.Is(err, repo.ErrModFetchNoResults) {
+		return fmt.Errorf("%w: %w", ErrModNotInstalled, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
+	}
+	if current.Parent != "" {
+		return fmt.Errorf("%w: %s", ErrModInModpack, current.Parent)
+	}
+	if current.Modpack {
+		err = ms.removeModpack(current)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
 		}
+		return nil
 	}
-	if tries >= 2 {
-		return ErrMaxAttempts
+
+	// Remove mod record
+	err = ms.r.DeleteMod(name, namespace)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
+	}
+
+	// Remove mod files
+	err = ms.fm.RemoveMod(current.FullName())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
 	}
 	return nil
 }
 
 func (ms *modService) RemoveAllMods() error {
-	fmt.Printf("are you sure you want to remove ALL mods? %s\n", yesOrNoLong)
+	ok, err := ms.c.Confirm("are you sure you want to remove ALL mods?", true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
 
-	tries := 0
-	for ms.in.Scan() && tries < 2 {
-		if ms.in.Text() == yesLong {
-			errRepo := ms.r.DeleteAllMods()
-			errFile := ms.fm.RemoveAllMods()
+	errRepo := ms.r.DeleteAllMods()
+	errFile := ms.fm.RemoveAllMods()
 
-			if errRepo != nil || errFile != nil {
-				return ErrUnableToRemoveMod
-			}
-			return nil
-		} else if ms.in.Text() == noLong {
-			fmt.Println("... aborting ...")
-			return nil
-		} else {
-			tries++
-		}
-	}
-	if tries >= 2 {
-		return ErrMaxAttempts
+	if errRepo != nil || errFile != nil {
+		return ErrUnableToRemoveMod
 	}
 	return nil
 }
 
+// addDependencies installs the latest release of every dependency. Dependencies are always
+// Thunderstore packages.
 func (ms *modService) addDependencies(dependencies []string) error {
+	ts, err := ms.sources.Get(source.Thunderstore)
+	if err != nil {
+		return err
+	}
+
 	for _, dep := range dependencies {
-		details := strings.Split(dep, "-")
-		namespace, name := details[0], details[1]
+		namespace, name, _, err := parseDependency(dep)
+		if err != nil {
+			return err
+		}
 
 		// If dep is BepInEx, skip
 		if name == framework.BepInEx {
 			continue
 		}
 
-		pkg, err := ms.ts.GetPackage(namespace, name)
+		// Mods installed by a modpack stay at the version it pins
+		if current, err := ms.r.GetMod(name); err == nil && current.Parent != "" {
+			fmt.Fprintf(progress, "... %s %s is pinned at %s by %s ...\n", current.Namespace, current.Name, current.Version, current.Parent)
+			continue
+		}
+
+		pkg, err := ts.GetPackage(namespace, name)
 		if err != nil {
 			return err
 		}
-		err = ms.installMod(pkg.Latest)
+		err = ms.installMod(pkg)
 		if err != nil {
 			return err
 		}
@@ -276,19 +326,56 @@ func (ms *modService) addDependencies(dependencies []string) error {
 	return nil
 }
 
-func (ms *modService) updateMod(fullname string, latest thunderstore.Release) error {
+// updateRelease updates an installed mod to the latest release of its package. A modpack is
+// reinstalled instead, which updates every mod it pins.
+func (ms *modService) updateRelease(current mod.Mod, pkg source.Package) error {
+	if current.Modpack {
+		err := ms.installModpack(pkg)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+		}
+		return nil
+	}
+
+	err := ms.updateMod(current.FullName(), pkg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+	}
+	err = ms.addDependencies(pkg.Latest.Dependencies)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAddDependenciesFailed, err)
+	}
+	return nil
+}
+
+func (ms *modService) updateMod(fullname string, pkg source.Package) error {
 	// Delete the previous mod files
 	err := ms.fm.RemoveMod(fullname)
 	if err != nil {
 		return err
 	}
 	// Install the newest version and update DB record
-	return ms.installMod(latest)
+	return ms.installMod(pkg)
 }
 
-func (ms *modService) installMod(release thunderstore.Release) error {
+func (ms *modService) installMod(pkg source.Package) error {
+	return ms.installRelease(pkg, pkg.Latest, "")
+}
+
+// installRelease installs a specific release of a package. Mods installed by a modpack are given
+// the modpack's package name as their parent.
+func (ms *modService) installRelease(pkg source.Package, release source.Release, parent string) error {
+	src, err := ms.sources.Get(pkg.Source)
+	if err != nil {
+		return err
+	}
+	url, err := src.DownloadURL(release)
+	if err != nil {
+		return err
+	}
+
 	// Download and install the mod files
-	path, err := ms.fm.InstallMod(release.DownloadURL, release.FullName)
+	path, err := ms.fm.InstallMod(url, release.FullName)
 	if err != nil {
 		ms.r.DeleteMod(release.Name, release.Namespace)
 		return err
@@ -299,10 +386,337 @@ func (ms *modService) installMod(release thunderstore.Release) error {
 		Name:         release.Name,
 		Namespace:    release.Namespace,
 		FilePath:     path,
-		Version:      release.VersionNumber,
+		Version:      release.Version,
 		WebsiteURL:   release.WebsiteURL,
 		Description:  release.Description,
 		Dependencies: release.Dependencies,
+		GameBuild:    ms.gameBuild().ID,
+		UpdatedAt:    pkg.UpdatedAt,
+		Categories:   pkg.Categories,
+		Deprecated:   pkg.Deprecated,
+		Parent:       parent,
+		Source:       pkg.Source,
+		Location:     archiveLocation(pkg, release),
 	}
 	return ms.r.UpsertMod(m)
 }
+
+// isModpack checks if a release is a modpack: it depends on other mods, but has no plugins of its
+// own. Checking for plugins downloads the release into the archive cache, so releases without any
+// mod dependencies are never downloaded.
+func (ms *modService) isModpack(release source.Release) (bool, error) {
+	if len(modpackMods(release)) == 0 {
+		return false, nil
+	}
+	hasPlugins, err := ms.fm.HasPlugins(release.DownloadURL, release.FullName)
+	if err != nil {
+		return false, err
+	}
+	return !hasPlugins, nil
+}
+
+// installModpack installs every mod in the latest release of a modpack at the version it pins,
+// and removes the mods an older release pinned that it no longer does. Mods that are already
+// installed some other way are left as they are. The modpack itself has no files, and is only
+// recorded so it can be updated and removed.
+func (ms *modService) installModpack(pkg source.Package) error {
+	release := pkg.Latest
+	pack := mod.Mod{Namespace: release.Namespace, Name: release.Name}
+	parent := pack.PackageName()
+
+	mods := modpackMods(release)
+	fmt.Fprintf(progress, "... %s is a modpack of %d mods, installing them ...\n", parent, len(mods))
+
+	// Modpacks are Thunderstore packages, so their mods are too
+	ts, err := ms.sources.Get(source.Thunderstore)
+	if err != nil {
+		return err
+	}
+	installed, err := ms.r.ListMods()
+	if err != nil {
+		return err
+	}
+
+	// Every release that's changing is downloaded before any files are touched, so a release
+	// that's gone doesn't leave the modpack half installed
+	pinned := map[string]bool{}
+	changes := []modpackChange{}
+	for _, m := range mods {
+		pinned[m.PackageName()] = true
+
+		current, err := ms.r.GetMod(m.Name)
+		if err != nil && !errors.Is(err, repo.ErrModFetchNoResults) {
+			return err
+		}
+		change := modpackChange{}
+		if err == nil {
+			if current.Version == m.Version && current.Parent == parent {
+				fmt.Fprintf(progress, "... %s %s is already installed (%s) ...\n", m.Namespace, m.Name, m.Version)
+				continue
+			}
+			// Mods added on their own, or by another modpack, would otherwise be removed along with
+			// this one
+			if current.Parent != parent {
+				fmt.Fprintf(progress, "... %s %s (%s) wasn't installed by %s, leaving it as it is ...\n", m.Namespace, m.Name, current.Version, parent)
+				continue
+			}
+			// The installed version is cached too, so it can be put back if the modpack fails
+			if err := ms.cacheInstalled(current); err != nil {
+				return err
+			}
+			change.current = &current
+		}
+
+		change.pkg, err = ts.GetPackage(m.Namespace, m.Name)
+		if err != nil {
+			return err
+		}
+		change.release, err = ts.GetRelease(m.Namespace, m.Name, m.Version)
+		if err != nil {
+			return err
+		}
+		url, err := ts.DownloadURL(change.release)
+		if err != nil {
+			return err
+		}
+		if err := ms.fm.CacheMod(url, change.release.FullName); err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+
+	// If any mod fails, the ones that are new to the server are removed again, since they can't
+	// be removed on their own, and the versions that were replaced are put back from the cache
+	added := []mod.Mod{}
+	replaced := []modpackChange{}
+	for _, change := range changes {
+		if change.current != nil {
+			replaced = append(replaced, change)
+			if err := ms.fm.RemoveMod(change.current.FullName()); err != nil {
+				ms.rollBackModpack(added, replaced)
+				return err
+			}
+		}
+		fmt.Fprintf(progress, "... installing %s %s (%s) ...\n", change.release.Namespace, change.release.Name, change.release.Version)
+		if err := ms.installRelease(change.pkg, change.release, parent); err != nil {
+			ms.rollBackModpack(added, replaced)
+			return err
+		}
+		if change.current == nil {
+			added = append(added, mod.Mod{Namespace: change.release.Namespace, Name: change.release.Name, Version: change.release.Version})
+		}
+	}
+
+	for _, m := range installed {
+		if m.Parent == parent && !pinned[m.PackageName()] {
+			fmt.Fprintf(progress, "... %s %s is no longer in the modpack, removing it ...\n", m.Namespace, m.Name)
+			if err := ms.removeInstalledMod(m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return ms.r.UpsertMod(mod.Mod{
+		Name:         release.Name,
+		Namespace:    release.Namespace,
+		Version:      release.Version,
+		WebsiteURL:   release.WebsiteURL,
+		Description:  release.Description,
+		Dependencies: release.Dependencies,
+		GameBuild:    ms.gameBuild().ID,
+		UpdatedAt:    pkg.UpdatedAt,
+		Categories:   pkg.Categories,
+		Deprecated:   pkg.Deprecated,
+		Modpack:      true,
+		Source:       pkg.Source,
+		Location:     archiveLocation(pkg, release),
+	})
+}
+
+// A modpackChange is a mod a modpack pins that has to be installed, along with the version of it
+// that's already installed, if there is one
+type modpackChange struct {
+	pkg     source.Package
+	release source.Release
+	current *mod.Mod
+}
+
+// rollBackModpack removes the mods a modpack installed before it failed, and reinstalls the
+// versions it replaced from the archive cache
+func (ms *modService) rollBackModpack(added []mod.Mod, replaced []modpackChange) {
+	for _, m := range added {
+		fmt.Fprintf(progress, "... removing %s %s ...\n", m.Namespace, m.Name)
+		ms.removeInstalledMod(m)
+	}
+	for _, change := range replaced {
+		m := *change.current
+		fmt.Fprintf(progress, "... restoring %s %s (%s) ...\n", m.Namespace, m.Name, m.Version)
+		ms.fm.RemoveMod(change.release.FullName)
+		path, err := ms.fm.InstallMod(m.Location, m.FullName())
+		if err != nil {
+			continue
+		}
+		m.FilePath = path
+		ms.r.UpsertMod(m)
+	}
+}
+
+// cacheInstalled makes sure an installed mod's release is in the archive cache. Mods added
+// straight from an archive are fetched from where they were added from, and every other mod from
+// the source it was installed from.
+func (ms *modService) cacheInstalled(m mod.Mod) error {
+	if ms.fm.IsCached(m.FullName()) {
+		return nil
+	}
+	url := m.Location
+	if url == "" {
+		src, err := ms.sources.Get(m.Source)
+		if err != nil {
+			return err
+		}
+		release, err := src.GetRelease(m.Namespace, m.Name, m.Version)
+		if err != nil {
+			return err
+		}
+		if url, err = src.DownloadURL(release); err != nil {
+			return err
+		}
+	}
+	return ms.fm.CacheMod(url, m.FullName())
+}
+
+// removeModpack removes a modpack along with every mod it installed
+func (ms *modService) removeModpack(pack mod.Mod) error {
+	mods, err := ms.r.ListMods()
+	if err != nil {
+		return err
+	}
+	for _, m := range mods {
+		if m.Parent == pack.PackageName() {
+			if err := ms.removeInstalledMod(m); err != nil {
+				return err
+			}
+		}
+	}
+	return ms.r.DeleteMod(pack.Name, pack.Namespace)
+}
+
+func (ms *modService) removeInstalledMod(m mod.Mod) error {
+	if err := ms.r.DeleteMod(m.Name, m.Namespace); err != nil {
+		return err
+	}
+	return ms.fm.RemoveMod(m.FullName())
+}
+
+// modpackMods returns the mods a modpack release pins, leaving out BepInEx, which is managed
+// separately. Dependencies are "namespace-name-version", and none of the parts can contain a -.
+func modpackMods(release source.Release) []mod.Mod {
+	mods := []mod.Mod{}
+	for _, dep := range release.Dependencies {
+		namespace, name, version, err := parseDependency(dep)
+		if err != nil || name == framework.BepInEx {
+			continue
+		}
+		mods = append(mods, mod.Mod{Namespace: namespace, Name: name, Version: version})
+	}
+	return mods
+}
+
+// parseDependency splits a Thunderstore dependency string, e.g. "Azumatt-Sleepover-1.0.1", into
+// its namespace, name and version. None of the parts can contain a -.
+func parseDependency(dep string) (namespace, name, version string, err error) {
+	details := strings.Split(dep, "-")
+	if len(details) != 3 {
+		return "", "", "", fmt.Errorf("%w: %q", ErrInvalidDependency, dep)
+	}
+	return details[0], details[1], details[2], nil
+}
+
+// gameBuild returns the installed Valheim build, or an unknown build if it can't be read, e.g. the
+// server wasn't installed through Steam. Mods are never warned about against an unknown build.
+func (ms *modService) gameBuild() game.Build {
+	build, err := ms.fm.GameBuild()
+	if err != nil {
+		return game.Build{}
+	}
+	return build
+}
+
+// warnIncompatible prints every reason the latest release of a mod might not work with the
+// installed game build. The mod's recorded build is kept, since the release hasn't been installed yet.
+func warnIncompatible(current mod.Mod, pkg source.Package, build game.Build) {
+	latest := mod.Mod{
+		GameBuild:  current.GameBuild,
+		UpdatedAt:  pkg.UpdatedAt,
+		Deprecated: pkg.Deprecated,
+	}
+	for _, w := range latest.Warnings(build) {
+		fmt.Fprintf(progress, "... WARNING: %s %s is %s ...\n", current.Namespace, current.Name, w)
+	}
+}
+
+// refreshMetadata records what's changed about a mod's package since it was installed, for a mod
+// that's already at the latest release. Categories and deprecation can change without a new
+// release, so they'd otherwise stay as they were when the mod was installed.
+func (ms *modService) refreshMetadata(current mod.Mod, pkg source.Package) error {
+	m := current
+	m.UpdatedAt = pkg.UpdatedAt
+	m.Categories = pkg.Categories
+	m.Deprecated = pkg.Deprecated
+	if m.Equals(&current) {
+		return nil
+	}
+	return ms.r.UpdateMod(m)
+}
+
+// getPackage looks up an installed mod's package, from the source it was installed from
+func (ms *modService) getPackage(m mod.Mod) (source.Package, error) {
+	src, err := ms.sources.Get(m.Source)
+	if err != nil {
+		return source.Package{}, err
+	}
+	if m.Location != "" {
+		return src.GetPackage(m.Namespace, m.Location)
+	}
+	return src.GetPackage(m.Namespace, m.Name)
+}
+
+// archiveLocation is where a release added straight from an archive was fetched from, so it can be
+// fetched from the same place again to check for updates. Other sources look mods up by name.
+func archiveLocation(pkg source.Package, release source.Release) string {
+	if pkg.Source == source.File || pkg.Source == source.URL {
+		return release.DownloadURL
+	}
+	return ""
+}
+
+// checkNotInstalled checks that no mod with the given name is installed yet
+func (ms *modService) checkNotInstalled(name string) error {
+	current, err := ms.r.GetMod(name)
+	if err == nil && !current.Equals(&mod.Mod{}) {
+		return ErrModAlreadyInstalled
+	}
+	// If repo fetch returns any error BESIDES no results, return mod install failure
+	if err != nil && !errors.Is(err, repo.ErrModFetchNoResults) {
+		return fmt.Errorf("%w: %w", ErrModInstallFailed, err)
+	}
+	return nil
+}
+
+// skipFileMod explains why a mod added from a local file isn't updated. There's nowhere to check
+// for a new version, and the file might not even be there anymore.
+func skipFileMod(m mod.Mod) {
+	fmt.Fprintf(progress, "... %s %s was added from a file, remove it and add the new file to update it ...\n", m.Namespace, m.Name)
+}
+
+// thunderstoreMods returns the mods installed from Thunderstore, leaving out any from other
+// sources. Modpacks and r2modman profiles can only list Thunderstore packages.
+func thunderstoreMods(mods []mod.Mod) []mod.Mod {
+	installed := []mod.Mod{}
+	for _, m := range mods {
+		if m.Source == "" || m.Source == source.Thunderstore {
+			installed = append(installed, m)
+		}
+	}
+	return installed
+}
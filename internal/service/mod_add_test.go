@@ -1,13 +1,24 @@
 package service_test
 
 import (
+	"archive/zip"
+	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+	"warden/internal/api/source"
 	"warden/internal/api/thunderstore"
 	"warden/internal/data/file"
 	"warden/internal/data/repo"
+	"warden/internal/domain/game"
 	"warden/internal/domain/mod"
+	"warden/internal/domain/modpack"
 	"warden/internal/service"
 	"warden/internal/test/mock"
 )
@@ -22,6 +33,9 @@ func TestAddMod_Happy(t *testing.T) {
 		},
 	}
 	fm := mock.Manager{
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, file.ErrGameBuildNotFound
+		},
 		InstallModFunc: func(url, fullName string) (string, error) {
 			return "/some/test/path", nil
 		},
@@ -55,9 +69,9 @@ func TestAddMod_Happy(t *testing.T) {
 					}, nil
 				},
 			}
-			ms := service.NewModService(&r, &fm, &ts, &io.LimitedReader{})
+			ms := service.NewModService(&r, &fm, thunderstoreSources(&ts), service.NewConfirmer(&io.LimitedReader{}))
 
-			err := ms.AddMod("Azumatt", "Sleepover")
+			err := ms.AddMod(source.Thunderstore, "Azumatt", "Sleepover")
 			if err != nil {
 				t.Errorf("expected a nil error, received: %+v", err)
 			}
@@ -65,6 +79,60 @@ func TestAddMod_Happy(t *testing.T) {
 	}
 }
 
+func TestAddMod_Happy_RecordsCompatibility(t *testing.T) {
+	var recorded mod.Mod
+	r := mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			return mod.Mod{}, repo.ErrModFetchNoResults
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			recorded = m
+			return nil
+		},
+	}
+	fm := mock.Manager{
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{ID: "13385436"}, nil
+		},
+		InstallModFunc: func(url, fullName string) (string, error) {
+			return "/some/test/path", nil
+		},
+	}
+	ts := mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			return thunderstore.Package{
+				DateUpdated:  "2024-02-03T18:30:00.000000Z",
+				IsDeprecated: true,
+				Latest:       thunderstore.Release{Namespace: namespace, Name: name},
+				CommunityListings: []thunderstore.Listing{
+					{Community: "lethal-company", Categories: []string{"Suits"}},
+					{Community: thunderstore.Community, Categories: []string{"Server-side", "Tweaks"}},
+				},
+			}, nil
+		},
+	}
+	ms := service.NewModService(&r, &fm, thunderstoreSources(&ts), service.NewConfirmer(&io.LimitedReader{}))
+
+	err := ms.AddMod(source.Thunderstore, "Azumatt", "Sleepover")
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+
+	expected := mod.Mod{
+		Name:       "Sleepover",
+		Namespace:  "Azumatt",
+		FilePath:   "/some/test/path",
+		GameBuild:  "13385436",
+		UpdatedAt:  time.Date(2024, 2, 3, 18, 30, 0, 0, time.UTC),
+		Categories: []string{"Server-side", "Tweaks"},
+		Deprecated: true,
+		Source:     source.Thunderstore,
+	}
+	if !recorded.Equals(&expected) {
+		t.Errorf("expected mod to be recorded as: %+v, received: %+v", expected, recorded)
+	}
+}
+
 func TestAddMod_Sad(t *testing.T) {
 	attempts := 0
 
@@ -131,6 +199,9 @@ func TestAddMod_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				InstallModFunc: func(url, fullName string) (string, error) {
 					return "", file.ErrFileWriteFailed
 				},
@@ -161,6 +232,9 @@ func TestAddMod_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				InstallModFunc: func(url, fullName string) (string, error) {
 					return "/some/file/path", nil
 				},
@@ -196,9 +270,15 @@ func TestAddMod_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				InstallModFunc: func(url, fullName string) (string, error) {
 					return "/some/file/path", nil
 				},
+				HasPluginsFunc: func(url, fullName string) (bool, error) {
+					return true, nil
+				},
 			},
 			expected: service.ErrAddDependenciesFailed,
 		},
@@ -206,9 +286,9 @@ func TestAddMod_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(test.r, test.fm, test.ts, &io.LimitedReader{})
+			ms := service.NewModService(test.r, test.fm, thunderstoreSources(test.ts), service.NewConfirmer(&io.LimitedReader{}))
 
-			err := ms.AddMod("Azumatt", "Sleepover")
+			err := ms.AddMod(source.Thunderstore, "Azumatt", "Sleepover")
 			if err == nil {
 				t.Errorf("expected a nil error, received: %+v", err)
 			}
@@ -218,3 +298,242 @@ func TestAddMod_Sad(t *testing.T) {
 		})
 	}
 }
+
+func TestAddMod_Nexus(t *testing.T) {
+	var recorded mod.Mod
+	r := &mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			if recorded.Name == name {
+				return recorded, nil
+			}
+			return mod.Mod{}, repo.ErrModFetchNoResults
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			recorded = m
+			return nil
+		},
+	}
+	var downloaded string
+	fm := &mock.Manager{
+		InstallModFunc: func(url, fullName string) (string, error) {
+			downloaded = url
+			return "/plugins/" + fullName, nil
+		},
+		RemoveModFunc: func(fullName string) error {
+			return nil
+		},
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, file.ErrGameBuildNotFound
+		},
+	}
+	version := "0.9.8"
+	nexus := &mock.Source{
+		GetPackageFunc: func(namespace, name string) (source.Package, error) {
+			return source.Package{
+				Source:    source.Nexus,
+				Namespace: "Grantapher",
+				Name:      name,
+				Latest: source.Release{
+					Namespace: "Grantapher",
+					Name:      name,
+					Version:   version,
+					FullName:  "Grantapher-" + name + "-" + version,
+					ID:        version,
+				},
+			}, nil
+		},
+		DownloadURLFunc: func(release source.Release) (string, error) {
+			return "https://cdn.example.com/" + release.ID + ".zip", nil
+		},
+	}
+	// Thunderstore is never used, so any request to it panics
+	sources := source.Sources{source.Thunderstore: thunderstore.NewSource(&mock.Thunderstore{}), source.Nexus: nexus}
+	ms := service.NewModService(r, fm, sources, service.NewConfirmer(strings.NewReader("Y\n")))
+
+	if err := ms.AddMod(source.Nexus, "", "4"); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if recorded.Source != source.Nexus || recorded.Version != "0.9.8" || downloaded != "https://cdn.example.com/0.9.8.zip" {
+		t.Errorf("expected the mod to be installed from Nexus Mods, received: %+v from %s", recorded, downloaded)
+	}
+
+	// Updates come from the same source the mod was installed from
+	version = "0.9.9"
+	if err := ms.UpdateMod("4"); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if recorded.Version != "0.9.9" || downloaded != "https://cdn.example.com/0.9.9.zip" {
+		t.Errorf("expected the mod to be updated from Nexus Mods, received: %+v from %s", recorded, downloaded)
+	}
+}
+
+func TestAddMod_Archive(t *testing.T) {
+	tests := map[string]struct {
+		source          string
+		location        string
+		expectedVersion string
+		expectedFetches int
+	}{
+		"skip updates for a mod added from a file": {
+			source:          source.File,
+			location:        "/home/valheim/In_House.zip",
+			expectedVersion: "1.0.0",
+			expectedFetches: 1,
+		},
+		"fetch a mod added from a URL again to update it": {
+			source:          source.URL,
+			location:        "https://example.com/In_House.zip",
+			expectedVersion: "1.1.0",
+			expectedFetches: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var recorded mod.Mod
+			r := &mock.ModsRepo{
+				GetModFunc: func(name string) (mod.Mod, error) {
+					if recorded.Name == name {
+						return recorded, nil
+					}
+					return mod.Mod{}, repo.ErrModFetchNoResults
+				},
+				UpsertModFunc: func(m mod.Mod) error {
+					recorded = m
+					return nil
+				},
+			}
+			version := "1.0.0"
+			fetched := []string{}
+			fm := &mock.Manager{
+				CachePackageFunc: func(location, namespace string) (modpack.Manifest, error) {
+					fetched = append(fetched, location)
+					return modpack.Manifest{Name: "In_House", VersionNumber: version, Dependencies: []string{}}, nil
+				},
+				InstallModFunc: func(url, fullName string) (string, error) {
+					return "/plugins/" + fullName, nil
+				},
+				RemoveModFunc: func(fullName string) error {
+					return nil
+				},
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
+			}
+			// Archives are never looked up online, so any request to Thunderstore panics
+			ms := service.NewModService(r, fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(strings.NewReader("Y\n")))
+
+			if err := ms.AddMod(test.source, "", test.location); err != nil {
+				t.Fatalf("expected a nil error, received: %+v", err)
+			}
+			if recorded.Name != "In_House" || recorded.Namespace != "Local" || recorded.Source != test.source || recorded.Location != test.location {
+				t.Errorf("expected the mod to be recorded from its manifest, received: %+v", recorded)
+			}
+
+			version = "1.1.0"
+			if err := ms.UpdateMod("In_House"); err != nil {
+				t.Fatalf("expected a nil error, received: %+v", err)
+			}
+			if recorded.Version != test.expectedVersion || len(fetched) != test.expectedFetches {
+				t.Errorf("expected version %s after %d fetches, received: %s after %+v", test.expectedVersion, test.expectedFetches, recorded.Version, fetched)
+			}
+		})
+	}
+}
+
+func TestAddMod_Archive_Sad(t *testing.T) {
+	tests := map[string]struct {
+		namespace string
+		installed mod.Mod
+		expected  error
+	}{
+		"return an error if the archive's mod is already installed": {
+			installed: mod.Mod{Namespace: "Local", Name: "In_House", Version: "1.0.0"},
+			expected:  service.ErrModAlreadyInstalled,
+		},
+		"return an error if the namespace can't be used": {
+			namespace: "../plugins",
+			expected:  service.ErrInvalidNamespace,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				GetModFunc: func(name string) (mod.Mod, error) {
+					if test.installed.Name == name {
+						return test.installed, nil
+					}
+					return mod.Mod{}, repo.ErrModFetchNoResults
+				},
+			}
+			fm := &mock.Manager{
+				CachePackageFunc: func(location, namespace string) (modpack.Manifest, error) {
+					return modpack.Manifest{Name: "In_House", VersionNumber: "1.0.0", Dependencies: []string{}}, nil
+				},
+			}
+			ms := service.NewModService(r, fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(&io.LimitedReader{}))
+
+			err := ms.AddMod(source.File, test.namespace, "/home/valheim/In_House.zip")
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+// thunderstoreSources lets tests install mods from a mock Thunderstore
+func thunderstoreSources(ts thunderstore.Thunderstore) source.Sources {
+	return source.Sources{source.Thunderstore: thunderstore.NewSource(ts)}
+}
+
+func TestAddMod_PrivateRegistry(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The registry's archives are behind the same auth as its API
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/experimental/package/Azumatt/Sleepover":
+			release := testRelease("Azumatt", "Sleepover", "1.0.1")
+			release.DownloadURL = server.URL + "/package/download/Azumatt/Sleepover/1.0.1/"
+			json.NewEncoder(w).Encode(thunderstore.Package{Namespace: "Azumatt", Name: "Sleepover", Latest: release})
+		case "/package/download/Azumatt/Sleepover/1.0.1/":
+			archive := zip.NewWriter(w)
+			f, _ := archive.Create("plugins/Sleepover.dll")
+			f.Write([]byte("plugin"))
+			archive.Close()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var recorded mod.Mod
+	r := mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			return mod.Mod{}, repo.ErrModFetchNoResults
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			recorded = m
+			return nil
+		},
+		DeleteModFunc: func(modName, namespace string) error {
+			return nil
+		},
+	}
+	dir := t.TempDir()
+	client := thunderstore.NewTokenClient(server.Client(), map[string]string{server.URL: "secret"})
+	fm := file.NewManager(client, dir, filepath.Join(dir, "BepInEx", "plugins"), filepath.Join(dir, "cache"))
+	ts := thunderstore.New(server.Client(), server.URL, "secret")
+	ms := service.NewModService(&r, fm, thunderstoreSources(ts), service.NewConfirmer(&io.LimitedReader{}))
+
+	if err := ms.AddMod(source.Thunderstore, "Azumatt", "Sleepover"); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if _, err := os.Stat(filepath.Join(recorded.FilePath, "plugins", "Sleepover.dll")); err != nil {
+		t.Errorf("expected the mod to be downloaded from the registry, received: %+v", err)
+	}
+}
@@ -39,7 +39,7 @@ func TestListMods_Happy(t *testing.T) {
 			return expected, nil
 		},
 	}
-	ms := service.NewModService(&r, &mock.Manager{}, &mock.Thunderstore{}, &io.LimitedReader{})
+	ms := service.NewModService(&r, &mock.Manager{}, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(&io.LimitedReader{}))
 
 	results, err := ms.ListMods()
 	if err != nil {
@@ -60,7 +60,7 @@ func TestListMods_Sad(t *testing.T) {
 			return []mod.Mod{}, repo.ErrModListFailed
 		},
 	}
-	ms := service.NewModService(&r, &mock.Manager{}, &mock.Thunderstore{}, &io.LimitedReader{})
+	ms := service.NewModService(&r, &mock.Manager{}, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(&io.LimitedReader{}))
 
 	results, err := ms.ListMods()
 	if err == nil {
@@ -0,0 +1,383 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"warden/internal/api/source"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/repo"
+	"warden/internal/domain/game"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/plan"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const testModpack = "Someone-Server_Pack"
+
+func TestAddModpack_Happy(t *testing.T) {
+	r, installed := newModpackRepo()
+	fm, downloads, _ := newModpackManager()
+	ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(&io.LimitedReader{}))
+
+	err := ms.AddMod(source.Thunderstore, "Someone", "Server_Pack")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	pack := installed["Server_Pack"]
+	if !pack.Modpack || pack.Version != "1.1.0" || pack.FilePath != "" {
+		t.Errorf("expected the modpack to be recorded without files, received: %+v", pack)
+	}
+	for name, version := range map[string]string{"Sleepover": "1.0.1", "AzuClock": "1.0.0"} {
+		m := installed[name]
+		if m.Version != version || m.Parent != testModpack {
+			t.Errorf("expected %s %s to be installed by %s, received: %+v", name, version, testModpack, m)
+		}
+		url := testDownloadURL("Azumatt", name, version)
+		if !slices.Contains(*downloads, url) {
+			t.Errorf("expected %s to be downloaded, received: %+v", url, *downloads)
+		}
+	}
+	if len(*downloads) != 2 {
+		t.Errorf("expected only the modpack's mods to be downloaded, received: %+v", *downloads)
+	}
+}
+
+func TestUpdateModpack_Happy(t *testing.T) {
+	r, installed := newModpackRepo(
+		mod.Mod{Namespace: "Someone", Name: "Server_Pack", Version: "1.0.0", Modpack: true},
+		mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0", Parent: testModpack},
+		mod.Mod{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.0", Parent: testModpack},
+		mod.Mod{Namespace: "Azumatt", Name: "Where_You_At", Version: "1.0.9", Parent: testModpack},
+	)
+	fm, downloads, removed := newModpackManager()
+	ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(strings.NewReader("Y\n")))
+
+	err := ms.UpdateMod("Server_Pack")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	if installed["Server_Pack"].Version != "1.1.0" {
+		t.Errorf("expected the modpack to be updated, received: %+v", installed["Server_Pack"])
+	}
+	if installed["Sleepover"].Version != "1.0.1" || installed["Sleepover"].Parent != testModpack {
+		t.Errorf("expected Sleepover to be updated to the pinned version, received: %+v", installed["Sleepover"])
+	}
+	if _, ok := installed["Where_You_At"]; ok {
+		t.Error("expected a mod the modpack no longer pins to be removed")
+	}
+	if !slices.Contains(*removed, "Azumatt-Where_You_At-1.0.9") || !slices.Contains(*removed, "Azumatt-Sleepover-1.0.0") {
+		t.Errorf("expected old mod files to be removed, received: %+v", *removed)
+	}
+	if len(*downloads) != 1 {
+		t.Errorf("expected only the changed mod to be downloaded, received: %+v", *downloads)
+	}
+}
+
+func TestAddModpack_LeavesOtherMods(t *testing.T) {
+	r, installed := newModpackRepo(
+		mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0"},
+		mod.Mod{Namespace: "Someone", Name: "Other_Pack", Version: "1.0.0", Modpack: true},
+		mod.Mod{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.3", Parent: "Someone-Other_Pack"},
+	)
+	fm, downloads, removed := newModpackManager()
+	ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(&io.LimitedReader{}))
+
+	err := ms.AddMod(source.Thunderstore, "Someone", "Server_Pack")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	if m := installed["Sleepover"]; m.Version != "1.0.0" || m.Parent != "" {
+		t.Errorf("expected a mod added on its own to be left alone, received: %+v", m)
+	}
+	if m := installed["AzuClock"]; m.Version != "1.0.3" || m.Parent != "Someone-Other_Pack" {
+		t.Errorf("expected a mod from another modpack to be left alone, received: %+v", m)
+	}
+	if !installed["Server_Pack"].Modpack || len(*downloads) != 0 || len(*removed) != 0 {
+		t.Errorf("expected only the modpack to be recorded, received: %+v, %+v", *downloads, *removed)
+	}
+}
+
+func TestRemoveModpack_Happy(t *testing.T) {
+	r, installed := newModpackRepo(
+		mod.Mod{Namespace: "Someone", Name: "Server_Pack", Version: "1.1.0", Modpack: true},
+		mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1", Parent: testModpack},
+		mod.Mod{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.0", Parent: testModpack},
+		mod.Mod{Namespace: "Azumatt", Name: "Where_You_At", Version: "1.0.9"},
+	)
+	fm, _, removed := newModpackManager()
+	ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(strings.NewReader("Y\n")))
+
+	err := ms.RemoveMod("Someone", "Server_Pack")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	if len(installed) != 1 || installed["Where_You_At"].Name == "" {
+		t.Errorf("expected only mods outside the modpack to be left, received: %+v", installed)
+	}
+	if len(*removed) != 2 {
+		t.Errorf("expected the modpack's mod files to be removed, received: %+v", *removed)
+	}
+}
+
+func TestModInModpack_Sad(t *testing.T) {
+	tests := map[string]func(ms service.Mod) error{
+		"return an error if updating a mod in a modpack": func(ms service.Mod) error {
+			return ms.UpdateMod("Sleepover")
+		},
+		"return an error if removing a mod in a modpack": func(ms service.Mod) error {
+			return ms.RemoveMod("Azumatt", "Sleepover")
+		},
+		"return an error if planning to update a mod in a modpack": func(ms service.Mod) error {
+			_, err := ms.PlanUpdateMod("Sleepover")
+			return err
+		},
+		"return an error if planning to remove a mod in a modpack": func(ms service.Mod) error {
+			_, err := ms.PlanRemoveMod("Azumatt", "Sleepover")
+			return err
+		},
+	}
+
+	for name, run := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, _ := newModpackRepo(
+				mod.Mod{Namespace: "Someone", Name: "Server_Pack", Version: "1.1.0", Modpack: true},
+				mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1", Parent: testModpack},
+			)
+			fm, _, _ := newModpackManager()
+			ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(strings.NewReader("Y\n")))
+
+			err := run(ms)
+			if !errors.Is(err, service.ErrModInModpack) {
+				t.Errorf("expected error: %+v, received: %+v", service.ErrModInModpack, err)
+			}
+		})
+	}
+}
+
+func TestAddModpack_Sad(t *testing.T) {
+	tests := map[string]struct {
+		installed []mod.Mod
+		fail      func(fm *mock.Manager)
+		expected  map[string]string
+		restored  map[string]string
+	}{
+		"remove the mods already installed if another one can't be installed": {
+			fail: func(fm *mock.Manager) {
+				install := fm.InstallModFunc
+				fm.InstallModFunc = func(url, fullName string) (string, error) {
+					if strings.Contains(fullName, "AzuClock") {
+						return "", errors.New("unzip failed")
+					}
+					return install(url, fullName)
+				}
+			},
+			expected: map[string]string{},
+		},
+		"leave the old version installed if the pinned one can't be downloaded": {
+			installed: []mod.Mod{
+				{Namespace: "Someone", Name: "Server_Pack", Version: "1.0.0", Modpack: true},
+				{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0", Parent: testModpack},
+			},
+			fail: func(fm *mock.Manager) {
+				fm.CacheModFunc = func(url, fullName string) error {
+					if strings.Contains(fullName, "AzuClock") {
+						return errors.New("download failed")
+					}
+					return nil
+				}
+			},
+			expected: map[string]string{"Server_Pack": "1.0.0", "Sleepover": "1.0.0"},
+		},
+		"restore the replaced versions if another mod can't be installed": {
+			installed: []mod.Mod{
+				{Namespace: "Someone", Name: "Server_Pack", Version: "1.0.0", Modpack: true},
+				{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0", Parent: testModpack},
+				{Namespace: "Azumatt", Name: "AzuClock", Version: "0.9.0", Parent: testModpack},
+			},
+			fail: func(fm *mock.Manager) {
+				install := fm.InstallModFunc
+				fm.InstallModFunc = func(url, fullName string) (string, error) {
+					if fullName == "Azumatt-AzuClock-1.0.0" {
+						return "", errors.New("unzip failed")
+					}
+					return install(url, fullName)
+				}
+			},
+			expected: map[string]string{"Server_Pack": "1.0.0", "Sleepover": "1.0.0", "AzuClock": "0.9.0"},
+			restored: map[string]string{"Sleepover": "Azumatt-Sleepover-1.0.0", "AzuClock": "Azumatt-AzuClock-0.9.0"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, installed := newModpackRepo(test.installed...)
+			fm, _, removed := newModpackManager()
+			test.fail(fm)
+			ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(strings.NewReader("Y\n")))
+
+			var err error
+			if len(test.installed) == 0 {
+				err = ms.AddMod(source.Thunderstore, "Someone", "Server_Pack")
+			} else {
+				err = ms.UpdateMod("Server_Pack")
+			}
+			if err == nil {
+				t.Fatal("expected an error, received nil")
+			}
+
+			versions := map[string]string{}
+			for name, m := range installed {
+				versions[name] = m.Version
+			}
+			if !maps.Equal(versions, test.expected) {
+				t.Errorf("expected installed mods: %+v, received: %+v", test.expected, versions)
+			}
+			if len(test.installed) > 0 && len(test.restored) == 0 && len(*removed) != 0 {
+				t.Errorf("expected no mod files to be removed, received: %+v", *removed)
+			}
+			for name, fullName := range test.restored {
+				if m := installed[name]; m.FilePath != "/plugins/"+fullName {
+					t.Errorf("expected %s to be reinstalled, received: %+v", fullName, m)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanAddModpack_Happy(t *testing.T) {
+	r, installed := newModpackRepo(mod.Mod{Namespace: "Azumatt", Name: "AzuClock", Version: "0.9.0"})
+	fm, downloads, _ := newModpackManager()
+	ms := service.NewModService(r, fm, thunderstoreSources(newModpackThunderstore()), service.NewConfirmer(&io.LimitedReader{}))
+
+	p, err := ms.PlanAddMod(source.Thunderstore, "Someone", "Server_Pack")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+
+	// AzuClock was added on its own, so the modpack leaves it alone
+	expected := []plan.Step{
+		{Action: plan.Install, Name: "Sleepover", ToVersion: "1.0.1", Dependency: true},
+		{Action: plan.Install, Name: "Server_Pack", ToVersion: "1.1.0"},
+	}
+	if len(p.Steps) != len(expected) {
+		t.Fatalf("expected %d steps, received: %+v", len(expected), p.Steps)
+	}
+	for i, e := range expected {
+		s := p.Steps[i]
+		if s.Action != e.Action || s.Name != e.Name || s.FromVersion != e.FromVersion || s.ToVersion != e.ToVersion || s.Dependency != e.Dependency {
+			t.Errorf("expected step: %+v, received: %+v", e, s)
+		}
+	}
+	if len(*downloads) != 0 || len(installed) != 1 {
+		t.Errorf("expected nothing to be installed, received: %+v", installed)
+	}
+}
+
+// newModpackRepo keeps mods in memory, keyed by name, so tests can check what a modpack changed
+func newModpackRepo(mods ...mod.Mod) (*mock.ModsRepo, map[string]mod.Mod) {
+	installed := map[string]mod.Mod{}
+	for _, m := range mods {
+		installed[m.Name] = m
+	}
+
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			list := []mod.Mod{}
+			for _, m := range installed {
+				list = append(list, m)
+			}
+			return list, nil
+		},
+		GetModFunc: func(name string) (mod.Mod, error) {
+			m, ok := installed[name]
+			if !ok {
+				return mod.Mod{}, repo.ErrModFetchNoResults
+			}
+			return m, nil
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			installed[m.Name] = m
+			return nil
+		},
+		DeleteModFunc: func(modName, namespace string) error {
+			delete(installed, modName)
+			return nil
+		},
+	}
+	return r, installed
+}
+
+// newModpackManager records every download and removal. Only the modpack has no plugins.
+func newModpackManager() (*mock.Manager, *[]string, *[]string) {
+	downloads, removed := []string{}, []string{}
+	fm := &mock.Manager{
+		HasPluginsFunc: func(url, fullName string) (bool, error) {
+			return !strings.HasPrefix(fullName, testModpack), nil
+		},
+		CacheModFunc: func(url, fullName string) error {
+			return nil
+		},
+		IsCachedFunc: func(fullName string) bool {
+			return false
+		},
+		InstallModFunc: func(url, fullName string) (string, error) {
+			downloads = append(downloads, url)
+			return "/plugins/" + fullName, nil
+		},
+		RemoveModFunc: func(fullName string) error {
+			removed = append(removed, fullName)
+			return nil
+		},
+		ModPathFunc: func(fullName string) string {
+			return "/plugins/" + fullName
+		},
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, nil
+		},
+	}
+	return fm, &downloads, &removed
+}
+
+// newModpackThunderstore serves a modpack that pins two mods, both older than their latest release
+func newModpackThunderstore() *mock.Thunderstore {
+	return &mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			release := testRelease(namespace, name, "1.0.3")
+			if name == "Server_Pack" {
+				release = testRelease(namespace, name, "1.1.0")
+				release.Dependencies = []string{"denikson-BepInExPack_Valheim-5.4.2202", "Azumatt-Sleepover-1.0.1", "Azumatt-AzuClock-1.0.0"}
+			}
+			return thunderstore.Package{Namespace: namespace, Name: name, Latest: release}, nil
+		},
+		GetReleaseFunc: func(namespace, name, version string) (thunderstore.Release, error) {
+			return testRelease(namespace, name, version), nil
+		},
+		GetDownloadSizeFunc: func(url string) (int64, error) {
+			return 0, thunderstore.ErrUnknownDownloadSize
+		},
+	}
+}
+
+func testRelease(namespace, name, version string) thunderstore.Release {
+	return thunderstore.Release{
+		Namespace:     namespace,
+		Name:          name,
+		VersionNumber: version,
+		FullName:      namespace + "-" + name + "-" + version,
+		DownloadURL:   testDownloadURL(namespace, name, version),
+	}
+}
+
+func testDownloadURL(namespace, name, version string) string {
+	return fmt.Sprintf("https://thunderstore.io/package/download/%s/%s/%s/", namespace, name, version)
+}
@@ -38,14 +38,11 @@ func TestRemoveMod_Happy(t *testing.T) {
 		"if user confirms delete, remove the mod and return success": {
 			rd: strings.NewReader("Y"),
 		},
-		"if user denies delete, cancel mod removal and return success": {
-			rd: strings.NewReader("n"),
-		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(r, fm, &mock.Thunderstore{}, test.rd)
+			ms := service.NewModService(r, fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(test.rd))
 
 			err := ms.RemoveMod("Azumatt", "Sleepover")
 			if err != nil {
@@ -68,6 +65,10 @@ func TestRemoveMod_Sad(t *testing.T) {
 			rd:       strings.NewReader("TEST\nRANDOM\nINPUTS\nTEST\n"),
 			expected: service.ErrMaxAttempts,
 		},
+		"return an error if user denies delete": {
+			rd:       strings.NewReader("n"),
+			expected: service.ErrAborted,
+		},
 		"return error if mod isn't installed": {
 			r: &mock.ModsRepo{
 				GetModFunc: func(name string) (mod.Mod, error) {
@@ -127,7 +128,7 @@ func TestRemoveMod_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(test.r, test.fm, test.ts, test.rd)
+			ms := service.NewModService(test.r, test.fm, thunderstoreSources(test.ts), service.NewConfirmer(test.rd))
 
 			err := ms.RemoveMod("Azumatt", "Sleepover")
 			if err == nil {
@@ -158,14 +159,11 @@ func TestRemoveAllMods_Happy(t *testing.T) {
 		"if user confirms delete, remove all mods and return success": {
 			rd: strings.NewReader("YES I AM"),
 		},
-		"if user denies delete, cancel mod removal and return success": {
-			rd: strings.NewReader("no"),
-		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(r, fm, &mock.Thunderstore{}, test.rd)
+			ms := service.NewModService(r, fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(test.rd))
 
 			err := ms.RemoveAllMods()
 			if err != nil {
@@ -186,6 +184,10 @@ func TestRemoveAllMods_Sad(t *testing.T) {
 			rd:       strings.NewReader("I'M\nTESTING\nRANDOM\nINPUTS\n"),
 			expected: service.ErrMaxAttempts,
 		},
+		"return error if user denies delete": {
+			rd:       strings.NewReader("no"),
+			expected: service.ErrAborted,
+		},
 		"return error if unable to remove mod records": {
 			r: &mock.ModsRepo{
 				DeleteAllModsFunc: func() error {
@@ -218,7 +220,7 @@ func TestRemoveAllMods_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(test.r, test.fm, &mock.Thunderstore{}, test.rd)
+			ms := service.NewModService(test.r, test.fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(test.rd))
 
 			err := ms.RemoveAllMods()
 			if err == nil {
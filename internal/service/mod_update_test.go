@@ -8,6 +8,7 @@ import (
 	"warden/internal/api/thunderstore"
 	"warden/internal/data/file"
 	"warden/internal/data/repo"
+	"warden/internal/domain/game"
 	"warden/internal/domain/mod"
 	"warden/internal/service"
 	"warden/internal/test/mock"
@@ -79,6 +80,9 @@ func TestUpdateMod_Happy(t *testing.T) {
 				},
 			}
 			fm := mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				RemoveModFunc: func(fullName string) error {
 					return nil
 				},
@@ -94,7 +98,7 @@ func TestUpdateMod_Happy(t *testing.T) {
 				},
 			}
 			rd := strings.NewReader("Y")
-			ms := service.NewModService(&r, &fm, &ts, rd)
+			ms := service.NewModService(&r, &fm, thunderstoreSources(&ts), service.NewConfirmer(rd))
 
 			err := ms.UpdateMod("Sleepover")
 			if err != nil {
@@ -104,6 +108,47 @@ func TestUpdateMod_Happy(t *testing.T) {
 	}
 }
 
+func TestUpdateMod_RefreshesMetadata(t *testing.T) {
+	current := mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1", Categories: []string{"Mods"}}
+	updated := []mod.Mod{}
+	r := mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			return current, nil
+		},
+		UpdateModFunc: func(m mod.Mod) error {
+			updated = append(updated, m)
+			return nil
+		},
+	}
+	fm := mock.Manager{
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, file.ErrGameBuildNotFound
+		},
+	}
+	ts := mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			return thunderstore.Package{
+				DateUpdated:       "2024-02-03T18:30:00Z",
+				IsDeprecated:      true,
+				Latest:            thunderstore.Release{Namespace: namespace, Name: name, VersionNumber: "1.0.1"},
+				CommunityListings: []thunderstore.Listing{{Community: thunderstore.Community, Categories: []string{"Mods", "Tweaks"}}},
+			}, nil
+		},
+	}
+	ms := service.NewModService(&r, &fm, thunderstoreSources(&ts), service.NewConfirmer(strings.NewReader("Y")))
+
+	if err := ms.UpdateMod("Sleepover"); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if len(updated) != 1 {
+		t.Fatalf("expected the mod's record to be refreshed once, received: %+v", updated)
+	}
+	m := updated[0]
+	if !m.Deprecated || m.UpdatedAt.IsZero() || len(m.Categories) != 2 || m.Version != "1.0.1" {
+		t.Errorf("expected the package's latest details at the same version, received: %+v", m)
+	}
+}
+
 func TestUpdateMod_Sad(t *testing.T) {
 	namespace := "Azumatt"
 	modName := "Sleepover"
@@ -175,6 +220,11 @@ func TestUpdateMod_Sad(t *testing.T) {
 					}, nil
 				},
 			},
+			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
+			},
 			rd:       strings.NewReader("TEST\nTEST\nTEST\nTEST\n"),
 			expected: service.ErrMaxAttempts,
 		},
@@ -194,6 +244,9 @@ func TestUpdateMod_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				RemoveModFunc: func(fullName string) error {
 					return file.ErrModDeleteFailed
 				},
@@ -225,6 +278,9 @@ func TestUpdateMod_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				RemoveModFunc: func(fullName string) error {
 					return nil
 				},
@@ -239,7 +295,7 @@ func TestUpdateMod_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(test.r, test.fm, test.ts, test.rd)
+			ms := service.NewModService(test.r, test.fm, thunderstoreSources(test.ts), service.NewConfirmer(test.rd))
 
 			err := ms.UpdateMod(modName)
 			if err == nil {
@@ -262,6 +318,9 @@ func TestUpdateAllMods_Happy(t *testing.T) {
 	depFullName := depNamespace + "-" + depName + "-" + depVersion
 
 	fm := &mock.Manager{
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, file.ErrGameBuildNotFound
+		},
 		RemoveModFunc: func(fullName string) error {
 			return nil
 		},
@@ -326,9 +385,6 @@ func TestUpdateAllMods_Happy(t *testing.T) {
 				VersionNumber: depVersion,
 			},
 		},
-		"return successful if user aborts update all": {
-			rd: strings.NewReader("n"),
-		},
 	}
 
 	for name, test := range tests {
@@ -357,7 +413,7 @@ func TestUpdateAllMods_Happy(t *testing.T) {
 					}, nil
 				},
 			}
-			ms := service.NewModService(r, fm, ts, test.rd)
+			ms := service.NewModService(r, fm, thunderstoreSources(ts), service.NewConfirmer(test.rd))
 
 			err := ms.UpdateAllMods()
 			if err != nil {
@@ -379,6 +435,10 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 			rd:       strings.NewReader("TEST\nTEST\nTEST\nTEST\n"),
 			expected: service.ErrMaxAttempts,
 		},
+		"return error if user aborts update all": {
+			rd:       strings.NewReader("n"),
+			expected: service.ErrAborted,
+		},
 		"return error if unable to fetch list of installed mods": {
 			r: &mock.ModsRepo{
 				ListModsFunc: func() ([]mod.Mod, error) {
@@ -406,6 +466,11 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 					return thunderstore.Package{}, thunderstore.ErrPackageNotFound
 				},
 			},
+			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
+			},
 			rd:       strings.NewReader("Y"),
 			expected: service.ErrModNotFound,
 		},
@@ -437,6 +502,9 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				RemoveModFunc: func(fullName string) error {
 					return file.ErrModDeleteFailed
 				},
@@ -456,6 +524,9 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 						},
 					}, nil
 				},
+				GetModFunc: func(name string) (mod.Mod, error) {
+					return mod.Mod{}, repo.ErrModFetchNoResults
+				},
 				UpsertModFunc: func(m mod.Mod) error {
 					return nil
 				},
@@ -478,6 +549,9 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 				},
 			},
 			fm: &mock.Manager{
+				GameBuildFunc: func() (game.Build, error) {
+					return game.Build{}, file.ErrGameBuildNotFound
+				},
 				RemoveModFunc: func(fullName string) error {
 					return nil
 				},
@@ -492,7 +566,7 @@ func TestUpdateAllMods_Sad(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			ms := service.NewModService(test.r, test.fm, test.ts, test.rd)
+			ms := service.NewModService(test.r, test.fm, thunderstoreSources(test.ts), service.NewConfirmer(test.rd))
 
 			err := ms.UpdateAllMods()
 			if err == nil {
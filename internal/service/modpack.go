@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/modpack"
+)
+
+var (
+	ErrInvalidModpack       = errors.New("invalid modpack")
+	ErrModpackFileNotFound  = errors.New("modpack icon or README not found")
+	ErrUnableToBuildModpack = errors.New("unable to build modpack")
+)
+
+// Exposes all methods for distributing the server's mods as a Thunderstore modpack
+type Modpacks interface {
+	// Builds a Thunderstore package that depends on every installed mod, including BepInEx, at
+	// its installed version, so players get exactly what the server runs. The package is checked
+	// against Thunderstore's rules, so it can be uploaded as is. Returns the package's manifest
+	// and where it was written.
+	BuildModpack(o modpack.Options) (modpack.Manifest, string, error)
+}
+
+type modpackService struct {
+	r  repo.Mods
+	fr repo.Frameworks
+}
+
+func NewModpackService(r repo.Mods, fr repo.Frameworks) Modpacks {
+	return &modpackService{
+		r:  r,
+		fr: fr,
+	}
+}
+
+func (mps *modpackService) BuildModpack(o modpack.Options) (modpack.Manifest, string, error) {
+	installed, err := mps.r.ListMods()
+	if err != nil {
+		return modpack.Manifest{}, "", fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+	}
+	// A modpack that's installed is already made up of the mods it came with
+	mods := []mod.Mod{}
+	for _, m := range thunderstoreMods(installed) {
+		if !m.Modpack {
+			mods = append(mods, m)
+		}
+	}
+	bepinex, err := mps.fr.GetFramework(framework.BepInEx)
+	if err == nil {
+		mods = append(mods, mod.Mod{Namespace: bepinex.Namespace, Name: bepinex.Name, Version: bepinex.Version})
+	} else if !errors.Is(err, repo.ErrFrameworkFetchNoResults) {
+		return modpack.Manifest{}, "", fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+	}
+
+	m := modpack.NewManifest(o.Name, o.Version, o.Description, o.WebsiteURL, mods)
+	if err := m.Validate(); err != nil {
+		return m, "", fmt.Errorf("%w: %w", ErrInvalidModpack, err)
+	}
+
+	var icon []byte
+	if o.Icon == "" {
+		if icon, err = modpack.DefaultIcon(); err != nil {
+			return m, "", fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+		}
+	} else if icon, err = readModpackFile(o.Icon); err != nil {
+		return m, "", err
+	}
+	if err := modpack.ValidateIcon(icon); err != nil {
+		return m, "", fmt.Errorf("%w: %w", ErrInvalidModpack, err)
+	}
+
+	readme := []byte(m.Readme())
+	if o.Readme != "" {
+		if readme, err = readModpackFile(o.Readme); err != nil {
+			return m, "", err
+		}
+	}
+	if err := modpack.ValidateReadme(readme); err != nil {
+		return m, "", fmt.Errorf("%w: %w", ErrInvalidModpack, err)
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return m, "", fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+	}
+
+	path := o.Path
+	if path == "" {
+		path = fmt.Sprintf("%s-%s%s", m.Name, m.VersionNumber, file.ZipFileExtension)
+	}
+	err = file.WriteZip(path, []file.ZipEntry{
+		{Name: modpack.ManifestFileName, Data: manifest},
+		{Name: modpack.IconFileName, Data: icon},
+		{Name: modpack.ReadmeFileName, Data: readme},
+	})
+	if err != nil {
+		return m, "", fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+	}
+	return m, path, nil
+}
+
+// readModpackFile reads an icon or README given for a modpack
+func readModpackFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrModpackFileNotFound, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnableToBuildModpack, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/modpack"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestBuildModpack_Happy(t *testing.T) {
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}}, nil
+		},
+	}
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{Namespace: framework.BepInExNamespace, Name: framework.BepInEx, Version: "5.4.2202"}, nil
+		},
+	}
+	path := filepath.Join(t.TempDir(), "modpack.zip")
+
+	mps := service.NewModpackService(r, fr)
+	m, written, err := mps.BuildModpack(modpack.Options{Name: "Server_Modpack", Version: "1.0.0", Path: path})
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if written != path {
+		t.Errorf("expected the modpack to be written to: %s, received: %s", path, written)
+	}
+	expected := []string{"Azumatt-Sleepover-1.0.1", "denikson-BepInExPack_Valheim-5.4.2202"}
+	if !slices.Equal(m.Dependencies, expected) {
+		t.Errorf("expected dependencies: %v, received: %v", expected, m.Dependencies)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("expected the modpack to be a zip archive, received: %+v", err)
+	}
+	defer archive.Close()
+	names := []string{}
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	if !slices.Equal(names, []string{modpack.ManifestFileName, modpack.IconFileName, modpack.ReadmeFileName}) {
+		t.Errorf("expected the manifest, icon and README in the modpack, received: %v", names)
+	}
+
+	f, err := archive.Open(modpack.ManifestFileName)
+	if err != nil {
+		t.Fatalf("unexpected error opening manifest, received: %+v", err)
+	}
+	defer f.Close()
+	var manifest modpack.Manifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil || manifest.Name != "Server_Modpack" {
+		t.Errorf("expected a manifest for the modpack, received: %+v, %+v", manifest, err)
+	}
+}
+
+func TestBuildModpack_Sad(t *testing.T) {
+	installed := []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}}
+	dir := t.TempDir()
+	notPNG := filepath.Join(dir, "icon.png")
+	if err := os.WriteFile(notPNG, []byte("GIF89a"), 0644); err != nil {
+		t.Fatalf("unexpected error creating test icon, received: %+v", err)
+	}
+
+	tests := map[string]struct {
+		mods     []mod.Mod
+		options  modpack.Options
+		expected error
+	}{
+		"return an error if the name isn't valid on Thunderstore": {
+			mods:     installed,
+			options:  modpack.Options{Name: "Server Modpack", Version: "1.0.0"},
+			expected: service.ErrInvalidModpack,
+		},
+		"return an error if no mods are installed": {
+			mods:     []mod.Mod{},
+			options:  modpack.Options{Name: "Server_Modpack", Version: "1.0.0"},
+			expected: service.ErrInvalidModpack,
+		},
+		"return an error if the icon doesn't exist": {
+			mods:     installed,
+			options:  modpack.Options{Name: "Server_Modpack", Version: "1.0.0", Icon: filepath.Join(dir, "missing.png")},
+			expected: service.ErrModpackFileNotFound,
+		},
+		"return an error if the icon isn't a PNG": {
+			mods:     installed,
+			options:  modpack.Options{Name: "Server_Modpack", Version: "1.0.0", Icon: notPNG},
+			expected: service.ErrInvalidModpack,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				ListModsFunc: func() ([]mod.Mod, error) {
+					return test.mods, nil
+				},
+			}
+			fr := &mock.FrameworksRepo{
+				GetFrameworkFunc: func(name string) (framework.Framework, error) {
+					return framework.Framework{}, repo.ErrFrameworkFetchNoResults
+				},
+			}
+			test.options.Path = filepath.Join(t.TempDir(), "modpack.zip")
+
+			_, _, err := service.NewModpackService(r, fr).BuildModpack(test.options)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if _, err := os.Stat(test.options.Path); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected no modpack to be written, received: %+v", err)
+			}
+		})
+	}
+}
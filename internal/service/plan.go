@@ -0,0 +1,392 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"warden/internal/api/source"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/plan"
+)
+
+// The Plan* methods mirror each mutating method on Mod and Framework. They check and fetch
+// everything the real method would, but only describe the changes instead of making them, so
+// nothing is downloaded, deleted or written to the database.
+
+func (ms *modService) PlanAddMod(sourceName, namespace, name string) (plan.Plan, error) {
+	p := plan.Plan{}
+
+	if err := ms.checkNotInstalled(name); err != nil {
+		return p, err
+	}
+
+	src, err := ms.sources.Get(sourceName)
+	if err != nil {
+		return p, err
+	}
+	pkg, err := src.GetPackage(namespace, name)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+	if pkg.Name != name {
+		if err := ms.checkNotInstalled(pkg.Name); err != nil {
+			return p, err
+		}
+	}
+
+	modpack, err := ms.isModpack(pkg.Latest)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrModInstallFailed, err)
+	}
+	if modpack {
+		steps, err := ms.planModpack(pkg, plan.Install, "")
+		if err != nil {
+			return p, fmt.Errorf("%w: %w", ErrModInstallFailed, err)
+		}
+		p.Add(steps...)
+		return p, nil
+	}
+	p.Add(ms.releaseStep(plan.Install, pkg.Source, pkg.Latest))
+
+	deps, err := ms.planDependencies(pkg.Latest.Dependencies)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrAddDependenciesFailed, err)
+	}
+	p.Add(deps...)
+	return p, nil
+}
+
+func (ms *modService) PlanUpdateMod(name string) (plan.Plan, error) {
+	current, err := ms.r.GetMod(name)
+	if err != nil && errors.Is(err, repo.ErrModFetchNoResults) {
+		return plan.Plan{}, fmt.Errorf("%w: %w", ErrModNotInstalled, err)
+	}
+	if err != nil {
+		return plan.Plan{}, fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+	}
+	if current.Parent != "" {
+		return plan.Plan{}, fmt.Errorf("%w: %s", ErrModInModpack, current.Parent)
+	}
+	return ms.planUpdate(current)
+}
+
+func (ms *modService) PlanUpdateAllMods() (plan.Plan, error) {
+	p := plan.Plan{}
+
+	mods, err := ms.r.ListMods()
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToListMods, err)
+	}
+	for _, m := range mods {
+		if m.Parent != "" {
+			continue
+		}
+		update, err := ms.planUpdate(m)
+		if err != nil {
+			return p, err
+		}
+		p.Merge(update)
+	}
+	return p, nil
+}
+
+func (ms *modService) PlanRemoveMod(namespace, name string) (plan.Plan, error) {
+	p := plan.Plan{}
+
+	current, err := ms.r.GetMod(name)
+	if err != nil && errors.Is(err, repo.ErrModFetchNoResults) {
+		return p, fmt.Errorf("%w: %w", ErrModNotInstalled, err)
+	}
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
+	}
+	if current.Parent != "" {
+		return p, fmt.Errorf("%w: %s", ErrModInModpack, current.Parent)
+	}
+
+	if current.Modpack {
+		mods, err := ms.r.ListMods()
+		if err != nil {
+			return p, fmt.Errorf("%w: %w", ErrUnableToRemoveMod, err)
+		}
+		for _, m := range mods {
+			if m.Parent == current.PackageName() {
+				p.Add(ms.removeStep(m))
+			}
+		}
+	}
+	p.Add(ms.removeStep(current))
+	return p, nil
+}
+
+func (ms *modService) PlanRemoveAllMods() (plan.Plan, error) {
+	p := plan.Plan{}
+
+	mods, err := ms.r.ListMods()
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToListMods, err)
+	}
+	for _, m := range mods {
+		p.Add(ms.removeStep(m))
+	}
+	return p, nil
+}
+
+func (ms *modService) planUpdate(current mod.Mod) (plan.Plan, error) {
+	p := plan.Plan{}
+	if current.Source == source.File {
+		return p, nil
+	}
+
+	pkg, err := ms.getPackage(current)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+	if current.Version >= pkg.Latest.Version {
+		return p, nil
+	}
+
+	if current.Modpack {
+		steps, err := ms.planModpack(pkg, plan.Update, current.Version)
+		if err != nil {
+			return p, fmt.Errorf("%w: %w", ErrUnableToUpdateMod, err)
+		}
+		p.Add(steps...)
+		return p, nil
+	}
+
+	step := ms.releaseStep(plan.Update, pkg.Source, pkg.Latest)
+	step.FromVersion = current.Version
+	step.Delete = []string{ms.fm.ModPath(current.FullName())}
+	p.Add(step)
+
+	deps, err := ms.planDependencies(pkg.Latest.Dependencies)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrAddDependenciesFailed, err)
+	}
+	p.Add(deps...)
+	return p, nil
+}
+
+// planDependencies mirrors addDependencies: every dependency except BepInEx is (re)installed at
+// its latest version, on top of whatever version is already installed.
+func (ms *modService) planDependencies(dependencies []string) ([]plan.Step, error) {
+	steps := []plan.Step{}
+
+	ts, err := ms.sources.Get(source.Thunderstore)
+	if err != nil {
+		return steps, err
+	}
+	for _, dep := range dependencies {
+		namespace, name, _, err := parseDependency(dep)
+		if err != nil {
+			return steps, err
+		}
+
+		if name == framework.BepInEx {
+			continue
+		}
+
+		// Mods installed by a modpack stay at the version it pins
+		current, errCurrent := ms.r.GetMod(name)
+		if errCurrent == nil && current.Parent != "" {
+			continue
+		}
+
+		pkg, err := ts.GetPackage(namespace, name)
+		if err != nil {
+			return steps, err
+		}
+
+		step := ms.releaseStep(plan.Install, pkg.Source, pkg.Latest)
+		if errCurrent == nil {
+			step.Action = plan.Update
+			step.FromVersion = current.Version
+		}
+		step.Dependency = true
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// planModpack mirrors installModpack: every mod the modpack pins is installed at that version,
+// replacing any other version it installed, and mods an older release pinned are removed. Mods
+// installed some other way are left alone. The modpack's own step comes last, since it's recorded
+// once its mods are installed.
+func (ms *modService) planModpack(pkg source.Package, action plan.Action, fromVersion string) ([]plan.Step, error) {
+	steps := []plan.Step{}
+	pack := mod.Mod{Namespace: pkg.Namespace, Name: pkg.Name}
+
+	ts, err := ms.sources.Get(source.Thunderstore)
+	if err != nil {
+		return steps, err
+	}
+	installed, err := ms.r.ListMods()
+	if err != nil {
+		return steps, err
+	}
+
+	pinned := map[string]bool{}
+	for _, m := range modpackMods(pkg.Latest) {
+		pinned[m.PackageName()] = true
+
+		current, err := ms.r.GetMod(m.Name)
+		if err != nil && !errors.Is(err, repo.ErrModFetchNoResults) {
+			return steps, err
+		}
+		if err == nil && (current.Version == m.Version || current.Parent != pack.PackageName()) {
+			continue
+		}
+
+		release, errRelease := ts.GetRelease(m.Namespace, m.Name, m.Version)
+		if errRelease != nil {
+			return steps, errRelease
+		}
+		step := ms.releaseStep(plan.Install, source.Thunderstore, release)
+		if err == nil {
+			step.Action = plan.Update
+			step.FromVersion = current.Version
+			step.Delete = []string{ms.fm.ModPath(current.FullName())}
+		}
+		step.Dependency = true
+		steps = append(steps, step)
+	}
+
+	for _, m := range installed {
+		if m.Parent == pack.PackageName() && !pinned[m.PackageName()] {
+			steps = append(steps, ms.removeStep(m))
+		}
+	}
+
+	step := ms.releaseStep(action, pkg.Source, pkg.Latest)
+	step.FromVersion = fromVersion
+	return append(steps, step), nil
+}
+
+func (ms *modService) releaseStep(action plan.Action, sourceName string, release source.Release) plan.Step {
+	return plan.Step{
+		Action:      action,
+		Namespace:   release.Namespace,
+		Name:        release.Name,
+		ToVersion:   release.Version,
+		DownloadURL: release.DownloadURL,
+		Size:        ms.releaseSize(sourceName, release),
+		Table:       plan.ModsTable,
+	}
+}
+
+func (ms *modService) removeStep(m mod.Mod) plan.Step {
+	step := plan.Step{
+		Action:      plan.Remove,
+		Namespace:   m.Namespace,
+		Name:        m.Name,
+		FromVersion: m.Version,
+		Table:       plan.ModsTable,
+	}
+	// Modpacks don't have any files of their own
+	if !m.Modpack {
+		step.Delete = []string{ms.fm.ModPath(m.FullName())}
+	}
+	return step
+}
+
+func (fs *frameworkService) PlanInstallBepInEx() (plan.Plan, error) {
+	p := plan.Plan{}
+
+	if _, err := fs.fr.GetFramework(framework.BepInEx); err == nil {
+		return p, nil
+	}
+
+	pkg, err := fs.ts.GetPackage(framework.BepInExNamespace, framework.BepInEx)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrFrameworkNotFound, err)
+	}
+	p.Add(plan.Step{
+		Action:      plan.Install,
+		Namespace:   pkg.Latest.Namespace,
+		Name:        pkg.Latest.Name,
+		ToVersion:   pkg.Latest.VersionNumber,
+		DownloadURL: pkg.Latest.DownloadURL,
+		Size:        downloadSize(fs.ts, pkg.Latest.DownloadURL),
+		Table:       plan.FrameworksTable,
+	})
+	return p, nil
+}
+
+func (fs *frameworkService) PlanUpdateBepInEx() (plan.Plan, error) {
+	p := plan.Plan{}
+
+	current, err := fs.fr.GetFramework(framework.BepInEx)
+	if err != nil && errors.Is(err, repo.ErrFrameworkFetchNoResults) {
+		return p, fmt.Errorf("%w: %w", ErrFrameworkNotInstalled, err)
+	}
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToUpdateFramework, err)
+	}
+
+	pkg, err := fs.ts.GetPackage(framework.BepInExNamespace, framework.BepInEx)
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToUpdateFramework, err)
+	}
+	if pkg.Latest.VersionNumber <= current.Version {
+		return p, nil
+	}
+
+	// Installed mods are moved aside and put back afterwards, so only BepInEx's own files are lost
+	p.Add(plan.Step{
+		Action:      plan.Update,
+		Namespace:   pkg.Latest.Namespace,
+		Name:        pkg.Latest.Name,
+		FromVersion: current.Version,
+		ToVersion:   pkg.Latest.VersionNumber,
+		DownloadURL: pkg.Latest.DownloadURL,
+		Size:        downloadSize(fs.ts, pkg.Latest.DownloadURL),
+		Delete:      fs.fm.BepInExFiles(),
+		Table:       plan.FrameworksTable,
+	})
+	return p, nil
+}
+
+func (fs *frameworkService) PlanRemoveBepInEx() (plan.Plan, error) {
+	p := plan.Plan{}
+
+	current, err := fs.fr.GetFramework(framework.BepInEx)
+	if err != nil && !errors.Is(err, repo.ErrFrameworkFetchNoResults) {
+		return p, fmt.Errorf("%w: %w", ErrUnableToRemoveFramework, err)
+	}
+	p.Add(plan.Step{
+		Action:      plan.Remove,
+		Namespace:   framework.BepInExNamespace,
+		Name:        framework.BepInEx,
+		FromVersion: current.Version,
+		Delete:      fs.fm.BepInExFiles(),
+		Table:       plan.FrameworksTable,
+	})
+	return p, nil
+}
+
+// downloadSize looks up the size of a download for a plan. A missing size shouldn't stop a dry
+// run, so any failure is reported as an unknown size instead.
+func downloadSize(ts thunderstore.Thunderstore, url string) int64 {
+	size, err := ts.GetDownloadSize(url)
+	if err != nil {
+		return plan.UnknownSize
+	}
+	return size
+}
+
+// releaseSize looks up the size of a release's download for a plan, from the source it's
+// installed from. Just like downloadSize, any failure is reported as an unknown size.
+func (ms *modService) releaseSize(sourceName string, release source.Release) int64 {
+	src, err := ms.sources.Get(sourceName)
+	if err != nil {
+		return plan.UnknownSize
+	}
+	size, err := src.GetDownloadSize(release)
+	if err != nil {
+		return plan.UnknownSize
+	}
+	return size
+}
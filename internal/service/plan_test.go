@@ -0,0 +1,277 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"warden/internal/api/source"
+	"warden/internal/api/thunderstore"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/plan"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+// The mocks in these tests only implement read operations, so any attempt to change files or
+// the database panics and fails the test.
+
+func TestPlanAddMod_Happy(t *testing.T) {
+	r := &mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			if name == "AzuClock" {
+				return mod.Mod{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.0"}, nil
+			}
+			return mod.Mod{}, repo.ErrModFetchNoResults
+		},
+	}
+	ts := &mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			release := thunderstore.Release{Namespace: namespace, Name: name, VersionNumber: "1.0.1"}
+			if name == "Sleepover" {
+				release.Dependencies = []string{"denikson-BepInExPack_Valheim-5.4.2202", "Azumatt-AzuClock-1.0.1", "Azumatt-Where_You_At-1.0.9"}
+			}
+			return thunderstore.Package{Latest: release}, nil
+		},
+		GetDownloadSizeFunc: func(url string) (int64, error) {
+			return 0, thunderstore.ErrUnknownDownloadSize
+		},
+	}
+	fm := &mock.Manager{
+		HasPluginsFunc: func(url, fullName string) (bool, error) {
+			return true, nil
+		},
+	}
+	ms := service.NewModService(r, fm, thunderstoreSources(ts), service.NewConfirmer(&io.LimitedReader{}))
+
+	p, err := ms.PlanAddMod(source.Thunderstore, "Azumatt", "Sleepover")
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+
+	expected := []plan.Step{
+		{Action: plan.Install, Name: "Sleepover", ToVersion: "1.0.1"},
+		{Action: plan.Update, Name: "AzuClock", FromVersion: "1.0.0", ToVersion: "1.0.1", Dependency: true},
+		{Action: plan.Install, Name: "Where_You_At", ToVersion: "1.0.1", Dependency: true},
+	}
+	if len(p.Steps) != len(expected) {
+		t.Fatalf("expected %d steps, received: %+v", len(expected), p.Steps)
+	}
+	for i, e := range expected {
+		s := p.Steps[i]
+		if s.Action != e.Action || s.Name != e.Name || s.FromVersion != e.FromVersion || s.ToVersion != e.ToVersion || s.Dependency != e.Dependency {
+			t.Errorf("expected step: %+v, received: %+v", e, s)
+		}
+		if s.Size != plan.UnknownSize {
+			t.Errorf("expected an unknown download size, received: %d", s.Size)
+		}
+	}
+}
+
+func TestPlanAddMod_Sad(t *testing.T) {
+	tests := map[string]struct {
+		installed    bool
+		dependencies []string
+		expected     error
+	}{
+		"return an error if the mod is already installed": {
+			installed: true,
+			expected:  service.ErrModAlreadyInstalled,
+		},
+		"return an error if a dependency isn't namespace-name-version": {
+			dependencies: []string{"Azumatt-AzuClock"},
+			expected:     service.ErrInvalidDependency,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				GetModFunc: func(name string) (mod.Mod, error) {
+					if test.installed {
+						return mod.Mod{Name: name}, nil
+					}
+					return mod.Mod{}, repo.ErrModFetchNoResults
+				},
+			}
+			ts := &mock.Thunderstore{
+				GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+					release := thunderstore.Release{Namespace: namespace, Name: name, VersionNumber: "1.0.1", Dependencies: test.dependencies}
+					return thunderstore.Package{Latest: release}, nil
+				},
+				GetDownloadSizeFunc: func(url string) (int64, error) {
+					return 0, thunderstore.ErrUnknownDownloadSize
+				},
+			}
+			fm := &mock.Manager{
+				HasPluginsFunc: func(url, fullName string) (bool, error) {
+					return true, nil
+				},
+			}
+			ms := service.NewModService(r, fm, thunderstoreSources(ts), service.NewConfirmer(&io.LimitedReader{}))
+
+			_, err := ms.PlanAddMod(source.Thunderstore, "Azumatt", "Sleepover")
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestPlanUpdateMod_Happy(t *testing.T) {
+	current := mod.Mod{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0"}
+	fm := &mock.Manager{
+		ModPathFunc: func(fullName string) string {
+			return filepath.Join("plugins", fullName)
+		},
+	}
+
+	tests := map[string]struct {
+		latest   string
+		expected int
+	}{
+		"plan an update if a newer version exists": {
+			latest:   "1.0.1",
+			expected: 1,
+		},
+		"plan nothing if mod is up-to-date": {
+			latest:   "1.0.0",
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				GetModFunc: func(name string) (mod.Mod, error) {
+					return current, nil
+				},
+			}
+			ts := &mock.Thunderstore{
+				GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+					return thunderstore.Package{
+						Latest: thunderstore.Release{Namespace: namespace, Name: name, VersionNumber: test.latest},
+					}, nil
+				},
+				GetDownloadSizeFunc: func(url string) (int64, error) {
+					return 2048, nil
+				},
+			}
+			ms := service.NewModService(r, fm, thunderstoreSources(ts), service.NewConfirmer(&io.LimitedReader{}))
+
+			p, err := ms.PlanUpdateMod("Sleepover")
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if len(p.Steps) != test.expected {
+				t.Fatalf("expected %d steps, received: %+v", test.expected, p.Steps)
+			}
+			if test.expected == 0 {
+				return
+			}
+			oldPath := filepath.Join("plugins", current.FullName())
+			if len(p.Steps[0].Delete) != 1 || p.Steps[0].Delete[0] != oldPath {
+				t.Errorf("expected %s to be deleted, received: %+v", oldPath, p.Steps[0].Delete)
+			}
+			if p.DownloadSize() != 2048 {
+				t.Errorf("expected download size: 2048, received: %d", p.DownloadSize())
+			}
+		})
+	}
+}
+
+func TestPlanUpdateMod_Sad(t *testing.T) {
+	r := &mock.ModsRepo{
+		GetModFunc: func(name string) (mod.Mod, error) {
+			return mod.Mod{}, repo.ErrModFetchNoResults
+		},
+	}
+	ms := service.NewModService(r, &mock.Manager{}, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(&io.LimitedReader{}))
+
+	_, err := ms.PlanUpdateMod("Sleepover")
+	if !errors.Is(err, service.ErrModNotInstalled) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrModNotInstalled, err)
+	}
+}
+
+func TestPlanRemoveAllMods_Happy(t *testing.T) {
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return []mod.Mod{
+				{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.0"},
+				{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.0"},
+			}, nil
+		},
+	}
+	fm := &mock.Manager{
+		ModPathFunc: func(fullName string) string {
+			return fullName
+		},
+	}
+	ms := service.NewModService(r, fm, thunderstoreSources(&mock.Thunderstore{}), service.NewConfirmer(&io.LimitedReader{}))
+
+	p, err := ms.PlanRemoveAllMods()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(p.Steps) != 2 {
+		t.Fatalf("expected 2 steps, received: %+v", p.Steps)
+	}
+	for _, s := range p.Steps {
+		if s.Action != plan.Remove || s.DatabaseChange() != "delete from mods" {
+			t.Errorf("expected a removal step, received: %+v", s)
+		}
+	}
+}
+
+func TestPlanUpdateBepInEx_Happy(t *testing.T) {
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{Name: framework.BepInEx, Version: "5.4.2200"}, nil
+		},
+	}
+	fm := &mock.Manager{
+		BepInExFilesFunc: func() []string {
+			return []string{"BepInEx", "doorstop_libs"}
+		},
+	}
+	ts := &mock.Thunderstore{
+		GetPackageFunc: func(namespace, name string) (thunderstore.Package, error) {
+			return thunderstore.Package{
+				Latest: thunderstore.Release{Namespace: namespace, Name: name, VersionNumber: "5.4.2202"},
+			}, nil
+		},
+		GetDownloadSizeFunc: func(url string) (int64, error) {
+			return 711730, nil
+		},
+	}
+	fs := service.NewFrameworkService(fr, fm, ts, service.NewConfirmer(&io.LimitedReader{}))
+
+	p, err := fs.PlanUpdateBepInEx()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(p.Steps) != 1 {
+		t.Fatalf("expected 1 step, received: %+v", p.Steps)
+	}
+	s := p.Steps[0]
+	if s.FromVersion != "5.4.2200" || s.ToVersion != "5.4.2202" || len(s.Delete) != 2 || s.Table != plan.FrameworksTable {
+		t.Errorf("unexpected BepInEx update step: %+v", s)
+	}
+}
+
+func TestPlanUpdateBepInEx_Sad(t *testing.T) {
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{}, repo.ErrFrameworkFetchNoResults
+		},
+	}
+	fs := service.NewFrameworkService(fr, &mock.Manager{}, &mock.Thunderstore{}, service.NewConfirmer(&io.LimitedReader{}))
+
+	_, err := fs.PlanUpdateBepInEx()
+	if !errors.Is(err, service.ErrFrameworkNotInstalled) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrFrameworkNotInstalled, err)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"warden/internal/data/file"
+	"warden/internal/domain/player"
+)
+
+var (
+	ErrInvalidPlayerList      = errors.New("invalid player list")
+	ErrInvalidPlayerID        = errors.New("invalid player ID")
+	ErrPlayerAlreadyListed    = errors.New("player is already listed")
+	ErrPlayerNotListed        = errors.New("player is not listed")
+	ErrUnableToListPlayers    = errors.New("unable to list players")
+	ErrUnableToEditPlayerList = errors.New("unable to edit player list")
+	ErrUnableToReloadPlayers  = errors.New("unable to restart the server to reload player lists")
+)
+
+// Exposes all methods for managing the admin, banned and permitted player lists. Valheim only
+// reads the lists when it starts, so changes don't apply to a running server until it's reloaded.
+type Players interface {
+	// Returns every player ID in a list
+	ListPlayers(list string) ([]string, error)
+
+	// Adds a Steam64 or platform prefixed player ID to a list
+	AddPlayer(list, id string) error
+
+	// Removes a player ID from a list, even one that isn't valid
+	RemovePlayer(list, id string) error
+
+	// Restarts the game server so it reads the lists again. It reports false, without doing
+	// anything, if the server isn't running.
+	Reload() (bool, error)
+
+	IsValidList(list string) bool
+}
+
+type playerService struct {
+	pl     file.PlayerLists
+	server Server
+}
+
+func NewPlayerService(pl file.PlayerLists, server Server) Players {
+	return &playerService{
+		pl:     pl,
+		server: server,
+	}
+}
+
+func (ps *playerService) ListPlayers(list string) ([]string, error) {
+	if !ps.IsValidList(list) {
+		return []string{}, fmt.Errorf("%w: %s", ErrInvalidPlayerList, list)
+	}
+	lines, err := ps.pl.Read(list)
+	if err != nil {
+		return []string{}, fmt.Errorf("%w: %w", ErrUnableToListPlayers, err)
+	}
+	return player.IDs(lines), nil
+}
+
+func (ps *playerService) AddPlayer(list, id string) error {
+	if err := player.ValidateID(id); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPlayerID, err)
+	}
+	return ps.edit(list, func(lines []string) ([]string, error) {
+		lines, added := player.Add(lines, id)
+		if !added {
+			return lines, fmt.Errorf("%w: %s", ErrPlayerAlreadyListed, id)
+		}
+		return lines, nil
+	})
+}
+
+func (ps *playerService) RemovePlayer(list, id string) error {
+	return ps.edit(list, func(lines []string) ([]string, error) {
+		lines, removed := player.Remove(lines, id)
+		if !removed {
+			return lines, fmt.Errorf("%w: %s", ErrPlayerNotListed, id)
+		}
+		return lines, nil
+	})
+}
+
+func (ps *playerService) Reload() (bool, error) {
+	status, err := ps.server.Status()
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrUnableToReloadPlayers, err)
+	}
+	if !status.Running {
+		return false, nil
+	}
+
+	fmt.Fprintln(progress, "... restarting the server to reload player lists ...")
+	if _, err := ps.server.Restart(""); err != nil {
+		return false, fmt.Errorf("%w: %w", ErrUnableToReloadPlayers, err)
+	}
+	return true, nil
+}
+
+func (ps *playerService) IsValidList(list string) bool {
+	return slices.Contains(player.Lists(), list)
+}
+
+// edit validates the list, then applies a change to it. Only IDs that are added are validated, so
+// a malformed one that was added by hand can still be removed.
+func (ps *playerService) edit(list string, change func(lines []string) ([]string, error)) error {
+	if !ps.IsValidList(list) {
+		return fmt.Errorf("%w: %s", ErrInvalidPlayerList, list)
+	}
+
+	err := ps.pl.Edit(list, change)
+	if errors.Is(err, ErrPlayerAlreadyListed) || errors.Is(err, ErrPlayerNotListed) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToEditPlayerList, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"warden/internal/data/file"
+	"warden/internal/domain/player"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const (
+	testSteamID = "76561198012345678"
+	testXboxID  = "Xbox_2535412345678901"
+
+	// Someone typed this into the admin list by hand
+	testMalformedID = "7656119801234567"
+)
+
+func TestEditPlayers_Happy(t *testing.T) {
+	tests := map[string]struct {
+		edit     func(ps service.Players) error
+		expected []string
+	}{
+		"add a player to the list": {
+			edit: func(ps service.Players) error {
+				return ps.AddPlayer(player.Admin, testXboxID)
+			},
+			expected: []string{testSteamID, testMalformedID, testXboxID},
+		},
+		"remove a player from the list": {
+			edit: func(ps service.Players) error {
+				return ps.RemovePlayer(player.Admin, testSteamID)
+			},
+			expected: []string{testMalformedID},
+		},
+		"remove a malformed ID that was added by hand": {
+			edit: func(ps service.Players) error {
+				return ps.RemovePlayer(player.Admin, testMalformedID)
+			},
+			expected: []string{testSteamID},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, _ := newTestPlayerService(t)
+
+			if err := test.edit(ps); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			ids, err := ps.ListPlayers(player.Admin)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if !slices.Equal(ids, test.expected) {
+				t.Errorf("expected players: %q, received: %q", test.expected, ids)
+			}
+		})
+	}
+}
+
+func TestEditPlayers_Sad(t *testing.T) {
+	tests := map[string]struct {
+		edit     func(ps service.Players) error
+		expected error
+	}{
+		"return an error if the list is invalid": {
+			edit: func(ps service.Players) error {
+				return ps.AddPlayer("moderator", testSteamID)
+			},
+			expected: service.ErrInvalidPlayerList,
+		},
+		"return an error if the player ID is invalid": {
+			edit: func(ps service.Players) error {
+				return ps.AddPlayer(player.Banned, "Viking")
+			},
+			expected: service.ErrInvalidPlayerID,
+		},
+		"return an error if the player is already listed": {
+			edit: func(ps service.Players) error {
+				return ps.AddPlayer(player.Admin, testSteamID)
+			},
+			expected: service.ErrPlayerAlreadyListed,
+		},
+		"return an error if the player isn't listed": {
+			edit: func(ps service.Players) error {
+				return ps.RemovePlayer(player.Permitted, testSteamID)
+			},
+			expected: service.ErrPlayerNotListed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, _ := newTestPlayerService(t)
+
+			if err := test.edit(ps); !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestReloadPlayers_Happy(t *testing.T) {
+	tests := map[string]struct {
+		running  bool
+		expected bool
+	}{
+		"restart the server if it's running": {
+			running:  true,
+			expected: true,
+		},
+		"do nothing if the server isn't running": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, ss := newTestPlayerService(t)
+			if test.running {
+				if _, err := ss.Start("vanilla"); err != nil {
+					t.Fatalf("unexpected error starting test server, received: %+v", err)
+				}
+			}
+
+			reloaded, err := ps.Reload()
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if reloaded != test.expected {
+				t.Errorf("expected reloaded to be %t, received: %t", test.expected, reloaded)
+			}
+			if status, _ := ss.Status(); status.Running != test.running {
+				t.Errorf("expected the server to be running: %t, received: %+v", test.running, status)
+			}
+		})
+	}
+}
+
+func TestReloadPlayers_Supervised(t *testing.T) {
+	ps, ss := newTestPlayerService(t)
+	before := superviseTestServer(t, ss)
+
+	reloaded, err := ps.Reload()
+	if err != nil || !reloaded {
+		t.Fatalf("expected the server to be reloaded, received: %t, error: %+v", reloaded, err)
+	}
+	if status, _ := ss.Status(); status.Process.PID == before.PID || !status.Process.Supervised() {
+		t.Errorf("expected the supervisor to restart the server, received: %+v", status)
+	}
+}
+
+// newTestPlayerService creates a Players service for a save directory whose admin list has a
+// player in it, along with a malformed ID
+func newTestPlayerService(t *testing.T) (service.Players, service.Server) {
+	dir := t.TempDir()
+	adminList := filepath.Join(dir, player.FileName(player.Admin))
+	if err := os.WriteFile(adminList, []byte("// List admin players ID  ONE per line\n"+testSteamID+"\n"+testMalformedID+"\n"), 0644); err != nil {
+		t.Fatalf("unexpected error creating test admin list, received: %+v", err)
+	}
+
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	return service.NewPlayerService(file.NewPlayerLists(dir), ss), ss
+}
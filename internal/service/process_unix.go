@@ -0,0 +1,47 @@
+//go:build !windows
+
+package service
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// detach starts the command in its own process group, so it keeps running after Warden exits
+// and can be signalled along with everything it launches
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// isRunning checks if a process with the given PID exists
+func isRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminate asks a single process to shut down with SIGTERM
+func terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// interrupt sends SIGINT to the process group led by the given PID. Valheim saves the world
+// before exiting when it's interrupted.
+func interrupt(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGINT)
+}
+
+// requestRestart asks a supervisor to restart the server it's running, with SIGHUP
+func requestRestart(pid int) error {
+	return syscall.Kill(pid, syscall.SIGHUP)
+}
+
+// notifyRestart relays the restart requests a supervisor receives to the channel
+func notifyRestart(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
@@ -0,0 +1,94 @@
+//go:build windows
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// The exit code Windows reports for a process that hasn't exited yet
+const stillActive = 259
+
+// detach starts the command in its own process group, so it keeps running after Warden exits
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// isRunning checks if a process with the given PID is still running the Valheim server or
+// Warden. Windows reuses PIDs quickly, so a PID left behind by a server that's gone could
+// belong to any other process by now.
+func isRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil || code != stillActive {
+		return false
+	}
+	image, err := imageName(h)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(image, windowsServerBinary) || strings.EqualFold(image, wardenImage())
+}
+
+// imageName returns the file name of the executable the process is running
+func imageName(h windows.Handle) (string, error) {
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return "", err
+	}
+	return filepath.Base(windows.UTF16ToString(buf[:size])), nil
+}
+
+// wardenImage returns the file name of Warden's own executable, which supervisors run as
+func wardenImage() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(exe)
+}
+
+// terminate stops a single process. Windows has no SIGTERM, so it's interrupted like the
+// server instead.
+func terminate(pid int) error {
+	return interrupt(pid)
+}
+
+// interrupt sends CTRL_BREAK to the process group led by the given PID, which detach started
+// the server in, so Valheim saves the world before exiting. Console events only reach
+// processes sharing Warden's console, so the process is killed if the event can't be sent.
+func interrupt(pid int) error {
+	if err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(pid)); err == nil {
+		return nil
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// requestRestart can't ask a supervisor to restart its server on Windows, which can't signal
+// another process
+func requestRestart(pid int) error {
+	return fmt.Errorf("%w: it can't be restarted on Windows, use stop and start instead", ErrServerSupervised)
+}
+
+// notifyRestart does nothing, since supervisors on Windows can't be asked to restart
+func notifyRestart(c chan<- os.Signal) {}
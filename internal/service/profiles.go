@@ -0,0 +1,421 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"time"
+	"warden/internal/api/source"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/profile"
+)
+
+// The profile name used in r2modman exports when there's no active profile
+const defaultExportName = "warden"
+
+var (
+	ErrInvalidProfileName    = errors.New("invalid profile name")
+	ErrProfileNotFound       = errors.New("profile not found")
+	ErrProfileAlreadyExists  = errors.New("profile already exists")
+	ErrProfileAlreadyActive  = errors.New("profile is already active")
+	ErrUnableToListProfiles  = errors.New("unable to list profiles")
+	ErrUnableToCreateProfile = errors.New("unable to create profile")
+	ErrUnableToSwitchProfile = errors.New("unable to switch profile")
+	ErrUnableToDeleteProfile = errors.New("unable to delete profile")
+	ErrInvalidProfileExport  = errors.New("invalid r2modman profile")
+	ErrProfileExportNotFound = errors.New("r2modman profile not found")
+	ErrUnableToImportProfile = errors.New("unable to import r2modman profile")
+	ErrUnableToExportProfile = errors.New("unable to export r2modman profile")
+)
+
+// Exposes all methods for managing mod profiles: named sets of mods, at specific versions, along
+// with the BepInEx config files they're set up with.
+type Profiles interface {
+	// Returns every saved profile, along with the name of the active one. There's no active profile
+	// until one is switched to.
+	ListProfiles() ([]profile.Profile, string, error)
+
+	// Saves the installed mods, at their installed versions, and the BepInEx config files as a new
+	// profile. Every mod's release is kept in the archive cache, so the profile can be switched
+	// back to without downloading it again.
+	CreateProfile(name string) (profile.Profile, error)
+
+	// Removes every installed mod that isn't in the profile, installs the profile's mods at their
+	// saved versions, and replaces the BepInEx config files with the profile's. Changes made since
+	// the active profile was switched to are saved to it first. Nothing is changed unless every
+	// release the profile needs is in the archive cache, or can be downloaded into it, and the
+	// installed mods are put back if the switch fails partway.
+	SwitchProfile(name string) (profile.Profile, error)
+
+	// Deletes a saved profile. The installed mods are left alone.
+	DeleteProfile(name string) error
+
+	// Reads an r2modman profile (.r2z) file, saves its enabled mods and config files as a new
+	// profile, and switches to it. The profile is named after the r2modman one unless a name is
+	// given, and isn't kept if the switch fails or is declined. BepInEx is left out, since Warden
+	// manages it separately from mods.
+	ImportProfile(path, name string) (profile.Profile, error)
+
+	// Writes the installed mods, including BepInEx, and the BepInEx config files to an r2modman
+	// profile (.r2z) file that players can import. The r2modman profile is named after the active
+	// profile unless a name is given.
+	ExportProfile(path, name string) (profile.Export, error)
+}
+
+type profileService struct {
+	r       repo.Mods
+	fr      repo.Frameworks
+	fm      file.Manager
+	pr      file.Profiles
+	sources source.Sources
+	server  Server
+	c       Confirmer
+}
+
+// NewProfileService creates a Profiles service. Releases that aren't in the archive cache are
+// downloaded from the source each mod was installed from.
+func NewProfileService(r repo.Mods, fr repo.Frameworks, fm file.Manager, pr file.Profiles, sources source.Sources, server Server, c Confirmer) Profiles {
+	return &profileService{
+		r:       r,
+		fr:      fr,
+		fm:      fm,
+		pr:      pr,
+		sources: sources,
+		server:  server,
+		c:       c,
+	}
+}
+
+func (ps *profileService) ListProfiles() ([]profile.Profile, string, error) {
+	profiles, err := ps.pr.List()
+	if err != nil {
+		return []profile.Profile{}, "", fmt.Errorf("%w: %w", ErrUnableToListProfiles, err)
+	}
+	active, err := ps.pr.Active()
+	if err != nil {
+		return []profile.Profile{}, "", fmt.Errorf("%w: %w", ErrUnableToListProfiles, err)
+	}
+	return profiles, active, nil
+}
+
+func (ps *profileService) CreateProfile(name string) (profile.Profile, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+	if _, err := ps.pr.Get(name); err == nil {
+		return profile.Profile{}, fmt.Errorf("%w: %s", ErrProfileAlreadyExists, name)
+	} else if !errors.Is(err, file.ErrProfileNotFound) {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrUnableToCreateProfile, err)
+	}
+
+	p, err := ps.save(profile.Profile{Name: name, CreatedAt: time.Now()})
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrUnableToCreateProfile, err)
+	}
+	return p, nil
+}
+
+func (ps *profileService) SwitchProfile(name string) (profile.Profile, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+	target, err := ps.pr.Get(name)
+	if errors.Is(err, file.ErrProfileNotFound) {
+		return profile.Profile{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+	}
+	active, err := ps.pr.Active()
+	if err != nil {
+		return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+	}
+	if active == name {
+		return target, fmt.Errorf("%w: %s", ErrProfileAlreadyActive, name)
+	}
+
+	// Mods can't be swapped out from under a running server
+	status, err := ps.server.Status()
+	if err != nil {
+		return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+	}
+	if status.Running {
+		return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, ErrServerAlreadyRunning)
+	}
+
+	installed, err := ps.r.ListMods()
+	if err != nil {
+		return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+	}
+	remove, install := target.Changes(installed)
+
+	question := fmt.Sprintf("did you want to switch to %s? %d mods will be removed, %d installed, and the BepInEx config files replaced", name, len(remove), len(install))
+	if active == "" {
+		question += ". The current mods aren't saved in a profile, so create one first to keep them"
+	}
+	ok, err := ps.c.Confirm(question, false)
+	if err != nil {
+		return target, err
+	}
+	if !ok {
+		return target, ErrAborted
+	}
+
+	// Download everything up front, so a release that's gone doesn't leave a profile half installed.
+	// The mods being removed are kept too, so they can be put back if the switch fails.
+	for _, m := range slices.Concat(remove, install) {
+		if err := ps.cache(m); err != nil {
+			return target, fmt.Errorf("%w: %s: %w", ErrUnableToSwitchProfile, m.FullName(), err)
+		}
+	}
+
+	// Keep whatever was changed while the active profile was in use
+	if active != "" {
+		if _, err := ps.save(profile.Profile{Name: active, CreatedAt: time.Now()}); err != nil {
+			return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+		}
+	}
+
+	if err := ps.apply(name, active, remove, install); err != nil {
+		return target, fmt.Errorf("%w: %w", ErrUnableToSwitchProfile, err)
+	}
+	return target, nil
+}
+
+func (ps *profileService) DeleteProfile(name string) error {
+	if err := profile.ValidateName(name); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+
+	ok, err := ps.c.Confirm(fmt.Sprintf("are you sure you want to delete %s?", name), false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	err = ps.pr.Delete(name)
+	if errors.Is(err, file.ErrProfileNotFound) {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToDeleteProfile, err)
+	}
+	return nil
+}
+
+func (ps *profileService) ImportProfile(path, name string) (profile.Profile, error) {
+	e, err := ps.pr.ReadExport(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profile.Profile{}, fmt.Errorf("%w: %s", ErrProfileExportNotFound, path)
+	}
+	if errors.Is(err, file.ErrInvalidExport) {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileExport, err)
+	}
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrUnableToImportProfile, err)
+	}
+
+	if name == "" {
+		name = e.ProfileName
+	}
+	if err := profile.ValidateName(name); err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+	if _, err := ps.pr.Get(name); err == nil {
+		return profile.Profile{}, fmt.Errorf("%w: %s", ErrProfileAlreadyExists, name)
+	} else if !errors.Is(err, file.ErrProfileNotFound) {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrUnableToImportProfile, err)
+	}
+
+	enabled, err := e.EnabledMods()
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileExport, err)
+	}
+	mods := []mod.Mod{}
+	for _, m := range enabled {
+		if m.Namespace == framework.BepInExNamespace && m.Name == framework.BepInEx {
+			continue
+		}
+		mods = append(mods, m)
+	}
+
+	_, err = ps.pr.Import(path, profile.Profile{Name: name, CreatedAt: time.Now(), Mods: mods})
+	if errors.Is(err, file.ErrInvalidExport) {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrInvalidProfileExport, err)
+	}
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("%w: %w", ErrUnableToImportProfile, err)
+	}
+
+	// An import that isn't switched to is discarded, so it can be tried again under the same name
+	p, err := ps.SwitchProfile(name)
+	if err != nil {
+		ps.pr.Delete(name)
+	}
+	return p, err
+}
+
+func (ps *profileService) ExportProfile(path, name string) (profile.Export, error) {
+	if name == "" {
+		active, err := ps.pr.Active()
+		if err != nil {
+			return profile.Export{}, fmt.Errorf("%w: %w", ErrUnableToExportProfile, err)
+		}
+		name = active
+	}
+	if name == "" {
+		name = defaultExportName
+	}
+
+	installed, err := ps.r.ListMods()
+	if err != nil {
+		return profile.Export{}, fmt.Errorf("%w: %w", ErrUnableToExportProfile, err)
+	}
+	mods := thunderstoreMods(installed)
+
+	// Players need BepInEx too, so it's listed first like r2modman does
+	bepinex, err := ps.fr.GetFramework(framework.BepInEx)
+	if err == nil {
+		mods = append([]mod.Mod{{Namespace: bepinex.Namespace, Name: bepinex.Name, Version: bepinex.Version}}, mods...)
+	} else if !errors.Is(err, repo.ErrFrameworkFetchNoResults) {
+		return profile.Export{}, fmt.Errorf("%w: %w", ErrUnableToExportProfile, err)
+	}
+
+	e, err := profile.NewExport(name, mods)
+	if err != nil {
+		return e, fmt.Errorf("%w: %w", ErrUnableToExportProfile, err)
+	}
+	if err := ps.pr.Export(path, e); err != nil {
+		return e, fmt.Errorf("%w: %w", ErrUnableToExportProfile, err)
+	}
+	return e, nil
+}
+
+// apply swaps the installed mods and BepInEx config files for the named profile's, and makes it
+// the active one. If anything fails partway, the mods are put back the way they were.
+func (ps *profileService) apply(name, active string, remove, install []mod.Mod) error {
+	removed, installed := []mod.Mod{}, []mod.Mod{}
+
+	for _, m := range remove {
+		fmt.Fprintf(progress, "... removing %s ...\n", m.FullName())
+		if err := ps.fm.RemoveMod(m.FullName()); err != nil {
+			ps.rollBack(active, removed, installed)
+			return err
+		}
+		removed = append(removed, m)
+		if err := ps.r.DeleteMod(m.Name, m.Namespace); err != nil {
+			ps.rollBack(active, removed, installed)
+			return err
+		}
+	}
+	for _, m := range install {
+		// Modpacks don't have any files of their own, they're only recorded
+		if !m.Modpack {
+			// Every release was cached up front, so there's nothing to download
+			fmt.Fprintf(progress, "... installing %s ...\n", m.FullName())
+			path, err := ps.fm.InstallMod(m.Location, m.FullName())
+			if err != nil {
+				ps.rollBack(active, removed, installed)
+				return err
+			}
+			m.FilePath = path
+		}
+		installed = append(installed, m)
+		if err := ps.r.UpsertMod(m); err != nil {
+			ps.rollBack(active, removed, installed)
+			return err
+		}
+	}
+
+	if err := ps.pr.RestoreConfig(name); err != nil {
+		ps.rollBack(active, removed, installed)
+		return err
+	}
+	if err := ps.pr.SetActive(name); err != nil {
+		ps.rollBack(active, removed, installed)
+		return err
+	}
+	return nil
+}
+
+// rollBack undoes a switch that failed partway. The mods it installed are removed, the ones it
+// removed are installed again from the archive cache, and the active profile's config files are
+// put back. It carries on past errors, so as much as possible is restored.
+func (ps *profileService) rollBack(active string, removed, installed []mod.Mod) {
+	for _, m := range installed {
+		fmt.Fprintf(progress, "... removing %s ...\n", m.FullName())
+		ps.fm.RemoveMod(m.FullName())
+		ps.r.DeleteMod(m.Name, m.Namespace)
+	}
+	for _, m := range removed {
+		if !m.Modpack {
+			fmt.Fprintf(progress, "... restoring %s ...\n", m.FullName())
+			path, err := ps.fm.InstallMod(m.Location, m.FullName())
+			if err != nil {
+				continue
+			}
+			m.FilePath = path
+		}
+		ps.r.UpsertMod(m)
+	}
+	if active != "" {
+		ps.pr.RestoreConfig(active)
+	}
+}
+
+// save records the installed mods and BepInEx config files in a profile, making sure every mod's
+// release is in the archive cache. An existing profile keeps when it was created.
+func (ps *profileService) save(p profile.Profile) (profile.Profile, error) {
+	if existing, err := ps.pr.Get(p.Name); err == nil {
+		p.CreatedAt = existing.CreatedAt
+	}
+
+	mods, err := ps.r.ListMods()
+	if err != nil {
+		return p, err
+	}
+	for _, m := range mods {
+		if err := ps.cache(m); err != nil {
+			return p, fmt.Errorf("%s: %w", m.FullName(), err)
+		}
+	}
+	p.Mods = mods
+	return ps.pr.Save(p)
+}
+
+// cache makes sure a mod's release is in the archive cache. Where it's downloaded from is only
+// looked up when it isn't, so switching between profiles that are cached doesn't reach any source.
+func (ps *profileService) cache(m mod.Mod) error {
+	if ps.fm.IsCached(m.FullName()) {
+		return nil
+	}
+	url, err := ps.downloadURL(m)
+	if err != nil {
+		return err
+	}
+	return ps.fm.CacheMod(url, m.FullName())
+}
+
+// downloadURL is where a mod's release is downloaded from. Mods added straight from an archive are
+// fetched from where they were added from, and every other mod from the source it was installed
+// from.
+func (ps *profileService) downloadURL(m mod.Mod) (string, error) {
+	if m.Location != "" {
+		return m.Location, nil
+	}
+	src, err := ps.sources.Get(m.Source)
+	if err != nil {
+		return "", err
+	}
+	release, err := src.GetRelease(m.Namespace, m.Name, m.Version)
+	if err != nil {
+		return "", err
+	}
+	return src.DownloadURL(release)
+}
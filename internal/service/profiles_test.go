@@ -0,0 +1,519 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"warden/internal/api/source"
+	"warden/internal/data/file"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/profile"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestCreateProfile_Happy(t *testing.T) {
+	installed := []mod.Mod{
+		{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"},
+		{Source: source.Nexus, Namespace: "ValheimPlus", Name: "4", Version: "0.9.9"},
+		{Source: source.File, Namespace: "Local", Name: "In_House", Version: "1.0.0", Location: "/home/valheim/In_House.zip"},
+		{Namespace: "Azumatt", Name: "AzuClock", Version: "1.0.3"},
+	}
+	cached := []string{}
+	urls := []string{}
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return installed, nil
+		},
+	}
+	fm := &mock.Manager{
+		IsCachedFunc: func(fullName string) bool {
+			return fullName == "Azumatt-AzuClock-1.0.3"
+		},
+		CacheModFunc: func(url, fullName string) error {
+			cached = append(cached, fullName)
+			urls = append(urls, url)
+			return nil
+		},
+	}
+
+	ps, pr, _ := newTestProfileService(t, r, fm, "")
+	p, err := ps.CreateProfile("vanilla+")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if len(p.Mods) != 4 || p.Mods[0].Version != "1.0.1" {
+		t.Errorf("expected the installed mods to be saved, received: %+v", p.Mods)
+	}
+	if len(cached) != 3 || cached[0] != "Azumatt-Sleepover-1.0.1" {
+		t.Errorf("expected the installed mods that aren't cached to be cached, received: %v", cached)
+	}
+
+	// Each mod is downloaded from the source it was installed from
+	expected := []string{"https://thunderstore.example.com/Azumatt/Sleepover/1.0.1", "https://nexus.example.com/ValheimPlus/4/0.9.9", "/home/valheim/In_House.zip"}
+	if !slices.Equal(urls, expected) {
+		t.Errorf("expected download URLs: %v, received: %v", expected, urls)
+	}
+	if _, err := pr.Get("vanilla+"); err != nil {
+		t.Errorf("expected the profile to be saved, received: %+v", err)
+	}
+}
+
+func TestCreateProfile_Sad(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		fm       *mock.Manager
+		expected error
+	}{
+		"return an error if the name is invalid": {
+			name:     "../vanilla",
+			expected: service.ErrInvalidProfileName,
+		},
+		"return an error if the profile already exists": {
+			name:     "existing",
+			expected: service.ErrProfileAlreadyExists,
+		},
+		"return an error if a mod can't be cached": {
+			name: "vanilla+",
+			fm: &mock.Manager{
+				IsCachedFunc: func(fullName string) bool {
+					return false
+				},
+				CacheModFunc: func(url, fullName string) error {
+					return errors.New("download failed")
+				},
+			},
+			expected: service.ErrUnableToCreateProfile,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				ListModsFunc: func() ([]mod.Mod, error) {
+					return []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}}, nil
+				},
+			}
+			ps, pr, _ := newTestProfileService(t, r, test.fm, "")
+			if _, err := pr.Save(profile.Profile{Name: "existing"}); err != nil {
+				t.Fatalf("unexpected error saving test profile, received: %+v", err)
+			}
+
+			_, err := ps.CreateProfile(test.name)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestSwitchProfile_Happy(t *testing.T) {
+	installed := []mod.Mod{
+		{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"},
+		{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9"},
+	}
+	removed, added := []string{}, []string{}
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return installed, nil
+		},
+		DeleteModFunc: func(modName, namespace string) error {
+			return nil
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			return nil
+		},
+	}
+	fm := &mock.Manager{
+		IsCachedFunc: func(fullName string) bool {
+			return false
+		},
+		CacheModFunc: func(url, fullName string) error {
+			return nil
+		},
+		RemoveModFunc: func(fullName string) error {
+			removed = append(removed, fullName)
+			return nil
+		},
+		InstallModFunc: func(url, fullName string) (string, error) {
+			added = append(added, fullName)
+			return "/BepInEx/plugins/" + fullName, nil
+		},
+	}
+
+	ps, pr, config := newTestProfileService(t, r, fm, "Y\n")
+	writeProfileConfig(t, config, "hardcore.cfg")
+	if _, err := pr.Save(profile.Profile{
+		Name: "hardcore",
+		Mods: []mod.Mod{
+			{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"},
+			{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.8"},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error saving test profile, received: %+v", err)
+	}
+	os.Remove(filepath.Join(config, "hardcore.cfg"))
+	writeProfileConfig(t, config, "other.cfg")
+
+	if _, err := ps.SwitchProfile("hardcore"); err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if len(removed) != 1 || removed[0] != "ValheimPlus-ValheimPlus-0.9.9" {
+		t.Errorf("expected the newer ValheimPlus to be removed, received: %v", removed)
+	}
+	if len(added) != 1 || added[0] != "ValheimPlus-ValheimPlus-0.9.8" {
+		t.Errorf("expected the profile's ValheimPlus to be installed, received: %v", added)
+	}
+	if _, err := os.Stat(filepath.Join(config, "hardcore.cfg")); err != nil {
+		t.Errorf("expected the profile's config files to be restored, received: %+v", err)
+	}
+	if active, _ := pr.Active(); active != "hardcore" {
+		t.Errorf("expected hardcore to be active, received: %q", active)
+	}
+}
+
+func TestSwitchProfile_Sad(t *testing.T) {
+	installed := []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}}
+
+	tests := map[string]struct {
+		name     string
+		active   string
+		input    string
+		cacheErr error
+		expected error
+	}{
+		"return an error if the name is invalid": {
+			name:     "../hardcore",
+			expected: service.ErrInvalidProfileName,
+		},
+		"return an error if the profile doesn't exist": {
+			name:     "missing",
+			expected: service.ErrProfileNotFound,
+		},
+		"return an error if the profile is already active": {
+			name:     "hardcore",
+			active:   "hardcore",
+			expected: service.ErrProfileAlreadyActive,
+		},
+		"return an error if the user denies the switch": {
+			name:     "hardcore",
+			input:    "n\n",
+			expected: service.ErrAborted,
+		},
+		"return an error without changing anything if a mod can't be downloaded": {
+			name:     "hardcore",
+			input:    "Y\n",
+			cacheErr: errors.New("download failed"),
+			expected: service.ErrUnableToSwitchProfile,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				ListModsFunc: func() ([]mod.Mod, error) {
+					return installed, nil
+				},
+			}
+			fm := &mock.Manager{
+				IsCachedFunc: func(fullName string) bool {
+					return false
+				},
+				CacheModFunc: func(url, fullName string) error {
+					return test.cacheErr
+				},
+				RemoveModFunc: func(fullName string) error {
+					t.Errorf("expected no mods to be removed, received: %s", fullName)
+					return nil
+				},
+			}
+			ps, pr, _ := newTestProfileService(t, r, fm, test.input)
+			if _, err := pr.Save(profile.Profile{
+				Name: "hardcore",
+				Mods: []mod.Mod{{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9"}},
+			}); err != nil {
+				t.Fatalf("unexpected error saving test profile, received: %+v", err)
+			}
+			if err := pr.SetActive(test.active); err != nil {
+				t.Fatalf("unexpected error activating test profile, received: %+v", err)
+			}
+
+			_, err := ps.SwitchProfile(test.name)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if active, _ := pr.Active(); active != test.active {
+				t.Errorf("expected the active profile to be %q, received: %q", test.active, active)
+			}
+		})
+	}
+}
+
+func TestSwitchProfile_RollsBack(t *testing.T) {
+	installed := []mod.Mod{{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.9", FilePath: "/BepInEx/plugins/ValheimPlus-ValheimPlus-0.9.9"}}
+	upserted, deleted := []string{}, []string{}
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return installed, nil
+		},
+		DeleteModFunc: func(modName, namespace string) error {
+			deleted = append(deleted, modName)
+			return nil
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			upserted = append(upserted, m.FullName())
+			return nil
+		},
+	}
+	fm := &mock.Manager{
+		IsCachedFunc: func(fullName string) bool {
+			return true
+		},
+		RemoveModFunc: func(fullName string) error {
+			return nil
+		},
+		InstallModFunc: func(url, fullName string) (string, error) {
+			if fullName == "Azumatt-Sleepover-1.0.1" {
+				return "", errors.New("archive is broken")
+			}
+			return "/BepInEx/plugins/" + fullName, nil
+		},
+	}
+
+	ps, pr, _ := newTestProfileService(t, r, fm, "Y\n")
+	if _, err := pr.Save(profile.Profile{
+		Name: "hardcore",
+		Mods: []mod.Mod{
+			{Namespace: "ValheimPlus", Name: "ValheimPlus", Version: "0.9.8"},
+			{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error saving test profile, received: %+v", err)
+	}
+
+	if _, err := ps.SwitchProfile("hardcore"); !errors.Is(err, service.ErrUnableToSwitchProfile) {
+		t.Fatalf("expected error: %+v, received: %+v", service.ErrUnableToSwitchProfile, err)
+	}
+	if !slices.Equal(deleted, []string{"ValheimPlus", "ValheimPlus"}) {
+		t.Errorf("expected the old and newly installed ValheimPlus records to be deleted, received: %v", deleted)
+	}
+	if !slices.Equal(upserted, []string{"ValheimPlus-ValheimPlus-0.9.8", "ValheimPlus-ValheimPlus-0.9.9"}) {
+		t.Errorf("expected the removed ValheimPlus to be put back last, received: %v", upserted)
+	}
+	if active, _ := pr.Active(); active != "" {
+		t.Errorf("expected no profile to be active, received: %q", active)
+	}
+}
+
+func TestDeleteProfile_Sad(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		input    string
+		expected error
+	}{
+		"return an error if the profile doesn't exist": {
+			name:     "missing",
+			input:    "Y\n",
+			expected: service.ErrProfileNotFound,
+		},
+		"return an error if the user denies delete": {
+			name:     "hardcore",
+			input:    "n\n",
+			expected: service.ErrAborted,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, pr, _ := newTestProfileService(t, &mock.ModsRepo{}, &mock.Manager{}, test.input)
+			if _, err := pr.Save(profile.Profile{Name: "hardcore"}); err != nil {
+				t.Fatalf("unexpected error saving test profile, received: %+v", err)
+			}
+
+			err := ps.DeleteProfile(test.name)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestExportImportProfile_Happy(t *testing.T) {
+	installed := []mod.Mod{{Namespace: "Azumatt", Name: "Sleepover", Version: "1.0.1"}}
+	r := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return installed, nil
+		},
+		UpsertModFunc: func(m mod.Mod) error {
+			return nil
+		},
+	}
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{Namespace: framework.BepInExNamespace, Name: framework.BepInEx, Version: "5.4.2202"}, nil
+		},
+	}
+	fm := &mock.Manager{
+		IsCachedFunc: func(fullName string) bool {
+			return false
+		},
+		CacheModFunc: func(url, fullName string) error {
+			return nil
+		},
+		InstallModFunc: func(url, fullName string) (string, error) {
+			return "/BepInEx/plugins/" + fullName, nil
+		},
+	}
+
+	ps, pr, config := newTestProfileServiceWithFrameworks(t, r, fr, fm, "Y\n")
+	writeProfileConfig(t, config, "Azumatt.Sleepover.cfg")
+	path := filepath.Join(t.TempDir(), "server.r2z")
+
+	e, err := ps.ExportProfile(path, "")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if e.ProfileName != "warden" || len(e.Mods) != 2 || e.Mods[0].Name != "denikson-BepInExPack_Valheim" {
+		t.Errorf("expected BepInEx and the installed mod to be exported, received: %+v", e)
+	}
+
+	// Importing the export again on a server without any mods installs them
+	installed = []mod.Mod{}
+	p, err := ps.ImportProfile(path, "players")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if len(p.Mods) != 1 || p.Mods[0].FullName() != "Azumatt-Sleepover-1.0.1" {
+		t.Errorf("expected only the mod to be imported, received: %+v", p.Mods)
+	}
+	if active, _ := pr.Active(); active != "players" {
+		t.Errorf("expected the imported profile to be active, received: %q", active)
+	}
+}
+
+func TestImportProfile_Sad(t *testing.T) {
+	tests := map[string]struct {
+		profileName string
+		name        string
+		path        string
+		input       string
+		cacheErr    error
+		expected    error
+	}{
+		"return an error if the file doesn't exist": {
+			path:     "missing.r2z",
+			expected: service.ErrProfileExportNotFound,
+		},
+		"return an error if the r2modman profile's name can't be used": {
+			profileName: "My Server",
+			expected:    service.ErrInvalidProfileName,
+		},
+		"return an error if the profile already exists": {
+			profileName: "existing",
+			input:       "Y\n",
+			expected:    service.ErrProfileAlreadyExists,
+		},
+		"return an error without keeping the profile if the user denies the switch": {
+			profileName: "players",
+			input:       "n\n",
+			expected:    service.ErrAborted,
+		},
+		"return an error without keeping the profile if the switch fails": {
+			profileName: "players",
+			input:       "Y\n",
+			cacheErr:    errors.New("download failed"),
+			expected:    service.ErrUnableToSwitchProfile,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &mock.ModsRepo{
+				ListModsFunc: func() ([]mod.Mod, error) {
+					return []mod.Mod{}, nil
+				},
+			}
+			fm := &mock.Manager{
+				IsCachedFunc: func(fullName string) bool {
+					return false
+				},
+				CacheModFunc: func(url, fullName string) error {
+					return test.cacheErr
+				},
+			}
+			ps, pr, _ := newTestProfileService(t, r, fm, test.input)
+			if _, err := pr.Save(profile.Profile{Name: "existing"}); err != nil {
+				t.Fatalf("unexpected error saving test profile, received: %+v", err)
+			}
+			dir := t.TempDir()
+			path := filepath.Join(dir, "server.r2z")
+			e := profile.Export{
+				ProfileName: test.profileName,
+				Mods:        []profile.ExportMod{{Name: "Azumatt-Sleepover", Version: profile.ExportVersion{Major: 1, Patch: 1}, Enabled: true}},
+			}
+			if err := pr.Export(path, e); err != nil {
+				t.Fatalf("unexpected error exporting test profile, received: %+v", err)
+			}
+			if test.path != "" {
+				path = filepath.Join(dir, test.path)
+			}
+
+			_, err := ps.ImportProfile(path, test.name)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+			if profiles, _ := pr.List(); len(profiles) != 1 || profiles[0].Name != "existing" {
+				t.Errorf("expected only the existing profile to be kept, received: %+v", profiles)
+			}
+		})
+	}
+}
+
+// newTestProfileService creates a Profiles service that stores its profiles in a temporary
+// directory, and returns the storage along with the BepInEx config directory it saves
+func newTestProfileService(t *testing.T, r *mock.ModsRepo, fm *mock.Manager, input string) (service.Profiles, file.Profiles, string) {
+	return newTestProfileServiceWithFrameworks(t, r, &mock.FrameworksRepo{}, fm, input)
+}
+
+// newTestProfileServiceWithFrameworks is the same as newTestProfileService, but uses the given
+// frameworks repo
+func newTestProfileServiceWithFrameworks(t *testing.T, r *mock.ModsRepo, fr *mock.FrameworksRepo, fm *mock.Manager, input string) (service.Profiles, file.Profiles, string) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "BepInEx", "config")
+	if err := os.MkdirAll(config, os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating test config directory, received: %+v", err)
+	}
+
+	pr := file.NewProfiles(filepath.Join(dir, "profiles"), config, t.TempDir())
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	sources := source.Sources{
+		source.Thunderstore: newTestProfileSource("https://thunderstore.example.com"),
+		source.Nexus:        newTestProfileSource("https://nexus.example.com"),
+	}
+	return service.NewProfileService(r, fr, fm, pr, sources, ss, service.NewConfirmer(strings.NewReader(input))), pr, config
+}
+
+// newTestProfileSource creates a source that has every release, downloaded from the given host
+func newTestProfileSource(host string) *mock.Source {
+	return &mock.Source{
+		GetReleaseFunc: func(namespace, name, version string) (source.Release, error) {
+			url := fmt.Sprintf("%s/%s/%s/%s", host, namespace, name, version)
+			return source.Release{Namespace: namespace, Name: name, Version: version, DownloadURL: url}, nil
+		},
+		DownloadURLFunc: func(release source.Release) (string, error) {
+			return release.DownloadURL, nil
+		},
+	}
+}
+
+// writeProfileConfig creates an empty BepInEx config file
+func writeProfileConfig(t *testing.T, config, name string) {
+	if err := os.WriteFile(filepath.Join(config, name), []byte{}, 0644); err != nil {
+		t.Fatalf("unexpected error creating test config file, received: %+v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"io"
+	"os"
+)
+
+// progress is where services print what they're doing as they go, along with confirmation
+// prompts. It's stdout unless a command needs stdout for its results.
+var progress io.Writer = os.Stdout
+
+// SetProgressOutput changes where services print their progress and prompts
+func SetProgressOutput(w io.Writer) {
+	progress = w
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"warden/internal/config"
+	"warden/internal/data/repo"
+	"warden/internal/domain/schedule"
+)
+
+var (
+	ErrInvalidSchedule      = errors.New("invalid job schedule")
+	ErrJobNotFound          = errors.New("scheduled job does not exist")
+	ErrNoJobsScheduled      = errors.New("no jobs are scheduled")
+	ErrJobFailed            = errors.New("scheduled job failed")
+	ErrUnableToRecordJobRun = errors.New("unable to record job run")
+	ErrUnableToListJobRuns  = errors.New("unable to list job history")
+
+	// Returned by a job that had nothing to do, e.g. restarting a server that isn't running
+	errJobSkipped = errors.New("job skipped")
+)
+
+// Exposes all methods for running maintenance jobs on a schedule.
+type Scheduler interface {
+	// Returns every job that has a schedule, along with when it next runs after the given time
+	Jobs(now time.Time) ([]schedule.Job, error)
+
+	// Runs a job right away, whether or not it has a schedule, and records it in the job history
+	RunJob(ctx context.Context, name string) (schedule.Run, error)
+
+	// Returns the most recent job runs, newest first. A limit of zero returns every run.
+	History(limit int) ([]schedule.Run, error)
+
+	// Runs every scheduled job whenever it's due, until the context is cancelled. Jobs are
+	// scheduled on purpose, so any confirmation they need is answered with yes.
+	Daemon(ctx context.Context) error
+}
+
+type schedulerService struct {
+	config.Config
+
+	jobs repo.Jobs
+	ws   World
+	ms   Mod
+	ss   Server
+	c    Confirmer
+}
+
+func NewSchedulerService(cfg config.Config, jobs repo.Jobs, ws World, ms Mod, ss Server, c Confirmer) Scheduler {
+	return &schedulerService{
+		Config: cfg,
+		jobs:   jobs,
+		ws:     ws,
+		ms:     ms,
+		ss:     ss,
+		c:      c,
+	}
+}
+
+func (s *schedulerService) Jobs(now time.Time) ([]schedule.Job, error) {
+	specs := map[string]string{
+		schedule.Backup:  s.ScheduleBackup,
+		schedule.Update:  s.ScheduleUpdate,
+		schedule.Restart: s.ScheduleRestart,
+	}
+
+	jobs := []schedule.Job{}
+	for _, name := range schedule.Jobs {
+		if specs[name] == "" {
+			continue
+		}
+		e, err := schedule.Parse(specs[name])
+		if err != nil {
+			return jobs, fmt.Errorf("%w: %s: %w", ErrInvalidSchedule, name, err)
+		}
+		jobs = append(jobs, schedule.Job{
+			Name:       name,
+			Expression: e,
+			Next:       e.Next(now),
+		})
+	}
+	return jobs, nil
+}
+
+func (s *schedulerService) RunJob(ctx context.Context, name string) (schedule.Run, error) {
+	if !slices.Contains(schedule.Jobs, name) {
+		return schedule.Run{}, ErrJobNotFound
+	}
+
+	run := schedule.Run{
+		Job:       name,
+		StartedAt: time.Now(),
+		Status:    schedule.Succeeded,
+	}
+	err := s.job(ctx, name)
+	run.FinishedAt = time.Now()
+
+	if errors.Is(err, errJobSkipped) {
+		run.Status = schedule.Skipped
+		run.Message = strings.TrimPrefix(err.Error(), errJobSkipped.Error()+": ")
+		err = nil
+	} else if err != nil {
+		run.Status = schedule.Failed
+		run.Message = err.Error()
+		err = fmt.Errorf("%w: %w", ErrJobFailed, err)
+	}
+
+	if recordErr := s.jobs.InsertJobRun(run); recordErr != nil {
+		return run, errors.Join(err, fmt.Errorf("%w: %w", ErrUnableToRecordJobRun, recordErr))
+	}
+	return run, err
+}
+
+func (s *schedulerService) History(limit int) ([]schedule.Run, error) {
+	runs, err := s.jobs.ListJobRuns(limit)
+	if err != nil {
+		return runs, fmt.Errorf("%w: %w", ErrUnableToListJobRuns, err)
+	}
+	return runs, nil
+}
+
+func (s *schedulerService) Daemon(ctx context.Context) error {
+	jobs, err := s.Jobs(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return ErrNoJobsScheduled
+	}
+	s.c.SetMode(AssumeYes)
+
+	for _, j := range jobs {
+		fmt.Fprintf(progress, "... %s scheduled for %s, next run at %s ...\n", j.Name, j.Expression, j.Next.Format(time.DateTime))
+	}
+
+	for {
+		next := earliest(jobs)
+		if next.IsZero() {
+			return fmt.Errorf("%w: no schedule matches a future time", ErrNoJobsScheduled)
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		// Jobs due at the same time run one after the other, in the order they're listed in
+		for _, j := range jobs {
+			if !j.Next.Equal(next) {
+				continue
+			}
+			fmt.Fprintf(progress, "... running %s job ...\n", j.Name)
+			run, err := s.RunJob(ctx, j.Name)
+			if err != nil {
+				fmt.Fprintf(progress, "... %s job %s: %s ...\n", j.Name, run.Status, err)
+			} else {
+				fmt.Fprintf(progress, "... %s job %s ...\n", j.Name, run.Status)
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+
+		// Scheduled from now rather than from when the jobs were due, so a run that took longer
+		// than the gap between runs doesn't cause a burst of missed ones
+		if jobs, err = s.Jobs(time.Now()); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *schedulerService) job(ctx context.Context, name string) error {
+	switch name {
+	case schedule.Backup:
+		_, err := s.ws.BackupWorlds()
+		return err
+	case schedule.Update:
+		// Same as the update command, worlds are backed up first in case the new mods break them
+		if _, err := s.ws.BackupWorlds(); err != nil {
+			return err
+		}
+		return s.ms.UpdateAllMods()
+	case schedule.Restart:
+		return s.restart(ctx)
+	default:
+		return ErrJobNotFound
+	}
+}
+
+// restart announces the restart, waits for the configured delay and then restarts the server the
+// same way it was last started
+func (s *schedulerService) restart(ctx context.Context) error {
+	status, err := s.ss.Status()
+	if err != nil {
+		return err
+	}
+	if !status.Running {
+		return fmt.Errorf("%w: %w", errJobSkipped, ErrServerNotRunning)
+	}
+
+	if s.ScheduleRestartDelay > 0 {
+		fmt.Fprintf(progress, "... server restarting in %s ...\n", s.ScheduleRestartDelay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: cancelled before the server was restarted", errJobSkipped)
+		case <-time.After(s.ScheduleRestartDelay):
+		}
+	}
+
+	_, err = s.ss.Restart("")
+	return err
+}
+
+// earliest returns the soonest time any of the jobs runs next
+func earliest(jobs []schedule.Job) time.Time {
+	next := time.Time{}
+	for _, j := range jobs {
+		if !j.Next.IsZero() && (next.IsZero() || j.Next.Before(next)) {
+			next = j.Next
+		}
+	}
+	return next
+}
@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/domain/game"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/schedule"
+	"warden/internal/domain/world"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestJobs_Happy(t *testing.T) {
+	now := time.Date(2024, time.March, 2, 18, 30, 0, 0, time.UTC)
+	cfg := config.Config{ScheduleBackup: "0 4 * * *", ScheduleRestart: "@weekly"}
+	s, _ := newTestScheduler(t, cfg, &mock.Worlds{}, &mock.ModsRepo{})
+
+	jobs, err := s.Jobs(now)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	expected := []schedule.Job{
+		{Name: schedule.Backup, Next: time.Date(2024, time.March, 3, 4, 0, 0, 0, time.UTC)},
+		{Name: schedule.Restart, Next: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(jobs) != len(expected) {
+		t.Fatalf("expected jobs: %+v, received: %+v", expected, jobs)
+	}
+	for i, j := range jobs {
+		if j.Name != expected[i].Name || !j.Next.Equal(expected[i].Next) {
+			t.Errorf("expected job: %+v, received: %+v", expected[i], j)
+		}
+	}
+}
+
+func TestJobs_Sad(t *testing.T) {
+	s, _ := newTestScheduler(t, config.Config{ScheduleUpdate: "every sunday"}, &mock.Worlds{}, &mock.ModsRepo{})
+
+	_, err := s.Jobs(time.Now())
+	if !errors.Is(err, service.ErrInvalidSchedule) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrInvalidSchedule, err)
+	}
+}
+
+func TestRunJob_Happy(t *testing.T) {
+	tests := map[string]struct {
+		job      string
+		expected string
+	}{
+		"back up worlds": {
+			job:      schedule.Backup,
+			expected: schedule.Succeeded,
+		},
+		"back up worlds and update every mod": {
+			job:      schedule.Update,
+			expected: schedule.Succeeded,
+		},
+		"skip restarting a server that isn't running": {
+			job:      schedule.Restart,
+			expected: schedule.Skipped,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, recorded := newTestScheduler(t, config.Config{}, newTestWorlds(nil), &mock.ModsRepo{
+				ListModsFunc: func() ([]mod.Mod, error) {
+					return []mod.Mod{}, nil
+				},
+			})
+
+			run, err := s.RunJob(context.Background(), tt.job)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if run.Job != tt.job || run.Status != tt.expected {
+				t.Errorf("expected a %s run of %s, received: %+v", tt.expected, tt.job, run)
+			}
+			if len(*recorded) != 1 || !(*recorded)[0].Equals(&run) {
+				t.Errorf("expected run to be recorded: %+v, received: %+v", run, *recorded)
+			}
+		})
+	}
+}
+
+func TestRunJob_RestartSupervised(t *testing.T) {
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	before := superviseTestServer(t, ss)
+	s, _ := newTestSchedulerWithServer(t, config.Config{}, newTestWorlds(nil), &mock.ModsRepo{}, ss)
+
+	run, err := s.RunJob(context.Background(), schedule.Restart)
+	if err != nil || run.Status != schedule.Succeeded {
+		t.Fatalf("expected a successful restart, received: %+v, error: %+v", run, err)
+	}
+	status, err := ss.Status()
+	if err != nil || status.Process.PID == before.PID || !status.Process.Supervised() {
+		t.Errorf("expected the supervisor to restart the server, received: %+v, error: %+v", status, err)
+	}
+}
+
+func TestRunJob_Sad(t *testing.T) {
+	tests := map[string]struct {
+		job      string
+		w        *mock.Worlds
+		expected error
+	}{
+		"return an error if the job doesn't exist": {
+			job:      "defragment",
+			w:        newTestWorlds(nil),
+			expected: service.ErrJobNotFound,
+		},
+		"return an error and record the run if the job fails": {
+			job:      schedule.Backup,
+			w:        newTestWorlds(errors.New("disk full")),
+			expected: service.ErrJobFailed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, recorded := newTestScheduler(t, config.Config{}, tt.w, &mock.ModsRepo{})
+
+			run, err := s.RunJob(context.Background(), tt.job)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+			if errors.Is(err, service.ErrJobFailed) && (run.Status != schedule.Failed || len(*recorded) != 1) {
+				t.Errorf("expected a failed run to be recorded, received: %+v, recorded: %+v", run, *recorded)
+			}
+		})
+	}
+}
+
+func TestDaemon_Happy(t *testing.T) {
+	s, recorded := newTestScheduler(t, config.Config{ScheduleBackup: "0 4 * * *"}, newTestWorlds(nil), &mock.ModsRepo{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := s.Daemon(ctx); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(*recorded) != 0 {
+		t.Errorf("expected no jobs to run before they're due, received: %+v", *recorded)
+	}
+}
+
+func TestDaemon_Sad(t *testing.T) {
+	tests := map[string]struct {
+		cfg      config.Config
+		expected error
+	}{
+		"return an error if no jobs are scheduled": {
+			cfg:      config.Config{},
+			expected: service.ErrNoJobsScheduled,
+		},
+		"return an error if a schedule is invalid": {
+			cfg:      config.Config{ScheduleBackup: "0 25 * * *"},
+			expected: service.ErrInvalidSchedule,
+		},
+		"return an error if no schedule ever matches": {
+			cfg:      config.Config{ScheduleBackup: "0 0 31 2 *"},
+			expected: service.ErrNoJobsScheduled,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _ := newTestScheduler(t, tt.cfg, &mock.Worlds{}, &mock.ModsRepo{})
+
+			err := s.Daemon(context.Background())
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// newTestScheduler creates a Scheduler whose job runs are recorded in the returned slice.
+// Confirmations are answered with yes.
+func newTestScheduler(t *testing.T, cfg config.Config, w *mock.Worlds, mr *mock.ModsRepo) (service.Scheduler, *[]schedule.Run) {
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	return newTestSchedulerWithServer(t, cfg, w, mr, ss)
+}
+
+// newTestSchedulerWithServer is the same as newTestScheduler, but restarts the given server
+func newTestSchedulerWithServer(t *testing.T, cfg config.Config, w *mock.Worlds, mr *mock.ModsRepo, ss service.Server) (service.Scheduler, *[]schedule.Run) {
+	recorded := []schedule.Run{}
+	jobs := &mock.JobsRepo{
+		InsertJobRunFunc: func(r schedule.Run) error {
+			recorded = append(recorded, r)
+			return nil
+		},
+	}
+
+	c := service.NewConfirmer(&io.LimitedReader{})
+	c.SetMode(service.AssumeYes)
+	ws := service.NewWorldService(w, world.Retention{}, ss, c)
+	fm := &mock.Manager{
+		GameBuildFunc: func() (game.Build, error) {
+			return game.Build{}, file.ErrGameBuildNotFound
+		},
+	}
+	ms := service.NewModService(mr, fm, thunderstoreSources(&mock.Thunderstore{}), c)
+	return service.NewSchedulerService(cfg, jobs, ws, ms, ss, c), &recorded
+}
+
+// newTestWorlds creates a world store with a single world, whose backups fail with the given error
+func newTestWorlds(backupErr error) *mock.Worlds {
+	return &mock.Worlds{
+		ListWorldsFunc: func() ([]string, error) {
+			return []string{"Dedicated"}, nil
+		},
+		BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+			return world.Snapshot{World: name, CreatedAt: createdAt}, backupErr
+		},
+		ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+			return []world.Snapshot{}, nil
+		},
+	}
+}
@@ -1,58 +1,341 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/server"
 )
 
 const (
-	vanilla = "vanilla"
-	modded  = "modded"
+	vanilla = server.Vanilla
+	modded  = server.Modded
 
-	linuxStartScript   = "start_server.sh"
-	macOSStartScript   = "start_server.command"
-	windowsStartScript = "start_headless_server.bat"
-	moddedStartScript  = "start_server_bepinex.sh"
+	// How long a new server has to stay up before it counts as started
+	startUpGracePeriod = 500 * time.Millisecond
+
+	// How long to wait for the server to save the world and exit after being interrupted
+	StopTimeout = 2 * time.Minute
+
+	stopPollInterval = 250 * time.Millisecond
+
+	// The longest the supervisor waits before restarting a crashed server
+	maxSuperviseBackoff = 5 * time.Minute
 )
 
 var (
-	ErrInvalidGameType   = errors.New("invalid game server type")
-	ErrServerStartFailed = errors.New("unable to start game server")
+	ErrInvalidGameType       = errors.New("invalid game server type")
+	ErrServerStartFailed     = errors.New("unable to start game server")
+	ErrServerAlreadyRunning  = errors.New("game server is already running")
+	ErrServerNotRunning      = errors.New("game server is not running")
+	ErrServerStopFailed      = errors.New("unable to stop game server")
+	ErrServerStopTimeout     = errors.New("timed out waiting for game server to stop")
+	ErrServerStatusFailed    = errors.New("unable to check game server status")
+	ErrServerSupervised      = errors.New("game server is supervised")
+	ErrServerCrashLoop       = errors.New("game server keeps crashing")
+	ErrInvalidServerSettings = errors.New("invalid game server settings")
+	ErrUnsupportedPlatform   = errors.New("Valheim has no dedicated server for this platform")
 )
 
 // Exposes all methods for interacting with the Valheim game server.
 type Server interface {
-	Start(config string) (string, error)
+	// Starts the game server in the background and records its PID
+	Start(gameType string) (server.Process, error)
+
+	// Interrupts the running game server so it saves the world, then waits for it to exit
+	Stop() error
+
+	// Stops the game server if it's running, then starts it again. If no game type is given,
+	// the server is restarted with the one it was last started with. A supervised server is
+	// restarted by its supervisor instead, so it stays supervised, and keeps its game type.
+	Restart(gameType string) (server.Process, error)
+
+	// Returns whether the game server is running, and how it was started
+	Status() (server.Status, error)
+
+	// Runs the game server in the foreground until the context is cancelled, restarting it
+	// whenever it crashes. Gives up if it crashes too often within the configured crash window.
+	Supervise(ctx context.Context, gameType string) error
+
 	IsValidGameType(config string) bool
 }
 
 type serverService struct {
 	config.Config
+
+	fr      repo.Frameworks
+	pids    file.PIDFile
+	logFile string
 }
 
-func NewServerService(cfg config.Config) Server {
+// NewServerService creates a Server that records the game server it starts in the PID file, and
+// writes everything the server prints to the log file
+func NewServerService(cfg config.Config, fr repo.Frameworks, pids file.PIDFile, logFile string) Server {
 	return &serverService{
-		cfg,
+		Config:  cfg,
+		fr:      fr,
+		pids:    pids,
+		logFile: logFile,
+	}
+}
+
+func (s *serverService) Start(gameType string) (server.Process, error) {
+	gameType = normalize(gameType)
+	if !s.IsValidGameType(gameType) {
+		return server.Process{}, ErrInvalidGameType
+	}
+
+	if p, err := s.pids.Read(); err == nil && isRunning(p.PID) {
+		return p, ErrServerAlreadyRunning
+	}
+
+	cmd, err := s.serverCommand(gameType)
+	if err != nil {
+		return server.Process{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.logFile), os.ModePerm); err != nil {
+		return server.Process{}, fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+	log, err := os.OpenFile(s.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return server.Process{}, fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+	defer log.Close()
+
+	if err := s.launch(cmd, log); err != nil {
+		return server.Process{}, err
+	}
+
+	// Watch the server for a moment, so a broken start script is reported instead of
+	// leaving a stale PID file behind
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	select {
+	case err := <-exited:
+		if err == nil {
+			err = errors.New("server exited during start-up")
+		}
+		return server.Process{}, fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	case <-time.After(startUpGracePeriod):
+	}
+
+	p := server.Process{
+		PID:       cmd.Process.Pid,
+		GameType:  gameType,
+		StartedAt: time.Now().Truncate(time.Second),
+		LogFile:   s.logFile,
+	}
+	if err := s.pids.Write(p); err != nil {
+		return p, fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+	return p, nil
+}
+
+func (s *serverService) Stop() error {
+	p, err := s.running()
+	if err != nil {
+		return err
+	}
+
+	// A supervisor would restart the server, so it's asked to shut the server down instead
+	supervised := p.Supervised() && isRunning(p.SupervisorPID)
+
+	fmt.Fprintln(progress, "... waiting for the server to save and shut down ...")
+	if supervised {
+		err = terminate(p.SupervisorPID)
+	} else {
+		err = interrupt(p.PID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrServerStopFailed, err)
+	}
+
+	deadline := time.Now().Add(StopTimeout)
+	for isRunning(p.PID) || (supervised && isRunning(p.SupervisorPID)) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %w", ErrServerStopFailed, ErrServerStopTimeout)
+		}
+		time.Sleep(stopPollInterval)
+	}
+
+	if err := s.pids.Remove(); err != nil {
+		return fmt.Errorf("%w: %w", ErrServerStopFailed, err)
+	}
+	return nil
+}
+
+func (s *serverService) Restart(gameType string) (server.Process, error) {
+	p, err := s.running()
+	if err != nil && !errors.Is(err, ErrServerNotRunning) {
+		return server.Process{}, err
+	}
+	if err == nil && p.Supervised() && isRunning(p.SupervisorPID) {
+		return s.restartSupervised(p, gameType)
+	}
+	if err == nil {
+		if err := s.Stop(); err != nil {
+			return server.Process{}, err
+		}
 	}
+
+	if normalize(gameType) == "" {
+		gameType = p.GameType
+	}
+	return s.Start(gameType)
+}
+
+// restartSupervised asks a supervisor to restart the server it's running, then waits for it to
+// record the new server
+func (s *serverService) restartSupervised(p server.Process, gameType string) (server.Process, error) {
+	if gameType = normalize(gameType); gameType != "" && gameType != p.GameType {
+		return p, fmt.Errorf("%w: it can only be restarted as %s", ErrServerSupervised, p.GameType)
+	}
+
+	fmt.Fprintln(progress, "... asking the supervisor to restart the server ...")
+	if err := requestRestart(p.SupervisorPID); err != nil {
+		return p, err
+	}
+
+	deadline := time.Now().Add(StopTimeout + startUpGracePeriod)
+	for time.Now().Before(deadline) {
+		restarted, err := s.pids.Read()
+		if err == nil && restarted.PID != p.PID && isRunning(restarted.PID) {
+			return restarted, nil
+		}
+		if !isRunning(p.SupervisorPID) {
+			return p, fmt.Errorf("%w: the supervisor exited", ErrServerStartFailed)
+		}
+		time.Sleep(stopPollInterval)
+	}
+	return p, fmt.Errorf("%w: %w", ErrServerStopFailed, ErrServerStopTimeout)
+}
+
+func (s *serverService) Status() (server.Status, error) {
+	p, err := s.running()
+	if errors.Is(err, ErrServerNotRunning) {
+		return server.Status{}, nil
+	}
+	if err != nil {
+		return server.Status{}, fmt.Errorf("%w: %w", ErrServerStatusFailed, err)
+	}
+
+	status := server.Status{
+		Running: true,
+		Process: p,
+	}
+	if p.GameType == modded {
+		f, err := s.fr.GetFramework(framework.BepInEx)
+		if err != nil && !errors.Is(err, repo.ErrFrameworkFetchNoResults) {
+			return status, fmt.Errorf("%w: %w", ErrServerStatusFailed, err)
+		}
+		status.BepInExVersion = f.Version
+	}
+	return status, nil
 }
 
-func (s *serverService) Start(gameType string) (string, error) {
+func (s *serverService) Supervise(ctx context.Context, gameType string) error {
 	gameType = normalize(gameType)
 	if !s.IsValidGameType(gameType) {
-		return "", ErrInvalidGameType
+		return ErrInvalidGameType
+	}
+	if _, err := s.running(); err == nil {
+		return ErrServerAlreadyRunning
 	}
 
-	cmd := exec.Command("sh", s.getStartScript(gameType))
+	// Check the settings and installation once up front, rather than on the first launch
+	if _, err := s.serverCommand(gameType); err != nil {
+		return err
+	}
 
-	// Capture the output of the script
-	output, err := cmd.CombinedOutput()
+	log, err := file.NewRotatingLog(s.logFile, int64(s.LogMaxSize)*1024*1024, s.LogMaxFiles)
 	if err != nil {
-		return string(output), ErrServerStartFailed
+		return fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+	defer log.Close()
+	defer s.pids.Remove()
+
+	backoff := s.SuperviseBackoff
+	crashes := []time.Time{}
+
+	// Restarts are requested by other Warden commands, e.g. a scheduled restart
+	restart := make(chan os.Signal, 1)
+	notifyRestart(restart)
+	defer signal.Stop(restart)
+
+	for {
+		cmd, err := s.serverCommand(gameType)
+		if err != nil {
+			return err
+		}
+		if err := s.launch(cmd, log); err != nil {
+			return err
+		}
+		p := server.Process{
+			PID:           cmd.Process.Pid,
+			GameType:      gameType,
+			StartedAt:     time.Now().Truncate(time.Second),
+			LogFile:       s.logFile,
+			SupervisorPID: os.Getpid(),
+		}
+		if err := s.pids.Write(p); err != nil {
+			interrupt(p.PID)
+			return fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+		}
+		fmt.Fprintf(progress, "... %s server started (PID %d), logging to %s ...\n", gameType, p.PID, s.logFile)
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+		}()
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(progress, "... waiting for the server to save and shut down ...")
+			return shutDown(p.PID, exited)
+		case <-restart:
+			fmt.Fprintln(progress, "... restarting the server, waiting for it to save and shut down ...")
+			if err := shutDown(p.PID, exited); err != nil {
+				return err
+			}
+		case err := <-exited:
+			if err == nil {
+				fmt.Fprintln(progress, "... server exited normally, no longer supervising ...")
+				return nil
+			}
+
+			now := time.Now()
+			if now.Sub(p.StartedAt) > s.SuperviseCrashWindow {
+				// The server was up for a while, so this isn't part of a crash loop
+				backoff = s.SuperviseBackoff
+			}
+			crashes = append(recentCrashes(crashes, now.Add(-s.SuperviseCrashWindow)), now)
+			if s.SuperviseMaxCrashes > 0 && len(crashes) >= s.SuperviseMaxCrashes {
+				return fmt.Errorf("%w: crashed %d times in %s: %w", ErrServerCrashLoop, len(crashes), s.SuperviseCrashWindow, err)
+			}
+
+			fmt.Fprintf(progress, "... server crashed (%s), restarting in %s ...\n", err, backoff)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxSuperviseBackoff)
+		}
 	}
-	return string(output), nil
 }
 
 func (s *serverService) IsValidGameType(config string) bool {
@@ -60,21 +343,60 @@ func (s *serverService) IsValidGameType(config string) bool {
 	return config == vanilla || config == modded
 }
 
-func (s *serverService) getStartScript(gameType string) string {
-	if gameType == modded {
-		return filepath.Join(s.ValheimDirectory, moddedStartScript)
-	} else {
-		switch s.Platform {
-		case config.Linux:
-			return filepath.Join(s.ValheimDirectory, linuxStartScript)
-		case config.MacOS:
-			return filepath.Join(s.ValheimDirectory, macOSStartScript)
-		case config.Windows:
-			return filepath.Join(s.ValheimDirectory, windowsStartScript)
-		default:
-			return ""
+// running returns the game server recorded in the PID file, as long as it's still running. If
+// the server has exited on its own, the stale PID file is removed.
+func (s *serverService) running() (server.Process, error) {
+	p, err := s.pids.Read()
+	if errors.Is(err, file.ErrPIDFileNotFound) {
+		return server.Process{}, ErrServerNotRunning
+	}
+	if err != nil {
+		return server.Process{}, err
+	}
+
+	if !isRunning(p.PID) {
+		if err := s.pids.Remove(); err != nil {
+			return p, err
+		}
+		return p, ErrServerNotRunning
+	}
+	return p, nil
+}
+
+// launch runs the server in its own process group, sending everything it prints to out
+func (s *serverService) launch(cmd *exec.Cmd, out io.Writer) error {
+	cmd.Stdout = out
+	cmd.Stderr = out
+	detach(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%w: %w", ErrServerStartFailed, err)
+	}
+	return nil
+}
+
+// shutDown interrupts a server started by this process and waits for it to exit
+func shutDown(pid int, exited <-chan error) error {
+	if err := interrupt(pid); err != nil {
+		return fmt.Errorf("%w: %w", ErrServerStopFailed, err)
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(StopTimeout):
+		return fmt.Errorf("%w: %w", ErrServerStopFailed, ErrServerStopTimeout)
+	}
+}
+
+// recentCrashes drops every crash that happened before the given time
+func recentCrashes(crashes []time.Time, since time.Time) []time.Time {
+	recent := []time.Time{}
+	for _, c := range crashes {
+		if c.After(since) {
+			recent = append(recent, c)
 		}
 	}
+	return recent
 }
 
 func normalize(s string) string {
@@ -1,41 +1,73 @@
 package service_test
 
 import (
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/server"
 	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const (
+	testStartScript       = "echo \"Starting Vanilla Server\"\nsleep 30\n"
+	testModdedStartScript = "echo \"Starting Modded Server\"\nsleep 30\n"
 )
 
 func TestStart_Happy(t *testing.T) {
 	tests := map[string]struct {
 		gameType string
+		script   string
 		expected string
 	}{
 		"successfully starts vanilla game server": {
 			gameType: "vanilla",
+			script:   testStartScript,
 			expected: "Starting Vanilla Server",
 		},
 		"successfully starts modded game server": {
 			gameType: "modded",
+			script:   testStartScript,
 			expected: "Starting Modded Server",
 		},
+		"pass the configured settings to the game server": {
+			gameType: "vanilla",
+			script:   "echo \"$@\"\nsleep 30\n",
+			expected: "-nographics -batchmode -name Test server -port 2456 -world Dedicated -public 0",
+		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			cfg := config.Config{
-				ValheimDirectory: "../test/data",
-				Platform:         config.Linux,
-			}
-			ss := service.NewServerService(cfg)
+			ss, pids := newTestServerService(t, tt.script, testModdedStartScript, &mock.FrameworksRepo{})
 
-			output, err := ss.Start(tt.gameType)
+			p, err := ss.Start(tt.gameType)
 			if err != nil {
 				t.Errorf("unexpected error, received: %+v", err)
 			}
-			if strings.TrimSpace(output) != tt.expected {
+			if p.PID <= 0 || p.GameType != tt.gameType {
+				t.Errorf("expected a running %s server, received: %+v", tt.gameType, p)
+			}
+
+			recorded, err := pids.Read()
+			if err != nil || !recorded.Equals(&p) {
+				t.Errorf("expected PID file to record: %+v, received: %+v, error: %+v", p, recorded, err)
+			}
+
+			output, err := os.ReadFile(p.LogFile)
+			if err != nil {
+				t.Errorf("unexpected error reading server log, received: %+v", err)
+			}
+			// The fake BepInEx install can't actually be preloaded, so the loader may complain
+			if !strings.Contains(string(output), tt.expected) {
 				t.Errorf("expected output: %s, received: %s", tt.expected, output)
 			}
 		})
@@ -43,10 +75,412 @@ func TestStart_Happy(t *testing.T) {
 }
 
 func TestStart_Sad(t *testing.T) {
-	ss := service.NewServerService(config.Config{})
-	_, err := ss.Start("niaudbiwabdiu dd")
-	if !errors.Is(err, service.ErrInvalidGameType) {
-		t.Errorf("expected error: %+v, received: %+v", service.ErrInvalidGameType, err)
+	tests := map[string]struct {
+		gameType string
+		setUp    func(t *testing.T) (service.Server, file.PIDFile)
+		expected error
+	}{
+		"return an error if the game type is invalid": {
+			gameType: "niaudbiwabdiu dd",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				return newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+			},
+			expected: service.ErrInvalidGameType,
+		},
+		"return an error if the server settings are invalid": {
+			gameType: "vanilla",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				ss, pids, _ := newTestServerServiceWithConfig(t, config.Config{ServerPassword: "abc"}, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+				return ss, pids
+			},
+			expected: service.ErrInvalidServerSettings,
+		},
+		"return an error if the server binary doesn't exist": {
+			gameType: "vanilla",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				pids := file.NewPIDFile(filepath.Join(t.TempDir(), "warden.pid"))
+				cfg := withTestServerSettings(config.Config{ValheimDirectory: t.TempDir(), Platform: config.Linux})
+				return service.NewServerService(cfg, &mock.FrameworksRepo{}, pids, filepath.Join(t.TempDir(), "server.log")), pids
+			},
+			expected: service.ErrServerStartFailed,
+		},
+		"return an error if the platform has no dedicated server": {
+			gameType: "vanilla",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				pids := file.NewPIDFile(filepath.Join(t.TempDir(), "warden.pid"))
+				cfg := withTestServerSettings(config.Config{ValheimDirectory: t.TempDir(), Platform: config.MacOS})
+				return service.NewServerService(cfg, &mock.FrameworksRepo{}, pids, filepath.Join(t.TempDir(), "server.log")), pids
+			},
+			expected: service.ErrUnsupportedPlatform,
+		},
+		"return an error if a modded server is started without BepInEx": {
+			gameType: "modded",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				dir := t.TempDir()
+				if err := os.WriteFile(filepath.Join(dir, "valheim_server.x86_64"), []byte("#!/bin/sh\nsleep 30\n"), 0755); err != nil {
+					t.Errorf("unexpected error creating test server binary, received: %+v", err)
+				}
+				pids := file.NewPIDFile(filepath.Join(t.TempDir(), "warden.pid"))
+				cfg := withTestServerSettings(config.Config{ValheimDirectory: dir, Platform: config.Linux})
+				return service.NewServerService(cfg, &mock.FrameworksRepo{}, pids, filepath.Join(t.TempDir(), "server.log")), pids
+			},
+			expected: service.ErrServerStartFailed,
+		},
+		"return an error if the server exits during start-up": {
+			gameType: "vanilla",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				return newTestServerService(t, "echo \"Starting Vanilla Server\"\n", testModdedStartScript, &mock.FrameworksRepo{})
+			},
+			expected: service.ErrServerStartFailed,
+		},
+		"return an error if the server is already running": {
+			gameType: "vanilla",
+			setUp: func(t *testing.T) (service.Server, file.PIDFile) {
+				ss, pids := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+				if _, err := ss.Start("modded"); err != nil {
+					t.Errorf("unexpected error starting test server, received: %+v", err)
+				}
+				return ss, pids
+			},
+			expected: service.ErrServerAlreadyRunning,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, _ := tt.setUp(t)
+
+			_, err := ss.Start(tt.gameType)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestStop_Happy(t *testing.T) {
+	ss, pids := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	if _, err := ss.Start("vanilla"); err != nil {
+		t.Errorf("unexpected error starting test server, received: %+v", err)
+	}
+
+	if err := ss.Stop(); err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if _, err := pids.Read(); !errors.Is(err, file.ErrPIDFileNotFound) {
+		t.Errorf("expected PID file to be removed, received: %+v", err)
+	}
+}
+
+func TestStop_Sad(t *testing.T) {
+	tests := map[string]struct {
+		setUp func(t *testing.T, pids file.PIDFile)
+	}{
+		"return an error if the server was never started": {
+			setUp: func(_ *testing.T, _ file.PIDFile) {},
+		},
+		"return an error if the server has already exited": {
+			setUp: func(t *testing.T, pids file.PIDFile) {
+				// PIDs this large are never handed out, so the process can't exist
+				if err := pids.Write(server.Process{PID: 999999999, GameType: "vanilla"}); err != nil {
+					t.Errorf("unexpected error writing PID file, received: %+v", err)
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, pids := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+			tt.setUp(t, pids)
+
+			err := ss.Stop()
+			if !errors.Is(err, service.ErrServerNotRunning) {
+				t.Errorf("expected error: %+v, received: %+v", service.ErrServerNotRunning, err)
+			}
+			if _, err := pids.Read(); !errors.Is(err, file.ErrPIDFileNotFound) {
+				t.Errorf("expected stale PID file to be removed, received: %+v", err)
+			}
+		})
+	}
+}
+
+func TestRestart_Happy(t *testing.T) {
+	tests := map[string]struct {
+		running  bool
+		gameType string
+		expected string
+	}{
+		"restart a running server with the game type it was started with": {
+			running:  true,
+			gameType: "",
+			expected: "modded",
+		},
+		"restart a running server with a different game type": {
+			running:  true,
+			gameType: "vanilla",
+			expected: "vanilla",
+		},
+		"start the server if it isn't running": {
+			running:  false,
+			gameType: "modded",
+			expected: "modded",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+
+			before := server.Process{}
+			if tt.running {
+				p, err := ss.Start("modded")
+				if err != nil {
+					t.Errorf("unexpected error starting test server, received: %+v", err)
+				}
+				before = p
+			}
+
+			p, err := ss.Restart(tt.gameType)
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if p.PID == before.PID || p.GameType != tt.expected {
+				t.Errorf("expected a new %s server, received: %+v", tt.expected, p)
+			}
+		})
+	}
+}
+
+func TestRestart_Supervised(t *testing.T) {
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	before := superviseTestServer(t, ss)
+
+	p, err := ss.Restart("")
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if p.PID == before.PID || p.SupervisorPID != before.SupervisorPID || p.GameType != "vanilla" {
+		t.Errorf("expected the supervisor to start a new server, received: %+v", p)
+	}
+	if status, err := ss.Status(); err != nil || status.Process.PID != p.PID {
+		t.Errorf("expected the new server to be running, received: %+v, error: %+v", status, err)
+	}
+}
+
+func TestRestart_Sad(t *testing.T) {
+	tests := map[string]struct {
+		gameType  string
+		supervise bool
+		expected  error
+	}{
+		"return an error if the server hasn't been started before": {
+			expected: service.ErrInvalidGameType,
+		},
+		"return an error if a supervised server is restarted as another game type": {
+			gameType:  "modded",
+			supervise: true,
+			expected:  service.ErrServerSupervised,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+			if tt.supervise {
+				superviseTestServer(t, ss)
+			}
+
+			_, err := ss.Restart(tt.gameType)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestStatus_Happy(t *testing.T) {
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{Name: name, Version: "5.4.2202"}, nil
+		},
+	}
+
+	tests := map[string]struct {
+		gameType string
+		expected server.Status
+	}{
+		"report a stopped server": {
+			expected: server.Status{},
+		},
+		"report a running vanilla server": {
+			gameType: "vanilla",
+			expected: server.Status{Running: true},
+		},
+		"report a running modded server along with its BepInEx version": {
+			gameType: "modded",
+			expected: server.Status{Running: true, BepInExVersion: "5.4.2202"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, fr)
+			if tt.gameType != "" {
+				if _, err := ss.Start(tt.gameType); err != nil {
+					t.Errorf("unexpected error starting test server, received: %+v", err)
+				}
+			}
+
+			status, err := ss.Status()
+			if err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if status.Running != tt.expected.Running || status.BepInExVersion != tt.expected.BepInExVersion {
+				t.Errorf("expected status: %+v, received: %+v", tt.expected, status)
+			}
+			if status.Running && status.Process.GameType != tt.gameType {
+				t.Errorf("expected game type: %s, received: %s", tt.gameType, status.Process.GameType)
+			}
+		})
+	}
+}
+
+func TestStatus_Sad(t *testing.T) {
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{}, repo.ErrFrameworkFetchFailed
+		},
+	}
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, fr)
+	if _, err := ss.Start("modded"); err != nil {
+		t.Errorf("unexpected error starting test server, received: %+v", err)
+	}
+
+	_, err := ss.Status()
+	if !errors.Is(err, service.ErrServerStatusFailed) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrServerStatusFailed, err)
+	}
+}
+
+func TestSupervise_Happy(t *testing.T) {
+	cfg := config.Config{
+		SuperviseMaxCrashes:  3,
+		SuperviseCrashWindow: time.Minute,
+		SuperviseBackoff:     10 * time.Millisecond,
+		LogMaxSize:           1,
+		LogMaxFiles:          2,
+	}
+
+	tests := map[string]struct {
+		script   string
+		cancel   bool
+		expected string
+	}{
+		"stop supervising once the context is cancelled": {
+			script:   "echo \"Starting Vanilla Server\"\nsleep 30\n",
+			cancel:   true,
+			expected: "Starting Vanilla Server\n",
+		},
+		"stop supervising if the server exits normally": {
+			script:   "echo \"Starting Vanilla Server\"\nexit 0\n",
+			expected: "Starting Vanilla Server\n",
+		},
+		"restart the server after it crashes": {
+			// Crash on the first run only, by leaving a marker file in the Valheim directory
+			script:   "if [ -f crashed ]; then echo \"Restarted\"; sleep 30; fi\necho \"Crashing\"\ntouch crashed\nexit 1\n",
+			cancel:   true,
+			expected: "Crashing\nRestarted\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, pids, logFile := newTestServerServiceWithConfig(t, cfg, tt.script, tt.script, &mock.FrameworksRepo{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				go func() {
+					// Give the supervisor time to restart the server, then check it's recorded
+					time.Sleep(500 * time.Millisecond)
+					p, err := pids.Read()
+					if err != nil || !p.Supervised() {
+						t.Errorf("expected a supervised server in the PID file, received: %+v, error: %+v", p, err)
+					}
+					cancel()
+				}()
+			}
+
+			if err := ss.Supervise(ctx, "vanilla"); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if _, err := pids.Read(); !errors.Is(err, file.ErrPIDFileNotFound) {
+				t.Errorf("expected PID file to be removed, received: %+v", err)
+			}
+
+			status, err := ss.Status()
+			if err != nil || status.Running {
+				t.Errorf("expected server to be stopped, received: %+v, error: %+v", status, err)
+			}
+			output, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Errorf("unexpected error reading server log, received: %+v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("expected output: %q, received: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestSupervise_Sad(t *testing.T) {
+	cfg := config.Config{
+		SuperviseMaxCrashes:  3,
+		SuperviseCrashWindow: time.Minute,
+		SuperviseBackoff:     10 * time.Millisecond,
+	}
+
+	tests := map[string]struct {
+		gameType string
+		script   string
+		setUp    func(t *testing.T, ss service.Server)
+		expected error
+	}{
+		"return an error if the game type is invalid": {
+			gameType: "niaudbiwabdiu dd",
+			script:   testStartScript,
+			setUp:    func(_ *testing.T, _ service.Server) {},
+			expected: service.ErrInvalidGameType,
+		},
+		"return an error if the server is already running": {
+			gameType: "vanilla",
+			script:   testStartScript,
+			setUp: func(t *testing.T, ss service.Server) {
+				if _, err := ss.Start("vanilla"); err != nil {
+					t.Errorf("unexpected error starting test server, received: %+v", err)
+				}
+			},
+			expected: service.ErrServerAlreadyRunning,
+		},
+		"give up if the server keeps crashing": {
+			gameType: "vanilla",
+			script:   "exit 1\n",
+			setUp:    func(_ *testing.T, _ service.Server) {},
+			expected: service.ErrServerCrashLoop,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ss, _, _ := newTestServerServiceWithConfig(t, cfg, tt.script, tt.script, &mock.FrameworksRepo{})
+			tt.setUp(t, ss)
+
+			err := ss.Supervise(context.Background(), tt.gameType)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
 	}
 }
 
@@ -64,7 +498,7 @@ func TestIsValidGameType_Happy(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			ss := service.NewServerService(config.Config{})
+			ss := service.NewServerService(config.Config{}, &mock.FrameworksRepo{}, &mock.PIDFile{}, "")
 			if !ss.IsValidGameType(tt.config) {
 				t.Error("expected true, got false")
 			}
@@ -73,9 +507,90 @@ func TestIsValidGameType_Happy(t *testing.T) {
 }
 
 func TestIsValidGameType_Sad(t *testing.T) {
-	ss := service.NewServerService(config.Config{})
+	ss := service.NewServerService(config.Config{}, &mock.FrameworksRepo{}, &mock.PIDFile{}, "")
 
 	if ss.IsValidGameType("RANDOM TEST VALUE") {
 		t.Error("expected false, got true")
 	}
 }
+
+// newTestServerService creates a Server for a fake Valheim installation, whose server binary runs
+// the given scripts depending on whether BepInEx is loaded. Any server it starts is stopped when
+// the test finishes.
+func newTestServerService(t *testing.T, vanillaScript, moddedScript string, fr repo.Frameworks) (service.Server, file.PIDFile) {
+	ss, pids, _ := newTestServerServiceWithConfig(t, config.Config{}, vanillaScript, moddedScript, fr)
+	return ss, pids
+}
+
+// newTestServerServiceWithConfig is the same as newTestServerService, but uses the given config
+// and also returns where the server's log is written
+func newTestServerServiceWithConfig(t *testing.T, cfg config.Config, vanillaScript, moddedScript string, fr repo.Frameworks) (service.Server, file.PIDFile, string) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"vanilla.sh":                         vanillaScript,
+		"modded.sh":                          moddedScript,
+		"valheim_server.x86_64":              "#!/bin/sh\nif [ \"$DOORSTOP_ENABLE\" = TRUE ]; then exec sh ./modded.sh \"$@\"; fi\nexec sh ./vanilla.sh \"$@\"\n",
+		"BepInEx/core/BepInEx.Preloader.dll": "",
+		"doorstop_libs/libdoorstop_x64.so":   "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Errorf("unexpected error creating test Valheim directory, received: %+v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Errorf("unexpected error creating test Valheim file, received: %+v", err)
+		}
+	}
+
+	pids := file.NewPIDFile(filepath.Join(t.TempDir(), "warden.pid"))
+	cfg = withTestServerSettings(cfg)
+	cfg.ValheimDirectory = dir
+	cfg.Platform = config.Linux
+	logFile := filepath.Join(t.TempDir(), "server.log")
+	ss := service.NewServerService(cfg, fr, pids, logFile)
+
+	t.Cleanup(func() {
+		ss.Stop()
+	})
+	return ss, pids, logFile
+}
+
+// superviseTestServer supervises a vanilla test server in the background until the test finishes,
+// and returns the server once the supervisor has started it
+func superviseTestServer(t *testing.T, ss service.Server) server.Process {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ss.Supervise(ctx, "vanilla")
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := ss.Status()
+		if err == nil && status.Process.Supervised() {
+			return status.Process
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the supervised test server to start")
+	return server.Process{}
+}
+
+// withTestServerSettings fills in valid server settings for anything the config leaves unset
+func withTestServerSettings(cfg config.Config) config.Config {
+	if cfg.ServerName == "" {
+		cfg.ServerName = "Test server"
+	}
+	if cfg.ServerPort == 0 {
+		cfg.ServerPort = config.DefaultServerPort
+	}
+	if cfg.ServerWorld == "" {
+		cfg.ServerWorld = config.DefaultServerWorld
+	}
+	return cfg
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"warden/internal/config"
+	"warden/internal/domain/steamcmd"
+)
+
+var (
+	ErrSteamCMDNotFound       = errors.New("steamcmd not found")
+	ErrSteamCMDFailed         = errors.New("steamcmd failed")
+	ErrServerNotInstalled     = errors.New("game server is not installed")
+	ErrServerAlreadyInstalled = errors.New("game server is already installed")
+)
+
+// Exposes all methods for installing the Valheim dedicated server with SteamCMD. None of them run
+// while the game server is running, since its files would change underneath it.
+type SteamCMD interface {
+	// Downloads the server into the Valheim directory, which mustn't already have one
+	Install(ctx context.Context) error
+
+	// Updates the installed server to the latest version of the configured branch
+	Update(ctx context.Context) error
+
+	// Checks every file of the installed server, downloading any that are missing or changed
+	Validate(ctx context.Context) error
+}
+
+type steamCMDService struct {
+	config.Config
+
+	server Server
+}
+
+func NewSteamCMDService(cfg config.Config, server Server) SteamCMD {
+	return &steamCMDService{
+		Config: cfg,
+		server: server,
+	}
+}
+
+func (s *steamCMDService) Install(ctx context.Context) error {
+	installed, err := s.installed()
+	if err != nil {
+		return err
+	}
+	if installed {
+		return fmt.Errorf("%w: %s", ErrServerAlreadyInstalled, s.ValheimDirectory)
+	}
+	if err := os.MkdirAll(s.ValheimDirectory, os.ModePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDFailed, err)
+	}
+	return s.appUpdate(ctx, false)
+}
+
+func (s *steamCMDService) Update(ctx context.Context) error {
+	installed, err := s.installed()
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return fmt.Errorf("%w: %s", ErrServerNotInstalled, s.ValheimDirectory)
+	}
+	return s.appUpdate(ctx, false)
+}
+
+func (s *steamCMDService) Validate(ctx context.Context) error {
+	installed, err := s.installed()
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return fmt.Errorf("%w: %s", ErrServerNotInstalled, s.ValheimDirectory)
+	}
+	return s.appUpdate(ctx, true)
+}
+
+// installed checks if the Valheim directory has a server executable for this platform
+func (s *steamCMDService) installed() (bool, error) {
+	binary, err := serverBinary(s.Platform)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(filepath.Join(s.ValheimDirectory, binary))
+	return err == nil, nil
+}
+
+// appUpdate runs SteamCMD, printing its progress as it goes. SteamCMD doesn't reliably exit with
+// an error when an update fails, so it's only treated as a success if it says so.
+func (s *steamCMDService) appUpdate(ctx context.Context, validate bool) error {
+	status, err := s.server.Status()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDFailed, err)
+	}
+	if status.Running {
+		return ErrServerAlreadyRunning
+	}
+
+	path, err := exec.LookPath(s.SteamCMDPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDNotFound, err)
+	}
+
+	cmd := exec.CommandContext(ctx, path, steamcmd.Args(s.ValheimDirectory, s.SteamBeta, validate)...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDFailed, err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDFailed, err)
+	}
+
+	success, failure := readSteamCMD(out)
+	err = cmd.Wait()
+	if failure != "" {
+		return fmt.Errorf("%w: %s", ErrSteamCMDFailed, failure)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSteamCMDFailed, err)
+	}
+	if success == "" {
+		return fmt.Errorf("%w: finished without installing the server", ErrSteamCMDFailed)
+	}
+	fmt.Fprintf(progress, "... Valheim server %s ...\n", success)
+	return nil
+}
+
+// readSteamCMD prints SteamCMD's progress until it exits, returning how it finished
+func readSteamCMD(out io.Reader) (string, string) {
+	success, failure := "", ""
+	scanner := bufio.NewScanner(out)
+	scanner.Split(scanLinesOrReturns)
+	for scanner.Scan() {
+		l := steamcmd.Parse(scanner.Text())
+		switch {
+		case l.Progress != nil:
+			fmt.Fprintf(progress, "... %s: %.2f%% ...\n", l.Progress.State, l.Progress.Percent)
+		case l.Success != "":
+			success = l.Success
+		case l.Error != "":
+			failure = l.Error
+		}
+	}
+	return success, failure
+}
+
+// scanLinesOrReturns splits output into lines, treating a carriage return as a line break too,
+// since SteamCMD redraws its progress in place on some platforms
+func scanLinesOrReturns(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"warden/internal/config"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const (
+	// A fake SteamCMD that records its arguments, then installs the server into the directory
+	// it's given
+	testSteamCMDScript = `#!/bin/sh
+echo "$@" > "$(dirname "$0")/args"
+echo "Logging in user 'anonymous' to Steam Public...OK"
+echo " Update state (0x61) downloading, progress: 45.12 (1234567 / 2736201)"
+printf " Update state (0x61) downloading, progress: 100.00 (2736201 / 2736201)\r"
+touch "$2/valheim_server.x86_64"
+echo "Success! App '896660' fully installed."
+`
+	testSteamCMDFailingScript = `#!/bin/sh
+echo "Error! App '896660' state is 0x202 after update job."
+`
+	testSteamCMDSilentScript = `#!/bin/sh
+echo "Loading Steam API...OK"
+`
+)
+
+func TestSteamCMD_Happy(t *testing.T) {
+	tests := map[string]struct {
+		installed bool
+		beta      string
+		run       func(s service.SteamCMD) error
+		expected  string
+	}{
+		"install the server": {
+			run:      func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected: "+login anonymous +app_update 896660 +quit",
+		},
+		"update the server to a beta branch": {
+			installed: true,
+			beta:      "public-test",
+			run:       func(s service.SteamCMD) error { return s.Update(context.Background()) },
+			expected:  "+app_update 896660 -beta public-test +quit",
+		},
+		"validate the server": {
+			installed: true,
+			run:       func(s service.SteamCMD) error { return s.Validate(context.Background()) },
+			expected:  "+app_update 896660 validate +quit",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, dirs := newTestSteamCMD(t, testSteamCMDScript, tt.installed, tt.beta)
+
+			if err := tt.run(s); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dirs.valheim, "valheim_server.x86_64")); err != nil {
+				t.Errorf("expected the server to be installed, received: %+v", err)
+			}
+			args, _ := os.ReadFile(filepath.Join(dirs.steamCMD, "args"))
+			if !strings.HasPrefix(string(args), "+force_install_dir "+dirs.valheim) || !strings.Contains(string(args), tt.expected) {
+				t.Errorf("expected steamcmd args to contain: %q, received: %q", tt.expected, args)
+			}
+		})
+	}
+}
+
+func TestSteamCMD_Sad(t *testing.T) {
+	tests := map[string]struct {
+		script    string
+		installed bool
+		running   bool
+		missing   bool
+		platform  string
+		run       func(s service.SteamCMD) error
+		expected  error
+	}{
+		"return an error if the server is already installed": {
+			script:    testSteamCMDScript,
+			installed: true,
+			run:       func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected:  service.ErrServerAlreadyInstalled,
+		},
+		"return an error if there's no server to update": {
+			script:   testSteamCMDScript,
+			run:      func(s service.SteamCMD) error { return s.Update(context.Background()) },
+			expected: service.ErrServerNotInstalled,
+		},
+		"return an error if there's no server to validate": {
+			script:   testSteamCMDScript,
+			run:      func(s service.SteamCMD) error { return s.Validate(context.Background()) },
+			expected: service.ErrServerNotInstalled,
+		},
+		"return an error if the server is running": {
+			script:    testSteamCMDScript,
+			installed: true,
+			running:   true,
+			run:       func(s service.SteamCMD) error { return s.Update(context.Background()) },
+			expected:  service.ErrServerAlreadyRunning,
+		},
+		"return an error if steamcmd doesn't exist": {
+			script:   testSteamCMDScript,
+			missing:  true,
+			run:      func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected: service.ErrSteamCMDNotFound,
+		},
+		"return an error if the platform has no dedicated server": {
+			script:   testSteamCMDScript,
+			platform: config.MacOS,
+			run:      func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected: service.ErrUnsupportedPlatform,
+		},
+		"return an error if steamcmd reports an error": {
+			script:   testSteamCMDFailingScript,
+			run:      func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected: service.ErrSteamCMDFailed,
+		},
+		"return an error if steamcmd never reports success": {
+			script:   testSteamCMDSilentScript,
+			run:      func(s service.SteamCMD) error { return s.Install(context.Background()) },
+			expected: service.ErrSteamCMDFailed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, dirs := newTestSteamCMD(t, tt.script, tt.installed, "")
+			if tt.missing {
+				os.Remove(filepath.Join(dirs.steamCMD, "steamcmd"))
+			}
+			if tt.platform != "" {
+				cfg := config.Config{ValheimDirectory: dirs.valheim, Platform: tt.platform, SteamCMDPath: filepath.Join(dirs.steamCMD, "steamcmd")}
+				s = service.NewSteamCMDService(cfg, dirs.server)
+			}
+			if tt.running {
+				if _, err := dirs.server.Start("vanilla"); err != nil {
+					t.Fatalf("unexpected error starting test server, received: %+v", err)
+				}
+			}
+
+			if err := tt.run(s); !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+type testSteamCMDDirs struct {
+	valheim  string
+	steamCMD string
+	server   service.Server
+}
+
+// newTestSteamCMD creates a SteamCMD service that runs the given script as steamcmd, installing
+// into an empty Valheim directory unless installed is set
+func newTestSteamCMD(t *testing.T, script string, installed bool, beta string) (service.SteamCMD, testSteamCMDDirs) {
+	dirs := testSteamCMDDirs{
+		valheim:  filepath.Join(t.TempDir(), "valheim"),
+		steamCMD: t.TempDir(),
+	}
+	steamCMD := filepath.Join(dirs.steamCMD, "steamcmd")
+	if err := os.WriteFile(steamCMD, []byte(script), 0755); err != nil {
+		t.Fatalf("unexpected error creating test steamcmd, received: %+v", err)
+	}
+	if installed {
+		if err := os.MkdirAll(dirs.valheim, os.ModePerm); err != nil {
+			t.Fatalf("unexpected error creating test Valheim directory, received: %+v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dirs.valheim, "valheim_server.x86_64"), []byte{}, 0755); err != nil {
+			t.Fatalf("unexpected error creating test Valheim server, received: %+v", err)
+		}
+	}
+
+	ss, _ := newTestServerService(t, testStartScript, testModdedStartScript, &mock.FrameworksRepo{})
+	dirs.server = ss
+	cfg := config.Config{
+		ValheimDirectory: dirs.valheim,
+		Platform:         config.Linux,
+		SteamCMDPath:     steamCMD,
+		SteamBeta:        beta,
+	}
+	return service.NewSteamCMDService(cfg, ss), dirs
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"warden/internal/config"
+	"warden/internal/domain/instance"
+	"warden/internal/domain/systemd"
+)
+
+// Leaves systemd enough time to let the server save the world before it's killed
+const unitStopTimeout = StopTimeout + 30*time.Second
+
+var (
+	ErrInvalidUnitScope    = errors.New("invalid systemd unit scope")
+	ErrUnitNotInstalled    = errors.New("systemd unit is not installed")
+	ErrUnitInstallFailed   = errors.New("unable to install systemd unit")
+	ErrUnitUninstallFailed = errors.New("unable to uninstall systemd unit")
+	ErrUnitStatusFailed    = errors.New("unable to check systemd unit status")
+)
+
+// Exposes all methods for running the Valheim game server as a systemd service. Only unit files
+// are managed, so systemd itself doesn't need to be available.
+type Systemd interface {
+	// Writes a unit that supervises the game server to the scope's unit directory. An existing
+	// unit is replaced.
+	Install(gameType string, scope string) (systemd.Status, error)
+
+	// Removes the unit from the scope's unit directory, along with the link that enables it
+	Uninstall(scope string) (systemd.Status, error)
+
+	// Returns whether the unit is installed and enabled, and if it matches the current config
+	Status(scope string) (systemd.Status, error)
+}
+
+type systemdService struct {
+	config.Config
+
+	server     Server
+	dirs       map[string]string
+	executable string
+	user       string
+	home       string
+}
+
+// NewSystemdService creates a Systemd that installs units into the given directory for each
+// scope. Units run the Warden executable as the given user, with its home directory.
+func NewSystemdService(cfg config.Config, server Server, dirs map[string]string, executable, user, home string) Systemd {
+	return &systemdService{
+		Config:     cfg,
+		server:     server,
+		dirs:       dirs,
+		executable: executable,
+		user:       user,
+		home:       home,
+	}
+}
+
+func (s *systemdService) Install(gameType string, scope string) (systemd.Status, error) {
+	gameType = normalize(gameType)
+	if !s.server.IsValidGameType(gameType) {
+		return systemd.Status{}, ErrInvalidGameType
+	}
+	dir, err := s.unitDirectory(scope)
+	if err != nil {
+		return systemd.Status{}, err
+	}
+
+	u := s.unit(gameType, scope)
+	path := filepath.Join(dir, systemd.FileName(s.Instance))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return systemd.Status{}, fmt.Errorf("%w: %w", ErrUnitInstallFailed, err)
+	}
+	if err := os.WriteFile(path, []byte(u.Render()), 0644); err != nil {
+		return systemd.Status{}, fmt.Errorf("%w: %w", ErrUnitInstallFailed, err)
+	}
+
+	status, err := s.Status(scope)
+	if err != nil {
+		return status, fmt.Errorf("%w: %w", ErrUnitInstallFailed, err)
+	}
+	return status, nil
+}
+
+func (s *systemdService) Uninstall(scope string) (systemd.Status, error) {
+	status, err := s.Status(scope)
+	if err != nil {
+		return status, fmt.Errorf("%w: %w", ErrUnitUninstallFailed, err)
+	}
+	if !status.Installed {
+		return status, ErrUnitNotInstalled
+	}
+
+	if err := os.Remove(status.Path); err != nil {
+		return status, fmt.Errorf("%w: %w", ErrUnitUninstallFailed, err)
+	}
+	if err := os.Remove(s.wantsLink(scope)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return status, fmt.Errorf("%w: %w", ErrUnitUninstallFailed, err)
+	}
+	status.Installed = false
+	status.Enabled = false
+	return status, nil
+}
+
+func (s *systemdService) Status(scope string) (systemd.Status, error) {
+	dir, err := s.unitDirectory(scope)
+	if err != nil {
+		return systemd.Status{}, err
+	}
+
+	status := systemd.Status{
+		Scope: scope,
+		Name:  systemd.FileName(s.Instance),
+		Path:  filepath.Join(dir, systemd.FileName(s.Instance)),
+	}
+	contents, err := os.ReadFile(status.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("%w: %w", ErrUnitStatusFailed, err)
+	}
+
+	status.Installed = true
+	status.GameType = systemd.GameType(string(contents))
+	if status.GameType != "" {
+		u := s.unit(status.GameType, scope)
+		status.UpToDate = string(contents) == u.Render()
+	}
+	if _, err := os.Lstat(s.wantsLink(scope)); err == nil {
+		status.Enabled = true
+	}
+	return status, nil
+}
+
+// unit builds the unit Warden would install right now for the game type and scope
+func (s *systemdService) unit(gameType string, scope string) systemd.Unit {
+	// The supervisor has to find the same config file, if it isn't in the home directory, and
+	// manage the same instance
+	env := []string{}
+	if dir := instance.Root(s.ConfigDirectory, s.Instance); dir != "" && dir != s.home {
+		env = append(env, config.ConfigDirectoryEnv+"="+dir)
+	}
+	if s.Instance != "" {
+		env = append(env, config.InstanceEnv+"="+s.Instance)
+	}
+	return systemd.Unit{
+		Scope:            scope,
+		GameType:         gameType,
+		Instance:         s.Instance,
+		Executable:       s.executable,
+		User:             s.user,
+		Home:             s.home,
+		Environment:      env,
+		WorkingDirectory: s.ValheimDirectory,
+		StopTimeout:      unitStopTimeout,
+	}
+}
+
+func (s *systemdService) unitDirectory(scope string) (string, error) {
+	dir, ok := s.dirs[scope]
+	if !ok || !systemd.IsValidScope(scope) {
+		return "", ErrInvalidUnitScope
+	}
+	return dir, nil
+}
+
+// wantsLink is the symlink systemctl enable creates, which starts the unit with its target
+func (s *systemdService) wantsLink(scope string) string {
+	return filepath.Join(s.dirs[scope], systemd.WantedBy(scope)+".wants", systemd.FileName(s.Instance))
+}
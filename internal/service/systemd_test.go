@@ -0,0 +1,225 @@
+package service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"warden/internal/config"
+	"warden/internal/domain/systemd"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestInstall_Happy(t *testing.T) {
+	tests := map[string]struct {
+		gameType string
+		scope    string
+		expected string
+	}{
+		"install a user unit": {
+			gameType: "vanilla",
+			scope:    systemd.UserScope,
+			expected: "ExecStart=/usr/local/bin/warden supervise vanilla\n",
+		},
+		"install a system unit that runs as the current user": {
+			gameType: "modded",
+			scope:    systemd.SystemScope,
+			expected: "User=viking\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sd := newTestSystemdService(t)
+
+			status, err := sd.Install(tt.gameType, tt.scope)
+			if err != nil {
+				t.Errorf("unexpected error, received: %+v", err)
+			}
+			if !status.Installed || !status.UpToDate || status.Enabled || status.GameType != tt.gameType {
+				t.Errorf("expected an installed, up to date %s unit, received: %+v", tt.gameType, status)
+			}
+
+			contents, err := os.ReadFile(status.Path)
+			if err != nil {
+				t.Errorf("unexpected error reading unit, received: %+v", err)
+			}
+			if !strings.Contains(string(contents), tt.expected) {
+				t.Errorf("expected unit to contain: %q, received: %s", tt.expected, contents)
+			}
+		})
+	}
+}
+
+func TestInstall_Instance(t *testing.T) {
+	cfg := config.Config{
+		ValheimDirectory: "/srv/valheim-event",
+		Platform:         config.Linux,
+		ConfigDirectory:  "/etc/warden/.warden-instances/event",
+		Instance:         "event",
+	}
+	ss := service.NewServerService(cfg, &mock.FrameworksRepo{}, &mock.PIDFile{}, "")
+	dirs := map[string]string{systemd.UserScope: t.TempDir()}
+	sd := service.NewSystemdService(cfg, ss, dirs, "/usr/local/bin/warden", "viking", "/home/viking")
+
+	status, err := sd.Install("vanilla", systemd.UserScope)
+	if err != nil {
+		t.Errorf("unexpected error, received: %+v", err)
+	}
+	if status.Name != "warden-valheim-event.service" || filepath.Base(status.Path) != status.Name {
+		t.Errorf("expected the unit to be named after the instance, received: %+v", status)
+	}
+
+	contents, err := os.ReadFile(status.Path)
+	if err != nil {
+		t.Errorf("unexpected error reading unit, received: %+v", err)
+	}
+	for _, e := range []string{"Environment=WARDEN_CONFIG_DIR=/etc/warden\n", "Environment=WARDEN_INSTANCE=event\n"} {
+		if !strings.Contains(string(contents), e) {
+			t.Errorf("expected unit to contain: %q, received: %s", e, contents)
+		}
+	}
+}
+
+func TestInstall_Sad(t *testing.T) {
+	tests := map[string]struct {
+		gameType string
+		scope    string
+		expected error
+	}{
+		"return an error if the game type is invalid": {
+			gameType: "niaudbiwabdiu dd",
+			scope:    systemd.UserScope,
+			expected: service.ErrInvalidGameType,
+		},
+		"return an error if the scope is invalid": {
+			gameType: "vanilla",
+			scope:    "global",
+			expected: service.ErrInvalidUnitScope,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sd := newTestSystemdService(t)
+
+			_, err := sd.Install(tt.gameType, tt.scope)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error: %+v, received: %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestUninstall_Happy(t *testing.T) {
+	sd := newTestSystemdService(t)
+	installed, err := sd.Install("vanilla", systemd.UserScope)
+	if err != nil {
+		t.Errorf("unexpected error installing test unit, received: %+v", err)
+	}
+	link := enableTestUnit(t, installed.Path, "default.target.wants")
+
+	status, err := sd.Uninstall(systemd.UserScope)
+	if err != nil {
+		t.Errorf("unexpected error, received: %+v", err)
+	}
+	if status.Installed || status.Enabled {
+		t.Errorf("expected unit to be removed, received: %+v", status)
+	}
+	for _, path := range []string{installed.Path, link} {
+		if _, err := os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected %s to be removed, received: %+v", path, err)
+		}
+	}
+}
+
+func TestUninstall_Sad(t *testing.T) {
+	sd := newTestSystemdService(t)
+
+	_, err := sd.Uninstall(systemd.UserScope)
+	if !errors.Is(err, service.ErrUnitNotInstalled) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrUnitNotInstalled, err)
+	}
+}
+
+func TestUnitStatus_Happy(t *testing.T) {
+	tests := map[string]struct {
+		setUp    func(t *testing.T, sd service.Systemd)
+		expected systemd.Status
+	}{
+		"report a unit that isn't installed": {
+			setUp:    func(_ *testing.T, _ service.Systemd) {},
+			expected: systemd.Status{},
+		},
+		"report an installed and enabled unit": {
+			setUp: func(t *testing.T, sd service.Systemd) {
+				status, _ := sd.Install("modded", systemd.SystemScope)
+				enableTestUnit(t, status.Path, "multi-user.target.wants")
+			},
+			expected: systemd.Status{Installed: true, Enabled: true, UpToDate: true, GameType: "modded"},
+		},
+		"report a unit that was changed by hand": {
+			setUp: func(t *testing.T, sd service.Systemd) {
+				status, _ := sd.Install("vanilla", systemd.SystemScope)
+				contents, _ := os.ReadFile(status.Path)
+				changed := strings.Replace(string(contents), "RestartSec=30", "RestartSec=5", 1)
+				if err := os.WriteFile(status.Path, []byte(changed), 0644); err != nil {
+					t.Errorf("unexpected error changing test unit, received: %+v", err)
+				}
+			},
+			expected: systemd.Status{Installed: true, GameType: "vanilla"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sd := newTestSystemdService(t)
+			tt.setUp(t, sd)
+
+			status, err := sd.Status(systemd.SystemScope)
+			if err != nil {
+				t.Errorf("unexpected error, received: %+v", err)
+			}
+			if status.Installed != tt.expected.Installed ||
+				status.Enabled != tt.expected.Enabled ||
+				status.UpToDate != tt.expected.UpToDate ||
+				status.GameType != tt.expected.GameType {
+				t.Errorf("expected status: %+v, received: %+v", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestUnitStatus_Sad(t *testing.T) {
+	sd := newTestSystemdService(t)
+
+	_, err := sd.Status("global")
+	if !errors.Is(err, service.ErrInvalidUnitScope) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrInvalidUnitScope, err)
+	}
+}
+
+// newTestSystemdService creates a Systemd that installs units into temporary directories
+func newTestSystemdService(t *testing.T) service.Systemd {
+	cfg := config.Config{ValheimDirectory: "/srv/valheim", Platform: config.Linux}
+	ss := service.NewServerService(cfg, &mock.FrameworksRepo{}, &mock.PIDFile{}, "")
+	dirs := map[string]string{
+		systemd.UserScope:   filepath.Join(t.TempDir(), "user"),
+		systemd.SystemScope: filepath.Join(t.TempDir(), "system"),
+	}
+	return service.NewSystemdService(cfg, ss, dirs, "/usr/local/bin/warden", "viking", "/home/viking")
+}
+
+// enableTestUnit links a unit into a target's wants directory, like systemctl enable does
+func enableTestUnit(t *testing.T, path, wants string) string {
+	link := filepath.Join(filepath.Dir(path), wants, filepath.Base(path))
+	if err := os.MkdirAll(filepath.Dir(link), os.ModePerm); err != nil {
+		t.Errorf("unexpected error enabling test unit, received: %+v", err)
+	}
+	if err := os.Symlink(path, link); err != nil {
+		t.Errorf("unexpected error enabling test unit, received: %+v", err)
+	}
+	return link
+}
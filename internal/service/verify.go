@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/data/repo"
+	"warden/internal/domain/logs"
+	"warden/internal/domain/mod"
+	"warden/internal/domain/server"
+)
+
+// How often the BepInEx log is checked while the server starts
+const verifyPollInterval = 500 * time.Millisecond
+
+var (
+	ErrUnableToVerifyUpdate     = errors.New("unable to verify update")
+	ErrUpdateVerificationFailed = errors.New("server did not start cleanly after the update")
+	ErrUpdateRollbackFailed     = errors.New("unable to roll back update")
+)
+
+// Exposes all methods for checking that an update didn't break the game server
+type Verifier interface {
+	// Runs an update, then starts the modded server and watches the BepInEx log until the world
+	// loads. If a plugin fails to load, the server stops, or the world doesn't load before the
+	// verify timeout, every mod and its record is rolled back to how it was before the update.
+	// They're rolled back the same way if the update itself fails. The server is stopped again
+	// once it's been checked.
+	VerifyUpdate(ctx context.Context, update func() error) (server.Verification, error)
+}
+
+type verifierService struct {
+	config.Config
+
+	r               repo.Mods
+	server          Server
+	logs            Logs
+	backup          file.Backup
+	pluginDirectory string
+}
+
+// NewVerifierService creates a Verifier that backs up the given plugin directory into the cache
+// directory before each update, so it can be restored if the update is rolled back
+func NewVerifierService(cfg config.Config, mr repo.Mods, server Server, logs Logs, pluginDirectory string) Verifier {
+	return &verifierService{
+		Config:          cfg,
+		r:               mr,
+		server:          server,
+		logs:            logs,
+		backup:          file.NewBackupIn(cfg.CacheDirectory),
+		pluginDirectory: pluginDirectory,
+	}
+}
+
+func (vs *verifierService) VerifyUpdate(ctx context.Context, update func() error) (server.Verification, error) {
+	// The check needs to start the server itself, and mods can't be rolled back under a running one
+	status, err := vs.server.Status()
+	if err != nil {
+		return server.Verification{}, fmt.Errorf("%w: %w", ErrUnableToVerifyUpdate, err)
+	}
+	if status.Running {
+		return server.Verification{}, fmt.Errorf("%w: %w", ErrUnableToVerifyUpdate, ErrServerAlreadyRunning)
+	}
+
+	// Keep the state from before the update, so it can be put back
+	mods, err := vs.r.ListMods()
+	if err != nil {
+		return server.Verification{}, fmt.Errorf("%w: %w", ErrUnableToVerifyUpdate, err)
+	}
+	if err := vs.backup.Create(vs.pluginDirectory); err != nil {
+		return server.Verification{}, fmt.Errorf("%w: %w", ErrUnableToVerifyUpdate, err)
+	}
+	defer vs.backup.Remove()
+
+	// An update that fails partway can leave some mods updated, so it's rolled back too
+	if err := update(); err != nil {
+		fmt.Fprintln(progress, "... update failed, rolling it back ...")
+		if rollbackErr := vs.rollback(mods); rollbackErr != nil {
+			return server.Verification{}, fmt.Errorf("%w: %w: %w", ErrUpdateRollbackFailed, rollbackErr, err)
+		}
+		return server.Verification{Reason: "update failed", RolledBack: true}, err
+	}
+
+	fmt.Fprintln(progress, "... starting the modded server to check the update ...")
+	v, err := vs.check(ctx)
+	if err != nil {
+		return v, fmt.Errorf("%w: %w", ErrUnableToVerifyUpdate, err)
+	}
+	if v.Passed {
+		fmt.Fprintln(progress, "... world loaded, every plugin loaded cleanly ...")
+		return v, nil
+	}
+
+	for _, p := range v.Problems {
+		fmt.Fprintf(progress, "... %s\n", describeProblem(p))
+	}
+	fmt.Fprintf(progress, "... %s, rolling back the update ...\n", v.Reason)
+	if err := vs.rollback(mods); err != nil {
+		return v, fmt.Errorf("%w: %w", ErrUpdateRollbackFailed, err)
+	}
+	v.RolledBack = true
+	return v, fmt.Errorf("%w: %s", ErrUpdateVerificationFailed, v.Reason)
+}
+
+// check starts the modded server and waits for the world to load, a plugin to fail to load, or
+// the server to stop. The server is always stopped again before returning.
+func (vs *verifierService) check(ctx context.Context) (server.Verification, error) {
+	// BepInEx replaces its log on every start, but the old one is removed first so there's no
+	// chance of reading it while the server is still starting
+	if err := vs.logs.Reset(logs.BepInEx); err != nil {
+		return server.Verification{}, err
+	}
+	_, err := vs.server.Start(modded)
+	if errors.Is(err, ErrServerStartFailed) {
+		// A server that exits right away is as broken as one that crashes while loading
+		return server.Verification{Reason: "server failed to start"}, nil
+	}
+	if err != nil {
+		return server.Verification{}, err
+	}
+
+	v, err := vs.watch(ctx)
+	if stopErr := vs.server.Stop(); stopErr != nil && !errors.Is(stopErr, ErrServerNotRunning) {
+		return v, stopErr
+	}
+	return v, err
+}
+
+func (vs *verifierService) watch(ctx context.Context) (server.Verification, error) {
+	timeout := time.NewTimer(vs.VerifyTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(verifyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return server.Verification{Reason: "check was interrupted"}, nil
+		case <-timeout.C:
+			return server.Verification{Reason: fmt.Sprintf("world did not load within %s", vs.VerifyTimeout)}, nil
+		case <-ticker.C:
+		}
+
+		loaded, problems, err := vs.readLog(ctx)
+		if err != nil {
+			return server.Verification{}, err
+		}
+		if len(problems) > 0 {
+			return server.Verification{Reason: "plugins failed to load", Problems: problems}, nil
+		}
+		if loaded {
+			return server.Verification{Passed: true}, nil
+		}
+
+		status, err := vs.server.Status()
+		if err != nil {
+			return server.Verification{}, err
+		}
+		if !status.Running {
+			return server.Verification{Reason: "server stopped before the world loaded"}, nil
+		}
+	}
+}
+
+// readLog reads the BepInEx log from the start, returning whether the world has loaded and every
+// plugin that failed to load so far. The log doesn't exist until BepInEx has started.
+func (vs *verifierService) readLog(ctx context.Context) (bool, []logs.Problem, error) {
+	loaded := false
+	problems := []logs.Problem{}
+	err := vs.logs.Tail(ctx, logs.BepInEx, -1, false, logs.Debug, func(l logs.Line) {
+		if logs.WorldLoaded(l.Text) {
+			loaded = true
+		}
+		if l.Problem != nil && l.Problem.IsLoadProblem() {
+			problems = append(problems, *l.Problem)
+		}
+	})
+	if errors.Is(err, file.ErrLogNotFound) {
+		return false, problems, nil
+	}
+	return loaded, problems, err
+}
+
+// rollback restores the plugin directory, then replaces every mod record with the ones from
+// before the update
+func (vs *verifierService) rollback(mods []mod.Mod) error {
+	if err := vs.backup.Restore(vs.pluginDirectory); err != nil {
+		return err
+	}
+	if err := vs.r.DeleteAllMods(); err != nil {
+		return err
+	}
+	for _, m := range mods {
+		if err := vs.r.InsertMod(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeProblem explains why a plugin didn't load, along with the mod it belongs to if it's known
+func describeProblem(p logs.Problem) string {
+	plugin := p.Plugin
+	if p.Mod != "" {
+		plugin += " (" + p.Mod + ")"
+	}
+	if p.Kind == logs.MissingDependency {
+		return plugin + " is missing dependencies: " + p.Dependency
+	}
+	if p.Detail != "" {
+		return plugin + " failed to load: " + p.Detail
+	}
+	return plugin + " failed to load"
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"warden/internal/config"
+	"warden/internal/domain/framework"
+	"warden/internal/domain/logs"
+	"warden/internal/domain/mod"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+const (
+	testWorldLoadedLog = "[Info   : Unity Log] 02/03/2024 18:30:00: Game server connected"
+	testLoadFailureLog = "[Error  :   BepInEx] Could not load [Sleepover 1.0.2] because it has missing dependencies: com.jotunn.jotunn"
+)
+
+func TestVerifyUpdate_Happy(t *testing.T) {
+	vs, env := newTestVerifier(t, "echo '"+testWorldLoadedLog+"' > $LOG\nsleep 30\n", config.Config{})
+
+	v, err := vs.VerifyUpdate(context.Background(), env.update)
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if !v.Passed || v.RolledBack {
+		t.Errorf("expected the update to pass without a rollback, received: %+v", v)
+	}
+	if contents, _ := os.ReadFile(env.plugin); string(contents) != "1.0.2" {
+		t.Errorf("expected the update to be kept, received plugin: %q", contents)
+	}
+	if env.restored != nil {
+		t.Errorf("expected no mod records to be restored, received: %+v", env.restored)
+	}
+	if status, _ := env.server.Status(); status.Running {
+		t.Errorf("expected the server to be stopped after the check")
+	}
+}
+
+func TestVerifyUpdate_Sad(t *testing.T) {
+	tests := map[string]struct {
+		script   string
+		timeout  time.Duration
+		problems int
+	}{
+		"roll back if a plugin fails to load": {
+			script:   "echo '" + testLoadFailureLog + "' > $LOG\nsleep 30\n",
+			problems: 1,
+		},
+		"roll back if the server stops before the world loads": {
+			script: "echo 'Starting Modded Server'\nexit 1\n",
+		},
+		"roll back if the world doesn't load in time": {
+			script:  "sleep 30\n",
+			timeout: time.Second,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			vs, env := newTestVerifier(t, tt.script, config.Config{VerifyTimeout: tt.timeout})
+
+			v, err := vs.VerifyUpdate(context.Background(), env.update)
+			if !errors.Is(err, service.ErrUpdateVerificationFailed) {
+				t.Errorf("expected error: %+v, received: %+v", service.ErrUpdateVerificationFailed, err)
+			}
+			if v.Passed || !v.RolledBack || len(v.Problems) != tt.problems {
+				t.Errorf("expected a rolled back update with %d problems, received: %+v", tt.problems, v)
+			}
+			if contents, _ := os.ReadFile(env.plugin); string(contents) != "1.0.1" {
+				t.Errorf("expected the plugin to be rolled back, received: %q", contents)
+			}
+			if len(env.restored) != 1 || env.restored[0].Version != "1.0.1" {
+				t.Errorf("expected the mod record to be rolled back, received: %+v", env.restored)
+			}
+		})
+	}
+}
+
+func TestVerifyUpdate_Sad_UpdateFailed(t *testing.T) {
+	updateErr := errors.New("update failed")
+	vs, env := newTestVerifier(t, "sleep 30\n", config.Config{})
+
+	// The update changes a plugin before failing, like one that stops partway through the mods
+	v, err := vs.VerifyUpdate(context.Background(), func() error {
+		if err := env.update(); err != nil {
+			return err
+		}
+		return updateErr
+	})
+	if !errors.Is(err, updateErr) {
+		t.Errorf("expected error: %+v, received: %+v", updateErr, err)
+	}
+	if !v.RolledBack {
+		t.Errorf("expected the update to be rolled back, received: %+v", v)
+	}
+	if contents, _ := os.ReadFile(env.plugin); string(contents) != "1.0.1" {
+		t.Errorf("expected the plugin to be rolled back, received: %q", contents)
+	}
+	if len(env.restored) != 1 || env.restored[0].Version != "1.0.1" {
+		t.Errorf("expected the mod record to be rolled back, received: %+v", env.restored)
+	}
+}
+
+func TestVerifyUpdate_Sad_NoRollback(t *testing.T) {
+	vs, env := newTestVerifier(t, "sleep 30\n", config.Config{})
+	if _, err := env.server.Start("vanilla"); err != nil {
+		t.Fatalf("unexpected error starting test server, received: %+v", err)
+	}
+
+	_, err := vs.VerifyUpdate(context.Background(), env.update)
+	if !errors.Is(err, service.ErrServerAlreadyRunning) {
+		t.Errorf("expected error: %+v, received: %+v", service.ErrServerAlreadyRunning, err)
+	}
+	if env.restored != nil {
+		t.Errorf("expected nothing to be rolled back, received: %+v", env.restored)
+	}
+}
+
+// testVerifierEnv is everything a test update touches: a single installed plugin, and the mod
+// records that are put back if the update is rolled back
+type testVerifierEnv struct {
+	server   service.Server
+	plugin   string
+	update   func() error
+	restored []mod.Mod
+}
+
+// newTestVerifier creates a Verifier for a modded server that runs the given script. The script
+// can write to the BepInEx log through $LOG.
+func newTestVerifier(t *testing.T, script string, cfg config.Config) (service.Verifier, *testVerifierEnv) {
+	dir := t.TempDir()
+	pluginDir := filepath.Join(dir, "plugins")
+	plugin := filepath.Join(pluginDir, "Azumatt-Sleepover", "Sleepover.dll")
+	if err := os.MkdirAll(filepath.Dir(plugin), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating test plugin, received: %+v", err)
+	}
+	if err := os.WriteFile(plugin, []byte("1.0.1"), 0644); err != nil {
+		t.Fatalf("unexpected error creating test plugin, received: %+v", err)
+	}
+
+	env := &testVerifierEnv{plugin: plugin}
+	env.update = func() error {
+		return os.WriteFile(plugin, []byte("1.0.2"), 0644)
+	}
+	mods := []mod.Mod{{Name: "Sleepover", Namespace: "Azumatt", FilePath: filepath.Dir(plugin), Version: "1.0.1"}}
+	mr := &mock.ModsRepo{
+		ListModsFunc: func() ([]mod.Mod, error) {
+			return mods, nil
+		},
+		DeleteAllModsFunc: func() error {
+			env.restored = []mod.Mod{}
+			return nil
+		},
+		InsertModFunc: func(m mod.Mod) error {
+			env.restored = append(env.restored, m)
+			return nil
+		},
+	}
+
+	logFile := filepath.Join(dir, "LogOutput.log")
+	if cfg.VerifyTimeout == 0 {
+		cfg.VerifyTimeout = 10 * time.Second
+	}
+	fr := &mock.FrameworksRepo{
+		GetFrameworkFunc: func(name string) (framework.Framework, error) {
+			return framework.Framework{Name: name, Version: "5.4.2202"}, nil
+		},
+	}
+	ss, _ := newTestServerService(t, testStartScript, "LOG='"+logFile+"'\n"+script, fr)
+	ls := service.NewLogService(mr, map[string]string{logs.BepInEx: logFile})
+	env.server = ss
+	return service.NewVerifierService(cfg, mr, ss, ls, pluginDir), env
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"warden/internal/data/file"
+	"warden/internal/domain/world"
+)
+
+// How many consecutive seconds are tried for a backup, if earlier ones are already taken
+const maxBackupAttempts = 60
+
+var (
+	ErrUnableToBackupWorld  = errors.New("unable to back up worlds")
+	ErrUnableToListBackups  = errors.New("unable to list world backups")
+	ErrUnableToPruneBackups = errors.New("unable to remove expired world backups")
+	ErrUnableToRestoreWorld = errors.New("unable to restore world backup")
+	ErrWorldBackupNotFound  = errors.New("world backup not found")
+)
+
+// Encapsulates all the business logic for backing up Valheim worlds. Each backup run snapshots
+// every world in the save directory, then prunes old snapshots based on the retention policy.
+type World interface {
+	BackupWorlds() ([]world.Snapshot, error)
+	ListBackups() ([]world.Snapshot, error)
+	RestoreBackup(id string) error
+}
+
+type worldService struct {
+	w         file.Worlds
+	retention world.Retention
+	server    Server
+	c         Confirmer
+}
+
+func NewWorldService(w file.Worlds, retention world.Retention, server Server, c Confirmer) World {
+	return &worldService{
+		w:         w,
+		retention: retention,
+		server:    server,
+		c:         c,
+	}
+}
+
+func (ws *worldService) BackupWorlds() ([]world.Snapshot, error) {
+	names, err := ws.w.ListWorlds()
+	if err != nil {
+		return []world.Snapshot{}, fmt.Errorf("%w: %w", ErrUnableToBackupWorld, err)
+	}
+
+	now := time.Now()
+	snapshots := []world.Snapshot{}
+	for _, name := range names {
+		s, err := ws.backup(name, now)
+		if err != nil {
+			return snapshots, fmt.Errorf("%w: %w", ErrUnableToBackupWorld, err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := ws.prune(); err != nil {
+		return snapshots, err
+	}
+	return snapshots, nil
+}
+
+func (ws *worldService) ListBackups() ([]world.Snapshot, error) {
+	snapshots, err := ws.w.ListSnapshots()
+	if err != nil {
+		return []world.Snapshot{}, fmt.Errorf("%w: %w", ErrUnableToListBackups, err)
+	}
+	return snapshots, nil
+}
+
+func (ws *worldService) RestoreBackup(id string) error {
+	snapshots, err := ws.w.ListSnapshots()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToListBackups, err)
+	}
+
+	var target *world.Snapshot
+	for i := range snapshots {
+		if snapshots[i].ID() == id {
+			target = &snapshots[i]
+			break
+		}
+	}
+	if target == nil {
+		return ErrWorldBackupNotFound
+	}
+
+	// A running server keeps the world in memory, and would overwrite the restored files the next
+	// time it saves
+	status, err := ws.server.Status()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRestoreWorld, err)
+	}
+	if status.Running {
+		return fmt.Errorf("%w: %w", ErrUnableToRestoreWorld, ErrServerAlreadyRunning)
+	}
+
+	question := fmt.Sprintf("are you sure you want to overwrite %s with the backup from %s?", target.World, target.CreatedAt.Format(time.DateTime))
+	ok, err := ws.c.Confirm(question, false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAborted
+	}
+
+	// Snapshot the current world first so the restore itself can be undone
+	if _, err := ws.backup(target.World, time.Now()); err != nil && !errors.Is(err, file.ErrWorldNotFound) {
+		return fmt.Errorf("%w: %w", ErrUnableToRestoreWorld, err)
+	}
+	if err := ws.w.Restore(*target); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToRestoreWorld, err)
+	}
+	return nil
+}
+
+// backup snapshots a world. Snapshot IDs only go down to the second, so if a backup of the world
+// was already taken that second, e.g. by a scheduled backup or the one being restored, the next
+// free second is used instead.
+func (ws *worldService) backup(name string, at time.Time) (world.Snapshot, error) {
+	for i := 0; i < maxBackupAttempts; i++ {
+		s, err := ws.w.Backup(name, at.Add(time.Duration(i)*time.Second))
+		if !errors.Is(err, file.ErrSnapshotAlreadyExists) {
+			return s, err
+		}
+	}
+	return world.Snapshot{}, file.ErrSnapshotAlreadyExists
+}
+
+// prune deletes every world backup that falls outside of the retention policy
+func (ws *worldService) prune() error {
+	snapshots, err := ws.w.ListSnapshots()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnableToPruneBackups, err)
+	}
+	for _, s := range ws.retention.Expired(snapshots) {
+		if err := ws.w.Delete(s); err != nil {
+			return fmt.Errorf("%w: %w", ErrUnableToPruneBackups, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"warden/internal/config"
+	"warden/internal/data/file"
+	"warden/internal/domain/server"
+	"warden/internal/domain/world"
+	"warden/internal/service"
+	"warden/internal/test/mock"
+)
+
+func TestBackupWorlds_Happy(t *testing.T) {
+	old := world.Snapshot{World: "Dedicated", CreatedAt: time.Now().AddDate(0, 0, -1)}
+	deleted := []world.Snapshot{}
+
+	w := &mock.Worlds{
+		ListWorldsFunc: func() ([]string, error) {
+			return []string{"Dedicated"}, nil
+		},
+		BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+			return world.Snapshot{World: name, CreatedAt: createdAt}, nil
+		},
+		ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+			return []world.Snapshot{old, {World: "Dedicated", CreatedAt: time.Now()}}, nil
+		},
+		DeleteFunc: func(s world.Snapshot) error {
+			deleted = append(deleted, s)
+			return nil
+		},
+	}
+	ws := service.NewWorldService(w, world.Retention{KeepLast: 1}, newTestWorldServer(false), service.NewConfirmer(&io.LimitedReader{}))
+
+	snapshots, err := ws.BackupWorlds()
+	if err != nil {
+		t.Errorf("expected a nil error, received: %+v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("expected 1 new backup, received: %d", len(snapshots))
+	}
+	if len(deleted) != 1 || !deleted[0].Equals(&old) {
+		t.Errorf("expected expired backup %+v to be deleted, received: %+v", old, deleted)
+	}
+}
+
+func TestBackupWorlds_SecondTaken(t *testing.T) {
+	attempts := []time.Time{}
+	w := &mock.Worlds{
+		ListWorldsFunc: func() ([]string, error) {
+			return []string{"Dedicated"}, nil
+		},
+		BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+			attempts = append(attempts, createdAt)
+			// A scheduled backup was already taken this second
+			if len(attempts) == 1 {
+				return world.Snapshot{}, file.ErrSnapshotAlreadyExists
+			}
+			return world.Snapshot{World: name, CreatedAt: createdAt}, nil
+		},
+		ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+			return []world.Snapshot{}, nil
+		},
+	}
+	ws := service.NewWorldService(w, world.Retention{}, newTestWorldServer(false), service.NewConfirmer(&io.LimitedReader{}))
+
+	snapshots, err := ws.BackupWorlds()
+	if err != nil {
+		t.Fatalf("expected a nil error, received: %+v", err)
+	}
+	if len(attempts) != 2 || attempts[1].Sub(attempts[0]) != time.Second {
+		t.Errorf("expected the backup to be retried a second later, received: %+v", attempts)
+	}
+	if len(snapshots) != 1 || !snapshots[0].CreatedAt.Equal(attempts[1]) {
+		t.Errorf("expected the backup from the next free second, received: %+v", snapshots)
+	}
+}
+
+func TestBackupWorlds_Sad(t *testing.T) {
+	tests := map[string]struct {
+		w        file.Worlds
+		expected error
+	}{
+		"return an error if worlds can't be listed": {
+			w: &mock.Worlds{
+				ListWorldsFunc: func() ([]string, error) {
+					return []string{}, file.ErrWorldListFailed
+				},
+			},
+			expected: service.ErrUnableToBackupWorld,
+		},
+		"return an error if a world can't be backed up": {
+			w: &mock.Worlds{
+				ListWorldsFunc: func() ([]string, error) {
+					return []string{"Dedicated"}, nil
+				},
+				BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+					return world.Snapshot{}, file.ErrWorldBackupFailed
+				},
+			},
+			expected: service.ErrUnableToBackupWorld,
+		},
+		"return an error if expired backups can't be deleted": {
+			w: &mock.Worlds{
+				ListWorldsFunc: func() ([]string, error) {
+					return []string{}, nil
+				},
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{
+						{World: "Dedicated", CreatedAt: time.Now()},
+						{World: "Dedicated", CreatedAt: time.Now().AddDate(0, 0, -1)},
+					}, nil
+				},
+				DeleteFunc: func(s world.Snapshot) error {
+					return file.ErrSnapshotDeleteFailed
+				},
+			},
+			expected: service.ErrUnableToPruneBackups,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws := service.NewWorldService(test.w, world.Retention{KeepLast: 1}, newTestWorldServer(false), service.NewConfirmer(&io.LimitedReader{}))
+
+			_, err := ws.BackupWorlds()
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestRestoreBackup_Happy(t *testing.T) {
+	s := world.Snapshot{
+		World:     "Dedicated",
+		CreatedAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local),
+	}
+
+	tests := map[string]struct {
+		rd        io.Reader
+		conflicts int
+		restored  bool
+	}{
+		"if user confirms restore, restore the backup and return success": {
+			rd:       strings.NewReader("Y"),
+			restored: true,
+		},
+		"if a backup was already taken this second, take the safety backup a second later": {
+			rd:        strings.NewReader("Y"),
+			conflicts: 1,
+			restored:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			restored := false
+			backups := []time.Time{}
+			w := &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+				BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+					backups = append(backups, createdAt)
+					if len(backups) <= test.conflicts {
+						return world.Snapshot{}, file.ErrSnapshotAlreadyExists
+					}
+					return world.Snapshot{}, nil
+				},
+				RestoreFunc: func(_ world.Snapshot) error {
+					restored = true
+					return nil
+				},
+			}
+			ws := service.NewWorldService(w, world.Retention{}, newTestWorldServer(false), service.NewConfirmer(test.rd))
+
+			if err := ws.RestoreBackup(s.ID()); err != nil {
+				t.Errorf("expected a nil error, received: %+v", err)
+			}
+			if restored != test.restored {
+				t.Errorf("expected restored: %t, received: %t", test.restored, restored)
+			}
+			if len(backups) != test.conflicts+1 {
+				t.Fatalf("expected %d safety backup attempts, received: %d", test.conflicts+1, len(backups))
+			}
+			if gap := backups[len(backups)-1].Sub(backups[0]); gap != time.Duration(test.conflicts)*time.Second {
+				t.Errorf("expected the safety backup %d seconds after the first attempt, received: %s", test.conflicts, gap)
+			}
+		})
+	}
+}
+
+func TestRestoreBackup_Sad(t *testing.T) {
+	s := world.Snapshot{
+		World:     "Dedicated",
+		CreatedAt: time.Date(2024, time.March, 2, 18, 30, 5, 0, time.Local),
+	}
+
+	tests := map[string]struct {
+		id       string
+		w        file.Worlds
+		running  bool
+		rd       io.Reader
+		expected error
+	}{
+		"return an error if the backup doesn't exist": {
+			id: "Dedicated-20000101T000000",
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+			},
+			rd:       strings.NewReader("Y"),
+			expected: service.ErrWorldBackupNotFound,
+		},
+		"return an error if the server is running": {
+			id: s.ID(),
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+			},
+			running:  true,
+			rd:       strings.NewReader("Y"),
+			expected: service.ErrServerAlreadyRunning,
+		},
+		"return an error if user fails to confirm restore": {
+			id: s.ID(),
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+			},
+			rd:       strings.NewReader("TEST\nRANDOM\nINPUTS\nTEST\n"),
+			expected: service.ErrMaxAttempts,
+		},
+		"return an error if user denies restore": {
+			id: s.ID(),
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+			},
+			rd:       strings.NewReader("n"),
+			expected: service.ErrAborted,
+		},
+		"return an error if no second is free for the safety backup": {
+			id: s.ID(),
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+				BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+					return world.Snapshot{}, file.ErrSnapshotAlreadyExists
+				},
+			},
+			rd:       strings.NewReader("Y"),
+			expected: file.ErrSnapshotAlreadyExists,
+		},
+		"return an error if the backup can't be restored": {
+			id: s.ID(),
+			w: &mock.Worlds{
+				ListSnapshotsFunc: func() ([]world.Snapshot, error) {
+					return []world.Snapshot{s}, nil
+				},
+				BackupFunc: func(name string, createdAt time.Time) (world.Snapshot, error) {
+					return world.Snapshot{}, nil
+				},
+				RestoreFunc: func(_ world.Snapshot) error {
+					return file.ErrWorldRestoreFailed
+				},
+			},
+			rd:       strings.NewReader("Y"),
+			expected: service.ErrUnableToRestoreWorld,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws := service.NewWorldService(test.w, world.Retention{}, newTestWorldServer(test.running), service.NewConfirmer(test.rd))
+
+			err := ws.RestoreBackup(test.id)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error: %+v, received: %+v", test.expected, err)
+			}
+		})
+	}
+}
+
+// newTestWorldServer returns a server that's running if its PID file points at this test process,
+// and stopped if there's no PID file
+func newTestWorldServer(running bool) service.Server {
+	pids := &mock.PIDFile{
+		ReadFunc: func() (server.Process, error) {
+			if !running {
+				return server.Process{}, file.ErrPIDFileNotFound
+			}
+			return server.Process{PID: os.Getpid(), GameType: "vanilla"}, nil
+		},
+	}
+	return service.NewServerService(config.Config{}, &mock.FrameworksRepo{}, pids, "")
+}
@@ -8,13 +8,23 @@ import (
 )
 
 type HTTPClient struct {
-	GetFunc func(url string) (resp *http.Response, err error)
+	GetFunc  func(url string) (resp *http.Response, err error)
+	HeadFunc func(url string) (resp *http.Response, err error)
+	DoFunc   func(req *http.Request) (resp *http.Response, err error)
 }
 
 func (hc *HTTPClient) Get(url string) (*http.Response, error) {
 	return hc.GetFunc(url)
 }
 
+func (hc *HTTPClient) Head(url string) (*http.Response, error) {
+	return hc.HeadFunc(url)
+}
+
+func (hc *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return hc.DoFunc(req)
+}
+
 // ResponseBodyToReader() is a helper function for serializing a struct into JSON, then into
 // an io.ReadCloser. This is helpful for mocking HTTP responses with the HTTPClient mock because
 // io.ReadCloser is how Go's HTTP library represents response body data from HTTP responses.
@@ -0,0 +1,18 @@
+package mock
+
+import "warden/internal/domain/schedule"
+
+// JobsRepo implements the repo.Jobs interface and exposes anonymous member functions for mocking
+// repo.Jobs behavior
+type JobsRepo struct {
+	ListJobRunsFunc  func(limit int) ([]schedule.Run, error)
+	InsertJobRunFunc func(r schedule.Run) error
+}
+
+func (r *JobsRepo) ListJobRuns(limit int) ([]schedule.Run, error) {
+	return r.ListJobRunsFunc(limit)
+}
+
+func (r *JobsRepo) InsertJobRun(run schedule.Run) error {
+	return r.InsertJobRunFunc(run)
+}
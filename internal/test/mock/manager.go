@@ -1,20 +1,44 @@
 package mock
 
+import (
+	"warden/internal/domain/game"
+	"warden/internal/domain/modpack"
+)
+
 // Manager implements the file.Manager interface and exposes anonymous member functions for mocking
 // file.Manager behavior
 type Manager struct {
 	InstallModFunc     func(url, fullName string) (string, error)
+	CacheModFunc       func(url, fullName string) error
+	IsCachedFunc       func(fullName string) bool
+	HasPluginsFunc     func(url, fullName string) (bool, error)
 	RemoveModFunc      func(fullName string) error
 	RemoveAllModsFunc  func() error
 	InstallBepInExFunc func(url, fullName string) (string, error)
 	UpdateBepInExFunc  func(url, fullName string) error
 	RemoveBepInExFunc  func() error
+	ModPathFunc        func(fullName string) string
+	BepInExFilesFunc   func() []string
+	GameBuildFunc      func() (game.Build, error)
+	CachePackageFunc   func(location, namespace string) (modpack.Manifest, error)
 }
 
 func (m *Manager) InstallMod(url, fullName string) (string, error) {
 	return m.InstallModFunc(url, fullName)
 }
 
+func (m *Manager) CacheMod(url, fullName string) error {
+	return m.CacheModFunc(url, fullName)
+}
+
+func (m *Manager) IsCached(fullName string) bool {
+	return m.IsCachedFunc(fullName)
+}
+
+func (m *Manager) HasPlugins(url, fullName string) (bool, error) {
+	return m.HasPluginsFunc(url, fullName)
+}
+
 func (m *Manager) RemoveMod(fullName string) error {
 	return m.RemoveModFunc(fullName)
 }
@@ -34,3 +58,19 @@ func (m *Manager) UpdateBepInEx(url, fullName string) error {
 func (m *Manager) RemoveBepInEx() error {
 	return m.RemoveBepInExFunc()
 }
+
+func (m *Manager) ModPath(fullName string) string {
+	return m.ModPathFunc(fullName)
+}
+
+func (m *Manager) BepInExFiles() []string {
+	return m.BepInExFilesFunc()
+}
+
+func (m *Manager) GameBuild() (game.Build, error) {
+	return m.GameBuildFunc()
+}
+
+func (m *Manager) CachePackage(location, namespace string) (modpack.Manifest, error) {
+	return m.CachePackageFunc(location, namespace)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import "warden/internal/domain/server"
+
+// PIDFile implements the file.PIDFile interface and exposes anonymous member functions for mocking
+// file.PIDFile behavior
+type PIDFile struct {
+	ReadFunc   func() (server.Process, error)
+	WriteFunc  func(p server.Process) error
+	RemoveFunc func() error
+	PathFunc   func() string
+}
+
+func (f *PIDFile) Read() (server.Process, error) {
+	return f.ReadFunc()
+}
+
+func (f *PIDFile) Write(p server.Process) error {
+	return f.WriteFunc(p)
+}
+
+func (f *PIDFile) Remove() error {
+	return f.RemoveFunc()
+}
+
+func (f *PIDFile) Path() string {
+	return f.PathFunc()
+}
@@ -0,0 +1,28 @@
+package mock
+
+import "warden/internal/api/source"
+
+// Source implements the source.Source interface and exposes anonymous member functions for mocking
+// source.Source behavior
+type Source struct {
+	GetPackageFunc      func(namespace, name string) (source.Package, error)
+	GetReleaseFunc      func(namespace, name, version string) (source.Release, error)
+	DownloadURLFunc     func(release source.Release) (string, error)
+	GetDownloadSizeFunc func(release source.Release) (int64, error)
+}
+
+func (s *Source) GetPackage(namespace, name string) (source.Package, error) {
+	return s.GetPackageFunc(namespace, name)
+}
+
+func (s *Source) GetRelease(namespace, name, version string) (source.Release, error) {
+	return s.GetReleaseFunc(namespace, name, version)
+}
+
+func (s *Source) DownloadURL(release source.Release) (string, error) {
+	return s.DownloadURLFunc(release)
+}
+
+func (s *Source) GetDownloadSize(release source.Release) (int64, error) {
+	return s.GetDownloadSizeFunc(release)
+}
@@ -5,9 +5,19 @@ import "warden/internal/api/thunderstore"
 // Thunderstore implements the thunderstore.Thunderstore interface and exposes anonymous member functions for mocking
 // thunderstore.Thunderstore behavior
 type Thunderstore struct {
-	GetPackageFunc func(namespace, name string) (thunderstore.Package, error)
+	GetPackageFunc      func(namespace, name string) (thunderstore.Package, error)
+	GetReleaseFunc      func(namespace, name, version string) (thunderstore.Release, error)
+	GetDownloadSizeFunc func(url string) (int64, error)
 }
 
 func (ts *Thunderstore) GetPackage(namespace, name string) (thunderstore.Package, error) {
 	return ts.GetPackageFunc(namespace, name)
 }
+
+func (ts *Thunderstore) GetRelease(namespace, name, version string) (thunderstore.Release, error) {
+	return ts.GetReleaseFunc(namespace, name, version)
+}
+
+func (ts *Thunderstore) GetDownloadSize(url string) (int64, error) {
+	return ts.GetDownloadSizeFunc(url)
+}
@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"time"
+	"warden/internal/domain/world"
+)
+
+// Worlds implements the file.Worlds interface and exposes anonymous member functions for mocking
+// file.Worlds behavior
+type Worlds struct {
+	ListWorldsFunc    func() ([]string, error)
+	BackupFunc        func(name string, createdAt time.Time) (world.Snapshot, error)
+	ListSnapshotsFunc func() ([]world.Snapshot, error)
+	RestoreFunc       func(s world.Snapshot) error
+	DeleteFunc        func(s world.Snapshot) error
+}
+
+func (w *Worlds) ListWorlds() ([]string, error) {
+	return w.ListWorldsFunc()
+}
+
+func (w *Worlds) Backup(name string, createdAt time.Time) (world.Snapshot, error) {
+	return w.BackupFunc(name, createdAt)
+}
+
+func (w *Worlds) ListSnapshots() ([]world.Snapshot, error) {
+	return w.ListSnapshotsFunc()
+}
+
+func (w *Worlds) Restore(s world.Snapshot) error {
+	return w.RestoreFunc(s)
+}
+
+func (w *Worlds) Delete(s world.Snapshot) error {
+	return w.DeleteFunc(s)
+}
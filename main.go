@@ -4,54 +4,178 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/user"
 	"path/filepath"
 	"warden/command"
+	"warden/internal/api"
+	"warden/internal/api/nexus"
+	"warden/internal/api/source"
 	"warden/internal/api/thunderstore"
 	"warden/internal/config"
 	"warden/internal/data/file"
 	"warden/internal/data/repo"
+	"warden/internal/domain/instance"
+	"warden/internal/domain/logs"
+	"warden/internal/domain/systemd"
+	"warden/internal/domain/world"
 	"warden/internal/service"
 
 	"github.com/mitchellh/go-homedir"
 )
 
 func main() {
-	// Load in the config
+	// Load in the config, which is kept in the home directory unless another one is picked
 	home, err := homedir.Dir()
 	if err != nil {
 		log.Fatal(err)
 	}
+	configDir := home
+	if dir := os.Getenv(config.ConfigDirectoryEnv); dir != "" {
+		if configDir, err = homedir.Expand(dir); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// Named instances each have their own config, inside the config directory
+	name := command.Instance(os.Args[1:])
+	if instance.IsDefault(name) {
+		name = ""
+	}
+	instanceDir := instance.Directory(configDir, name)
+	if name != "" {
+		if err := instance.ValidateName(name); err != nil {
+			log.Fatal(err.Error())
+		}
+		if _, err := os.Stat(filepath.Join(instanceDir, config.WardenConfigFile)); err != nil {
+			log.Fatalf("instance %s does not exist, add it with 'warden instances add %s'", name, name)
+		}
+	}
+
+	cfg, err := config.Load(instanceDir)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	cfg.Instance = name
 
-	cfg, err := config.Load(home)
+	// Every path Warden uses comes from the config. They're passed on to other programs, e.g. the
+	// game server, which don't expand ~.
+	paths, err := cfg.Expand()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
 	// Open database and initialize tables if they don't already exist
-	db, err := repo.OpenDatabase(filepath.Join(home, ".warden.db"))
+	db, err := repo.OpenDatabase(paths.DatabasePath)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 	repo.CreateModsTable(db)
 	repo.CreateFrameworksTable(db)
+	repo.CreateJobRunsTable(db)
 
 	// Initialize and injection dependencies into commands
 	mr := repo.NewModsRepo(db)
 	fr := repo.NewFrameworksRepo(db)
-	ts := thunderstore.New(&http.Client{})
-	fm := file.NewManager(&http.Client{}, cfg.ValheimDirectory)
+	ts := newThunderstore(cfg)
+	fm := file.NewManager(newDownloadClient(cfg), paths.ValheimDirectory, paths.ModDirectory, paths.CacheDirectory)
+
+	c := service.NewConfirmer(os.Stdin)
+	sources := source.Sources{
+		source.Thunderstore: thunderstore.NewSource(ts),
+		source.Nexus:        nexus.New(&http.Client{}, nexus.API, cfg.NexusAPIKey),
+	}
+	ms := service.NewModService(mr, fm, sources, c)
+	fs := service.NewFrameworkService(fr, fm, ts, c)
+
+	// The game server is pointed at the same save directory that worlds are backed up from
+	pids := file.NewPIDFile(filepath.Join(paths.ConfigDirectory, config.PIDFile))
+	serverLog := filepath.Join(paths.ConfigDirectory, config.ServerLogFile)
+	ss := service.NewServerService(paths, fr, pids, serverLog)
 
-	ms := service.NewModService(mr, fm, ts, os.Stdin)
-	fs := service.NewFrameworkService(fr, fm, ts, os.Stdin)
-	ss := service.NewServerService(*cfg)
+	retention := world.Retention{
+		KeepLast:   cfg.BackupKeepLast,
+		KeepDaily:  cfg.BackupKeepDaily,
+		KeepWeekly: cfg.BackupKeepWeekly,
+	}
+	ws := service.NewWorldService(file.NewWorlds(paths.SaveDirectory, paths.BackupDirectory), retention, ss, c)
+
+	// systemd units run this same Warden executable, as the current user
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	currentUser, err := user.Current()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	unitDirs := map[string]string{
+		systemd.UserScope:   filepath.Join(home, ".config", "systemd", "user"),
+		systemd.SystemScope: "/etc/systemd/system",
+	}
+	sch := service.NewSchedulerService(paths, repo.NewJobsRepo(db), ws, ms, ss, c)
+	logPaths := map[string]string{
+		logs.Server:  serverLog,
+		logs.BepInEx: filepath.Join(paths.ValheimDirectory, "BepInEx", "LogOutput.log"),
+	}
+	ls := service.NewLogService(mr, logPaths)
+	vs := service.NewVerifierService(paths, mr, ss, ls, paths.ModDirectory)
+	st := service.NewSteamCMDService(paths, ss)
+	ps := service.NewPlayerService(file.NewPlayerLists(paths.SaveDirectory), ss)
+	sd := service.NewSystemdService(paths, ss, unitDirs, executable, currentUser.Username, home)
+	is := service.NewInstanceService(paths, configDir, c)
+	pr := file.NewProfiles(filepath.Join(paths.ConfigDirectory, config.ProfileDirectory), filepath.Join(paths.ValheimDirectory, file.BepInExConfigDirectory), paths.CacheDirectory)
+	prs := service.NewProfileService(mr, fr, fm, pr, sources, ss, c)
+	mps := service.NewModpackService(mr, fr)
+	mrs := service.NewMirrorService(ts, fm, file.NewMirror(paths.CacheDirectory))
 
 	// Register commands
 	listCmd := command.NewListCommand(ms)
 	addCmd := command.NewAddCommand(fs, ms)
 	removeCmd := command.NewRemoveCommand(fs, ms)
-	updateCmd := command.NewUpdateCommand(fs, ms)
+	updateCmd := command.NewUpdateCommand(fs, ms, ws, vs)
 	configCmd := command.NewConfigCommand(*cfg)
 	startCmd := command.NewStartCommand(ss)
+	stopCmd := command.NewStopCommand(ss)
+	restartCmd := command.NewRestartCommand(ss)
+	statusCmd := command.NewStatusCommand(ss)
+	superviseCmd := command.NewSuperviseCommand(ss)
+	serviceCmd := command.NewServiceCommand(sd, ss)
+	worldCmd := command.NewWorldCommand(ws)
+	scheduleCmd := command.NewScheduleCommand(sch)
+	daemonCmd := command.NewDaemonCommand(sch)
+	logsCmd := command.NewLogsCommand(ls)
+	serverCmd := command.NewServerCommand(st)
+	playersCmd := command.NewPlayersCommand(ps)
+	instancesCmd := command.NewInstancesCommand(is, name)
+	profileCmd := command.NewProfileCommand(prs)
+	modpackCmd := command.NewModpackCommand(mps)
+	mirrorCmd := command.NewMirrorCommand(mrs)
 
-	command.Execute(listCmd, addCmd, removeCmd, updateCmd, configCmd, startCmd)
+	command.Execute(c, listCmd, addCmd, removeCmd, updateCmd, configCmd, startCmd, stopCmd, restartCmd, statusCmd, superviseCmd, serviceCmd, worldCmd, scheduleCmd, daemonCmd, logsCmd, serverCmd, playersCmd, instancesCmd, profileCmd, modpackCmd, mirrorCmd)
+}
+
+// newThunderstore looks mods up in every configured Thunderstore registry, or in Thunderstore
+// itself if there aren't any
+func newThunderstore(cfg *config.Config) thunderstore.Thunderstore {
+	registries := []thunderstore.Thunderstore{}
+	for _, r := range cfg.Registries() {
+		registries = append(registries, thunderstore.New(&http.Client{}, r.URL, r.Token))
+	}
+	if len(registries) == 0 {
+		registries = append(registries, thunderstore.New(&http.Client{}, thunderstore.URL, ""))
+	}
+	return thunderstore.NewRegistries(registries...)
+}
+
+// newDownloadClient downloads mod archives, sending private registries' tokens with downloads from
+// them
+func newDownloadClient(cfg *config.Config) api.HTTPClient {
+	tokens := map[string]string{}
+	for _, r := range cfg.Registries() {
+		tokens[r.URL] = r.Token
+	}
+	return thunderstore.NewTokenClient(&http.Client{}, tokens)
 }